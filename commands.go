@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// command is an "amazing <name>" subcommand that runs instead of the launcher.
+// Subcommands are a plain table with a flag.FlagSet each rather than cobra or urfave/cli:
+// the launcher stays on the standard library for its CLI, and "amazing <tool> [args...]"
+// and "amazing -- <args...>" pass arguments through untouched, which a framework would parse.
+type command struct {
+	name  string
+	usage []string // What follows the name, one line per form
+	run   func(args []string) int
+}
+
+// commands are the subcommands, in the order of the usage message. "launch" and "tour"
+// aren't among them: they go on to launch a tool like bare "amazing".
+var commands = []command{
+	{"list", []string{"[--json]"}, runList},
+	{"install", []string{"<tool>..."}, runInstall},
+	{"status", []string{"[--json]"}, runStatus},
+	{"config", []string{"", "path", "edit", "get <key>", "set <key> <value>", "hide <tool>", "unhide <tool>"}, runConfig},
+	{"usage", []string{"[--format text|json|gha] [--all]"}, runUsage},
+	{"resets", []string{"[--ics] [--output file] [--all]"}, runResets},
+	{"history", []string{"[--limit 20] [search...]", "export [--format csv|json] [--since 30d] [--output file]", "import [--dry-run]"}, runHistory},
+	{"doctor", []string{"[--json]"}, runDoctor},
+	{"daemon", []string{"[--interval 5m] [--once] [--metrics :9090] [--all]"}, runDaemon},
+}
+
+// findCommand returns the subcommand called name, or nil.
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// writeUsage writes the forms "amazing" can be run in, for -h.
+func writeUsage(w io.Writer, program string) {
+	lines := []string{
+		"[[--launch] <tool>] [--] [args...]",
+		"launch [--note text] [--cwd dir] <tool> [--] [args...]",
+		"tour",
+	}
+	for _, c := range commands {
+		for _, form := range c.usage {
+			lines = append(lines, strings.TrimSpace(c.name+" "+form))
+		}
+	}
+	for i, line := range lines {
+		prefix := "Usage:"
+		if i > 0 {
+			prefix = "      "
+		}
+		fmt.Fprintf(w, "%s %s %s\n", prefix, program, line)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+// testHome points HOME at an empty directory and PATH at one holding only a fake codex,
+// so the commands see neither the user's settings nor their tools.
+func testHome(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake codex is a shell script")
+	}
+	t.Setenv("HOME", t.TempDir())
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "codex"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+}
+
+// runCaptured runs a command with args and returns its exit code and what it printed
+// to stdout and stderr.
+func runCaptured(t *testing.T, run func([]string) int, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	capture := func(f **os.File) (done func() string) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		old := *f
+		*f = w
+		out := make(chan string)
+		go func() {
+			data, _ := io.ReadAll(r)
+			out <- string(data)
+		}()
+		return func() string {
+			*f = old
+			w.Close()
+			return <-out
+		}
+	}
+	doneOut, doneErr := capture(&os.Stdout), capture(&os.Stderr)
+	code = run(args)
+	return code, doneOut(), doneErr()
+}
+
+func TestRunList(t *testing.T) {
+	testHome(t)
+	if err := config.SetHidden("gemini", true); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out, _ := runCaptured(t, runList)
+	if code != 0 || !strings.Contains(out, "codex\tinstalled\n") || !strings.Contains(out, "claude\tnot-installed\n") {
+		t.Errorf("list = %d:\n%s\nwant codex installed and claude not", code, out)
+	}
+
+	code, out, _ = runCaptured(t, runList, "--json")
+	var list struct {
+		SchemaVersion int `json:"schema_version"`
+		Tools         []struct {
+			Name, Status, Path string
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); code != 0 || err != nil {
+		t.Fatalf("list --json = %d, %v:\n%s", code, err, out)
+	}
+	if list.SchemaVersion != 1 {
+		t.Errorf("schema_version = %d, want 1", list.SchemaVersion)
+	}
+	found := false
+	for _, lt := range list.Tools {
+		if lt.Name == "gemini" {
+			t.Error("list --json shows the hidden gemini")
+		}
+		if lt.Name == "codex" {
+			found = true
+			if lt.Status != "installed" || filepath.Base(lt.Path) != "codex" {
+				t.Errorf("codex = %+v, want installed with its path", lt)
+			}
+		}
+	}
+	if !found {
+		t.Error("list --json has no codex")
+	}
+
+	if code, _, _ := runCaptured(t, runList, "--nope"); code != 2 {
+		t.Errorf("list --nope = %d, want 2", code)
+	}
+}
+
+func TestRunDoctor(t *testing.T) {
+	testHome(t)
+	if err := config.SetHidden("gemini", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetSetting("tools", `[{"name": "mytool", "command": "mytool"}]`); err != nil {
+		t.Fatal(err)
+	}
+
+	_, out, stderr := runCaptured(t, runDoctor, "--json")
+	var report struct {
+		Findings []struct{ Check string } `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("doctor --json printed %v:\n%s\nstderr:\n%s", err, out, stderr)
+	}
+	checked := make(map[string]bool)
+	for _, f := range report.Findings {
+		checked[f.Check] = true
+	}
+	// The tools list shows: the custom one, not the hidden one
+	for check, want := range map[string]bool{"tool:codex": true, "tool:mytool": true, "tool:gemini": false} {
+		if checked[check] != want {
+			t.Errorf("doctor checked %s = %v, want %v", check, checked[check], want)
+		}
+	}
+}
+
+func TestRunInstall(t *testing.T) {
+	testHome(t)
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantStderr string
+	}{
+		{"no tools", nil, 2, "", "Usage:"},
+		{"unknown tool", []string{"--dry-run", "codex", "nope"}, 2, "", "unknown tool: nope"},
+		{"already installed", []string{"--dry-run", "codex"}, 0, "# codex is already installed\n", ""},
+		{"dry run", []string{"--dry-run", "claude"}, 0, "# claude code\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out, stderr := runCaptured(t, runInstall, tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("install %q = %d, want %d; stderr:\n%s", tt.args, code, tt.wantCode, stderr)
+			}
+			if !strings.Contains(out, tt.wantOut) || !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("install %q printed:\n%s\nstderr:\n%s\nwant %q and %q", tt.args, out, stderr, tt.wantOut, tt.wantStderr)
+			}
+			if tt.wantCode == 2 && out != "" {
+				t.Errorf("install %q printed %q, want nothing installed or planned", tt.args, out)
+			}
+		})
+	}
+}
+
+func TestRunConfig(t *testing.T) {
+	testHome(t)
+	// In order: each step sees the settings the ones before it saved
+	steps := []struct {
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		{[]string{"path"}, 0, filepath.Join(".amazing-cli", "config.json")},
+		{[]string{"get", "theme"}, 0, `""`},
+		{[]string{"set", "theme", "dracula"}, 0, ""},
+		{[]string{"get", "theme"}, 0, `"dracula"`},
+		{[]string{"set", "ranking.order", "frecency"}, 0, ""},
+		{[]string{"get", "ranking.order"}, 0, `"frecency"`},
+		{[]string{"set", "clear_screen", "maybe"}, 1, ""},
+		{[]string{"set", "ranking.nope", "1"}, 1, ""},
+		{[]string{"get", "nope"}, 1, ""},
+		{[]string{"hide", "nope"}, 1, ""},
+		{[]string{"hide", "gemini"}, 0, ""},
+		{[]string{"hide", "gemini"}, 1, ""},
+		{[]string{"get", "hidden"}, 0, `"gemini"`},
+		{[]string{"unhide", "gemini"}, 0, ""},
+		{[]string{"unhide", "gemini"}, 1, ""},
+		{nil, 0, `"theme": "dracula"`},
+		{[]string{"set", "theme"}, 2, ""},
+		{[]string{"nope"}, 2, ""},
+	}
+	for _, step := range steps {
+		code, out, stderr := runCaptured(t, runConfig, step.args...)
+		if code != step.wantCode || !strings.Contains(out, step.wantOut) {
+			t.Errorf("config %q = %d:\n%s\nstderr:\n%s\nwant %d and %q", step.args, code, out, stderr, step.wantCode, step.wantOut)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+// runConfig prints or changes the settings, and returns the process exit code:
+//
+//	amazing config                    the effective settings, as JSON
+//	amazing config path               the settings file
+//	amazing config edit               open the settings file in $VISUAL or $EDITOR
+//	amazing config get <key>          one setting, e.g. "ranking.order"
+//	amazing config set <key> <value>  change one setting in the settings file
+//	amazing config hide <tool>        leave a tool out of the launcher
+//	amazing config unhide <tool>      show a hidden tool again
+func runConfig(args []string) int {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s config [path | edit | get <key> | set <key> <value> | hide <tool> | unhide <tool>]\n", os.Args[0])
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var err error
+	switch action, rest := fs.Arg(0), fs.Args(); {
+	case action == "":
+		err = printJSON(config.LoadSettings())
+	case action == "path" && len(rest) == 1:
+		fmt.Println(config.SettingsFilePath())
+	case action == "edit" && len(rest) == 1:
+		err = editSettings()
+	case action == "get" && len(rest) == 2:
+		var value any
+		if value, err = config.GetSetting(rest[1]); err == nil {
+			err = printJSON(value)
+		}
+	case action == "set" && len(rest) == 3:
+		err = config.SetSetting(rest[1], rest[2])
+	case action == "hide" && len(rest) == 2:
+		err = hideTool(rest[1])
+	case action == "unhide" && len(rest) == 2:
+		err = config.SetHidden(rest[1], false)
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// editSettings opens the settings file in the user's editor and checks that it still
+// loads once the editor exits.
+func editSettings() error {
+	cmd, err := config.EditorCommand(config.SettingsFilePath())
+	if err != nil {
+		return err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor: %w", err)
+	}
+	if err := config.CheckSettingsFile(); err != nil {
+		return fmt.Errorf("%w (the defaults are used until it is fixed)", err)
+	}
+	return nil
+}
+
+// hideTool hides the tool called name, which must be one of the launcher's tools.
+func hideTool(name string) error {
+	settings := config.LoadSettings()
+	settings.Hidden = nil
+	if loadRegistry(settings).Get(name) == nil {
+		return fmt.Errorf("unknown tool %q", name)
+	}
+	return config.SetHidden(name, true)
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
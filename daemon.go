@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/metrics"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// pollTimeout bounds a whole poll; providers still running after it are cancelled.
+const pollTimeout = time.Minute
+
+// runDaemon polls provider balances on an interval and writes them to the balance cache,
+// so the TUI starts with fresh balances instead of fetching them itself.
+// Returns the process exit code.
+func runDaemon(args []string) int {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	interval := fs.Duration("interval", 5*time.Minute, "how often to poll balances")
+	once := fs.Bool("once", false, "poll once and exit")
+	metricsAddr := fs.String("metrics", "", "serve balances as Prometheus metrics on this address (e.g., :9090)")
+	all := fs.Bool("all", false, "also poll tools whose CLI isn't installed, e.g. in a container with only their credentials mounted")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *interval < time.Minute {
+		fmt.Fprintf(os.Stderr, "Error: --interval must be at least 1m, not %s\n", *interval)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *metricsAddr != "" {
+		srv, err := serveMetrics(*metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer srv.Close()
+	}
+
+	settings := config.LoadSettings()
+	registry := loadRegistry(settings)
+	started := time.Now()
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	var lastPoll time.Time
+	lowPower := false
+	for {
+		// Once idle, ticks only check for a launch until the low-power interval is up
+		every := settings.Idle.PollInterval(*interval, lastActive(started), time.Now())
+		if idle := every > *interval; idle != lowPower {
+			lowPower = idle
+			if idle {
+				fmt.Fprintf(os.Stderr, "Idle: no tool launched for %dm, polling every %dm until one is\n", settings.Idle.TimeoutMinutes, int(every/time.Minute))
+			} else {
+				fmt.Fprintf(os.Stderr, "A tool was launched, polling every %dm again\n", int(*interval/time.Minute))
+			}
+		}
+		// Ticks come a little early or late, so a poll half an interval early is on time
+		if time.Since(lastPoll)+*interval/2 >= every {
+			lastPoll = time.Now()
+			if err := pollBalances(ctx, registry, *interval, *all); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save balances: %v\n", err)
+			}
+			warnQuarantined()
+		}
+		if *once {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastActive returns when a tool was last launched, or since if none was launched after it.
+func lastActive(since time.Time) time.Time {
+	if launches := config.LoadLaunches(); len(launches) > 0 && launches[len(launches)-1].At.After(since) {
+		return launches[len(launches)-1].At
+	}
+	return since
+}
+
+// serveMetrics starts serving /metrics and /healthz on addr in the background.
+func serveMetrics(addr string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("metrics: %w", err)
+	}
+	srv := &http.Server{Handler: metrics.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "Warning: metrics server stopped: %v\n", err)
+		}
+	}()
+	return srv, nil
+}
+
+// pollBalances fetches the balances of all installed tools (every tool with all) with a
+// balance provider and saves them. Tools whose fetch returns nothing keep their last balance.
+func pollBalances(ctx context.Context, registry *tool.Registry, interval time.Duration, all bool) error {
+	cache, _ := config.LoadBalanceCache()
+	if cache.Balances == nil {
+		cache.Balances = make(map[string]*tool.Balance)
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+	for name, balance := range fetchBalances(fetchCtx, registry, all) {
+		cache.Balances[name] = balance
+	}
+
+	if ctx.Err() != nil {
+		// Interrupted mid-poll; don't mark partial results as fresh
+		return nil
+	}
+	cache.UpdatedAt = time.Now()
+	cache.Interval = interval
+	return config.SaveBalanceCache(cache)
+}
+
+// fetchBalances fetches the balances of all installed tools (every tool with all) with a
+// balance provider within ctx's budget, keyed by tool name, and records weekly samples.
+func fetchBalances(ctx context.Context, registry *tool.Registry, all bool) map[string]*tool.Balance {
+	balances := provider.FetchAll(ctx, registry, all)
+	for name, balance := range balances {
+		config.RecordWeeklySample(name, balance) // Keeps burn-rate projections up to date
+	}
+	return balances
+}
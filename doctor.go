@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/doctor"
+)
+
+// runDoctor runs the diagnostics and returns the process exit code:
+// 0 when no errors were found, 1 when at least one check failed, 2 on usage errors.
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "print findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	// The tools the menu lists: catalog and custom ones too, without the hidden ones
+	settings := config.LoadSettings()
+	registry := loadRegistry(settings)
+	findings := doctor.Run(context.Background(), doctor.DefaultChecks(registry, settings.LaunchOptions()), runtime.NumCPU())
+
+	var err error
+	if *jsonOutput {
+		err = doctor.WriteJSON(os.Stdout, findings)
+	} else {
+		err = doctor.WriteText(os.Stdout, findings)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if doctor.HasErrors(findings) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/importer"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/report"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// runHistory lists recent launches, oldest first, and returns the process exit code.
+// Positional arguments filter the launches by tool, note or directory; "history export"
+// writes them for spreadsheets and dashboards instead, and "history import" backfills them
+// from the agents' own session logs.
+func runHistory(args []string) int {
+	if len(args) > 0 && args[0] == "export" {
+		return runHistoryExport(args[1:])
+	}
+	if len(args) > 0 && args[0] == "import" {
+		return runHistoryImport(args[1:])
+	}
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	limit := fs.Int("limit", 20, "show at most this many of the most recent matching launches (0 for all)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	query := strings.Join(fs.Args(), " ")
+
+	timefmt.Set(config.LoadSettings().DateTimeFormat())
+	var launches []config.Launch
+	for _, l := range config.LoadLaunches() {
+		if l.Matches(query) {
+			launches = append(launches, l)
+		}
+	}
+	if *limit > 0 && len(launches) > *limit {
+		launches = launches[len(launches)-*limit:]
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, l := range launches {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", timefmt.DateTime(l.At), l.Tool, tool.ShortenHome(l.Dir), l.Note)
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runHistoryExport writes the launches, optionally only recent ones or those of a project,
+// as CSV or JSON.
+func runHistoryExport(args []string) int {
+	fs := flag.NewFlagSet("history export", flag.ContinueOnError)
+	format := fs.String("format", report.FormatCSV, "output format: csv, or json with per-tool stats")
+	since := fs.String("since", "", "only export launches from this long ago, e.g. 30d, 2w or 12h")
+	project := fs.String("project", "", "only export launches in projects whose path contains this, e.g. a client's repository")
+	output := fs.String("output", "", "file to write instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != report.FormatCSV && *format != report.FormatJSON {
+		fmt.Fprintf(os.Stderr, "Error: --format must be csv or json, not %q\n", *format)
+		return 2
+	}
+	var cutoff time.Time
+	if *since != "" {
+		age, err := report.ParseAge(*since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --since: %v\n", err)
+			return 2
+		}
+		cutoff = time.Now().Add(-age)
+	}
+
+	var launches []config.Launch
+	for _, l := range config.LoadLaunches() {
+		if !l.At.Before(cutoff) && strings.Contains(strings.ToLower(l.ProjectPath()), strings.ToLower(*project)) {
+			launches = append(launches, l)
+		}
+	}
+
+	var buf bytes.Buffer
+	err := report.WriteHistory(&buf, *format, launches)
+	if err == nil {
+		if *output != "" && *output != "-" {
+			err = os.WriteFile(*output, buf.Bytes(), 0644)
+		} else {
+			_, err = os.Stdout.Write(buf.Bytes())
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runHistoryImport adds the sessions found in the agents' logs from before amazing-cli was
+// adopted to the launch history, so its stats aren't empty for new users.
+func runHistoryImport(args []string) int {
+	fs := flag.NewFlagSet("history import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only report how many sessions were found")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var launches []config.Launch
+	for _, source := range importer.Sources {
+		found, err := source.Import()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read %s sessions: %v\n", source.Tool, err)
+			continue
+		}
+		fmt.Printf("Found %d %s sessions\n", len(found), source.Tool)
+		launches = append(launches, found...)
+	}
+	if *dryRun {
+		return 0
+	}
+	added, err := config.ImportLaunches(launches)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Added %d sessions to the launch history\n", added)
+	return 0
+}
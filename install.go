@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// runInstall installs the named tools without asking, for setup scripts, and returns the
+// process exit code: 2 for an unknown tool, 1 if any install failed. With --dry-run it
+// prints the commands instead of running them.
+func runInstall(args []string) int {
+	fs := flag.NewFlagSet("install", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "print the commands that would be run, without running them")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s install [--dry-run] <tool>...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	settings := config.LoadSettings()
+	tool.SetMirrors(settings.Mirrors.Mirrors())
+	registry := loadRegistry(settings)
+
+	// Check every name first, so a typo doesn't leave the list half installed
+	var tools []*tool.Tool
+	for _, name := range fs.Args() {
+		t := registry.Get(name)
+		if t == nil {
+			fmt.Fprintf(os.Stderr, "Error: unknown tool: %s\n", name)
+			printToolList(os.Stderr, registry)
+			return 2
+		}
+		tools = append(tools, t)
+	}
+
+	code := 0
+	for _, t := range tools {
+		if t.IsInstalled() {
+			if *dryRun {
+				fmt.Print("# ") // The output of a dry run reads as a script
+			}
+			fmt.Printf("%s is already installed\n", t.DisplayName)
+			continue
+		}
+		if *dryRun {
+			printInstallPlan(t)
+			continue
+		}
+		if !t.HasInstallCommand() {
+			fmt.Fprintf(os.Stderr, "Error: %s has no automated install", t.DisplayName)
+			if t.InstallURL != "" {
+				fmt.Fprintf(os.Stderr, "; see %s", t.InstallURL)
+			}
+			fmt.Fprintln(os.Stderr)
+			code = 1
+			continue
+		}
+		for _, warning := range t.PreInstallWarnings() {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		fmt.Printf("Installing %s...\n", t.DisplayName)
+		err := t.InstallWithOutput(os.Stdout)
+		// Nobody to ask: go down the other package managers until one works
+		for _, next := range t.InstallAlternatives() {
+			if err == nil {
+				break
+			}
+			fmt.Fprintf(os.Stderr, "Warning: installing %s: %v; retrying with %s\n", t.Name, err, next)
+			err = t.InstallWith(next, os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: installing %s: %v\n", t.Name, err)
+			code = 1
+			continue
+		}
+		fmt.Printf("✓ Installed %s\n", t.DisplayName)
+	}
+	return code
+}
+
+// printInstallPlan prints the commands that install t, or why it can't be installed.
+func printInstallPlan(t *tool.Tool) {
+	plan := t.InstallPlan()
+	if len(plan) == 0 {
+		fmt.Printf("# %s has no automated install here", t.DisplayName)
+		if t.InstallURL != "" {
+			fmt.Printf("; see %s", t.InstallURL)
+		}
+		fmt.Println()
+		return
+	}
+	fmt.Printf("# %s\n", t.DisplayName)
+	for _, step := range plan {
+		fmt.Println(step)
+	}
+	for _, next := range t.InstallAlternatives() {
+		fmt.Printf("# if that fails: %s\n", next.Command())
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/report"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// runList prints the tools of the registry with whether they are installed, and returns
+// the process exit code. Unlike status it runs nothing, so it is quick enough for completions.
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "print the tools as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	registry := loadRegistry(config.LoadSettings())
+	if !*jsonOutput {
+		printToolList(os.Stdout, registry)
+		return 0
+	}
+	if err := writeToolList(os.Stdout, registry); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeToolList writes the tools as JSON, see report.ListSchema.
+func writeToolList(w io.Writer, registry *tool.Registry) error {
+	var tools []report.ListedTool
+	for _, t := range registry.List() {
+		lt := report.ListedTool{Name: t.Name, DisplayName: t.DisplayName, Status: toolStatus(t)}
+		if path, err := t.Path(); err == nil {
+			lt.Path = path
+		}
+		tools = append(tools, lt)
+	}
+	return report.WriteList(w, tools)
+}
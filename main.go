@@ -3,96 +3,383 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/mattn/go-isatty"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/catalog"
 	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
-	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/codex"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
 	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 	"github.com/huajianxiaowanzi/amazing-cli/pkg/tui"
 )
 
 func main() {
+	launchName := flag.String("launch", "", "launch the named tool directly, skipping the TUI")
+	onSelect := flag.String("on-select", onSelectExec, "what to do with the selected tool: exec it, print its name, or json")
+	themeName := flag.String("theme", "", "color theme: "+strings.Join(tui.ThemeNames(), ", ")+", or the path of a theme file")
+	note := flag.String("note", "", "note about what the tool is launched for, kept in the history")
+	cwd := flag.String("cwd", "", "directory the tool starts in, instead of the current one or the tool's configured dir")
+	verbose := flag.Bool("verbose", false, "log debug details to "+tool.ShortenHome(log.Path())+" (also $"+log.DebugEnv+"=1)")
+	flag.Usage = func() {
+		writeUsage(flag.CommandLine.Output(), os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.Setup(*verbose)
+	passthrough := afterDoubleDash()
+	if !validOnSelect(*onSelect) {
+		fmt.Fprintf(os.Stderr, "Error: --on-select must be exec, print or json, not %q\n", *onSelect)
+		os.Exit(2)
+	}
+
+	// When printing the selection, stdout belongs to the wrapper and the TUI draws on stderr
+	uiOut := os.Stdout
+	if *onSelect != onSelectExec {
+		uiOut = os.Stderr
+	}
+
+	// Dispatch subcommands before starting the TUI
+	startTour := !config.TourCompleted() // Show the guided tour on first run
+	subcommand := flag.Arg(0)
+	if passthrough {
+		subcommand = ""
+	}
+	args := flag.Args()
+	switch subcommand {
+	case "launch":
+		var ok bool
+		if *launchName, args, ok = parseLaunch(args[1:], note, cwd); !ok {
+			os.Exit(2)
+		}
+	case "tour":
+		startTour = true
+	default:
+		if c := findCommand(subcommand); c != nil {
+			os.Exit(c.run(args[1:]))
+		}
+	}
+
+	// Apply user settings that affect every command
+	settings := config.LoadSettings()
+	timefmt.Set(settings.DateTimeFormat())
+	tool.SetMirrors(settings.Mirrors.Mirrors())
+	if *themeName == "" {
+		*themeName = settings.Theme
+	}
+	theme, err := tui.LoadTheme(*themeName, config.ThemeFilePath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: using the default theme: %v\n", err)
+	}
+
 	// Load available AI tools
-	registry := config.LoadDefaultTools()
+	registry := buildRegistry(settings)
+
+	// "amazing <tool> [args...]", "amazing --launch <tool> [args...]" and
+	// "amazing launch <tool> [args...]" skip the TUI;
+	// "amazing -- <args...>" passes the arguments to whichever tool is picked
+	extraArgs := args
+	if *launchName == "" && !passthrough && flag.NArg() > 0 && registry.Get(flag.Arg(0)) != nil {
+		*launchName, extraArgs = flag.Arg(0), flag.Args()[1:]
+	}
+	headless := *launchName != ""
+	if headless && registry.Get(*launchName) == nil {
+		fmt.Fprintf(os.Stderr, "Error: unknown tool: %s\n", *launchName)
+		printToolList(os.Stderr, registry)
+		os.Exit(2)
+	}
+	switch {
+	case headless && len(extraArgs) > 0 && extraArgs[0] == "--":
+		extraArgs = extraArgs[1:] // "amazing codex -- --model x"
+	case !headless && !passthrough:
+		extraArgs = nil
+	}
+
+	var postMortem *tui.PostMortem
+	for {
+		selectedToolName := selectTool(registry, uiOut, theme, *onSelect != onSelectExec, *launchName, startTour, postMortem, &extraArgs, note, cwd)
+		*launchName, startTour = "", false
+		warnQuarantined()
+
+		// If user quit without selecting, exit gracefully
+		if selectedToolName == "" {
+			os.Exit(0)
+		}
+
+		// Get the selected tool
+		selectedTool := registry.Get(selectedToolName)
+		if selectedTool == nil {
+			fmt.Fprintf(os.Stderr, "Error: tool not found: %s\n", selectedToolName)
+			os.Exit(1)
+		}
+
+		// Safety check: verify tool is installed before execution
+		// The TUI handles installation prompts, but we verify here as a safety measure
+		if !selectedTool.RefreshInstalled() {
+			if headless {
+				fmt.Fprintf(os.Stderr, "Error: %s is not installed (%s not found in PATH)\n", selectedTool.Name, selectedTool.Command)
+				if !selectedTool.SupportsPlatform() {
+					fmt.Fprintf(os.Stderr, "It isn't available on this OS (%s).\n", selectedTool.PlatformNote())
+				} else if path := selectedTool.WindowsSidePath(); path != "" {
+					fmt.Fprintf(os.Stderr, "It is only installed on the Windows side (%s). Install the Linux version from the menu, or run it with cmd.exe /c %s from a Windows drive.\n", path, selectedTool.Command)
+				} else if selectedTool.InstallURL != "" {
+					fmt.Fprintf(os.Stderr, "Install it from %s or run amazing without arguments to install it from the menu.\n", selectedTool.InstallURL)
+				}
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "\n❌ Tool not installed: %s\n", selectedTool.Command)
+			fmt.Fprintf(os.Stderr, "Note: This should not happen if you used the TUI installation feature.\n")
+			fmt.Fprintf(os.Stderr, "Please restart the application and try installing again.\n\n")
+			os.Exit(1)
+		}
+
+		// Update usage data with current time
+		launchedAt := time.Now()
+		if err := config.RecordToolLaunch(selectedToolName, launchedAt); err != nil {
+			// Non-fatal error, just log it
+			fmt.Fprintf(os.Stderr, "Warning: failed to save usage data: %v\n", err)
+		}
+		dir, err := selectedTool.LaunchDir(*cwd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if dir == "" {
+			dir, _ = os.Getwd()
+		}
+		if err := config.RecordRecentDir(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save recent directories: %v\n", err)
+		}
+		if err := config.RecordLaunch(config.Launch{Tool: selectedToolName, At: launchedAt, Dir: dir, Args: extraArgs, Note: *note}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save launch history: %v\n", err)
+		}
+		*note = "" // A retry after a post-mortem is a new session
+
+		// Let a wrapper launch the tool itself
+		if *onSelect != onSelectExec {
+			recordSession(selectedToolName, config.Session{Start: launchedAt}) // The wrapper runs it, for however long
+			if err := writeSelection(os.Stdout, *onSelect, selectedTool, extraArgs, dir); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		// Execute the tool
+		// This allows the tool to take full control of the terminal
+		opts := config.LoadSettings().LaunchOptions()
+		opts.ExtraArgs = extraArgs // Appended to the tool's configured arguments
+		opts.Dir = dir
+		stderrTail := tool.NewTailBuffer(tui.PostMortemLines)
+		if !opts.ReplacesProcess() {
+			// Exec-replaced tools never return here, so there is no post-mortem to capture for
+			opts.Stderr = stderrTail
+		}
+		if opts.ReplacesProcess() {
+			recordSession(selectedToolName, config.Session{Start: launchedAt, Exec: true}) // Nothing is left to time the tool once it replaces us
+		}
+		start := time.Now()
+		err = selectedTool.ExecuteWithOptions(opts)
+		elapsed := time.Since(start)
+		if !opts.ReplacesProcess() {
+			recordSession(selectedToolName, config.FinishedSession(start, elapsed, err))
+		}
+		if err == nil {
+			return
+		}
+
+		// A tool that fails right after starting reopens the menu with a post-mortem
+		if !headless && isInteractive() && tool.IsQuickFailure(err, elapsed) {
+			postMortem = &tui.PostMortem{
+				Tool:       selectedTool.Name,
+				ExitCode:   tool.ExitCode(err),
+				Elapsed:    elapsed,
+				StderrTail: stderrTail.Lines(),
+			}
+			continue
+		}
+
+		// Scripts see the tool's own exit code
+		if code := tool.ExitCode(err); code > 0 {
+			os.Exit(code)
+		}
+		fmt.Fprintf(os.Stderr, "Error executing tool: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// recordSession adds a finished session of the tool to the usage stats. Failing to save
+// only costs the stats, so it is only warned about.
+func recordSession(toolName string, s config.Session) {
+	if err := config.RecordSession(toolName, s); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save usage data: %v\n", err)
+	}
+}
+
+// warnQuarantined warns about the data files that were corrupted and moved aside, once
+// the TUI no longer owns the terminal.
+func warnQuarantined() {
+	for _, notice := range cache.Quarantined() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", notice)
+	}
+}
+
+// buildRegistry loads the tools with settings applied, seeded with their last known state
+// and usage so the list renders instantly and in order.
+func buildRegistry(settings config.Settings) *tool.Registry {
+	registry := loadRegistry(settings)
+	config.ApplyTags(registry, settings.Tags)
+	config.ApplyTips(registry, settings.Tips)
+	config.ApplyEnv(registry, settings.Env)
+	config.ApplyDirs(registry, settings.Dirs)
 
 	// Load tool usage history
 	usageData := config.LoadToolUsage()
 
+	// Seed tools with their last known state so the list renders instantly
+	if snap, ok := config.LoadSnapshot(); ok {
+		config.ApplySnapshot(registry, snap)
+	}
+
 	// Apply usage history to tools
 	for _, t := range registry.List() {
 		if lastUsed, ok := usageData[t.Name]; ok {
 			t.LastUsed = lastUsed
 		}
 	}
+	if settings.Ranking.Order == config.RankFrecency {
+		config.ApplyFrecency(registry, config.LoadLaunches(), settings.Ranking.HalfLife(), time.Now())
+	}
+	return registry
+}
 
-	// Fetch balances for tools that support it
-	fetchToolBalances(registry)
-
-	// Run the TUI and get user selection
-	selectedToolName, err := tui.Run(registry)
+// loadRegistry loads the built-in tools, adding or replacing them with verified catalog
+// entries and then with the custom tools of the settings. Hidden tools are left out and
+// overridden ones relabelled.
+func loadRegistry(settings config.Settings) *tool.Registry {
+	provider.SetToolEnv(settings.Env) // Every command that fetches balances loads the tools first
+	registry := config.LoadDefaultTools()
+	defer func() {
+		config.ApplyTools(registry, settings.Tools)
+		config.ApplyHidden(registry, settings.Hidden)
+		config.ApplyOverrides(registry, settings.Overrides)
+	}()
+	if settings.Catalog.URL == "" {
+		return registry
+	}
+	tools, err := catalog.Load(context.Background(), settings.Catalog.Source())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Warning: ignoring tool catalog: %v\n", err)
 	}
-
-	// If user quit without selecting, exit gracefully
-	if selectedToolName == "" {
-		os.Exit(0)
+	for _, t := range tools {
+		registry.Put(t)
 	}
+	return registry
+}
 
-	// Get the selected tool
-	selectedTool := registry.Get(selectedToolName)
-	if selectedTool == nil {
-		fmt.Fprintf(os.Stderr, "Error: tool not found: %s\n", selectedToolName)
-		os.Exit(1)
+// selectTool determines which tool to launch: the one named via flags, or the user's
+// choice in the TUI drawn on uiOut with theme. Exits the process when running non-interactively
+// without a tool. Arguments and the launch note edited in the TUI are stored in *args
+// and *note, and the working directory chosen in it in *dir. With selectOnly the TUI never
+// launches the tool itself.
+func selectTool(registry *tool.Registry, uiOut *os.File, theme tui.Theme, selectOnly bool, launchName string, startTour bool, postMortem *tui.PostMortem, args *[]string, note *string, dir *string) string {
+	if launchName != "" {
+		// Tool chosen via flags, no interaction needed
+		return launchName
 	}
 
-	// Safety check: verify tool is installed before execution
-	// The TUI handles installation prompts, but we verify here as a safety measure
-	if !selectedTool.IsInstalled() {
-		fmt.Fprintf(os.Stderr, "\n❌ Tool not installed: %s\n", selectedTool.Command)
-		fmt.Fprintf(os.Stderr, "Note: This should not happen if you used the TUI installation feature.\n")
-		fmt.Fprintf(os.Stderr, "Please restart the application and try installing again.\n\n")
-		os.Exit(1)
+	if !isTerminal(os.Stdin) || !isTerminal(uiOut) {
+		// Called from another program: never prompt, just describe what's available
+		printToolList(uiOut, registry)
+		fmt.Fprintln(os.Stderr, "Not running in a terminal; pass a tool name (or --launch <tool>) to start one of the tools above.")
+		os.Exit(2)
 	}
 
-	// Update usage data with current time
-	usageData[selectedToolName] = time.Now()
-	if err := config.SaveToolUsage(usageData); err != nil {
-		// Non-fatal error, just log it
-		fmt.Fprintf(os.Stderr, "Warning: failed to save usage data: %v\n", err)
+	// Run the TUI and get user selection
+	opts := []tui.Option{tui.WithArgs(args), tui.WithNote(note), tui.WithDir(dir), tui.WithOutput(uiOut), tui.WithTheme(theme), tui.WithReload(buildRegistry)}
+	if selectOnly {
+		opts = append(opts, tui.WithSelectOnly())
+	}
+	var selected string
+	var err error
+	switch {
+	case postMortem != nil:
+		selected, err = tui.Run(registry, append(opts, tui.WithPostMortem(*postMortem))...)
+	case startTour:
+		selected, err = tui.Run(registry, append(opts, tui.WithTour())...)
+		if markErr := config.MarkTourCompleted(); markErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save tour state: %v\n", markErr)
+		}
+	default:
+		selected, err = tui.Run(registry, opts...)
 	}
-
-	// Execute the tool (replaces current process)
-	// This allows the tool to take full control of the terminal
-	err = selectedTool.Execute()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error executing tool: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	return selected
 }
 
-// fetchToolBalances fetches the balance for each tool that supports it.
-func fetchToolBalances(registry *tool.Registry) {
-	ctx := context.Background()
+// parseLaunch parses the arguments of "amazing launch": its own --note and --cwd, stored
+// in *note and *cwd, then the tool's name and arguments. ok is false after a usage error.
+func parseLaunch(args []string, note, cwd *string) (name string, rest []string, ok bool) {
+	fs := flag.NewFlagSet("launch", flag.ContinueOnError)
+	fs.StringVar(note, "note", *note, "note about what the tool is launched for, kept in the history")
+	fs.StringVar(cwd, "cwd", *cwd, "directory the tool starts in")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s launch [--note text] [--cwd dir] <tool> [--] [args...]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", nil, false
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return "", nil, false
+	}
+	return fs.Arg(0), fs.Args()[1:], true
+}
 
+// afterDoubleDash reports whether the positional arguments followed "--",
+// meaning they are all meant for the launched tool.
+func afterDoubleDash() bool {
+	i := len(os.Args) - flag.NArg() - 1
+	return flag.NArg() > 0 && i >= 1 && os.Args[i] == "--"
+}
+
+// isInteractive reports whether both stdin and stdout are attached to a terminal.
+func isInteractive() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// printToolList writes one "name<TAB>status" line per tool, suitable for scripts.
+func printToolList(w io.Writer, registry *tool.Registry) {
 	for _, t := range registry.List() {
-		// Only fetch for tools that are installed
-		if !t.IsInstalled() {
-			continue
-		}
+		fmt.Fprintf(w, "%s\t%s\n", t.Name, toolStatus(t))
+	}
+}
 
-		// Fetch balance based on tool name
-		switch t.Name {
-		case "codex":
-			fetcher := codex.NewBalanceFetcher()
-			t.Balance = fetcher.GetBalance(ctx)
-		// Add more tools here as needed
-		default:
-			// Tools without specific balance fetchers get default balance
-			continue
-		}
+// toolStatus describes whether t is installed: installed, not-installed or unsupported
+// (can't be installed on this OS).
+func toolStatus(t *tool.Tool) string {
+	switch {
+	case t.IsInstalled():
+		return "installed"
+	case !t.SupportsPlatform():
+		return "unsupported"
+	default:
+		return "not-installed"
 	}
 }
@@ -0,0 +1,18 @@
+package analytics
+
+import "time"
+
+// BurnAlert reports whether the burn rate over the last window (e.g., the last hour) would
+// exhaust a limit resetting at resetsAt at least margin before the reset. The window must
+// span at least MinProjectionSpan of samples for a rate to be computed.
+func BurnAlert(samples []Sample, resetsAt, now time.Time, window, margin time.Duration) (Projection, bool) {
+	if resetsAt.IsZero() {
+		// Without a reset time there's no deadline to compare against
+		return Projection{}, false
+	}
+	p, ok := ProjectExhaustion(samples, now.Add(-window), resetsAt)
+	if !ok || p.ExhaustsAt.After(resetsAt.Add(-margin)) {
+		return p, false
+	}
+	return p, true
+}
@@ -0,0 +1,22 @@
+package analytics
+
+import (
+	"math"
+	"time"
+)
+
+// Frecency scores how often and how recently something was used, like zoxide: every use
+// counts 1 and halves in weight every halfLife, so a single launch fades away while daily
+// use keeps a tool on top. A halfLife of 0 or less counts every use fully.
+func Frecency(uses []time.Time, now time.Time, halfLife time.Duration) float64 {
+	score := 0.0
+	for _, at := range uses {
+		age := now.Sub(at)
+		if halfLife <= 0 || age <= 0 {
+			score++
+			continue
+		}
+		score += math.Exp2(-float64(age) / float64(halfLife))
+	}
+	return score
+}
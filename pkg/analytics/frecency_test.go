@@ -0,0 +1,34 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFrecency(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	daily := []time.Time{now.Add(-24 * time.Hour), now.Add(-48 * time.Hour), now.Add(-72 * time.Hour)}
+	once := []time.Time{now.Add(-time.Minute)}
+	tests := []struct {
+		name     string
+		uses     []time.Time
+		halfLife time.Duration
+		want     float64
+	}{
+		{"no uses", nil, week, 0},
+		{"one half-life ago", []time.Time{now.Add(-week)}, week, 0.5},
+		{"two half-lives ago", []time.Time{now.Add(-2 * week)}, week, 0.25},
+		{"in the future", []time.Time{now.Add(time.Hour)}, week, 1},
+		{"no decay", daily, 0, 3},
+	}
+	for _, tt := range tests {
+		if got := Frecency(tt.uses, now, tt.halfLife); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Frecency() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if Frecency(daily, now, week) <= Frecency(once, now, week) {
+		t.Error("daily use should outrank a single recent launch")
+	}
+}
@@ -0,0 +1,68 @@
+// Package analytics derives trends from recorded usage, such as when a limit will run out.
+package analytics
+
+import (
+	"sort"
+	"time"
+)
+
+// MinProjectionSpan is the shortest stretch of samples a burn rate is computed from.
+// Shorter spans are dominated by rounding of the reported percentages.
+const MinProjectionSpan = 30 * time.Minute
+
+// WeeklyWindow is the length of the weekly limit window.
+const WeeklyWindow = 7 * 24 * time.Hour
+
+// Sample is the remaining share of a limit at a point in time.
+type Sample struct {
+	At        time.Time `json:"at"`
+	Remaining int       `json:"remaining"` // Percent of the limit left (0-100)
+}
+
+// Projection is the estimated exhaustion of a limit at the current burn rate.
+type Projection struct {
+	ExhaustsAt  time.Time // When the limit runs out at the current rate
+	RatePerHour float64   // Percentage points used per hour
+	BeforeReset bool      // The limit runs out before it resets
+}
+
+// ProjectExhaustion estimates when a limit runs out, using the burn rate between the first
+// and last samples taken at or after since. resetsAt may be zero if the reset time is unknown.
+// Returns false when there isn't enough data or the limit isn't being used.
+func ProjectExhaustion(samples []Sample, since, resetsAt time.Time) (Projection, bool) {
+	var window []Sample
+	for _, s := range samples {
+		if !s.At.Before(since) {
+			window = append(window, s)
+		}
+	}
+	if len(window) < 2 {
+		return Projection{}, false
+	}
+	sort.Slice(window, func(i, j int) bool { return window[i].At.Before(window[j].At) })
+
+	first, last := window[0], window[len(window)-1]
+	span := last.At.Sub(first.At)
+	used := first.Remaining - last.Remaining
+	if span < MinProjectionSpan || used <= 0 {
+		return Projection{}, false
+	}
+
+	rate := float64(used) / span.Hours()
+	exhaustsAt := last.At.Add(time.Duration(float64(last.Remaining) / rate * float64(time.Hour)))
+	return Projection{
+		ExhaustsAt:  exhaustsAt,
+		RatePerHour: rate,
+		BeforeReset: resetsAt.IsZero() || exhaustsAt.Before(resetsAt),
+	}, true
+}
+
+// ProjectWeekly estimates when a weekly limit resetting at resetsAt runs out, using the
+// samples of the current week. Without a reset time the last week of samples is used.
+func ProjectWeekly(samples []Sample, resetsAt, now time.Time) (Projection, bool) {
+	since := now.Add(-WeeklyWindow)
+	if !resetsAt.IsZero() {
+		since = resetsAt.Add(-WeeklyWindow)
+	}
+	return ProjectExhaustion(samples, since, resetsAt)
+}
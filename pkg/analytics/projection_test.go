@@ -0,0 +1,135 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProjectExhaustion(t *testing.T) {
+	base := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC) // Monday
+	at := func(hours float64) time.Time {
+		return base.Add(time.Duration(hours * float64(time.Hour)))
+	}
+
+	tests := []struct {
+		name        string
+		samples     []Sample
+		since       time.Time
+		resetsAt    time.Time
+		wantOK      bool
+		wantAt      time.Time
+		beforeReset bool
+	}{
+		{
+			name:    "not enough samples",
+			samples: []Sample{{At: at(0), Remaining: 90}},
+			wantOK:  false,
+		},
+		{
+			name:    "span too short",
+			samples: []Sample{{At: at(0), Remaining: 90}, {At: at(0.1), Remaining: 80}},
+			wantOK:  false,
+		},
+		{
+			name:    "no usage",
+			samples: []Sample{{At: at(0), Remaining: 90}, {At: at(5), Remaining: 90}},
+			wantOK:  false,
+		},
+		{
+			// 10 points in 2h = 5/h; 80 left runs out 16h after the last sample
+			name:        "runs out before reset",
+			samples:     []Sample{{At: at(2), Remaining: 80}, {At: at(0), Remaining: 90}},
+			resetsAt:    at(100),
+			wantOK:      true,
+			wantAt:      at(18),
+			beforeReset: true,
+		},
+		{
+			name:        "lasts until reset",
+			samples:     []Sample{{At: at(0), Remaining: 90}, {At: at(2), Remaining: 80}},
+			resetsAt:    at(10),
+			wantOK:      true,
+			wantAt:      at(18),
+			beforeReset: false,
+		},
+		{
+			// Samples before since belong to the previous window and are ignored
+			name:        "ignores samples before since",
+			samples:     []Sample{{At: at(-10), Remaining: 5}, {At: at(0), Remaining: 100}, {At: at(4), Remaining: 80}},
+			since:       at(-1),
+			wantOK:      true,
+			wantAt:      at(20),
+			beforeReset: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since := tt.since
+			if since.IsZero() {
+				since = at(-1)
+			}
+			got, ok := ProjectExhaustion(tt.samples, since, tt.resetsAt)
+			if ok != tt.wantOK {
+				t.Fatalf("ProjectExhaustion() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !got.ExhaustsAt.Equal(tt.wantAt) {
+				t.Errorf("ExhaustsAt = %v, want %v", got.ExhaustsAt, tt.wantAt)
+			}
+			if got.BeforeReset != tt.beforeReset {
+				t.Errorf("BeforeReset = %v, want %v", got.BeforeReset, tt.beforeReset)
+			}
+		})
+	}
+}
+
+func TestProjectWeekly(t *testing.T) {
+	resetsAt := time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{At: resetsAt.Add(-8 * 24 * time.Hour), Remaining: 10}, // Previous week
+		{At: resetsAt.Add(-3 * 24 * time.Hour), Remaining: 60},
+		{At: resetsAt.Add(-2 * 24 * time.Hour), Remaining: 30},
+	}
+	got, ok := ProjectWeekly(samples, resetsAt, resetsAt.Add(-2*24*time.Hour))
+	if !ok {
+		t.Fatal("ProjectWeekly() should project from this week's samples")
+	}
+	if want := resetsAt.Add(-24 * time.Hour); !got.ExhaustsAt.Equal(want) || !got.BeforeReset {
+		t.Errorf("ProjectWeekly() = %+v, want exhaustion at %v before reset", got, want)
+	}
+}
+
+func TestBurnAlert(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	resetsAt := now.Add(48 * time.Hour)
+	// Slow all week, then 10 points in the last hour: 50 left runs out in 5h
+	samples := []Sample{
+		{At: now.Add(-72 * time.Hour), Remaining: 70},
+		{At: now.Add(-time.Hour), Remaining: 60},
+		{At: now, Remaining: 50},
+	}
+
+	tests := []struct {
+		name     string
+		resetsAt time.Time
+		window   time.Duration
+		margin   time.Duration
+		want     bool
+	}{
+		{"short window burns fast", resetsAt, 90 * time.Minute, 0, true},
+		{"weekly pace is fine", resetsAt, 7 * 24 * time.Hour, 0, false},
+		{"within margin", resetsAt, 90 * time.Minute, 12 * time.Hour, true},
+		{"margin not reached", resetsAt, 90 * time.Minute, 45 * time.Hour, false},
+		{"unknown reset", time.Time{}, 90 * time.Minute, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := BurnAlert(samples, tt.resetsAt, now, tt.window, tt.margin); got != tt.want {
+				t.Errorf("BurnAlert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
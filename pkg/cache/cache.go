@@ -0,0 +1,127 @@
+// Package cache keeps fetched results as JSON under ~/.amazing-cli/cache, in one namespace
+// (directory) per provider, so they can be shown right away on the next start.
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+)
+
+// Cache is one namespace of entries, each fresh for TTL after it was stored.
+type Cache struct {
+	dir     string
+	version int
+	ttl     time.Duration
+}
+
+// entry is the on-disk form of a cached value.
+type entry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Version  int             `json:"version,omitempty"` // Schema version of Value; 0 before entries had one
+	Value    json.RawMessage `json:"value"`
+}
+
+// New returns the cache for a namespace, e.g. the provider's tool name, holding values
+// in schema version (e.g., tool.BalanceSchema). Entries stored with another version are
+// ignored as if nothing were stored, since their fields may mean something else.
+func New(namespace string, version int, ttl time.Duration) *Cache {
+	return &Cache{dir: filepath.Join(getCacheDir(), namespace), version: version, ttl: ttl}
+}
+
+// getCacheDir returns the directory holding all namespaces
+func getCacheDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-cache"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "cache")
+}
+
+// path returns the file of the entry stored under key.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Load decodes the value stored under key into v and returns when it was stored.
+// Stale values are loaded too, see Fresh. Returns false if nothing usable is stored:
+// an entry of another schema version is skipped, and one that can't be decoded is
+// quarantined.
+func (c *Cache) Load(key string, v any) (time.Time, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		Quarantine(c.path(key), err)
+		return time.Time{}, false
+	}
+	if e.Version != c.version {
+		return time.Time{}, false // Overwritten by the next Store
+	}
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		Quarantine(c.path(key), err)
+		return time.Time{}, false
+	}
+	return e.StoredAt, true
+}
+
+// Fresh reports whether a value stored at storedAt is still within the TTL.
+func (c *Cache) Fresh(storedAt, now time.Time) bool {
+	return now.Sub(storedAt) < c.ttl
+}
+
+// Store saves v under key. The file is replaced atomically, since other processes
+// (the launcher, the daemon) may read it at the same time.
+func (c *Cache) Store(key string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entry{StoredAt: time.Now(), Version: c.version, Value: value}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after the rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Fetch fills v with the value stored under key while it is fresh. Otherwise it calls
+// fetch to fill v and stores the result; when fetch fails, v is filled with the stale value
+// instead if there is one. Returns fetch's error only when there is nothing to show.
+func (c *Cache) Fetch(key string, v any, fetch func() error) error {
+	storedAt, ok := c.Load(key, v)
+	if ok && c.Fresh(storedAt, time.Now()) {
+		return nil
+	}
+	err := fetch()
+	if err == nil {
+		if err := c.Store(key, v); err != nil {
+			log.Warn("cache write failed", "path", c.path(key), "err", err) // Non-fatal, the value is still fresh for this run
+		}
+		return nil
+	}
+	if _, ok := c.Load(key, v); ok {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type value struct {
+	N int `json:"n"`
+}
+
+func TestCache_StoreLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := New("codex", 1, time.Minute)
+
+	var v value
+	if _, ok := c.Load("balance", &v); ok {
+		t.Fatal("Load() on an empty cache should report nothing stored")
+	}
+	if err := c.Store("balance", value{N: 42}); err != nil {
+		t.Fatalf("Store() error: %v", err)
+	}
+	storedAt, ok := c.Load("balance", &v)
+	if !ok || v.N != 42 {
+		t.Fatalf("Load() = %+v, %v; want 42", v, ok)
+	}
+	if !c.Fresh(storedAt, time.Now()) || c.Fresh(storedAt, time.Now().Add(2*time.Minute)) {
+		t.Error("Fresh() should hold for the TTL only")
+	}
+
+	// Namespaces don't share entries
+	if _, ok := New("gemini", 1, time.Minute).Load("balance", &v); ok {
+		t.Error("another namespace should not see the entry")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(c.path("balance")))
+	if len(entries) != 1 {
+		t.Errorf("cache dir has %d files, want no temp files left over", len(entries))
+	}
+}
+
+func TestCache_Fetch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	errFetch := errors.New("offline")
+	calls := 0
+	fetch := func(v *value, n int, err error) func() error {
+		return func() error {
+			calls++
+			if err != nil {
+				return err
+			}
+			v.N = n
+			return nil
+		}
+	}
+
+	// Nothing stored and the fetch fails
+	c := New("codex", 1, time.Hour)
+	var v value
+	if err := c.Fetch("balance", &v, fetch(&v, 0, errFetch)); err != errFetch {
+		t.Fatalf("Fetch() error = %v, want %v", err, errFetch)
+	}
+
+	// Fetched and stored, then served fresh without fetching
+	if err := c.Fetch("balance", &v, fetch(&v, 1, nil)); err != nil || v.N != 1 {
+		t.Fatalf("Fetch() = %+v, %v", v, err)
+	}
+	calls = 0
+	if err := c.Fetch("balance", &v, fetch(&v, 2, nil)); err != nil || v.N != 1 || calls != 0 {
+		t.Errorf("Fetch() while fresh = %+v, %v with %d fetches, want the stored value", v, err, calls)
+	}
+
+	// Stale: fetched again, and the stale value is kept when that fails
+	stale := New("codex", 1, 0)
+	if err := stale.Fetch("balance", &v, fetch(&v, 3, nil)); err != nil || v.N != 3 {
+		t.Errorf("Fetch() while stale = %+v, %v, want a new value", v, err)
+	}
+	v = value{}
+	if err := stale.Fetch("balance", &v, fetch(&v, 0, errFetch)); err != nil || v.N != 3 {
+		t.Errorf("Fetch() failing = %+v, %v, want the stale value", v, err)
+	}
+}
+
+func TestCache_LoadCorrupted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := New("codex", 1, time.Minute)
+	Quarantined() // Forget notices from other tests
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"truncated", `{"stored_at": "2026-02-10T16:22:00Z", "value": {"n": 4`},
+		{"wrong value", `{"stored_at": "2026-02-10T16:22:00Z", "version": 1, "value": {"n": "four"}}`},
+	}
+	for _, tt := range tests {
+		path := c.path("balance")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var v value
+		if _, ok := c.Load("balance", &v); ok {
+			t.Errorf("%s: Load() should report nothing usable stored", tt.name)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s: the corrupted entry should be moved aside", tt.name)
+		}
+		if data, err := os.ReadFile(path + ".bak"); err != nil || string(data) != tt.data {
+			t.Errorf("%s: %s.bak = %q, %v; want the corrupted entry", tt.name, path, data, err)
+		}
+		if notices := Quarantined(); len(notices) != 1 {
+			t.Errorf("%s: Quarantined() = %q, want one notice", tt.name, notices)
+		}
+	}
+
+	// Storing starts over
+	if err := c.Store("balance", value{N: 5}); err != nil {
+		t.Fatalf("Store() error: %v", err)
+	}
+	var v value
+	if _, ok := c.Load("balance", &v); !ok || v.N != 5 {
+		t.Errorf("Load() after Store() = %+v, %v; want 5", v, ok)
+	}
+}
+
+func TestCache_Version(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := New("codex", 1, time.Minute).Store("balance", value{N: 42}); err != nil {
+		t.Fatalf("Store() error: %v", err)
+	}
+
+	// A binary expecting another schema ignores the entry, and fetches again
+	next := New("codex", 2, time.Minute)
+	var v value
+	if _, ok := next.Load("balance", &v); ok {
+		t.Errorf("Load() of another version = %+v, want nothing stored", v)
+	}
+	if err := next.Fetch("balance", &v, func() error { v.N = 43; return nil }); err != nil || v.N != 43 {
+		t.Fatalf("Fetch() = %+v, %v; want a new value", v, err)
+	}
+	if _, ok := New("codex", 2, time.Minute).Load("balance", &v); !ok || v.N != 43 {
+		t.Errorf("Load() after refetching = %+v, %v; want 43", v, ok)
+	}
+	if notices := Quarantined(); len(notices) != 0 {
+		t.Errorf("Quarantined() = %q, want entries of another version left alone", notices)
+	}
+}
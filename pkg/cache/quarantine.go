@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+)
+
+var (
+	quarantineMu sync.Mutex
+	quarantined  []string // Notices of the files Quarantine moved aside this run
+)
+
+// Quarantine moves a file that couldn't be decoded (a truncated write, a bad manual edit)
+// to path+".bak", replacing an older one, so the next write starts over from defaults
+// while the bad file can still be looked at. err is why it couldn't be decoded.
+// The move is noted for Quarantined.
+func Quarantine(path string, err error) {
+	quarantineMu.Lock()
+	defer quarantineMu.Unlock()
+
+	notice := fmt.Sprintf("%s is corrupted (%v)", path, err)
+	if renameErr := os.Rename(path, path+".bak"); renameErr != nil {
+		if os.IsNotExist(renameErr) {
+			return // Another process moved it aside first
+		}
+		notice += fmt.Sprintf(" and couldn't be moved aside: %v", renameErr)
+	} else {
+		notice += fmt.Sprintf("; moved it to %s and started over", filepath.Base(path)+".bak")
+	}
+	log.Warn("corrupted file", "path", path, "err", err)
+	quarantined = append(quarantined, notice)
+}
+
+// Quarantined returns the notices of the files moved aside since it was last called,
+// for the caller to log.
+func Quarantined() []string {
+	quarantineMu.Lock()
+	defer quarantineMu.Unlock()
+	notices := quarantined
+	quarantined = nil
+	return notices
+}
@@ -0,0 +1,296 @@
+// Package catalog loads extra tool definitions from a remote catalog. A catalog is only
+// trusted when its minisign signature or pinned SHA-256 checksum verifies, since its
+// install commands are run on the user's machine.
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// fetchTimeout bounds how long startup waits for the catalog before using the cached copy.
+const fetchTimeout = 5 * time.Second
+
+// Source describes where the catalog comes from and how it is verified.
+type Source struct {
+	URL           string // http(s) URL, file:// URL or local path of the catalog JSON
+	PublicKey     string // minisign public key; the signature is read from URL + ".minisig"
+	SHA256        string // Pinned checksum of the catalog, instead of or in addition to a signature
+	AllowUnsigned bool   // Trust a catalog without any verification (explicit opt-out)
+}
+
+// Definition is a tool entry in the catalog.
+type Definition struct {
+	Name           string            `json:"name"`
+	DisplayName    string            `json:"display_name"`
+	Icon           string            `json:"icon"`
+	Command        string            `json:"command"`
+	Description    string            `json:"description"`
+	Args           []string          `json:"args"`
+	Env            map[string]string `json:"env"`
+	LoginArgs      []string          `json:"login_args"`
+	PromptArgs     []string          `json:"prompt_args"`
+	SessionsGlob   string            `json:"sessions_glob"`
+	Tags           []string          `json:"tags"`
+	Tips           []string          `json:"tips"`
+	Platforms      []string          `json:"platforms"`
+	InstallCmds    map[string]string `json:"install_cmds"`
+	Installers     []Installer       `json:"installers"`
+	InstallURL     string            `json:"install_url"`
+	MinNodeVersion string            `json:"min_node_version"`
+	Dependencies   []Dependency      `json:"dependencies"`
+	NixPackage     string            `json:"nix_package"`
+	VersionCmd     string            `json:"version_cmd"`
+	UpgradeCmds    map[string]string `json:"upgrade_cmds"`
+	UpdateSource   string            `json:"update_source"`
+	ConfigPath     string            `json:"config_path"`
+	SearchDirs     []string          `json:"search_dirs"`
+}
+
+// Installer is a package manager install of a Definition, e.g.
+// {"manager": "npm", "package": "@acme/agent"}.
+type Installer struct {
+	Manager   string   `json:"manager"`
+	Package   string   `json:"package"`
+	Platforms []string `json:"platforms"`
+}
+
+// Dependency is a runtime a Definition needs, e.g. {"name": "node", "min_version": "20"}.
+type Dependency struct {
+	Name            string            `json:"name"`
+	MinVersion      string            `json:"min_version"`
+	VersionArgs     []string          `json:"version_args"`
+	AutoInstallCmds map[string]string `json:"auto_install_cmds"`
+	InstallURL      string            `json:"install_url"`
+}
+
+// Catalog is the JSON document served by a catalog source.
+type Catalog struct {
+	Tools []Definition `json:"tools"`
+}
+
+// Tool converts the definition into a tool for the registry.
+func (d Definition) Tool() *tool.Tool {
+	displayName := d.DisplayName
+	if displayName == "" {
+		displayName = d.Name
+	}
+	return &tool.Tool{
+		Name:           d.Name,
+		DisplayName:    displayName,
+		Icon:           d.Icon,
+		Command:        d.Command,
+		Description:    d.Description,
+		Args:           d.Args,
+		Env:            d.Env,
+		LoginArgs:      d.LoginArgs,
+		PromptArgs:     d.PromptArgs,
+		SessionsGlob:   d.SessionsGlob,
+		Tags:           d.Tags,
+		Tips:           d.Tips,
+		Platforms:      d.Platforms,
+		InstallCmds:    d.InstallCmds,
+		Installers:     installers(d.Installers),
+		InstallURL:     d.InstallURL,
+		MinNodeVersion: d.MinNodeVersion,
+		Dependencies:   dependencies(d.Dependencies),
+		NixPackage:     d.NixPackage,
+		VersionCmd:     d.VersionCmd,
+		UpgradeCmds:    d.UpgradeCmds,
+		UpdateSource:   d.UpdateSource,
+		ConfigPath:     d.ConfigPath,
+		SearchDirs:     d.SearchDirs,
+	}
+}
+
+// installers converts the catalog installers for the tool, or returns nil.
+func installers(defs []Installer) []tool.Installer {
+	var out []tool.Installer
+	for _, d := range defs {
+		out = append(out, tool.Installer{Manager: d.Manager, Package: d.Package, Platforms: d.Platforms})
+	}
+	return out
+}
+
+// dependencies converts the catalog dependencies for the tool, or returns nil.
+func dependencies(defs []Dependency) []tool.Dependency {
+	var out []tool.Dependency
+	for _, d := range defs {
+		out = append(out, tool.Dependency{
+			Name:            d.Name,
+			MinVersion:      d.MinVersion,
+			VersionArgs:     d.VersionArgs,
+			AutoInstallCmds: d.AutoInstallCmds,
+			InstallURL:      d.InstallURL,
+		})
+	}
+	return out
+}
+
+// Verify checks the catalog data against the pinned checksum and the minisign signature.
+// Unverifiable catalogs are refused unless AllowUnsigned is set.
+func (s Source) Verify(data, minisig []byte) error {
+	if s.SHA256 == "" && s.PublicKey == "" {
+		if s.AllowUnsigned {
+			return nil
+		}
+		return errors.New("refusing unsigned catalog: set public_key or sha256, or allow_unsigned to trust it anyway")
+	}
+
+	if s.SHA256 != "" {
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, s.SHA256) {
+			return fmt.Errorf("catalog checksum mismatch: got %s, want %s", got, s.SHA256)
+		}
+	}
+	if s.PublicKey != "" {
+		pk, err := ParsePublicKey(s.PublicKey)
+		if err != nil {
+			return err
+		}
+		if len(minisig) == 0 {
+			return errors.New("catalog is not signed")
+		}
+		if err := pk.Verify(data, minisig); err != nil {
+			return fmt.Errorf("catalog signature: %w", err)
+		}
+	}
+	return nil
+}
+
+// Load fetches, verifies and parses the catalog. When the source can't be reached,
+// the last verified copy is used instead.
+func Load(ctx context.Context, s Source) ([]*tool.Tool, error) {
+	data, sig, err := s.fetch(ctx)
+	if err != nil {
+		cached, ok := loadCache(s.URL)
+		if !ok {
+			return nil, err
+		}
+		data, sig = cached.Data, cached.Signature
+	}
+
+	// Cached copies are verified again, the configured key may have changed
+	if err := s.Verify(data, sig); err != nil {
+		return nil, err
+	}
+	tools, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	_ = saveCache(cacheEntry{URL: s.URL, Data: data, Signature: sig}) // Non-fatal
+	return tools, nil
+}
+
+// Parse parses catalog JSON into tools. Entries without a name or command are rejected.
+func Parse(data []byte) ([]*tool.Tool, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid catalog: %w", err)
+	}
+	tools := make([]*tool.Tool, 0, len(c.Tools))
+	for i, d := range c.Tools {
+		if d.Name == "" || d.Command == "" {
+			return nil, fmt.Errorf("invalid catalog: tool %d needs a name and a command", i+1)
+		}
+		for _, inst := range d.Installers {
+			if inst.Manager == "" || inst.Package == "" {
+				return nil, fmt.Errorf("invalid catalog: installers of %s need a manager and a package", d.Name)
+			}
+		}
+		for _, dep := range d.Dependencies {
+			if dep.Name == "" {
+				return nil, fmt.Errorf("invalid catalog: dependencies of %s need a name", d.Name)
+			}
+		}
+		tools = append(tools, d.Tool())
+	}
+	return tools, nil
+}
+
+// fetch reads the catalog and, when a public key is configured, its signature.
+func (s Source) fetch(ctx context.Context) (data, sig []byte, err error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	if data, err = read(ctx, s.URL); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch catalog: %w", err)
+	}
+	if s.PublicKey != "" {
+		if sig, err = read(ctx, s.URL+".minisig"); err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch catalog signature: %w", err)
+		}
+	}
+	return data, sig, nil
+}
+
+func read(ctx context.Context, url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return os.ReadFile(strings.TrimPrefix(url, "file://"))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// cacheEntry is the last catalog that passed verification.
+type cacheEntry struct {
+	URL       string `json:"url"`
+	Data      []byte `json:"data"`
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// getCacheFilePath returns the path to the catalog cache file
+func getCacheFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-catalog.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "cache", "catalog.json")
+}
+
+func loadCache(url string) (cacheEntry, bool) {
+	data, err := os.ReadFile(getCacheFilePath())
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func saveCache(entry cacheEntry) error {
+	filePath := getCacheFilePath()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
@@ -0,0 +1,202 @@
+package catalog
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBlake2b512(t *testing.T) {
+	long := make([]byte, 0, 768)
+	for i := 0; i < 3; i++ {
+		for b := 0; b < 256; b++ {
+			long = append(long, byte(b))
+		}
+	}
+	tests := []struct {
+		name string
+		in   []byte
+		want string
+	}{
+		{name: "empty", in: nil, want: "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+		{name: "abc", in: []byte("abc"), want: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		{name: "one full block", in: make([]byte, 128), want: "865939e120e6805438478841afb739ae4250cf372653078a065cdcfffca4caf798e6d462b65d658fc165782640eded70963449ae1500fb0f24981d7727e22c41"},
+		{name: "several blocks", in: long, want: "323e97a7a859ee63c9013debb0ca995811e73117a2f574723416e596ebc184e37a59b66d2f597df4a7c1b0d1d41a1a7f28774f46a6864d56c57b9d6c5f7302fb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := blake2b512(tt.in)
+			if got := hex.EncodeToString(sum[:]); got != tt.want {
+				t.Errorf("blake2b512() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// testKey is a minisign key pair for signing test catalogs.
+type testKey struct {
+	id   [8]byte
+	priv ed25519.PrivateKey
+}
+
+func newTestKey(seed byte) testKey {
+	k := testKey{priv: ed25519.NewKeyFromSeed(append(make([]byte, ed25519.SeedSize-1), seed))}
+	copy(k.id[:], []byte{seed, 1, 2, 3, 4, 5, 6, 7})
+	return k
+}
+
+func (k testKey) publicKey() string {
+	raw := append([]byte("Ed"), k.id[:]...)
+	raw = append(raw, k.priv.Public().(ed25519.PublicKey)...)
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(raw) + "\n"
+}
+
+// sign returns a .minisig for data, pre-hashed like current minisign unless legacy is set.
+func (k testKey) sign(data []byte, legacy bool) []byte {
+	alg, message := "ED", data
+	if legacy {
+		alg = "Ed"
+	} else {
+		sum := blake2b512(data)
+		message = sum[:]
+	}
+	sig := ed25519.Sign(k.priv, message)
+	comment := "timestamp:1700000000\tfile:catalog.json"
+	global := ed25519.Sign(k.priv, append(append([]byte{}, sig...), comment...))
+
+	raw := append([]byte(alg), k.id[:]...)
+	raw = append(raw, sig...)
+	return []byte("untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(raw) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n")
+}
+
+func TestSource_Verify(t *testing.T) {
+	data := []byte(`{"tools": []}`)
+	key, other := newTestKey(1), newTestKey(2)
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	tampered := key.sign(data, false)
+	tampered[len(tampered)-10] ^= 1 // Corrupt the trusted comment signature
+
+	tests := []struct {
+		name    string
+		src     Source
+		sig     []byte
+		wantErr string
+	}{
+		{name: "unsigned refused", src: Source{}, wantErr: "refusing unsigned catalog"},
+		{name: "unsigned allowed", src: Source{AllowUnsigned: true}},
+		{name: "checksum", src: Source{SHA256: strings.ToUpper(checksum)}},
+		{name: "checksum mismatch", src: Source{SHA256: strings.Repeat("0", 64)}, wantErr: "checksum mismatch"},
+		{name: "signature", src: Source{PublicKey: key.publicKey()}, sig: key.sign(data, false)},
+		{name: "legacy signature", src: Source{PublicKey: key.publicKey()}, sig: key.sign(data, true)},
+		{name: "signature and checksum", src: Source{PublicKey: key.publicKey(), SHA256: checksum}, sig: key.sign(data, false)},
+		{name: "missing signature", src: Source{PublicKey: key.publicKey()}, wantErr: "not signed"},
+		{name: "other key", src: Source{PublicKey: key.publicKey()}, sig: other.sign(data, false), wantErr: "not the configured key"},
+		{name: "tampered comment", src: Source{PublicKey: key.publicKey()}, sig: tampered, wantErr: "verification failed"},
+		{name: "tampered data", src: Source{PublicKey: key.publicKey()}, sig: key.sign([]byte(`{"tools": [{}]}`), false), wantErr: "verification failed"},
+		{name: "allow unsigned does not skip a configured key", src: Source{PublicKey: key.publicKey(), AllowUnsigned: true}, wantErr: "not signed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.src.Verify(data, tt.sig)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Verify() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	key := newTestKey(1)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "catalog.json")
+	data := []byte(`{"tools": [{"name": "aider", "command": "aider", "install_cmds": {"linux": "uv tool install aider-chat"}}]}`)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".minisig", key.sign(data, false), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src := Source{URL: path, PublicKey: key.publicKey()}
+
+	tools, err := Load(context.Background(), src)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "aider" || tools[0].DisplayName != "aider" || tools[0].InstallCmds["linux"] == "" {
+		t.Fatalf("Load() = %+v, want the aider definition", tools)
+	}
+
+	// Unreachable source falls back to the verified copy
+	os.Remove(path)
+	if tools, err := Load(context.Background(), src); err != nil || len(tools) != 1 {
+		t.Errorf("Load() from cache = %v, %v, want the cached tool", tools, err)
+	}
+
+	// The cache is verified again with the current key
+	src.PublicKey = newTestKey(2).publicKey()
+	if _, err := Load(context.Background(), src); err == nil {
+		t.Error("Load() should refuse a cached catalog signed with another key")
+	}
+}
+
+func TestParse_RejectsIncompleteTools(t *testing.T) {
+	if _, err := Parse([]byte(`{"tools": [{"name": "x"}]}`)); err == nil {
+		t.Error("Parse() should reject a tool without a command")
+	}
+}
+
+func TestParse_Installers(t *testing.T) {
+	tools, err := Parse([]byte(`{"tools": [{"name": "x", "command": "x", "installers": [
+		{"manager": "brew", "package": "x", "platforms": ["darwin"]},
+		{"manager": "npm", "package": "@acme/x"}
+	]}]}`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	installers := tools[0].Installers
+	if len(installers) != 2 || installers[0].Command() != "brew install x" || installers[0].Platforms[0] != "darwin" || installers[1].Command() != "npm install -g @acme/x" {
+		t.Errorf("Installers = %+v, want brew on darwin, then npm", installers)
+	}
+
+	if _, err := Parse([]byte(`{"tools": [{"name": "x", "command": "x", "installers": [{"manager": "npm"}]}]}`)); err == nil {
+		t.Error("Parse() should reject an installer without a package")
+	}
+}
+
+func TestParse_Dependencies(t *testing.T) {
+	tools, err := Parse([]byte(`{"tools": [{"name": "x", "command": "x", "dependencies": [
+		{"name": "node", "min_version": "20", "install_url": "https://nodejs.org"},
+		{"name": "git"}
+	]}]}`))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	deps := tools[0].Dependencies
+	if len(deps) != 2 || deps[0].String() != "node >= 20" || deps[0].InstallURL != "https://nodejs.org" || deps[1].String() != "git" {
+		t.Errorf("Dependencies = %+v, want node >= 20, then git", deps)
+	}
+
+	if _, err := Parse([]byte(`{"tools": [{"name": "x", "command": "x", "dependencies": [{"min_version": "20"}]}]}`)); err == nil {
+		t.Error("Parse() should reject a dependency without a name")
+	}
+}
@@ -0,0 +1,80 @@
+package catalog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PublicKey is a minisign public key.
+type PublicKey struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+// ParsePublicKey parses a minisign public key, either the base64 line ("RW...")
+// or the whole .pub file including its untrusted comment.
+func ParsePublicKey(s string) (PublicKey, error) {
+	line := ""
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		if l = strings.TrimSpace(l); l != "" && !strings.HasPrefix(l, "untrusted comment:") {
+			line = l
+			break
+		}
+	}
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != "Ed" {
+		return PublicKey{}, errors.New("invalid minisign public key")
+	}
+	var pk PublicKey
+	copy(pk.keyID[:], raw[2:10])
+	pk.key = ed25519.PublicKey(raw[10:])
+	return pk, nil
+}
+
+// Verify checks a minisign signature (the contents of a .minisig file) of data,
+// including the signature of its trusted comment.
+func (pk PublicKey) Verify(data, minisig []byte) error {
+	lines := strings.Split(strings.ReplaceAll(string(minisig), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], "untrusted comment:") {
+		return errors.New("malformed minisign signature")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return errors.New("malformed minisign signature")
+	}
+	if !bytes.Equal(sig[2:10], pk.keyID[:]) {
+		return fmt.Errorf("signed with key %X, not the configured key %X", sig[2:10], pk.keyID[:])
+	}
+
+	message := data
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		// Pre-hashed signature, the default of current minisign versions
+		sum := blake2b512(data)
+		message = sum[:]
+	default:
+		return fmt.Errorf("unsupported minisign signature algorithm %q", sig[:2])
+	}
+	if !ed25519.Verify(pk.key, message, sig[10:]) {
+		return errors.New("signature verification failed")
+	}
+
+	comment, ok := strings.CutPrefix(lines[2], "trusted comment: ")
+	if !ok {
+		return errors.New("malformed minisign signature: missing trusted comment")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return errors.New("malformed minisign signature")
+	}
+	if !ed25519.Verify(pk.key, append(append([]byte{}, sig[10:]...), comment...), globalSig) {
+		return errors.New("trusted comment signature verification failed")
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// BalanceCache holds the balances polled by "amazing daemon".
+type BalanceCache struct {
+	UpdatedAt time.Time                `json:"updated_at"`
+	Schema    int                      `json:"schema"`   // tool.BalanceSchema of the daemon that wrote the cache
+	Interval  time.Duration            `json:"interval"` // Polling interval of the daemon that wrote the cache
+	Balances  map[string]*tool.Balance `json:"balances"`
+}
+
+// Fresh reports whether the cache was updated recently enough to be used instead of
+// fetching, i.e. a daemon is still polling. One missed poll is tolerated.
+func (c BalanceCache) Fresh(now time.Time) bool {
+	return c.Interval > 0 && now.Sub(c.UpdatedAt) < 2*c.Interval
+}
+
+// getBalanceCacheFilePath returns the path to the daemon's balance cache file
+func getBalanceCacheFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-balances.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "cache", "balances.json")
+}
+
+// LoadBalanceCache loads the balances last written by the daemon. Balances written by a
+// daemon of another version whose balances mean something else are not loaded.
+func LoadBalanceCache() (BalanceCache, bool) {
+	filePath := getBalanceCacheFilePath()
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		// No daemon has run yet
+		return BalanceCache{}, false
+	}
+
+	var balances BalanceCache
+	if err := json.Unmarshal(data, &balances); err != nil {
+		cache.Quarantine(filePath, err)
+		return BalanceCache{}, false
+	}
+	if balances.Schema != tool.BalanceSchema {
+		return BalanceCache{}, false
+	}
+	return balances, true
+}
+
+// SaveBalanceCache saves polled balances to disk. The file is replaced atomically, through
+// a temp file of its own, since the launcher may read it while the daemon, or another
+// daemon, writes.
+func SaveBalanceCache(cache BalanceCache) error {
+	cache.Schema = tool.BalanceSchema
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(getBalanceCacheFilePath(), data)
+}
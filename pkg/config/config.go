@@ -2,9 +2,9 @@
 package config
 
 import (
-	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
@@ -15,7 +15,6 @@ import (
 type Balance struct {
 	Percentage int    // 0-100, current placeholder shows 100%
 	Display    string // Human-readable display (e.g., "100%", "1000 tokens")
-	Color      string // Color hint for display (e.g., "green", "yellow", "red")
 }
 
 // ToToolBalance converts config.Balance to tool.Balance.
@@ -24,7 +23,6 @@ func (b Balance) ToToolBalance() tool.Balance {
 	return tool.Balance{
 		Percentage: b.Percentage,
 		Display:    b.Display,
-		Color:      b.Color,
 	}
 }
 
@@ -34,7 +32,6 @@ func GetDefaultBalance() Balance {
 	return Balance{
 		Percentage: 100,
 		Display:    "100%",
-		Color:      "green",
 	}
 }
 
@@ -51,33 +48,50 @@ func LoadDefaultTools() *tool.Registry {
 	// Register supported AI CLI tools
 	// Note: Installation commands should be verified and updated based on actual installation methods
 	registry.Register(&tool.Tool{
-		Name:        "claude",
-		DisplayName: "claude code",
-		Command:     "claude",
-		Description: "Claude Code by Anthropic",
-		Args:        []string{},
-		InstallCmds: map[string]string{
-			"darwin":      "curl -fsSL https://claude.ai/install.sh | bash",
-			"linux":       "curl -fsSL https://claude.ai/install.sh | bash",
-			"windows_ps":  "irm https://claude.ai/install.ps1 | iex",
-			"windows_cmd": "curl -fsSL https://claude.ai/install.cmd -o install.cmd && install.cmd && del install.cmd",
+		Name:         "claude",
+		DisplayName:  "claude code",
+		Command:      "claude",
+		ConfigPath:   "~/.claude/settings.json",
+		Description:  "Claude Code by Anthropic",
+		Args:         []string{},
+		PromptArgs:   []string{"{prompt}"},
+		SessionsGlob: "~/.claude/projects/*/*.jsonl",
+		Tags:         []string{"anthropic"},
+		Tips: []string{
+			"/compact summarizes the conversation when the context fills up",
+			"/init writes a CLAUDE.md that describes the project",
+			"Shift+Tab cycles through the permission modes, including plan mode",
 		},
-		InstallURL: "https://docs.anthropic.com/en/docs/claude-code/getting-started",
+		InstallCmds:    claudeInstallCmds(),
+		NixPackage:     "claude-code",
+		InstallURL:     "https://docs.anthropic.com/en/docs/claude-code/getting-started",
+		UpgradeCmds:    claudeUpgradeCmds(),
+		UpdateSource:   "npm:@anthropic-ai/claude-code",
+		MinNodeVersion: "18",
 	})
 
 	registry.Register(&tool.Tool{
 		Name:        "copilot",
 		DisplayName: "copilot",
 		Command:     "copilot",
+		ConfigPath:  "~/.copilot/config.json",
 		Description: "GitHub's AI-powered CLI assistant",
 		Args:        []string{},
+		Tags:        []string{"github"},
 		InstallCmds: map[string]string{
-			"darwin":      "(curl -fsSL https://gh.io/copilot-install | bash) || (wget -qO- https://gh.io/copilot-install | bash) || brew install copilot-cli || npm install -g @github/copilot || npm install -g @github/copilot@prerelease",
-			"linux":       "(curl -fsSL https://gh.io/copilot-install | bash) || (wget -qO- https://gh.io/copilot-install | bash) || brew install copilot-cli || npm install -g @github/copilot || npm install -g @github/copilot@prerelease",
-			"windows_ps":  "winget install GitHub.Copilot; if ($LASTEXITCODE -ne 0) { npm install -g @github/copilot }; if ($LASTEXITCODE -ne 0) { npm install -g @github/copilot@prerelease }",
-			"windows_cmd": "winget install GitHub.Copilot || npm install -g @github/copilot || npm install -g @github/copilot@prerelease",
+			"darwin": "(curl -fsSL https://gh.io/copilot-install | bash) || (wget -qO- https://gh.io/copilot-install | bash)",
+			"linux":  "(curl -fsSL https://gh.io/copilot-install | bash) || (wget -qO- https://gh.io/copilot-install | bash)",
+			"termux": "npm install -g @github/copilot",
 		},
-		InstallURL: "https://github.com/github/copilot-cli",
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerWinget, Package: "GitHub.Copilot", Platforms: []string{"windows"}},
+			{Manager: tool.ManagerBrew, Package: "copilot-cli", Platforms: []string{"darwin", "linux"}},
+			{Manager: tool.ManagerNpm, Package: "@github/copilot"},
+			{Manager: tool.ManagerNpm, Package: "@github/copilot@prerelease"},
+		},
+		InstallURL:     "https://github.com/github/copilot-cli",
+		UpdateSource:   "npm:@github/copilot",
+		MinNodeVersion: "22",
 	})
 
 	registry.Register(&tool.Tool{
@@ -86,103 +100,435 @@ func LoadDefaultTools() *tool.Registry {
 		Command:     "kimi",
 		Description: "Kimi Code by Moonshot",
 		Args:        []string{},
+		Tags:        []string{"moonshot"},
 		InstallCmds: map[string]string{
 			"darwin":     "curl -L https://code.kimi.com/install.sh | bash",
 			"linux":      "curl -L https://code.kimi.com/install.sh | bash",
 			"windows_ps": "irm https://code.kimi.com/install.ps1 | iex",
+			"termux":     "pkg install -y python && pip install kimi-cli",
 		},
-		InstallURL: "https://code.kimi.com",
+		InstallURL:   "https://code.kimi.com",
+		UpdateSource: "pypi:kimi-cli",
 	})
 
 	registry.Register(&tool.Tool{
-		Name:        "codex",
-		DisplayName: "codex",
-		Command:     "codex",
-		Description: "OpenAI's Codex CLI",
-		Args:        []string{},
+		Name:         "codex",
+		DisplayName:  "codex",
+		Command:      "codex",
+		ConfigPath:   "~/.codex/config.toml",
+		Description:  "OpenAI's Codex CLI",
+		Args:         []string{},
+		LoginArgs:    []string{"login"},
+		PromptArgs:   []string{"{prompt}"},
+		SessionsGlob: "~/.codex/sessions/*/*/*/rollout-*.jsonl",
+		Tags:         []string{"openai"},
+		Tips: []string{
+			"/model switches the model and its reasoning effort",
+			"/init writes an AGENTS.md with instructions for the project",
+			"/compact summarizes the conversation when the context fills up",
+		},
 		InstallCmds: map[string]string{
-			"darwin":      "brew install codex || npm i -g @openai/codex",
-			"linux":       "npm i -g @openai/codex",
-			"windows_ps":  "npm i -g @openai/codex",
-			"windows_cmd": "npm i -g @openai/codex",
+			"termux": "npm i -g @openai/codex",
+		},
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerBrew, Package: "codex", Platforms: []string{"darwin"}},
+			{Manager: tool.ManagerNpm, Package: "@openai/codex"},
 		},
+		NixPackage: "codex",
 		InstallURL: "https://platform.openai.com/docs/guides/code",
+		UpgradeCmds: map[string]string{
+			// Upgrade with whichever package manager installed it
+			"darwin":      "brew upgrade codex || npm i -g @openai/codex@latest",
+			"linux":       "npm i -g @openai/codex@latest",
+			"windows_ps":  "npm i -g @openai/codex@latest",
+			"windows_cmd": "npm i -g @openai/codex@latest",
+			"termux":      "npm i -g @openai/codex@latest",
+		},
+		UpdateSource:   "npm:@openai/codex",
+		MinNodeVersion: "16",
+	})
+
+	registry.Register(&tool.Tool{
+		Name:        "aider",
+		DisplayName: "aider",
+		Command:     "aider",
+		ConfigPath:  "~/.aider.conf.yml",
+		SearchDirs:  []string{"~/.local/bin"}, // pipx links aider here, which isn't always on PATH
+		Description: "Aider - AI pair programming in your terminal",
+		Args:        []string{},
+		Tags:        []string{"opensource", "python"},
+		Tips: []string{
+			"/add puts files in the chat and /drop takes them out",
+			"/undo reverts the last commit aider made",
+			"/architect plans changes with one model and edits with another",
+		},
+		InstallCmds: map[string]string{
+			"darwin":      "pipx install aider-chat || (brew install pipx && pipx install aider-chat)",
+			"linux":       "pipx install aider-chat || (python3 -m pip install --user pipx && python3 -m pipx install aider-chat)",
+			"windows_ps":  "pipx install aider-chat; if ($LASTEXITCODE -ne 0) { py -m pip install --user pipx; py -m pipx install aider-chat }",
+			"windows_cmd": "pipx install aider-chat || (py -m pip install --user pipx && py -m pipx install aider-chat)",
+		},
+		// Termux and other systems without an install command use uv or pipx directly
+		PythonPackage: &tool.PythonPackage{Name: "aider-chat", Python: "3.12"},
+		Dependencies:  []tool.Dependency{pythonDependency("3.10")}, // For pipx
+		InstallURL:    "https://aider.chat/docs/install.html",
+		UpgradeCmds: map[string]string{
+			"darwin":      "pipx upgrade aider-chat",
+			"linux":       "pipx upgrade aider-chat",
+			"windows_ps":  "pipx upgrade aider-chat",
+			"windows_cmd": "pipx upgrade aider-chat",
+		},
+		UpdateSource: "pypi:aider-chat",
+	})
+
+	registry.Register(&tool.Tool{
+		Name:         "gemini",
+		DisplayName:  "gemini",
+		Command:      "gemini",
+		ConfigPath:   "~/.gemini/settings.json",
+		Description:  "Google's Gemini CLI",
+		Args:         []string{},
+		PromptArgs:   []string{"--prompt-interactive", "{prompt}"},
+		SessionsGlob: "~/.gemini/tmp/*/chats/*.json",
+		Tags:         []string{"google"},
+		Tips: []string{
+			"@path adds a file or directory to the prompt",
+			"/compress replaces the chat with a summary to free up context",
+			"/memory add keeps a fact for later sessions",
+		},
+		InstallCmds: map[string]string{
+			"termux": "npm install -g @google/gemini-cli",
+		},
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerBrew, Package: "gemini-cli", Platforms: []string{"darwin"}},
+			{Manager: tool.ManagerNpm, Package: "@google/gemini-cli"},
+		},
+		NixPackage: "gemini-cli",
+		InstallURL: "https://github.com/google-gemini/gemini-cli",
+		UpgradeCmds: map[string]string{
+			"darwin":      "brew upgrade gemini-cli || npm install -g @google/gemini-cli@latest",
+			"linux":       "npm install -g @google/gemini-cli@latest",
+			"windows_ps":  "npm install -g @google/gemini-cli@latest",
+			"windows_cmd": "npm install -g @google/gemini-cli@latest",
+			"termux":      "npm install -g @google/gemini-cli@latest",
+		},
+		UpdateSource:   "npm:@google/gemini-cli",
+		MinNodeVersion: "20",
+		Dependencies:   []tool.Dependency{nodeDependency("20")},
+	})
+
+	registry.Register(&tool.Tool{
+		Name:        "qwen",
+		DisplayName: "qwen code",
+		Command:     "qwen",
+		ConfigPath:  "~/.qwen/settings.json",
+		Description: "Qwen Code by Alibaba",
+		Args:        []string{},
+		PromptArgs:  []string{"--prompt-interactive", "{prompt}"},
+		Tags:        []string{"alibaba"},
+		InstallCmds: map[string]string{
+			"termux": "npm install -g @qwen-code/qwen-code",
+		},
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerBrew, Package: "qwen-code", Platforms: []string{"darwin"}},
+			{Manager: tool.ManagerNpm, Package: "@qwen-code/qwen-code"},
+		},
+		InstallURL: "https://github.com/QwenLM/qwen-code",
+		UpgradeCmds: map[string]string{
+			"darwin":      "brew upgrade qwen-code || npm install -g @qwen-code/qwen-code@latest",
+			"linux":       "npm install -g @qwen-code/qwen-code@latest",
+			"windows_ps":  "npm install -g @qwen-code/qwen-code@latest",
+			"windows_cmd": "npm install -g @qwen-code/qwen-code@latest",
+			"termux":      "npm install -g @qwen-code/qwen-code@latest",
+		},
+		UpdateSource:   "npm:@qwen-code/qwen-code",
+		MinNodeVersion: "20",
+		Dependencies:   []tool.Dependency{nodeDependency("20")},
+	})
+
+	registry.Register(&tool.Tool{
+		Name:        "iflow",
+		DisplayName: "iflow",
+		Command:     "iflow",
+		ConfigPath:  "~/.iflow/settings.json",
+		Description: "iFlow CLI",
+		Args:        []string{},
+		Tags:        []string{"alibaba"},
+		InstallCmds: map[string]string{
+			"darwin": "bash -c \"$(curl -fsSL https://cloud.iflow.cn/iflow-cli/install.sh)\"",
+			"linux":  "bash -c \"$(curl -fsSL https://cloud.iflow.cn/iflow-cli/install.sh)\"",
+			"termux": "npm install -g @iflow-ai/iflow-cli",
+		},
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerNpm, Package: "@iflow-ai/iflow-cli"},
+		},
+		InstallURL: "https://github.com/iflow-ai/iflow-cli",
+		UpgradeCmds: map[string]string{
+			"darwin":      "npm install -g @iflow-ai/iflow-cli@latest",
+			"linux":       "npm install -g @iflow-ai/iflow-cli@latest",
+			"windows_ps":  "npm install -g @iflow-ai/iflow-cli@latest",
+			"windows_cmd": "npm install -g @iflow-ai/iflow-cli@latest",
+			"termux":      "npm install -g @iflow-ai/iflow-cli@latest",
+		},
+		UpdateSource:   "npm:@iflow-ai/iflow-cli",
+		MinNodeVersion: "20",
+		Dependencies:   []tool.Dependency{nodeDependency("20")},
 	})
 
 	registry.Register(&tool.Tool{
 		Name:        "opencode",
 		DisplayName: "opencode",
 		Command:     "opencode",
+		ConfigPath:  "~/.config/opencode/opencode.json",
 		Description: "opencode",
 		Args:        []string{},
+		LoginArgs:   []string{"auth", "login"},
+		Tags:        []string{"opensource"},
 		InstallCmds: map[string]string{
-			"darwin":      "brew install anomalyco/tap/opencode || curl -fsSL https://opencode.ai/install | bash",
-			"linux":       "curl -fsSL https://opencode.ai/install | bash",
-			"windows_ps":  "npm i -g opencode-ai",
-			"windows_cmd": "npm i -g opencode-ai",
+			"darwin": "curl -fsSL https://opencode.ai/install | bash",
+			"linux":  "curl -fsSL https://opencode.ai/install | bash",
+			"termux": "npm i -g opencode-ai",
+		},
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerBrew, Package: "anomalyco/tap/opencode", Platforms: []string{"darwin"}},
+			{Manager: tool.ManagerNpm, Package: "opencode-ai"},
 		},
+		NixPackage: "opencode",
 		InstallURL: "https://opencode.ai",
+		UpgradeCmds: map[string]string{
+			"darwin":      "opencode upgrade",
+			"linux":       "opencode upgrade",
+			"windows_ps":  "opencode upgrade",
+			"windows_cmd": "opencode upgrade",
+			"termux":      "npm i -g opencode-ai@latest",
+		},
+		UpdateSource: "npm:opencode-ai",
+	})
+
+	registry.Register(&tool.Tool{
+		Name:        "goose",
+		DisplayName: "goose",
+		Command:     "goose",
+		ConfigPath:  "~/.config/goose/config.yaml",
+		SearchDirs:  []string{"~/.local/bin"}, // Where the install script puts it
+		Description: "goose, the open source agent by Block",
+		Args:        []string{},
+		LoginArgs:   []string{"configure"},
+		Tags:        []string{"opensource", "block"},
+		Tips: []string{
+			"goose configure switches the model provider or adds extensions",
+			"goose session --resume continues the last session",
+		},
+		InstallCmds: map[string]string{
+			"darwin":     "curl -fsSL https://github.com/block/goose/releases/download/stable/download_cli.sh | CONFIGURE=false bash",
+			"linux":      "curl -fsSL https://github.com/block/goose/releases/download/stable/download_cli.sh | CONFIGURE=false bash",
+			"windows_ps": "$env:CONFIGURE='false'; irm https://github.com/block/goose/releases/download/stable/download_cli.ps1 | iex",
+		},
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerBrew, Package: "block-goose-cli", Platforms: []string{"darwin", "linux"}},
+		},
+		NixPackage: "goose-cli",
+		InstallURL: "https://block.github.io/goose/docs/getting-started/installation",
+		UpgradeCmds: map[string]string{
+			"darwin":     "goose update",
+			"linux":      "goose update",
+			"windows_ps": "goose update",
+		},
+		UpdateSource: "github:block/goose",
+	})
+
+	registry.Register(&tool.Tool{
+		Name:        "amp",
+		DisplayName: "amp",
+		Icon:        "▲",
+		Command:     "amp",
+		ConfigPath:  "~/.config/amp/settings.json",
+		SearchDirs:  []string{"~/.local/bin", "~/.amp/bin"}, // Where the install script puts it
+		Description: "Amp, the coding agent by Sourcegraph",
+		Args:        []string{},
+		LoginArgs:   []string{"login"},
+		Tags:        []string{"sourcegraph"},
+		InstallCmds: map[string]string{
+			"darwin": "curl -fsSL https://ampcode.com/install.sh | bash",
+			"linux":  "curl -fsSL https://ampcode.com/install.sh | bash",
+			"termux": "npm install -g @sourcegraph/amp",
+		},
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerNpm, Package: "@sourcegraph/amp"},
+		},
+		InstallURL: "https://ampcode.com/manual",
+		UpgradeCmds: map[string]string{
+			"darwin":      "amp update",
+			"linux":       "amp update",
+			"windows_ps":  "npm i -g @sourcegraph/amp@latest",
+			"windows_cmd": "npm i -g @sourcegraph/amp@latest",
+			"termux":      "npm i -g @sourcegraph/amp@latest",
+		},
+		UpdateSource:   "npm:@sourcegraph/amp",
+		MinNodeVersion: "20",
+	})
+
+	registry.Register(&tool.Tool{
+		Name:        "crush",
+		DisplayName: "crush",
+		Icon:        "♥",
+		Command:     "crush",
+		ConfigPath:  "~/.config/crush/crush.json",
+		Description: "Crush, the glamourous coding agent by Charm",
+		Args:        []string{},
+		Tags:        []string{"charm", "opensource"},
+		InstallCmds: map[string]string{
+			"termux": "npm install -g @charmland/crush",
+		},
+		Installers: []tool.Installer{
+			{Manager: tool.ManagerBrew, Package: "charmbracelet/tap/crush", Platforms: []string{"darwin", "linux"}},
+			{Manager: tool.ManagerWinget, Package: "charmbracelet.crush", Platforms: []string{"windows"}},
+			{Manager: tool.ManagerNpm, Package: "@charmland/crush"},
+		},
+		NixPackage: "crush",
+		InstallURL: "https://github.com/charmbracelet/crush#installation",
+		UpgradeCmds: map[string]string{
+			// Upgrade with whichever package manager installed it
+			"darwin":      "brew upgrade charmbracelet/tap/crush || npm i -g @charmland/crush@latest",
+			"linux":       "brew upgrade charmbracelet/tap/crush || npm i -g @charmland/crush@latest",
+			"windows_ps":  "winget upgrade -e --id charmbracelet.crush",
+			"windows_cmd": "winget upgrade -e --id charmbracelet.crush",
+			"termux":      "npm i -g @charmland/crush@latest",
+		},
+		UpdateSource: "github:charmbracelet/crush",
+	})
+
+	registry.Register(&tool.Tool{
+		Name:        "cursor",
+		DisplayName: "cursor agent",
+		Command:     "cursor-agent",
+		ConfigPath:  "~/.cursor/cli-config.json",
+		SearchDirs:  []string{"~/.local/bin"}, // Where the install script links it
+		Description: "Cursor's CLI agent",
+		Args:        []string{},
+		LoginArgs:   []string{"login"},
+		Tags:        []string{"cursor"},
+		Platforms:   []string{"darwin", "linux"}, // Windows through WSL
+		InstallCmds: map[string]string{
+			"darwin": "curl -fsSL https://cursor.com/install | bash",
+			"linux":  "curl -fsSL https://cursor.com/install | bash",
+		},
+		InstallURL: "https://cursor.com/cli",
+		UpgradeCmds: map[string]string{
+			"darwin": "cursor-agent update",
+			"linux":  "cursor-agent update",
+		},
+	})
+
+	// Claude Code on the Anthropic-compatible APIs of DeepSeek and Zhipu (GLM), which most
+	// of our users in China code with. They install and update claude itself.
+	registry.Register(&tool.Tool{
+		Name:         "deepseek",
+		DisplayName:  "claude code · deepseek",
+		Command:      "claude",
+		Description:  "Claude Code on the DeepSeek API",
+		Args:         []string{},
+		PromptArgs:   []string{"{prompt}"},
+		SessionsGlob: "~/.claude/projects/*/*.jsonl",
+		Tags:         []string{"deepseek"},
+		Env: map[string]string{
+			"ANTHROPIC_BASE_URL":                       "https://api.deepseek.com/anthropic",
+			"ANTHROPIC_AUTH_TOKEN":                     "$DEEPSEEK_API_KEY",
+			"ANTHROPIC_MODEL":                          "deepseek-chat",
+			"ANTHROPIC_SMALL_FAST_MODEL":               "deepseek-chat",
+			"API_TIMEOUT_MS":                           "600000",
+			"CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC": "1",
+		},
+		KeychainEnv:    map[string]string{"DEEPSEEK_API_KEY": "deepseek"}, // Where the balance reads it too
+		InstallCmds:    claudeInstallCmds(),
+		NixPackage:     "claude-code",
+		InstallURL:     "https://api-docs.deepseek.com/guides/anthropic_api",
+		UpgradeCmds:    claudeUpgradeCmds(),
+		UpdateSource:   "npm:@anthropic-ai/claude-code",
+		MinNodeVersion: "18",
+	})
+
+	registry.Register(&tool.Tool{
+		Name:         "glm",
+		DisplayName:  "claude code · glm",
+		Command:      "claude",
+		Description:  "Claude Code on Zhipu's GLM API",
+		Args:         []string{},
+		PromptArgs:   []string{"{prompt}"},
+		SessionsGlob: "~/.claude/projects/*/*.jsonl",
+		Tags:         []string{"zhipu"},
+		Env: map[string]string{
+			"ANTHROPIC_BASE_URL":   "https://open.bigmodel.cn/api/anthropic",
+			"ANTHROPIC_AUTH_TOKEN": "$ZHIPUAI_API_KEY",
+			"API_TIMEOUT_MS":       "3000000",
+		},
+		InstallCmds:    claudeInstallCmds(),
+		NixPackage:     "claude-code",
+		InstallURL:     "https://docs.bigmodel.cn/cn/guide/develop/claude",
+		UpgradeCmds:    claudeUpgradeCmds(),
+		UpdateSource:   "npm:@anthropic-ai/claude-code",
+		MinNodeVersion: "18",
 	})
 
 	return registry
 }
 
-// getUsageFilePath returns the path to the usage data file
-func getUsageFilePath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return ".amazing-cli-usage.json"
+// claudeInstallCmds returns the install commands of claude, for every tool that runs it.
+func claudeInstallCmds() map[string]string {
+	return map[string]string{
+		"darwin":      "curl -fsSL https://claude.ai/install.sh | bash",
+		"linux":       "curl -fsSL https://claude.ai/install.sh | bash",
+		"windows_ps":  "irm https://claude.ai/install.ps1 | iex",
+		"windows_cmd": "curl -fsSL https://claude.ai/install.cmd -o install.cmd && install.cmd && del install.cmd",
+		"termux":      "npm install -g @anthropic-ai/claude-code",
 	}
-	return filepath.Join(homeDir, ".amazing-cli", "usage.json")
 }
 
-// LoadToolUsage loads the last usage times for tools from disk
-func LoadToolUsage() map[string]time.Time {
-	usage := make(map[string]time.Time)
-
-	filePath := getUsageFilePath()
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		// File doesn't exist yet, return empty map
-		return usage
-	}
+// nodeDependency returns the node runtime of the agents written in JavaScript, whichever
+// way they are installed.
+func nodeDependency(minVersion string) tool.Dependency {
+	return tool.Dependency{Name: "node", MinVersion: minVersion, InstallURL: "https://nodejs.org/en/download"}
+}
 
-	// Parse JSON with string timestamps
-	var rawData map[string]string
-	if err := json.Unmarshal(data, &rawData); err != nil {
-		return usage
+// pythonDependency returns the Python that pipx installs run on: py on Windows, as in the
+// install commands, python3 elsewhere.
+func pythonDependency(minVersion string) tool.Dependency {
+	name := "python3"
+	if runtime.GOOS == "windows" {
+		name = "py"
 	}
+	return tool.Dependency{Name: name, MinVersion: minVersion, InstallURL: "https://www.python.org/downloads/"}
+}
 
-	// Convert string times to time.Time
-	for toolName, timeStr := range rawData {
-		if t, err := time.Parse(time.RFC3339, timeStr); err == nil {
-			usage[toolName] = t
-		}
+// claudeUpgradeCmds returns the upgrade commands of claude.
+func claudeUpgradeCmds() map[string]string {
+	return map[string]string{
+		"darwin":     "claude update",
+		"linux":      "claude update",
+		"windows_ps": "claude update",
 	}
-
-	return usage
 }
 
-// SaveToolUsage saves the last usage times for tools to disk
-func SaveToolUsage(usage map[string]time.Time) error {
-	filePath := getUsageFilePath()
-
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
+// getTourMarkerPath returns the path to the file recording that the guided tour was shown
+func getTourMarkerPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-tour"
 	}
+	return filepath.Join(homeDir, ".amazing-cli", "tour-completed")
+}
 
-	// Convert time.Time to RFC3339 strings for JSON serialization
-	rawData := make(map[string]string)
-	for toolName, t := range usage {
-		rawData[toolName] = t.Format(time.RFC3339)
-	}
+// TourCompleted reports whether the guided tour has already been shown
+func TourCompleted() bool {
+	_, err := os.Stat(getTourMarkerPath())
+	return err == nil
+}
 
-	data, err := json.MarshalIndent(rawData, "", "  ")
-	if err != nil {
+// MarkTourCompleted records that the guided tour has been shown so it isn't started again on launch
+func MarkTourCompleted() error {
+	filePath := getTourMarkerPath()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return err
 	}
-
-	return os.WriteFile(filePath, data, 0644)
+	return os.WriteFile(filePath, []byte(time.Now().Format(time.RFC3339)+"\n"), 0644)
 }
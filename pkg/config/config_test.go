@@ -1,7 +1,18 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/analytics"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
 
 func TestLoadDefaultTools(t *testing.T) {
@@ -12,12 +23,12 @@ func TestLoadDefaultTools(t *testing.T) {
 	}
 
 	tools := registry.List()
-	if len(tools) != 5 {
-		t.Errorf("Expected 5 tools, got %d", len(tools))
+	if len(tools) != 15 {
+		t.Errorf("Expected 15 tools, got %d", len(tools))
 	}
 
 	// Check that all expected tools are present
-	expectedTools := []string{"claude", "copilot", "kimi", "codex", "opencode"}
+	expectedTools := []string{"claude", "copilot", "kimi", "codex", "aider", "gemini", "qwen", "iflow", "opencode", "goose", "cursor", "amp", "crush", "deepseek", "glm"}
 	for _, name := range expectedTools {
 		tool := registry.Get(name)
 		if tool == nil {
@@ -37,6 +48,75 @@ func TestLoadDefaultTools(t *testing.T) {
 	}
 }
 
+func TestLoadDefaultTools_Dependencies(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake runtimes are shell scripts")
+	}
+	// fakeRuntimes puts commands printing the given versions on an otherwise empty PATH,
+	// along with a pipx that records being run.
+	fakeRuntimes := func(t *testing.T, versions map[string]string) (pipxRan func() bool) {
+		bin := t.TempDir()
+		ran := filepath.Join(bin, "pipx-ran")
+		scripts := map[string]string{"pipx": "#!/bin/sh\ntouch " + ran + "\nexit 1\n"}
+		for name, version := range versions {
+			scripts[name] = "#!/bin/sh\necho " + version + "\n"
+		}
+		for name, script := range scripts {
+			if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		t.Setenv("PATH", bin)
+		t.Setenv("PREFIX", "")
+		return func() bool {
+			_, err := os.Stat(ran)
+			return err == nil
+		}
+	}
+
+	tests := []struct {
+		tool     string
+		versions map[string]string
+		want     string // Message of the unmet dependency, "" when they are all met
+	}{
+		{"aider", map[string]string{"python3": "Python 3.8.10"}, "python3 >= 3.10 is required (found Python 3.8.10)"},
+		{"aider", nil, "python3 >= 3.10 is required but was not found"},
+		{"aider", map[string]string{"python3": "Python 3.12.3"}, ""},
+		{"gemini", map[string]string{"node": "v18.19.0"}, "node >= 20 is required (found v18.19.0)"},
+		{"gemini", map[string]string{"node": "v22.11.0"}, ""},
+		{"qwen", nil, "node >= 20 is required but was not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool+" "+tt.want, func(t *testing.T) {
+			pipxRan := fakeRuntimes(t, tt.versions)
+			tl := LoadDefaultTools().Get(tt.tool)
+			var got []string
+			for _, status := range tl.CheckDependencies() {
+				if msg := status.Message(); msg != "" {
+					got = append(got, msg)
+				}
+			}
+			if tt.want == "" && len(got) != 0 || tt.want != "" && (len(got) != 1 || !strings.HasPrefix(got[0], tt.want)) {
+				t.Errorf("unmet dependencies = %q, want %q", got, tt.want)
+			}
+			if msg := tl.NodeVersionWarning(); msg != "" {
+				t.Errorf("NodeVersionWarning() = %q, want node left to the dependency check", msg)
+			}
+
+			// The install stops before pipx runs
+			if tt.tool == "aider" && tt.want != "" {
+				err := tl.InstallWithOutput(nil)
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("InstallWithOutput() error = %v, want the unmet dependency", err)
+				}
+				if pipxRan() {
+					t.Error("InstallWithOutput() ran pipx without a Python to run it on")
+				}
+			}
+		})
+	}
+}
+
 func TestGetDefaultBalance(t *testing.T) {
 	balance := GetDefaultBalance()
 
@@ -48,7 +128,867 @@ func TestGetDefaultBalance(t *testing.T) {
 		t.Errorf("Expected display '100%%', got %s", balance.Display)
 	}
 
-	if balance.Color != "green" {
-		t.Errorf("Expected color 'green', got %s", balance.Color)
+	if color := balance.ToToolBalance().Color(); color != "green" {
+		t.Errorf("Expected color 'green', got %s", color)
+	}
+}
+
+func TestLoadSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// Missing file yields defaults
+	if got := LoadSettings(); !reflect.DeepEqual(got, DefaultSettings()) {
+		t.Errorf("LoadSettings() without file = %+v, want defaults %+v", got, DefaultSettings())
+	}
+
+	// Keys present in the file override defaults, missing keys keep them
+	dir := filepath.Join(home, ".amazing-cli")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"launch_banner": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := LoadSettings()
+	if !got.ClearScreen {
+		t.Error("clear_screen should default to true when not set")
+	}
+	if !got.LaunchBanner {
+		t.Error("launch_banner should be loaded from the file")
+	}
+	if !got.ShowBalances {
+		t.Error("show_balances should default to true when not set")
+	}
+
+	// Round trip through SetSetting, which keeps the rest of the file
+	want := got
+	want.ClearScreen = false
+	if err := SetSetting("clear_screen", "false"); err != nil {
+		t.Fatalf("SetSetting() error: %v", err)
+	}
+	if got := LoadSettings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadSettings() after save = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadSettings_Env(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".amazing-cli")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"launch_banner": true, "layout": "single"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The environment overrides the file key by key
+	t.Setenv(SettingsEnv, `{"layout": "auto", "catalog": {"url": "https://tools.example/catalog.json"}}`)
+	got := LoadSettings()
+	if !got.LaunchBanner || got.Layout != LayoutAuto || got.Catalog.URL != "https://tools.example/catalog.json" {
+		t.Errorf("LoadSettings() = %+v, want launch_banner from the file and layout and catalog from %s", got, SettingsEnv)
+	}
+
+	// Invalid JSON is ignored
+	t.Setenv(SettingsEnv, `{"layout": `)
+	if got := LoadSettings(); got.Layout != LayoutSingle {
+		t.Errorf("LoadSettings() layout = %q with invalid %s, want the file's", got.Layout, SettingsEnv)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if _, ok := LoadSnapshot(); ok {
+		t.Fatal("LoadSnapshot() should report no snapshot on first run")
+	}
+
+	registry := LoadDefaultTools()
+	codex := registry.Get("codex")
+	codex.SetInstalled(true)
+	codex.Version = "0.5.0"
+	if err := SaveSnapshot(TakeSnapshot(registry.List())); err != nil {
+		t.Fatalf("SaveSnapshot() error: %v", err)
+	}
+
+	snap, ok := LoadSnapshot()
+	if !ok {
+		t.Fatal("LoadSnapshot() should find the saved snapshot")
+	}
+	fresh := LoadDefaultTools()
+	ApplySnapshot(fresh, snap)
+	if got := fresh.Get("codex"); !got.IsInstalled() || got.Version != "0.5.0" {
+		t.Errorf("codex after ApplySnapshot: installed=%v version=%q, want installed 0.5.0", got.IsInstalled(), got.Version)
+	}
+}
+
+func TestApplyTags(t *testing.T) {
+	registry := LoadDefaultTools()
+	ApplyTags(registry, map[string][]string{
+		"codex":   {"#work", "openai"},
+		"unknown": {"ignored"},
+	})
+
+	codex := registry.Get("codex")
+	if !codex.HasTag("work") || !codex.HasTag("openai") {
+		t.Errorf("codex tags = %v, want openai and work", codex.Tags)
+	}
+	if len(codex.Tags) != 2 {
+		t.Errorf("codex tags = %v, duplicate tags should be skipped", codex.Tags)
+	}
+}
+
+func TestApplyTips(t *testing.T) {
+	registry := LoadDefaultTools()
+	builtIn := len(registry.Get("claude").Tips)
+	ApplyTips(registry, map[string][]string{
+		"claude":  {"ask for a plan before big changes"},
+		"unknown": {"ignored"},
+	})
+
+	tips := registry.Get("claude").Tips
+	if len(tips) != builtIn+1 || tips[builtIn] != "ask for a plan before big changes" {
+		t.Errorf("claude tips = %q, want the built-in ones and the configured one", tips)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	registry := LoadDefaultTools()
+	ApplyEnv(registry, map[string]map[string]string{
+		"codex":   {"OPENAI_BASE_URL": "https://proxy.local/v1"},
+		"unknown": {"IGNORED": "1"},
+	})
+
+	if got := registry.Get("codex").Env["OPENAI_BASE_URL"]; got != "https://proxy.local/v1" {
+		t.Errorf("codex OPENAI_BASE_URL = %q, want the configured URL", got)
+	}
+	if env := registry.Get("claude").Env; len(env) != 0 {
+		t.Errorf("claude Env = %v, want none", env)
+	}
+}
+
+func TestApplyFrecency(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	registry := LoadDefaultTools()
+	codex, claude, aider := registry.Get("codex"), registry.Get("claude"), registry.Get("aider")
+	codex.LastUsed, claude.LastUsed = now.Add(-day), now.Add(-time.Minute)
+	launches := []Launch{
+		{Tool: "codex", At: now.Add(-3 * day)},
+		{Tool: "codex", At: now.Add(-2 * day)},
+		{Tool: "codex", At: now.Add(-day)},
+		{Tool: "claude", At: now.Add(-time.Minute)},
+		{Tool: "aider", At: now.Add(-time.Hour)}, // Recent use was cleared
+	}
+
+	ApplyFrecency(registry, launches, DefaultSettings().Ranking.HalfLife(), now)
+	if codex.Frecency <= claude.Frecency {
+		t.Errorf("codex frecency %v should outrank claude %v after daily use", codex.Frecency, claude.Frecency)
+	}
+	if aider.Frecency != 0 {
+		t.Errorf("aider frecency = %v, want 0 without a recorded last use", aider.Frecency)
+	}
+}
+
+func TestRecordUsageSample(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	samples := []analytics.Sample{
+		{At: now.Add(-9 * 24 * time.Hour), Remaining: 100}, // Past retention
+		{At: now.Add(-time.Hour), Remaining: 80},
+		{At: now.Add(-time.Hour), Remaining: 80}, // Same fetch served again
+		{At: now, Remaining: 70},
+	}
+	var got []analytics.Sample
+	for _, s := range samples {
+		var err error
+		if got, err = RecordUsageSample("codex", s); err != nil {
+			t.Fatalf("RecordUsageSample() error: %v", err)
+		}
+	}
+
+	if len(got) != 2 || got[0].Remaining != 80 || got[1].Remaining != 70 {
+		t.Errorf("samples = %+v, want the 80%% and 70%% samples", got)
+	}
+	if loaded := LoadUsageHistory()["codex"]; len(loaded) != 2 {
+		t.Errorf("LoadUsageHistory() = %+v, want the 2 recorded samples", loaded)
+	}
+}
+
+func TestRecordUsageSample_WaitsForOtherProcesses(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Hold the lock like the daemon writing a sample of its own
+	unlock, err := lockFile(getHistoryFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		_, err := RecordUsageSample("codex", analytics.Sample{At: time.Now(), Remaining: 70})
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("RecordUsageSample() didn't wait for the lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatalf("RecordUsageSample() error: %v", err)
+	}
+	if loaded := LoadUsageHistory()["codex"]; len(loaded) != 1 {
+		t.Errorf("LoadUsageHistory() = %+v, want the recorded sample", loaded)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	path := filepath.Join(dir, "usage-history.json")
+	for _, data := range []string{"{\"old\": true}\n", "{}\n"} {
+		if err := writeFile(path, []byte(data)); err != nil {
+			t.Fatalf("writeFile() error: %v", err)
+		}
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "{}\n" {
+		t.Errorf("file = %q, %v, want the last write", data, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files, want no temp files left", len(entries))
+	}
+	if info, err := os.Stat(path); runtime.GOOS != "windows" && (err != nil || info.Mode().Perm() != 0644) {
+		t.Errorf("mode = %v, %v, want 0644", info.Mode().Perm(), err)
+	}
+}
+
+func TestClearToolUsage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	used := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, name := range []string{"codex", "claude"} {
+		if err := RecordToolUsage(name, used); err != nil {
+			t.Fatalf("RecordToolUsage(%q) error: %v", name, err)
+		}
+	}
+	if err := ClearToolUsage("codex"); err != nil {
+		t.Fatalf("ClearToolUsage() error: %v", err)
+	}
+	if err := ClearToolUsage("never-used"); err != nil {
+		t.Fatalf("ClearToolUsage() of an unused tool error: %v", err)
+	}
+
+	usage := LoadToolUsage()
+	if _, ok := usage["codex"]; ok {
+		t.Error("codex usage should be cleared")
+	}
+	if !usage["claude"].Equal(used) {
+		t.Errorf("claude usage = %v, want %v", usage["claude"], used)
+	}
+}
+
+func TestRecordSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	start := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for i := range sessionsLimit + 2 {
+		if err := RecordToolLaunch("codex", start); err != nil {
+			t.Fatalf("RecordToolLaunch() error: %v", err)
+		}
+		if err := RecordSession("codex", FinishedSession(start.Add(time.Duration(i)*time.Hour), time.Duration(i)*time.Minute, nil)); err != nil {
+			t.Fatalf("RecordSession() error: %v", err)
+		}
+	}
+	if err := ClearToolUsage("codex"); err != nil {
+		t.Fatalf("ClearToolUsage() error: %v", err)
+	}
+
+	u := LoadUsageStats()["codex"]
+	if u.Launches != sessionsLimit+2 || !u.LastUsed.IsZero() {
+		t.Errorf("usage = %d launches, last used %v; want the launches kept after clearing", u.Launches, u.LastUsed)
+	}
+	// 0+1+...+21 minutes
+	if want := time.Duration((sessionsLimit+1)*(sessionsLimit+2)/2) * time.Minute; u.Total() != want {
+		t.Errorf("Total() = %v, want %v", u.Total(), want)
+	}
+	if len(u.Sessions) != sessionsLimit || u.Sessions[0].Duration() != 2*time.Minute {
+		t.Errorf("sessions = %d starting at %v, want the latest %d", len(u.Sessions), u.Sessions[0].Duration(), sessionsLimit)
+	}
+	if last, ok := u.LastSession(); !ok || last.Duration() != (sessionsLimit+1)*time.Minute || last.ExitCode == nil || *last.ExitCode != 0 {
+		t.Errorf("LastSession() = %+v, %v; want the last one, exited 0", last, ok)
+	}
+}
+
+func TestLoadUsageStats_OldFormat(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir := filepath.Join(home, ".amazing-cli")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"codex": "2026-02-10T16:22:00Z", "gemini": {"last_used": "2026-02-11T09:00:00Z", "launches": 3}}`
+	if err := os.WriteFile(filepath.Join(dir, "usage.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := LoadUsageStats()
+	if want := time.Date(2026, 2, 10, 16, 22, 0, 0, time.UTC); !stats["codex"].LastUsed.Equal(want) {
+		t.Errorf("codex last used = %v, want %v", stats["codex"].LastUsed, want)
+	}
+	if stats["gemini"].Launches != 3 {
+		t.Errorf("gemini launches = %d, want 3", stats["gemini"].Launches)
+	}
+}
+
+func TestLoadUsageStats_Corrupted(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	path := filepath.Join(home, ".amazing-cli", "usage.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"codex": {"launches": 3, "last_us`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats := LoadUsageStats(); len(stats) != 0 {
+		t.Errorf("LoadUsageStats() = %+v, want the defaults", stats)
+	}
+	if _, err := os.Stat(path + ".bak"); err != nil {
+		t.Errorf("the corrupted file should be moved to usage.json.bak: %v", err)
+	}
+	if err := RecordToolLaunch("codex", time.Now()); err != nil {
+		t.Fatalf("RecordToolLaunch() error: %v", err)
+	}
+	if u := LoadUsageStats()["codex"]; u.Launches != 1 {
+		t.Errorf("launches = %d after starting over, want 1", u.Launches)
+	}
+}
+
+func TestMirrorSettings_Mirrors(t *testing.T) {
+	m := MirrorSettings{
+		Rewrite: map[string]string{
+			"https://github.com/":         "https://mirror.corp/github/",
+			"https://github.com/openai/":  "https://mirror.corp/openai/",
+			"https://registry.npmjs.org/": "https://mirror.corp/npm/",
+		},
+		NPM: "https://mirror.corp/npm/",
+	}
+
+	got := m.Mirrors()
+	wantFrom := []string{"https://registry.npmjs.org/", "https://github.com/openai/", "https://github.com/"}
+	if len(got.Rules) != len(wantFrom) {
+		t.Fatalf("Rules = %+v, want %d rules", got.Rules, len(wantFrom))
+	}
+	for i, from := range wantFrom {
+		if got.Rules[i].From != from {
+			t.Errorf("Rules[%d].From = %q, want %q", i, got.Rules[i].From, from)
+		}
+	}
+	if got.NPMRegistry != m.NPM {
+		t.Errorf("NPMRegistry = %q, want %q", got.NPMRegistry, m.NPM)
+	}
+	if rewritten := got.Rewrite("https://github.com/openai/codex"); rewritten != "https://mirror.corp/openai/codex" {
+		t.Errorf("Rewrite() = %q, want the longest prefix to win", rewritten)
+	}
+}
+
+func TestBalanceCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if _, ok := LoadBalanceCache(); ok {
+		t.Fatal("LoadBalanceCache() should report no cache before the daemon ran")
+	}
+
+	now := time.Now()
+	cache := BalanceCache{
+		UpdatedAt: now,
+		Interval:  5 * time.Minute,
+		Balances:  map[string]*tool.Balance{"codex": {Percentage: 40, Display: "40%"}},
+	}
+	if err := SaveBalanceCache(cache); err != nil {
+		t.Fatalf("SaveBalanceCache() error: %v", err)
+	}
+	got, ok := LoadBalanceCache()
+	if !ok || got.Balances["codex"] == nil || got.Balances["codex"].Percentage != 40 {
+		t.Fatalf("LoadBalanceCache() = %+v, %v, want the saved codex balance", got, ok)
+	}
+
+	// Written by a daemon whose balances mean something else
+	stale := fmt.Sprintf(`{"updated_at": %q, "schema": %d, "balances": {"codex": {"Percentage": 60}}}`, now.Format(time.RFC3339), tool.BalanceSchema-1)
+	if err := os.WriteFile(getBalanceCacheFilePath(), []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if old, ok := LoadBalanceCache(); ok {
+		t.Errorf("LoadBalanceCache() = %+v, want balances of another schema ignored", old)
+	}
+
+	tests := []struct {
+		name  string
+		cache BalanceCache
+		want  bool
+	}{
+		{"just polled", got, true},
+		{"one missed poll", BalanceCache{UpdatedAt: now.Add(-9 * time.Minute), Interval: 5 * time.Minute}, true},
+		{"daemon stopped", BalanceCache{UpdatedAt: now.Add(-11 * time.Minute), Interval: 5 * time.Minute}, false},
+		{"no interval", BalanceCache{UpdatedAt: now}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.cache.Fresh(now); got != tt.want {
+			t.Errorf("%s: Fresh() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSaveBalanceCache_Concurrent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Two daemons, or a daemon and a launcher refreshing, saving at once
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache := BalanceCache{UpdatedAt: time.Now(), Balances: map[string]*tool.Balance{"codex": {Percentage: i * 10}}}
+			if err := SaveBalanceCache(cache); err != nil {
+				t.Errorf("SaveBalanceCache() error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, ok := LoadBalanceCache(); !ok || got.Balances["codex"] == nil {
+		t.Errorf("LoadBalanceCache() = %+v, %v, want one of the saved caches whole", got, ok)
+	}
+	entries, err := os.ReadDir(filepath.Dir(getBalanceCacheFilePath()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("cache directory holds %d files, want no temp files left", len(entries))
+	}
+}
+func TestUpdateChecks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	now := time.Now()
+	checks := LoadUpdateChecks()
+	checks["codex"] = UpdateCheck{Latest: "0.47.0", CheckedAt: now.Add(-time.Hour)}
+	checks["claude"] = UpdateCheck{Latest: "2.0.1", CheckedAt: now.Add(-25 * time.Hour)}
+	if err := SaveUpdateChecks(checks); err != nil {
+		t.Fatalf("SaveUpdateChecks() error: %v", err)
+	}
+
+	loaded := LoadUpdateChecks()
+	if got := loaded["codex"]; got.Latest != "0.47.0" || !got.Fresh(now) {
+		t.Errorf("codex check = %+v, want fresh 0.47.0", got)
+	}
+	if loaded["claude"].Fresh(now) {
+		t.Error("a check older than a day should not be fresh")
+	}
+}
+
+func TestRecordLaunch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	at := time.Date(2026, 2, 10, 16, 22, 0, 0, time.UTC)
+	first := Launch{Tool: "codex", At: at, Dir: "/src/api", Note: "fixing auth bug"}
+	second := Launch{Tool: "claude", At: at.Add(time.Hour), Args: []string{"--model", "opus"}}
+	for _, l := range []Launch{first, second} {
+		if err := RecordLaunch(l); err != nil {
+			t.Fatalf("RecordLaunch() error: %v", err)
+		}
+	}
+	first.Project = "/src/api" // Not in a git repository: the directory itself
+	if got := LoadLaunches(); !reflect.DeepEqual(got, []Launch{first, second}) {
+		t.Errorf("LoadLaunches() = %+v", got)
+	}
+
+	if err := RecordSession("codex", FinishedSession(at.Add(time.Second), 25*time.Minute, nil)); err != nil {
+		t.Fatalf("RecordSession() error: %v", err)
+	}
+	if got := LoadLaunches(); got[0].Seconds != 25*60 || got[1].Seconds != 0 {
+		t.Errorf("launches = %+v, want the codex session's 25m on its launch", got)
+	}
+}
+
+func TestProjectOf(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(repo, "cmd", "api")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+	tests := []struct {
+		dir, want string
+	}{
+		{repo, repo},
+		{sub, repo},
+		{outside, outside},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ProjectOf(tt.dir); got != tt.want {
+			t.Errorf("ProjectOf(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+	if got := (Launch{Dir: sub}).ProjectPath(); got != sub {
+		t.Errorf("ProjectPath() of an old launch = %q, want its directory", got)
+	}
+}
+
+func TestRecordRecentDir(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for i := range recentDirsLimit + 2 {
+		if err := RecordRecentDir(fmt.Sprintf("/src/%d", i)); err != nil {
+			t.Fatalf("RecordRecentDir() error: %v", err)
+		}
+	}
+	if err := RecordRecentDir("/src/5"); err != nil {
+		t.Fatalf("RecordRecentDir() error: %v", err)
+	}
+
+	dirs := LoadRecentDirs()
+	if len(dirs) != recentDirsLimit {
+		t.Fatalf("LoadRecentDirs() = %v, want the %d most recent", dirs, recentDirsLimit)
+	}
+	want := []string{"/src/5", "/src/11", "/src/10", "/src/9", "/src/8", "/src/7", "/src/6", "/src/4", "/src/3", "/src/2"}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("LoadRecentDirs() = %v, want %v: /src/5 moved to the front, the oldest dropped", dirs, want)
+	}
+}
+
+func TestImportLaunches(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	at := time.Date(2026, 2, 10, 16, 22, 0, 0, time.UTC)
+	recorded := Launch{Tool: "codex", At: at}
+	if err := RecordLaunch(recorded); err != nil {
+		t.Fatalf("RecordLaunch() error: %v", err)
+	}
+	older := Launch{Tool: "claude", At: at.Add(-48 * time.Hour), Session: "s1", Tokens: 1200}
+	oldest := Launch{Tool: "codex", At: at.Add(-72 * time.Hour), Session: "s2"}
+	imported := []Launch{
+		older,
+		oldest,
+		{Tool: "codex", At: at.Add(time.Hour), Session: "s3"}, // After amazing-cli was adopted
+	}
+
+	added, err := ImportLaunches(imported)
+	if err != nil || added != 2 {
+		t.Fatalf("ImportLaunches() = %d, %v, want 2 added", added, err)
+	}
+	if got := LoadLaunches(); !reflect.DeepEqual(got, []Launch{oldest, older, recorded}) {
+		t.Errorf("LoadLaunches() = %+v, want imported sessions before the recorded launch", got)
+	}
+	if added, err := ImportLaunches(imported); err != nil || added != 0 {
+		t.Errorf("ImportLaunches() again = %d, %v, want nothing added", added, err)
+	}
+}
+
+func TestLaunch_Matches(t *testing.T) {
+	l := Launch{Tool: "codex", Dir: "/src/api", Note: "Fixing auth bug"}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"auth", true},
+		{"codex AUTH", true},
+		{"api", true},
+		{"auth claude", false},
+	}
+	for _, tt := range tests {
+		if got := l.Matches(tt.query); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestIdleSettings_PollInterval(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		idle       IdleSettings
+		lastActive time.Time
+		want       time.Duration
+	}{
+		{name: "no timeout", idle: IdleSettings{LowPowerIntervalMinutes: 60}, lastActive: now.Add(-48 * time.Hour), want: 5 * time.Minute},
+		{name: "active", idle: IdleSettings{TimeoutMinutes: 30, LowPowerIntervalMinutes: 60}, lastActive: now.Add(-10 * time.Minute), want: 5 * time.Minute},
+		{name: "idle", idle: IdleSettings{TimeoutMinutes: 30, LowPowerIntervalMinutes: 60}, lastActive: now.Add(-30 * time.Minute), want: time.Hour},
+		{name: "low power faster than interval", idle: IdleSettings{TimeoutMinutes: 30, LowPowerIntervalMinutes: 1}, lastActive: now.Add(-time.Hour), want: 5 * time.Minute},
+		{name: "negative timeout", idle: IdleSettings{TimeoutMinutes: -5, LowPowerIntervalMinutes: 60}, lastActive: now.Add(-time.Hour), want: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := tt.idle.PollInterval(5*time.Minute, tt.lastActive, now); got != tt.want {
+			t.Errorf("%s: PollInterval() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetSetting(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".amazing-cli")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Keys the launcher doesn't know about survive
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"launch_banner": true, "x_custom": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kv := range [][2]string{{"theme", "dracula"}, {"clear_screen", "false"}, {"ranking.order", RankFrecency}, {"tags.codex", `["work"]`}, {"env.codex.OPENAI_BASE_URL", "https://proxy"}} {
+		if err := SetSetting(kv[0], kv[1]); err != nil {
+			t.Fatalf("SetSetting(%q, %q) error: %v", kv[0], kv[1], err)
+		}
+	}
+	got := LoadSettings()
+	if got.Theme != "dracula" || got.ClearScreen || !got.LaunchBanner || got.Ranking.Order != RankFrecency || got.Ranking.HalfLifeDays != 7 || !reflect.DeepEqual(got.Tags["codex"], []string{"work"}) || got.Env["codex"]["OPENAI_BASE_URL"] != "https://proxy" {
+		t.Errorf("LoadSettings() = %+v after SetSetting", got)
+	}
+	if v, err := GetSetting("ranking.order"); err != nil || v != RankFrecency {
+		t.Errorf("GetSetting(ranking.order) = %v, %v, want %q", v, err, RankFrecency)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "config.json"))
+	if !strings.Contains(string(data), "x_custom") {
+		t.Errorf("config.json lost a key it didn't set:\n%s", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("~/.amazing-cli holds %d files after SetSetting, want only config.json", len(entries))
+	}
+
+	for _, kv := range [][2]string{
+		{"no_such_key", "1"},
+		{"clear_screen", "maybe"},
+		{"", "1"},
+		{"ranking.nope", "1"},          // Typo below a known setting
+		{"ranking.order.by", "recent"}, // Below a value that isn't an object
+		{"theme.name", "dracula"},
+		{"idle.", "1"},
+	} {
+		if err := SetSetting(kv[0], kv[1]); err == nil {
+			t.Errorf("SetSetting(%q, %q) succeeded, want an error", kv[0], kv[1])
+		}
+	}
+	if got := LoadSettings(); got.Theme != "dracula" || got.Ranking.Order != RankFrecency {
+		t.Errorf("LoadSettings() = %+v, want the rejected keys to leave the file as it was", got)
+	}
+	if _, err := GetSetting("ranking.nope"); err == nil {
+		t.Error("GetSetting(ranking.nope) succeeded, want an error")
+	}
+}
+
+func TestSavePinned(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := SetSetting("theme", "dracula"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SavePinned([]string{"claude", "codex"}); err != nil {
+		t.Fatalf("SavePinned() error: %v", err)
+	}
+	if got := LoadSettings(); !reflect.DeepEqual(got.Pinned, []string{"claude", "codex"}) || got.Theme != "dracula" {
+		t.Errorf("LoadSettings() = pinned %q, theme %q, want the pinned tools and the theme kept", got.Pinned, got.Theme)
+	}
+	if err := SavePinned(nil); err != nil {
+		t.Fatalf("SavePinned(nil) error: %v", err)
+	}
+	data, _ := os.ReadFile(SettingsFilePath())
+	if !strings.Contains(string(data), `"pinned": []`) {
+		t.Errorf("config.json after unpinning everything:\n%s\nwant \"pinned\": []", data)
+	}
+}
+
+func TestSetHidden(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, name := range []string{"opencode", "kimi"} {
+		if err := SetHidden(name, true); err != nil {
+			t.Fatalf("SetHidden(%q, true) error: %v", name, err)
+		}
+	}
+	if err := SetHidden("kimi", true); err == nil {
+		t.Error("SetHidden() of a hidden tool succeeded, want an error")
+	}
+	if err := SetHidden("kimi", false); err != nil {
+		t.Fatalf("SetHidden(kimi, false) error: %v", err)
+	}
+	if err := SetHidden("codex", false); err == nil {
+		t.Error("SetHidden() showing a tool that isn't hidden succeeded, want an error")
+	}
+	if got := LoadSettings().Hidden; !reflect.DeepEqual(got, []string{"opencode"}) {
+		t.Errorf("Hidden = %q, want [opencode]", got)
+	}
+
+	registry := LoadDefaultTools()
+	ApplyHidden(registry, []string{"opencode", "unknown"})
+	if registry.Get("opencode") != nil || registry.Get("codex") == nil {
+		t.Error("ApplyHidden() should remove opencode and keep the other tools")
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if err := SaveOverride("codex", ToolOverride{DisplayName: "codex – work"}); err != nil {
+		t.Fatalf("SaveOverride() error: %v", err)
+	}
+	if err := SaveOverride("claude", ToolOverride{Icon: "★"}); err != nil {
+		t.Fatalf("SaveOverride() error: %v", err)
+	}
+	settings := LoadSettings()
+	registry := LoadDefaultTools()
+	ApplyOverrides(registry, settings.Overrides)
+	if codex := registry.Get("codex"); codex.DisplayName != "codex – work" || codex.Icon != "" {
+		t.Errorf("codex = %q %q, want the overridden name", codex.Icon, codex.DisplayName)
+	}
+	if name, icon := registry.Get("codex").OwnLabel(); name != "codex" || icon != "" {
+		t.Errorf("codex OwnLabel() = %q, %q, want the built-in label", name, icon)
+	}
+	if claude := registry.Get("claude"); claude.DisplayName != "claude code" || claude.Icon != "★" {
+		t.Errorf("claude = %q %q, want its own name with the overridden icon", claude.Icon, claude.DisplayName)
+	}
+
+	if err := SaveOverride("codex", ToolOverride{}); err != nil {
+		t.Fatalf("SaveOverride() of no override error: %v", err)
+	}
+	if got := LoadSettings().Overrides; len(got) != 1 || got["claude"].Icon != "★" {
+		t.Errorf("Overrides = %v, want only claude's left", got)
+	}
+}
+
+func TestCheckSettingsFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := CheckSettingsFile(); err != nil {
+		t.Errorf("CheckSettingsFile() without a file = %v, want nil", err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".amazing-cli"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		file  string
+		valid bool
+	}{
+		{`{"theme": "dracula", "tools": [{"name": "goose", "command": "goose"}]}`, true},
+		{`{"theme": "dracula",}`, false},
+		{`{"clear_screen": "yes"}`, false},
+		{`{"tools": [{"name": "goose"}]}`, false},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(SettingsFilePath(), []byte(tt.file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := CheckSettingsFile(); (err == nil) != tt.valid {
+			t.Errorf("CheckSettingsFile() of %s = %v, want valid %v", tt.file, err, tt.valid)
+		}
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new", "config.json")
+	tests := []struct {
+		visual, editor string
+		want           []string
+	}{
+		{visual: "code --wait", editor: "nano", want: []string{"code", "--wait", path}},
+		{editor: "nano", want: []string{"nano", path}},
+		{want: []string{"vi", path}},
+	}
+	if runtime.GOOS == "windows" {
+		tests[2].want[0] = "notepad"
+	}
+	for _, tt := range tests {
+		t.Setenv("VISUAL", tt.visual)
+		t.Setenv("EDITOR", tt.editor)
+		cmd, err := EditorCommand(path)
+		if err != nil {
+			t.Fatalf("EditorCommand() error: %v", err)
+		}
+		if !reflect.DeepEqual(cmd.Args, tt.want) {
+			t.Errorf("EditorCommand() with VISUAL=%q EDITOR=%q runs %q, want %q", tt.visual, tt.editor, cmd.Args, tt.want)
+		}
+	}
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		t.Errorf("EditorCommand() didn't create the file's directory: %v", err)
+	}
+	t.Setenv("VISUAL", `"unterminated`)
+	if _, err := EditorCommand(path); err == nil {
+		t.Error("EditorCommand() with an unparsable $VISUAL succeeded")
+	}
+}
+
+func TestDiscoverTools(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	for _, name := range []string{"qodo", "droid", "not-an-agent"} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := tool.NewRegistry()
+	registry.Register(&tool.Tool{Name: "factory-droid", Command: "droid"}) // Already there under another name
+	found := DiscoverTools(registry, nil)
+	if len(found) != 1 || found[0].Name != "qodo" {
+		t.Fatalf("DiscoverTools() = %v, want only qodo", found)
+	}
+	if hidden := DiscoverTools(registry, []string{"qodo"}); len(hidden) != 0 {
+		t.Errorf("DiscoverTools() with qodo hidden = %v, want it left alone", hidden)
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, ".amazing-cli"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(SettingsFilePath(), []byte(`{"theme": "dracula"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 { // Adding twice keeps one entry
+		if err := AddTools(found); err != nil {
+			t.Fatalf("AddTools() error: %v", err)
+		}
+	}
+	settings := LoadSettings()
+	if settings.Theme != "dracula" || len(settings.Tools) != 1 || settings.Tools[0].Command != "qodo" {
+		t.Fatalf("LoadSettings() = theme %q, tools %+v, want dracula kept and qodo added", settings.Theme, settings.Tools)
+	}
+	data, _ := os.ReadFile(SettingsFilePath())
+	if strings.Contains(string(data), "null") {
+		t.Errorf("config.json has the unset fields of the tool:\n%s", data)
+	}
+
+	ApplyTools(registry, settings.Tools)
+	if got := registry.Get("qodo"); got == nil || !got.IsInstalled() {
+		t.Errorf("registry.Get(qodo) = %v, want the installed custom tool", got)
+	}
+	if len(DiscoverTools(registry, nil)) != 0 {
+		t.Error("DiscoverTools() found qodo again after adding it")
+	}
+
+	// Hidden after adding, it's out of the registry but still not offered again
+	if err := SetHidden("qodo", true); err != nil {
+		t.Fatal(err)
+	}
+	settings = LoadSettings()
+	ApplyHidden(registry, settings.Hidden)
+	if registry.Get("qodo") != nil {
+		t.Fatal("registry.Get(qodo) is still there after hiding it")
+	}
+	if again := DiscoverTools(registry, settings.Hidden); len(again) != 0 {
+		t.Errorf("DiscoverTools() = %v after hiding qodo, want it not offered again", again)
 	}
 }
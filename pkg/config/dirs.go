@@ -0,0 +1,57 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+)
+
+// recentDirsLimit is how many working directories the recent list keeps.
+const recentDirsLimit = 10
+
+// getRecentDirsPath returns the path to the recent working directories file, next to usage.json
+func getRecentDirsPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-dirs.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "dirs.json")
+}
+
+// LoadRecentDirs loads the directories tools were launched in, most recent first
+func LoadRecentDirs() []string {
+	filePath := getRecentDirsPath()
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		// No launches in a chosen directory yet
+		return nil
+	}
+	var dirs []string
+	if err := json.Unmarshal(data, &dirs); err != nil {
+		cache.Quarantine(filePath, err)
+		return nil
+	}
+	return dirs
+}
+
+// RecordRecentDir moves dir to the front of the recent directories, dropping the oldest
+// ones past the limit.
+func RecordRecentDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	dirs := slices.DeleteFunc(LoadRecentDirs(), func(d string) bool { return d == dir })
+	dirs = append([]string{dir}, dirs...)
+	if len(dirs) > recentDirsLimit {
+		dirs = dirs[:recentDirsLimit]
+	}
+
+	data, err := json.MarshalIndent(dirs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(getRecentDirsPath(), data)
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/catalog"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// knownAgents are agent CLIs that aren't built in, looked for in PATH by DiscoverTools.
+// Found ones can be added as custom tools.
+var knownAgents = []catalog.Definition{
+	{Name: "qodo", Command: "qodo", Description: "Qodo Command", InstallURL: "https://docs.qodo.ai/qodo-documentation/qodo-command"},
+	{Name: "droid", Command: "droid", Description: "Droid by Factory", InstallURL: "https://docs.factory.ai/cli/getting-started/quickstart"},
+	{Name: "auggie", Command: "auggie", Description: "Auggie by Augment Code", InstallURL: "https://docs.augmentcode.com/cli/overview"},
+	{Name: "plandex", Command: "plandex", Description: "Plandex", InstallURL: "https://plandex.ai"},
+	{Name: "codebuff", Command: "codebuff", Description: "Codebuff", InstallURL: "https://www.codebuff.com"},
+}
+
+// DiscoverTools returns the known agents found in PATH that no tool in the registry
+// launches, as definitions to add with AddTools. Agents named in hidden were hidden
+// on purpose and aren't offered again.
+func DiscoverTools(registry *tool.Registry, hidden []string) []catalog.Definition {
+	var found []catalog.Definition
+	for _, d := range knownAgents {
+		if registered(registry, d) || slices.Contains(hidden, d.Name) {
+			continue
+		}
+		if _, err := tool.LookPath(d.Command); err == nil {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+// registered reports whether a tool in the registry has the definition's name or command.
+func registered(registry *tool.Registry, d catalog.Definition) bool {
+	return registry.Get(d.Name) != nil || slices.ContainsFunc(registry.List(), func(t *tool.Tool) bool {
+		return t.Command == d.Command
+	})
+}
+
+// ApplyTools adds the custom tools of the settings to the registry, replacing built-in
+// tools with the same name. Definitions without a name or command are skipped.
+func ApplyTools(registry *tool.Registry, defs []catalog.Definition) {
+	for _, d := range defs {
+		if d.Name != "" && d.Command != "" {
+			registry.Put(d.Tool())
+		}
+	}
+}
+
+// AddTools adds the definitions to the custom tools in the config file, skipping names
+// that are already there.
+func AddTools(defs []catalog.Definition) error {
+	return editSettingsFile(func(file map[string]any) error {
+		tools, _ := file["tools"].([]any)
+		var names []string
+		for _, t := range tools {
+			if def, ok := t.(map[string]any); ok {
+				name, _ := def["name"].(string)
+				names = append(names, name)
+			}
+		}
+		for _, d := range defs {
+			if d.Name == "" || d.Command == "" {
+				return fmt.Errorf("custom tool %q needs a name and a command", d.Name)
+			}
+			if slices.Contains(names, d.Name) {
+				continue
+			}
+			def, err := definitionObject(d)
+			if err != nil {
+				return err
+			}
+			tools = append(tools, def)
+			names = append(names, d.Name)
+		}
+		file["tools"] = tools
+		return nil
+	})
+}
+
+// definitionObject returns the JSON object of a definition without its unset fields, so
+// the config file only has what was filled in.
+func definitionObject(d catalog.Definition) (map[string]any, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var def map[string]any
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, err
+	}
+	for key, value := range def {
+		if value == nil || value == "" {
+			delete(def, key)
+		}
+	}
+	return def, nil
+}
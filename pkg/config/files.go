@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// writeFile replaces path with data, creating its directory if needed. The data goes to a
+// temp file of its own that is renamed over path, so a reader (another launcher, the
+// daemon) never sees half a file and two writers at once don't mix theirs.
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	tmp, err := os.CreateTemp(dir, strings.TrimSuffix(base, ext)+"-*"+ext+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Gone after the rename; cleans up after a failure
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil { // CreateTemp makes it 0600
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// lockFile takes an exclusive lock on path+".lock" that amazing-cli processes hold while
+// they read, change and write path back, waiting for it if another one holds it, and
+// returns the function that releases it.
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockOpenFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		unlockOpenFile(f)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/analytics"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// historyRetention is how long usage samples are kept; a bit more than one weekly window.
+const historyRetention = 8 * 24 * time.Hour
+
+// historyMu serializes read-modify-write cycles of the history file, since balances
+// of several tools are fetched concurrently; the file's lock does between processes
+var historyMu sync.Mutex
+
+// UsageHistory holds weekly limit samples per tool name, oldest first.
+type UsageHistory map[string][]analytics.Sample
+
+// getHistoryFilePath returns the path to the usage history file
+func getHistoryFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-usage-history.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "cache", "usage-history.json")
+}
+
+// LoadUsageHistory loads recorded usage samples from disk
+func LoadUsageHistory() UsageHistory {
+	history := make(UsageHistory)
+
+	filePath := getHistoryFilePath()
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		// No history yet
+		return history
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		cache.Quarantine(filePath, err)
+		return make(UsageHistory)
+	}
+	return history
+}
+
+// SaveUsageHistory saves usage samples to disk
+func SaveUsageHistory(history UsageHistory) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(getHistoryFilePath(), data)
+}
+
+// RecordUsageSample appends a sample for the tool, drops samples past the retention period,
+// and returns the tool's updated samples. A sample taken at the same time as the last one
+// (e.g., served from a provider cache) is not recorded twice.
+func RecordUsageSample(toolName string, sample analytics.Sample) ([]analytics.Sample, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	// The daemon and other launchers record samples too
+	unlock, err := lockFile(getHistoryFilePath())
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	history := LoadUsageHistory()
+
+	samples := history[toolName]
+	if n := len(samples); n == 0 || !samples[n-1].At.Equal(sample.At) {
+		samples = append(samples, sample)
+	}
+
+	cutoff := sample.At.Add(-historyRetention)
+	kept := samples[:0]
+	for _, s := range samples {
+		if !s.At.Before(cutoff) {
+			kept = append(kept, s)
+		}
+	}
+	history[toolName] = kept
+
+	return kept, SaveUsageHistory(history)
+}
+
+// RecordWeeklySample records the weekly limit of a fetched balance in the usage history
+// and returns the tool's samples. Returns false if the weekly usage is unknown.
+func RecordWeeklySample(toolName string, balance *tool.Balance) ([]analytics.Sample, bool) {
+	weekly, ok := balance.Limit(tool.LimitWeekly)
+	if !ok || weekly.Display == "" || strings.Contains(weekly.Display, "?") || balance.FetchedAt.IsZero() {
+		// Unknown usage isn't worth recording
+		return nil, false
+	}
+
+	samples, err := RecordUsageSample(toolName, analytics.Sample{At: balance.FetchedAt, Remaining: weekly.RemainingPercent})
+	if err != nil {
+		return nil, false
+	}
+	return samples, true
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+)
+
+// launchHistoryLimit is how many launches the history keeps; older ones are dropped.
+const launchHistoryLimit = 5000
+
+// Launch is one launch of a tool, kept in the launch history.
+type Launch struct {
+	Tool string    `json:"tool"`
+	At   time.Time `json:"at"`
+	Dir  string    `json:"dir,omitempty"`  // Working directory the tool was launched in
+	Args []string  `json:"args,omitempty"` // Extra arguments given for this launch
+	Note string    `json:"note,omitempty"` // What the session was for (e.g., "fixing auth bug")
+
+	Project string  `json:"project,omitempty"` // Root of the git repository Dir is in, or Dir outside one
+	Seconds float64 `json:"seconds,omitempty"` // How long the session lasted; 0 if unknown
+
+	// Set on launches imported from an agent's own session logs
+	Session string `json:"session,omitempty"` // ID of the agent's session
+	Tokens  int64  `json:"tokens,omitempty"`  // Tokens used in the session
+}
+
+// Matches reports whether every word of query appears in the launch's tool, note or
+// directory, ignoring case.
+func (l Launch) Matches(query string) bool {
+	text := strings.ToLower(l.Tool + " " + l.Note + " " + l.Dir)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// ProjectPath returns the project the launch is attributed to: its Project, or its Dir for
+// launches recorded before projects were.
+func (l Launch) ProjectPath() string {
+	if l.Project != "" {
+		return l.Project
+	}
+	return l.Dir
+}
+
+// ProjectOf returns the project dir belongs to: the root of the git repository it is in
+// (its nearest parent with a .git), or dir itself outside one.
+func ProjectOf(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d // A directory, or a file in worktrees and submodules
+		}
+		if filepath.Dir(d) == d {
+			return dir
+		}
+	}
+}
+
+// getLaunchHistoryPath returns the path to the launch history file
+func getLaunchHistoryPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-launches.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "launches.json")
+}
+
+// LoadLaunches loads the launch history from disk, oldest first
+func LoadLaunches() []Launch {
+	filePath := getLaunchHistoryPath()
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		// No launches yet
+		return nil
+	}
+	var launches []Launch
+	if err := json.Unmarshal(data, &launches); err != nil {
+		cache.Quarantine(filePath, err)
+		return nil
+	}
+	return launches
+}
+
+// RecordLaunch appends a launch to the history, dropping the oldest ones past the limit.
+// Its Project is filled in from its Dir.
+func RecordLaunch(launch Launch) error {
+	if launch.Project == "" {
+		launch.Project = ProjectOf(launch.Dir)
+	}
+	return saveLaunches(append(LoadLaunches(), launch))
+}
+
+// recordLaunchSeconds sets the duration of the session of toolName that started at start
+// on its launch: the latest one of the tool recorded before it.
+func recordLaunchSeconds(toolName string, start time.Time, seconds float64) error {
+	launches := LoadLaunches()
+	for i := len(launches) - 1; i >= 0; i-- {
+		l := &launches[i]
+		if l.Tool != toolName || l.At.After(start) {
+			continue
+		}
+		if l.Seconds > 0 {
+			return nil // Already timed: the session's own launch wasn't recorded
+		}
+		l.Seconds = seconds
+		return saveLaunches(launches)
+	}
+	return nil
+}
+
+// ImportLaunches backfills the history with sessions imported from the agents' logs and
+// returns how many were added. Only sessions from before the first launch recorded by
+// amazing-cli are added, and sessions already in the history are skipped, so importing
+// again is harmless.
+func ImportLaunches(imported []Launch) (int, error) {
+	launches := LoadLaunches()
+	var cutoff time.Time
+	seen := make(map[string]bool)
+	for _, l := range launches {
+		if l.Session != "" {
+			seen[l.Tool+"/"+l.Session] = true
+		} else if cutoff.IsZero() || l.At.Before(cutoff) {
+			cutoff = l.At
+		}
+	}
+
+	added := 0
+	for _, l := range imported {
+		key := l.Tool + "/" + l.Session
+		if l.Session == "" || seen[key] || !cutoff.IsZero() && !l.At.Before(cutoff) {
+			continue
+		}
+		seen[key] = true
+		if l.Project == "" {
+			l.Project = ProjectOf(l.Dir)
+		}
+		launches = append(launches, l)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	sort.SliceStable(launches, func(i, j int) bool { return launches[i].At.Before(launches[j].At) })
+	return added, saveLaunches(launches)
+}
+
+// saveLaunches writes the history, dropping the oldest launches past the limit.
+func saveLaunches(launches []Launch) error {
+	if len(launches) > launchHistoryLimit {
+		launches = launches[len(launches)-launchHistoryLimit:]
+	}
+
+	data, err := json.MarshalIndent(launches, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(getLaunchHistoryPath(), data)
+}
@@ -0,0 +1,346 @@
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/analytics"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/catalog"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Tool list layouts
+const (
+	LayoutAuto   = "auto"   // Use a grid on wide terminals, a single column otherwise
+	LayoutSingle = "single" // Always use a single column
+)
+
+// List densities
+const (
+	DensityComfortable = "comfortable" // Blank lines between sections, details under the selected tool
+	DensityCompact     = "compact"     // One line per tool and narrower bars, for small terminals
+)
+
+// Color modes
+const (
+	ColorAuto      = "auto"      // Detect what the terminal supports, honoring NO_COLOR
+	ColorTrueColor = "truecolor" // 24-bit colors
+	Color256       = "256"       // xterm 256-color palette
+	Color16        = "16"        // Basic ANSI colors
+	ColorNone      = "none"      // No colors or text attributes
+)
+
+// Orders of the installed tools
+const (
+	RankRecent   = "recent"   // Most recently launched first
+	RankFrecency = "frecency" // Most launched first, recent launches weighing more
+)
+
+// RankingSettings configures the order of the installed tools.
+type RankingSettings struct {
+	Order        string  `json:"order"`          // RankRecent or RankFrecency
+	HalfLifeDays float64 `json:"half_life_days"` // Frecency: a launch counts half as much after this long
+}
+
+// HalfLife returns the frecency half-life as a duration.
+func (r RankingSettings) HalfLife() time.Duration {
+	return time.Duration(r.HalfLifeDays * float64(24*time.Hour))
+}
+
+// BurnAlertSettings configures warnings when a tool burns through its weekly limit too fast.
+type BurnAlertSettings struct {
+	Enabled       bool    `json:"enabled"`
+	WindowMinutes int     `json:"window_minutes"` // Recent usage the burn rate is measured over
+	MarginHours   float64 `json:"margin_hours"`   // Only alert if the limit would run out at least this long before reset
+	Desktop       bool    `json:"desktop"`        // Also send a desktop notification
+}
+
+// Window returns the burn-rate window as a duration.
+func (b BurnAlertSettings) Window() time.Duration {
+	return time.Duration(b.WindowMinutes) * time.Minute
+}
+
+// Margin returns the alert margin as a duration.
+func (b BurnAlertSettings) Margin() time.Duration {
+	return time.Duration(b.MarginHours * float64(time.Hour))
+}
+
+// ToolGroup is a named section of the tool list in the launcher, e.g. "Coding agents".
+type ToolGroup struct {
+	Name      string   `json:"name"`
+	Tools     []string `json:"tools"`     // Tool names; a tool in several groups is shown in the first
+	Collapsed bool     `json:"collapsed"` // Start with the group collapsed
+}
+
+// IdleSettings configures what the launcher and the daemon do when nobody is using them.
+type IdleSettings struct {
+	TimeoutMinutes          int `json:"timeout_minutes"`            // Quit the launcher after this long without a key press, and slow the daemon down after this long without a launch; 0 never
+	LowPowerIntervalMinutes int `json:"low_power_interval_minutes"` // How often the daemon polls while idle
+}
+
+// Timeout returns the idle timeout, 0 if there is none.
+func (i IdleSettings) Timeout() time.Duration {
+	return time.Duration(max(0, i.TimeoutMinutes)) * time.Minute
+}
+
+// LowPowerInterval returns the daemon's polling interval while idle.
+func (i IdleSettings) LowPowerInterval() time.Duration {
+	return time.Duration(i.LowPowerIntervalMinutes) * time.Minute
+}
+
+// PollInterval returns how often a daemon polling every interval should poll: the
+// low-power interval, if it is longer, once nothing was used for the timeout since
+// lastActive.
+func (i IdleSettings) PollInterval(interval time.Duration, lastActive, now time.Time) time.Duration {
+	if timeout := i.Timeout(); timeout == 0 || now.Sub(lastActive) < timeout {
+		return interval
+	}
+	return max(interval, i.LowPowerInterval())
+}
+
+// MirrorSettings redirects installs to internal artifact mirrors (e.g., Artifactory or Nexus).
+type MirrorSettings struct {
+	Rewrite map[string]string `json:"rewrite"` // URL prefix to mirror prefix (e.g., "https://github.com/": "https://artifactory.corp/github/")
+	NPM     string            `json:"npm"`     // npm registry URL
+	PyPI    string            `json:"pypi"`    // Python package index URL
+}
+
+// Mirrors converts the settings for tool.SetMirrors. Longer prefixes are tried first.
+func (m MirrorSettings) Mirrors() tool.Mirrors {
+	rules := make([]tool.MirrorRule, 0, len(m.Rewrite))
+	for from, to := range m.Rewrite {
+		rules = append(rules, tool.MirrorRule{From: from, To: to})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].From) != len(rules[j].From) {
+			return len(rules[i].From) > len(rules[j].From)
+		}
+		return rules[i].From < rules[j].From
+	})
+	return tool.Mirrors{Rules: rules, NPMRegistry: m.NPM, PyPIIndex: m.PyPI}
+}
+
+// CatalogSettings configures a remote catalog of extra tool definitions.
+type CatalogSettings struct {
+	URL           string `json:"url"`            // Catalog JSON (http(s) URL or local path); empty disables the catalog
+	PublicKey     string `json:"public_key"`     // minisign public key that signed URL + ".minisig"
+	SHA256        string `json:"sha256"`         // Pinned checksum of the catalog
+	AllowUnsigned bool   `json:"allow_unsigned"` // Trust the catalog without a signature or checksum
+}
+
+// Source converts the settings for catalog.Load.
+func (c CatalogSettings) Source() catalog.Source {
+	return catalog.Source{URL: c.URL, PublicKey: c.PublicKey, SHA256: c.SHA256, AllowUnsigned: c.AllowUnsigned}
+}
+
+// ToolOverride relabels a tool without redefining it, e.g. after the account it uses
+// ("codex – work"). Empty fields keep the tool's own.
+type ToolOverride struct {
+	DisplayName string `json:"display_name,omitempty"`
+	Icon        string `json:"icon,omitempty"` // One-cell glyph, like the tools' own icons
+}
+
+// Settings holds user preferences loaded from ~/.amazing-cli/config.json.
+// Keys missing from the file keep their default values.
+type Settings struct {
+	ClearScreen         bool                         `json:"clear_screen"`         // Clear the terminal before launching a tool
+	LaunchBanner        bool                         `json:"launch_banner"`        // Print "Launching <tool> in <dir> …" before launching
+	AltScreen           bool                         `json:"alt_screen"`           // Run the launcher and launched tools in the alternate screen
+	ReturnToMenu        bool                         `json:"return_to_menu"`       // Come back to the launcher when a launched tool exits
+	ExecReplace         bool                         `json:"exec_replace"`         // Replace the launcher process with the launched tool (Linux and macOS); its sessions go untimed, with no post-mortem
+	Layout              string                       `json:"layout"`               // Tool list layout: LayoutAuto or LayoutSingle
+	CollapseUninstalled bool                         `json:"collapse_uninstalled"` // Start with the not installed group collapsed
+	HideUnsupported     bool                         `json:"hide_unsupported"`     // Leave tools that don't run on this OS out of the list instead of graying them out
+	ShowBalances        bool                         `json:"show_balances"`        // Draw balance bars and fetch balances; false makes a plain launcher
+	Density             string                       `json:"density"`              // DensityComfortable or DensityCompact
+	Tags                map[string][]string          `json:"tags"`                 // Extra tags per tool name (e.g., {"codex": ["work"]})
+	Env                 map[string]map[string]string `json:"env"`                  // Environment variables per tool name (e.g., {"codex": {"OPENAI_BASE_URL": "..."}})
+	Dirs                map[string]string            `json:"dirs"`                 // Working directory per tool name (e.g., {"codex": "~/src/work"})
+	Tips                map[string][]string          `json:"tips"`                 // Extra tips per tool name, added to the built-in ones
+	Groups              []ToolGroup                  `json:"groups"`               // Named sections the installed tools are shown in, in order
+	Pinned              []string                     `json:"pinned"`               // Tools listed first, above the groups, in this order, whatever the ranking
+	Hidden              []string                     `json:"hidden"`               // Tools left out of the launcher altogether, built-in or custom
+	Overrides           map[string]ToolOverride      `json:"overrides"`            // Display name and icon per tool name, over the tool's own
+	Ranking             RankingSettings              `json:"ranking"`
+	BurnAlerts          BurnAlertSettings            `json:"burn_alerts"`
+	Idle                IdleSettings                 `json:"idle"`
+	TimeFormat          string                       `json:"time_format"` // timefmt.Clock24h or timefmt.Clock12h
+	DateOrder           string                       `json:"date_order"`  // timefmt.DayMonth or timefmt.MonthDay
+	Mirrors             MirrorSettings               `json:"mirrors"`
+	Catalog             CatalogSettings              `json:"catalog"`
+	Tools               []catalog.Definition         `json:"tools"`         // Custom tools, in the catalog's format; they replace built-in tools with the same name
+	CheckUpdates        bool                         `json:"check_updates"` // Look up the latest version of installed tools once a day
+	Theme               string                       `json:"theme"`         // Built-in theme name or theme file path; empty uses ~/.amazing-cli/theme.yaml if present
+	Color               string                       `json:"color"`         // ColorAuto, ColorTrueColor, Color256, Color16 or ColorNone
+}
+
+// DefaultSettings returns the settings used when no config file exists.
+func DefaultSettings() Settings {
+	return Settings{
+		ClearScreen:         true,
+		LaunchBanner:        false,
+		AltScreen:           false,
+		ReturnToMenu:        false,
+		ExecReplace:         runtime.GOOS != "windows",
+		Layout:              LayoutAuto,
+		CollapseUninstalled: false,
+		ShowBalances:        true,
+		Density:             DensityComfortable,
+		Ranking: RankingSettings{
+			Order:        RankRecent,
+			HalfLifeDays: 7,
+		},
+		BurnAlerts: BurnAlertSettings{
+			Enabled:       true,
+			WindowMinutes: 60,
+			MarginHours:   0,
+			Desktop:       false,
+		},
+		Idle: IdleSettings{
+			TimeoutMinutes:          0,
+			LowPowerIntervalMinutes: 60,
+		},
+		TimeFormat:   timefmt.Clock24h,
+		DateOrder:    timefmt.DayMonth,
+		CheckUpdates: true,
+		Color:        ColorAuto,
+	}
+}
+
+// DateTimeFormat returns the configured format for times shown to the user.
+func (s Settings) DateTimeFormat() timefmt.Format {
+	return timefmt.Format{Clock: s.TimeFormat, DateOrder: s.DateOrder}
+}
+
+// LaunchOptions converts the settings into options for tool.ExecuteWithOptions.
+func (s Settings) LaunchOptions() tool.LaunchOptions {
+	return tool.LaunchOptions{
+		ClearScreen: s.ClearScreen,
+		Banner:      s.LaunchBanner,
+		AltScreen:   s.AltScreen,
+		Exec:        s.ExecReplace,
+	}
+}
+
+// ApplyTags adds the configured tags to the matching tools in the registry.
+// Tags for tools that aren't registered are ignored.
+func ApplyTags(registry *tool.Registry, tags map[string][]string) {
+	for name, toolTags := range tags {
+		if t := registry.Get(name); t != nil {
+			t.AddTags(toolTags...)
+		}
+	}
+}
+
+// ApplyHidden removes the hidden tools from the registry. Names that aren't registered
+// are ignored.
+func ApplyHidden(registry *tool.Registry, hidden []string) {
+	for _, name := range hidden {
+		registry.Remove(name)
+	}
+}
+
+// ApplyOverrides sets the configured display names and icons on the matching tools in
+// the registry. Overrides for tools that aren't registered are ignored.
+func ApplyOverrides(registry *tool.Registry, overrides map[string]ToolOverride) {
+	for name, o := range overrides {
+		if t := registry.Get(name); t != nil {
+			t.Relabel(cmp.Or(o.DisplayName, t.DisplayName), cmp.Or(o.Icon, t.Icon))
+		}
+	}
+}
+
+// ApplyTips adds the configured tips to the matching tools in the registry, after the ones
+// they come with. Tips for tools that aren't registered are ignored.
+func ApplyTips(registry *tool.Registry, tips map[string][]string) {
+	for name, toolTips := range tips {
+		if t := registry.Get(name); t != nil {
+			t.Tips = append(t.Tips, toolTips...)
+		}
+	}
+}
+
+// ApplyEnv sets the configured environment variables on the matching tools in the registry,
+// over the ones the tools define. Variables for tools that aren't registered are ignored.
+func ApplyEnv(registry *tool.Registry, env map[string]map[string]string) {
+	for name, toolEnv := range env {
+		if t := registry.Get(name); t != nil {
+			t.SetEnv(toolEnv)
+		}
+	}
+}
+
+// ApplyDirs sets the configured working directories on the matching tools in the registry.
+// Directories for tools that aren't registered are ignored.
+func ApplyDirs(registry *tool.Registry, dirs map[string]string) {
+	for name, dir := range dirs {
+		if t := registry.Get(name); t != nil && dir != "" {
+			t.Dir = dir
+		}
+	}
+}
+
+// ApplyFrecency sets the frecency of the tools in the registry from the launch history.
+// Tools without a recorded last use (never launched, or cleared with x) score 0.
+func ApplyFrecency(registry *tool.Registry, launches []Launch, halfLife time.Duration, now time.Time) {
+	uses := make(map[string][]time.Time)
+	for _, l := range launches {
+		uses[l.Tool] = append(uses[l.Tool], l.At)
+	}
+	for _, t := range registry.List() {
+		if !t.LastUsed.IsZero() {
+			t.Frecency = analytics.Frecency(uses[t.Name], now, halfLife)
+		}
+	}
+}
+
+// ThemeFilePath returns the path of the theme file used when no theme is configured.
+func ThemeFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-theme.yaml"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "theme.yaml")
+}
+
+// SettingsFilePath returns the path to the settings file, ~/.amazing-cli/config.json
+func SettingsFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-config.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "config.json")
+}
+
+// SettingsEnv names the environment variable holding settings as JSON, applied over the
+// config file (e.g., AMAZING_CONFIG='{"catalog": {"url": "..."}}'), so containers can be
+// configured without one.
+const SettingsEnv = "AMAZING_CONFIG"
+
+// LoadSettings loads user settings from disk and $AMAZING_CONFIG, falling back to defaults
+func LoadSettings() Settings {
+	settings := DefaultSettings()
+
+	// A missing file keeps the defaults
+	if data, err := os.ReadFile(SettingsFilePath()); err == nil {
+		// Unmarshal over the defaults so missing keys keep their default values
+		if err := json.Unmarshal(data, &settings); err != nil {
+			settings = DefaultSettings()
+		}
+	}
+
+	if env := os.Getenv(SettingsEnv); env != "" {
+		// Invalid JSON is ignored rather than half-applied
+		overridden := settings
+		if err := json.Unmarshal([]byte(env), &overridden); err == nil {
+			settings = overridden
+		}
+	}
+	return settings
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// GetSetting returns the value of a setting as loaded (file, $AMAZING_CONFIG and defaults),
+// by its JSON key; nested keys are joined with dots, e.g. "ranking.order".
+func GetSetting(key string) (any, error) {
+	settings, err := settingsTree(LoadSettings())
+	if err != nil {
+		return nil, err
+	}
+	value, ok := lookupKey(settings, key)
+	if !ok {
+		return nil, fmt.Errorf("unknown setting %q", key)
+	}
+	return value, nil
+}
+
+// SetSetting sets a setting in the config file, keeping the rest of the file as it is.
+// value is parsed as JSON (true, 30, ["work"]) and taken as a string otherwise, so
+// `set theme dracula` needs no quotes. key must be a known setting, or a tool name
+// under a per-tool setting like tags.codex, and the value must have its type.
+func SetSetting(key, value string) error {
+	defaults, err := settingsTree(DefaultSettings())
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(key, ".")
+	if !knownKey(defaults, parts) {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		parsed = value
+	}
+	err = editSettingsFile(func(file map[string]any) error {
+		node := file
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = parsed
+		return nil
+	})
+	if invalid := (invalidSettingsError{}); errors.As(err, &invalid) {
+		return fmt.Errorf("invalid value for %s: %w", key, invalid.err)
+	}
+	return err
+}
+
+// SavePinned sets the pinned tools in the config file, keeping the rest of the file as
+// it is.
+func SavePinned(pinned []string) error {
+	return editSettingsFile(func(file map[string]any) error {
+		if pinned == nil {
+			pinned = []string{} // Written as [] like the default, not null
+		}
+		file["pinned"] = pinned
+		return nil
+	})
+}
+
+// SetHidden hides a tool from the launcher in the config file, or shows it again.
+func SetHidden(name string, hidden bool) error {
+	return editSettingsFile(func(file map[string]any) error {
+		list, _ := file["hidden"].([]any)
+		names := []string{} // Written as [] like the default, not null
+		found := false
+		for _, v := range list {
+			if s, ok := v.(string); ok && s == name {
+				found = true
+			} else if ok {
+				names = append(names, s)
+			}
+		}
+		switch {
+		case hidden && found:
+			return fmt.Errorf("%s is already hidden", name)
+		case !hidden && !found:
+			return fmt.Errorf("%s is not hidden", name)
+		case hidden:
+			names = append(names, name)
+		}
+		file["hidden"] = names
+		return nil
+	})
+}
+
+// SaveOverride sets the display name and icon override of the tool called name in the
+// config file; an empty override removes it.
+func SaveOverride(name string, o ToolOverride) error {
+	return editSettingsFile(func(file map[string]any) error {
+		overrides, _ := file["overrides"].(map[string]any)
+		if overrides == nil {
+			overrides = map[string]any{}
+		}
+		if o == (ToolOverride{}) {
+			delete(overrides, name)
+		} else {
+			overrides[name] = o
+		}
+		file["overrides"] = overrides
+		return nil
+	})
+}
+
+// invalidSettingsError is why editSettingsFile refused to write an edit: the file would
+// no longer load.
+type invalidSettingsError struct {
+	err error
+}
+
+func (e invalidSettingsError) Error() string {
+	return "invalid settings: " + e.err.Error()
+}
+
+// editSettingsFile applies edit to the JSON object of the settings file (empty if there is
+// none) and writes it back, keeping the keys edit doesn't touch. The result must still
+// load as Settings; the file is replaced atomically.
+func editSettingsFile(edit func(file map[string]any) error) error {
+	filePath := SettingsFilePath()
+	file := map[string]any{}
+	if data, err := os.ReadFile(filePath); err == nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("%s is not valid JSON: %w", filePath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := edit(file); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	check := DefaultSettings()
+	if err := json.Unmarshal(data, &check); err != nil {
+		return invalidSettingsError{err}
+	}
+	return writeFile(filePath, append(data, '\n'))
+}
+
+// settingsTree returns the settings as their JSON object.
+func settingsTree(s Settings) (map[string]any, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	err = json.Unmarshal(data, &tree)
+	return tree, err
+}
+
+// knownKey reports whether the dotted key parts name a setting in the defaults tree.
+// Below a per-tool map, empty in the defaults, any tool name is known; its value is
+// checked when the file is validated.
+func knownKey(defaults map[string]any, parts []string) bool {
+	var value any = defaults
+	for _, part := range parts {
+		if value == nil {
+			return true // A map left null in the defaults, like tags
+		}
+		node, ok := value.(map[string]any)
+		if !ok {
+			return false // Below a value that isn't an object, like ranking.order.x
+		}
+		if len(node) == 0 {
+			return true
+		}
+		if value, ok = node[part]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupKey finds a dotted key in a JSON object.
+func lookupKey(tree map[string]any, key string) (any, bool) {
+	var value any = tree
+	for _, part := range strings.Split(key, ".") {
+		node, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = node[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// CheckSettingsFile reports why the settings file doesn't load, e.g. after it was edited
+// by hand. A missing file is fine: it leaves the defaults.
+func CheckSettingsFile() error {
+	filePath := SettingsFilePath()
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	settings := DefaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+	for i, d := range settings.Tools {
+		if d.Name == "" || d.Command == "" {
+			return fmt.Errorf("%s: custom tool %d (%q) needs a name and a command", filePath, i+1, d.Name)
+		}
+	}
+	return nil
+}
+
+// EditorCommand returns the command that opens path in the user's editor: $VISUAL, then
+// $EDITOR, then vi (notepad on Windows). The editor can have arguments ("code --wait").
+func EditorCommand(path string) (*exec.Cmd, error) {
+	editor := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"))
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	args, err := tool.SplitArgs(editor)
+	if err != nil || len(args) == 0 {
+		return nil, fmt.Errorf("invalid editor %q", editor)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return exec.Command(args[0], append(args[1:], path)...), nil
+}
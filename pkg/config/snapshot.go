@@ -0,0 +1,95 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// ToolSnapshot is the last known state of a single tool.
+type ToolSnapshot struct {
+	Installed bool          `json:"installed"`
+	Version   string        `json:"version,omitempty"`
+	Balance   *tool.Balance `json:"balance,omitempty"`
+}
+
+// RegistrySnapshot is the last known state of all tools, used to render the list
+// instantly on startup while the real state is re-validated in the background.
+type RegistrySnapshot struct {
+	SavedAt       time.Time               `json:"saved_at"`
+	BalanceSchema int                     `json:"balance_schema"` // tool.BalanceSchema of the balances
+	Tools         map[string]ToolSnapshot `json:"tools"`
+}
+
+// getSnapshotFilePath returns the path to the registry snapshot file
+func getSnapshotFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-registry.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "cache", "registry.json")
+}
+
+// TakeSnapshot captures the current state of the given tools.
+func TakeSnapshot(tools []*tool.Tool) RegistrySnapshot {
+	snap := RegistrySnapshot{
+		SavedAt:       time.Now(),
+		BalanceSchema: tool.BalanceSchema,
+		Tools:         make(map[string]ToolSnapshot, len(tools)),
+	}
+	for _, t := range tools {
+		snap.Tools[t.Name] = ToolSnapshot{
+			Installed: t.IsInstalled(),
+			Version:   t.Version,
+			Balance:   t.Balance,
+		}
+	}
+	return snap
+}
+
+// ApplySnapshot seeds tools in the registry with their last known state.
+// Tools missing from the snapshot are left untouched, and balances saved by a version
+// whose balances mean something else are left out.
+func ApplySnapshot(registry *tool.Registry, snap RegistrySnapshot) {
+	for name, state := range snap.Tools {
+		t := registry.Get(name)
+		if t == nil {
+			continue
+		}
+		t.SetInstalled(state.Installed)
+		t.Version = state.Version
+		if snap.BalanceSchema == tool.BalanceSchema {
+			t.Balance = state.Balance
+		}
+	}
+}
+
+// LoadSnapshot loads the last saved registry snapshot from disk
+func LoadSnapshot() (RegistrySnapshot, bool) {
+	filePath := getSnapshotFilePath()
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		// No snapshot yet (first run)
+		return RegistrySnapshot{}, false
+	}
+
+	var snap RegistrySnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		cache.Quarantine(filePath, err)
+		return RegistrySnapshot{}, false
+	}
+	return snap, true
+}
+
+// SaveSnapshot saves a registry snapshot to disk
+func SaveSnapshot(snap RegistrySnapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(getSnapshotFilePath(), data)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+)
+
+// updateCheckTTL is how long a looked-up latest version is trusted before checking again.
+const updateCheckTTL = 24 * time.Hour
+
+// UpdateCheck is the latest version of a tool found by an update check.
+type UpdateCheck struct {
+	Latest    string    `json:"latest"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Fresh reports whether the check is recent enough to skip looking the version up again.
+func (c UpdateCheck) Fresh(now time.Time) bool {
+	return now.Sub(c.CheckedAt) < updateCheckTTL
+}
+
+// UpdateChecks holds the last update check per tool name.
+type UpdateChecks map[string]UpdateCheck
+
+// getUpdatesFilePath returns the path to the update check cache file
+func getUpdatesFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-updates.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "cache", "updates.json")
+}
+
+// LoadUpdateChecks loads the cached update checks from disk
+func LoadUpdateChecks() UpdateChecks {
+	checks := make(UpdateChecks)
+
+	filePath := getUpdatesFilePath()
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		// Nothing checked yet
+		return checks
+	}
+	if err := json.Unmarshal(data, &checks); err != nil {
+		cache.Quarantine(filePath, err)
+		return make(UpdateChecks)
+	}
+	return checks
+}
+
+// SaveUpdateChecks saves update checks to disk
+func SaveUpdateChecks(checks UpdateChecks) error {
+	data, err := json.MarshalIndent(checks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(getUpdatesFilePath(), data)
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// sessionsLimit is how many of its latest sessions usage.json keeps per tool.
+const sessionsLimit = 20
+
+// ToolUsage is what usage.json keeps about one tool.
+type ToolUsage struct {
+	LastUsed     time.Time `json:"last_used,omitzero"` // Zero after its recent use was cleared
+	Launches     int       `json:"launches"`
+	TotalSeconds float64   `json:"total_seconds"`      // Time spent in the sessions with a known duration
+	Sessions     []Session `json:"sessions,omitempty"` // Latest sessions, oldest first
+}
+
+// LastSession returns the latest session of the tool, if any.
+func (u ToolUsage) LastSession() (Session, bool) {
+	if len(u.Sessions) == 0 {
+		return Session{}, false
+	}
+	return u.Sessions[len(u.Sessions)-1], true
+}
+
+// Total returns the time spent in the tool's sessions with a known duration.
+func (u ToolUsage) Total() time.Duration {
+	return time.Duration(u.TotalSeconds * float64(time.Second))
+}
+
+// Session is one run of a tool.
+type Session struct {
+	Start    time.Time `json:"start"`
+	Seconds  float64   `json:"seconds"`             // How long the tool ran; 0 if unknown (e.g., the launcher exec'd the tool)
+	ExitCode *int      `json:"exit_code,omitempty"` // nil if unknown; -1 if the tool didn't exit normally
+	Exec     bool      `json:"exec,omitempty"`      // The tool replaced the launcher (exec_replace), so nothing timed it
+}
+
+// FinishedSession returns the session of a tool that ran from start for d and exited with
+// err, the error of running it.
+func FinishedSession(start time.Time, d time.Duration, err error) Session {
+	code := tool.ExitCode(err)
+	return Session{Start: start, Seconds: d.Seconds(), ExitCode: &code}
+}
+
+// Duration returns how long the session lasted, 0 if unknown.
+func (s Session) Duration() time.Duration {
+	return time.Duration(s.Seconds * float64(time.Second))
+}
+
+// getUsageFilePath returns the path to the usage data file
+func getUsageFilePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli-usage.json"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "usage.json")
+}
+
+// LoadUsageStats loads the usage of every tool that was ever launched from disk.
+// Files written by older versions, with only a last use time per tool, are read too.
+func LoadUsageStats() map[string]ToolUsage {
+	stats := make(map[string]ToolUsage)
+
+	filePath := getUsageFilePath()
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		// File doesn't exist yet, return empty map
+		return stats
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		cache.Quarantine(filePath, err)
+		return stats
+	}
+	for toolName, value := range raw {
+		var usage ToolUsage
+		var lastUsed time.Time
+		if err := json.Unmarshal(value, &lastUsed); err == nil {
+			usage.LastUsed = lastUsed // Old format: "codex": "2026-02-10T16:22:00Z"
+		} else if err := json.Unmarshal(value, &usage); err != nil {
+			continue
+		}
+		stats[toolName] = usage
+	}
+	return stats
+}
+
+// saveUsageStats saves the usage of every tool to disk
+func saveUsageStats(stats map[string]ToolUsage) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(getUsageFilePath(), data)
+}
+
+// updateToolUsage applies update to the usage of a single tool on disk
+func updateToolUsage(toolName string, update func(*ToolUsage)) error {
+	stats := LoadUsageStats()
+	usage := stats[toolName]
+	update(&usage)
+	stats[toolName] = usage
+	return saveUsageStats(stats)
+}
+
+// LoadToolUsage loads the last usage times for tools from disk
+func LoadToolUsage() map[string]time.Time {
+	usage := make(map[string]time.Time)
+	for toolName, u := range LoadUsageStats() {
+		if !u.LastUsed.IsZero() {
+			usage[toolName] = u.LastUsed
+		}
+	}
+	return usage
+}
+
+// RecordToolLaunch counts a launch of a tool at t, which is also its last use
+func RecordToolLaunch(toolName string, t time.Time) error {
+	return updateToolUsage(toolName, func(u *ToolUsage) {
+		u.LastUsed = t
+		u.Launches++
+	})
+}
+
+// RecordSession adds a finished session of a tool, dropping its oldest sessions past
+// the limit. Its duration is also kept on its launch in the history, for the time spent
+// per project.
+func RecordSession(toolName string, s Session) error {
+	err := updateToolUsage(toolName, func(u *ToolUsage) {
+		u.TotalSeconds += s.Seconds
+		u.Sessions = append(u.Sessions, s)
+		if len(u.Sessions) > sessionsLimit {
+			u.Sessions = u.Sessions[len(u.Sessions)-sessionsLimit:]
+		}
+	})
+	if err != nil || s.Seconds <= 0 {
+		return err
+	}
+	return recordLaunchSeconds(toolName, s.Start, s.Seconds)
+}
+
+// RecordToolUsage updates the last usage time of a single tool on disk, e.g. to restore
+// it after ClearToolUsage; it doesn't count as a launch
+func RecordToolUsage(toolName string, t time.Time) error {
+	return updateToolUsage(toolName, func(u *ToolUsage) {
+		u.LastUsed = t
+	})
+}
+
+// ClearToolUsage forgets when a single tool was last used, keeping its launch counts
+func ClearToolUsage(toolName string) error {
+	stats := LoadUsageStats()
+	usage, ok := stats[toolName]
+	if !ok || usage.LastUsed.IsZero() {
+		return nil
+	}
+	usage.LastUsed = time.Time{}
+	stats[toolName] = usage
+	return saveUsageStats(stats)
+}
@@ -0,0 +1,275 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// DefaultChecks returns the standard set of checks for the given registry, whose tools
+// are launched with opts.
+func DefaultChecks(registry *tool.Registry, opts tool.LaunchOptions) []Check {
+	checks := []Check{
+		{Name: "path", Run: checkPath},
+		{Name: "terminal", Run: checkTerminal},
+		{Name: "launch", Run: func(ctx context.Context) []Finding { return checkLaunch(opts) }},
+		{Name: "data", Run: func(ctx context.Context) []Finding { return checkDataFiles(dataDir()) }},
+	}
+	for _, t := range registry.List() {
+		t := t
+		checks = append(checks, Check{
+			Name: "tool:" + t.Name,
+			Run:  func(ctx context.Context) []Finding { return checkTool(ctx, t) },
+		})
+		if factory, ok := provider.Lookup(t.Name); ok {
+			if checker, ok := factory().(provider.CredentialsChecker); ok {
+				checks = append(checks, Check{
+					Name: "auth:" + t.Name,
+					Run:  func(ctx context.Context) []Finding { return checkCredentials(t, checker) },
+				})
+			}
+		}
+	}
+	return checks
+}
+
+// dataDir returns the directory the launcher keeps its settings and data in
+func dataDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli"
+	}
+	return filepath.Join(homeDir, ".amazing-cli")
+}
+
+// checkPath reports missing and duplicate PATH entries.
+func checkPath(ctx context.Context) []Finding {
+	entries := filepath.SplitList(os.Getenv("PATH"))
+	if len(entries) == 0 {
+		return []Finding{{Severity: SeverityError, Message: "PATH is empty", Fix: "set PATH in your shell profile"}}
+	}
+
+	var findings []Finding
+	seen := make(map[string]bool)
+	for _, dir := range entries {
+		if dir == "" {
+			continue
+		}
+		if seen[dir] {
+			findings = append(findings, Finding{Severity: SeverityInfo, Message: fmt.Sprintf("duplicate PATH entry: %s", dir)})
+			continue
+		}
+		seen[dir] = true
+		if _, err := os.Stat(dir); err != nil {
+			findings = append(findings, Finding{Severity: SeverityWarn, Message: fmt.Sprintf("PATH entry does not exist: %s", dir)})
+		}
+	}
+	if len(findings) == 0 {
+		findings = append(findings, Finding{Severity: SeverityInfo, Message: fmt.Sprintf("%d entries look healthy", len(seen))})
+	}
+	return findings
+}
+
+// checkTool reports the tool's binary, version and dependency status.
+// Unmet dependencies are errors for installed tools (they won't run) and warnings otherwise.
+func checkTool(ctx context.Context, t *tool.Tool) []Finding {
+	var findings []Finding
+
+	installed := false
+	if path, err := t.Path(); err == nil {
+		installed = true
+		version := "version unknown"
+		if t.Version = t.DetectVersion(ctx); t.Version != "" {
+			version = t.InstalledVersion()
+		}
+		f := Finding{Severity: SeverityInfo, Message: fmt.Sprintf("installed at %s (%s)", path, version)}
+		if _, err := tool.LookPath(t.Command); err != nil {
+			// Found in one of the tool's SearchDirs, e.g. ~/.local/bin after a pipx install
+			f.Message += " (not on PATH)"
+			f.Fix = fmt.Sprintf("add %s to PATH to run %s outside amazing", filepath.Dir(path), t.Command)
+		}
+		findings = append(findings, f)
+	} else if path := t.WindowsSidePath(); path != "" {
+		// WSL: the Windows install is on PATH through interop but doesn't run properly from Linux
+		findings = append(findings, Finding{
+			Severity: SeverityWarn,
+			Message:  fmt.Sprintf("only installed on the Windows side (%s)", path),
+			Fix:      "install the Linux version from the menu, or launch the Windows one from a Windows drive (/mnt/c/...)",
+		})
+	} else {
+		f := Finding{Severity: SeverityInfo, Message: "not installed"}
+		if !t.SupportsPlatform() {
+			// Nothing to fix on this OS
+			return []Finding{{Severity: SeverityInfo, Message: "not available on this OS (" + t.PlatformNote() + ")"}}
+		}
+		if t.InstallURL != "" {
+			f.Fix = "install from " + t.InstallURL
+		}
+		findings = append(findings, f)
+	}
+
+	for _, status := range t.CheckDependencies() {
+		if status.Satisfied {
+			continue
+		}
+		severity := SeverityWarn
+		if installed {
+			severity = SeverityError
+		}
+		findings = append(findings, Finding{Severity: severity, Message: status.Message()})
+	}
+
+	if msg := t.NodeVersionWarning(); msg != "" {
+		findings = append(findings, Finding{Severity: SeverityWarn, Message: msg})
+	}
+	return findings
+}
+
+// checkCredentials reports whether the login an installed tool's balance is fetched with
+// can be read.
+func checkCredentials(t *tool.Tool, checker provider.CredentialsChecker) []Finding {
+	if _, err := t.Path(); err != nil {
+		return nil // Nothing to log in to yet
+	}
+	path, err := checker.CheckCredentials()
+	if err == nil {
+		return []Finding{{Severity: SeverityInfo, Message: "logged in (" + tool.ShortenHome(path) + ")"}}
+	}
+	f := Finding{
+		Severity: SeverityWarn,
+		Message:  fmt.Sprintf("can't use the login in %s: %v", tool.ShortenHome(path), err),
+		Fix:      fmt.Sprintf("log in to %s to see its balance", t.DisplayName),
+	}
+	if len(t.LoginArgs) > 0 {
+		f.Fix = fmt.Sprintf("run %s %s to see its balance", t.Command, tool.JoinArgs(t.LoginArgs))
+	}
+	return []Finding{f}
+}
+
+// colorProfiles describes what the TUI's colors look like in each terminal color profile
+var colorProfiles = map[termenv.Profile]string{
+	termenv.TrueColor: "true color",
+	termenv.ANSI256:   "256 colors",
+	termenv.ANSI:      "16 colors, themes are approximated",
+	termenv.Ascii:     "no colors",
+}
+
+// checkTerminal reports whether the terminal can show the TUI: a terminal on stdin and
+// stdout, a usable $TERM, its colors and a UTF-8 locale for the borders and bars.
+func checkTerminal(ctx context.Context) []Finding {
+	var findings []Finding
+	if !isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+		findings = append(findings, Finding{Severity: SeverityInfo, Message: "stdout is not a terminal: the menu needs one, tools can still be launched by name"})
+	}
+	if runtime.GOOS != "windows" {
+		switch term := os.Getenv("TERM"); term {
+		case "", "dumb":
+			findings = append(findings, Finding{
+				Severity: SeverityWarn,
+				Message:  fmt.Sprintf("TERM=%q can't draw the menu", term),
+				Fix:      "set TERM to your terminal's type, e.g. xterm-256color",
+			})
+		}
+		if locale := locale(); !isUTF8(locale) {
+			findings = append(findings, Finding{
+				Severity: SeverityWarn,
+				Message:  fmt.Sprintf("locale %q isn't UTF-8: borders and balance bars may be garbled", locale),
+				Fix:      "set LANG to a UTF-8 locale, e.g. en_US.UTF-8",
+			})
+		}
+	}
+	f := Finding{Severity: SeverityInfo, Message: "colors: " + colorProfiles[termenv.NewOutput(os.Stdout).Profile]}
+	if os.Getenv("NO_COLOR") != "" {
+		f.Message += " (NO_COLOR is set)"
+	}
+	return append(findings, f)
+}
+
+// checkLaunch notes what launching with opts gives up: an exec-replaced tool leaves nothing
+// behind to time its session, record its exit code or show a post-mortem.
+func checkLaunch(opts tool.LaunchOptions) []Finding {
+	if !opts.ReplacesProcess() {
+		return nil
+	}
+	return []Finding{{
+		Severity: SeverityInfo,
+		Message:  "exec_replace is on: session length, exit codes and the post-mortem menu are unavailable for launched tools",
+		Fix:      "amazing config set exec_replace false to record them",
+	}}
+}
+
+// locale returns the locale that decides the character set, as the C library picks it.
+func locale() string {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return "C"
+}
+
+// isUTF8 reports whether the locale uses UTF-8, e.g. "en_US.UTF-8" or "C.utf8".
+func isUTF8(locale string) bool {
+	l := strings.ToLower(locale)
+	return strings.Contains(l, "utf-8") || strings.Contains(l, "utf8")
+}
+
+// checkDataFiles reports the JSON files under dir that can't be decoded, and the
+// corrupted ones the launcher already moved aside.
+func checkDataFiles(dir string) []Finding {
+	var findings []Finding
+	valid, moved := 0, 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir() && d.Name() == "logs":
+			return filepath.SkipDir
+		case strings.HasSuffix(path, ".json.bak"):
+			moved++
+		case strings.HasSuffix(path, ".json"):
+			data, err := os.ReadFile(path)
+			if err == nil && json.Valid(data) {
+				valid++
+				return nil
+			}
+			f := Finding{Severity: SeverityWarn, Message: tool.ShortenHome(path) + " is not valid JSON"}
+			if rel, _ := filepath.Rel(dir, path); rel == "config.json" {
+				f.Fix = "fix or delete it: the default settings are used until then"
+			} else {
+				f.Fix = "delete it, or start amazing to move it aside to " + filepath.Base(path) + ".bak"
+			}
+			findings = append(findings, f)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return []Finding{{Severity: SeverityInfo, Message: "nothing saved yet (" + tool.ShortenHome(dir) + ")"}}
+	}
+	if err != nil {
+		return []Finding{{Severity: SeverityWarn, Message: fmt.Sprintf("can't read %s: %v", tool.ShortenHome(dir), err)}}
+	}
+	if moved > 0 {
+		findings = append(findings, Finding{
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("%d corrupted file(s) were moved aside to *.json.bak", moved),
+			Fix:      "delete them once you no longer need them",
+		})
+	}
+	if len(findings) == 0 {
+		findings = append(findings, Finding{Severity: SeverityInfo, Message: fmt.Sprintf("%d data files are valid", valid)})
+	}
+	return findings
+}
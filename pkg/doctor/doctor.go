@@ -0,0 +1,154 @@
+// Package doctor runs diagnostic checks for the launcher and its tools.
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/muesli/termenv"
+)
+
+// Severity classifies how serious a finding is.
+type Severity string
+
+const (
+	SeverityInfo  Severity = "info"
+	SeverityWarn  Severity = "warn"
+	SeverityError Severity = "error"
+)
+
+// Finding is a single result reported by a check.
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Fix      string   `json:"fix,omitempty"` // Suggested remedy, if any
+}
+
+// Check is a named diagnostic that produces zero or more findings.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) []Finding
+}
+
+// Run executes checks concurrently using a pool of workers and returns all findings.
+// Findings are returned grouped in the order the checks were given, so output is stable.
+func Run(ctx context.Context, checks []Check, workers int) []Finding {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([][]Finding, len(checks))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				findings := checks[i].Run(ctx)
+				// Fill in the check name so individual checks don't have to
+				for j := range findings {
+					if findings[j].Check == "" {
+						findings[j].Check = checks[i].Name
+					}
+				}
+				results[i] = findings
+			}
+		}()
+	}
+
+	for i := range checks {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var all []Finding
+	for _, findings := range results {
+		all = append(all, findings...)
+	}
+	return all
+}
+
+// HasErrors reports whether any finding has error severity.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONSchema is the version of the shape WriteJSON writes, {"schema_version", "findings":
+// [Finding]}. Fields are only added within a version; removing or renaming one, or
+// changing what it means, takes a new version. testdata/findings.golden has an example.
+const JSONSchema = 1
+
+// WriteJSON writes findings as indented JSON, see JSONSchema.
+func WriteJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	out := struct {
+		SchemaVersion int       `json:"schema_version"`
+		Findings      []Finding `json:"findings"`
+	}{SchemaVersion: JSONSchema, Findings: findings}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// WriteText writes a human-readable report followed by a one-line summary, colored
+// when w is a terminal (and NO_COLOR isn't set).
+func WriteText(w io.Writer, findings []Finding) error {
+	out := termenv.NewOutput(w)
+	counts := make(map[Severity]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+		icon := out.String(severityIcon(f.Severity)).Foreground(out.Color(severityColor(f.Severity)))
+		if _, err := fmt.Fprintf(w, "%s %-20s %s\n", icon, f.Check, f.Message); err != nil {
+			return err
+		}
+		if f.Fix != "" {
+			fix := out.String("→ " + f.Fix).Foreground(out.Color("6"))
+			if _, err := fmt.Fprintf(w, "  %-20s %s\n", "", fix); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d error(s), %d warning(s), %d info\n",
+		counts[SeverityError], counts[SeverityWarn], counts[SeverityInfo])
+	return err
+}
+
+// severityColor returns the ANSI color of a severity's icon
+func severityColor(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "1" // Red
+	case SeverityWarn:
+		return "3" // Yellow
+	default:
+		return "8" // Gray
+	}
+}
+
+func severityIcon(s Severity) string {
+	switch s {
+	case SeverityError:
+		return "✗"
+	case SeverityWarn:
+		return "⚠"
+	default:
+		return "•"
+	}
+}
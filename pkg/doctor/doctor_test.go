@@ -0,0 +1,194 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestRun_PreservesCheckOrder(t *testing.T) {
+	checks := []Check{
+		{Name: "slow", Run: func(ctx context.Context) []Finding {
+			time.Sleep(20 * time.Millisecond)
+			return []Finding{{Severity: SeverityInfo, Message: "first"}}
+		}},
+		{Name: "fast", Run: func(ctx context.Context) []Finding {
+			return []Finding{{Severity: SeverityWarn, Message: "second"}}
+		}},
+		{Name: "empty", Run: func(ctx context.Context) []Finding { return nil }},
+		{Name: "named", Run: func(ctx context.Context) []Finding {
+			return []Finding{{Check: "custom", Severity: SeverityError, Message: "third"}}
+		}},
+	}
+
+	findings := Run(context.Background(), checks, 4)
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(findings))
+	}
+
+	wantChecks := []string{"slow", "fast", "custom"}
+	for i, want := range wantChecks {
+		if findings[i].Check != want {
+			t.Errorf("finding %d: expected check %q, got %q", i, want, findings[i].Check)
+		}
+	}
+}
+
+func TestHasErrors(t *testing.T) {
+	if HasErrors([]Finding{{Severity: SeverityInfo}, {Severity: SeverityWarn}}) {
+		t.Error("HasErrors() should be false without error findings")
+	}
+	if !HasErrors([]Finding{{Severity: SeverityInfo}, {Severity: SeverityError}}) {
+		t.Error("HasErrors() should be true with an error finding")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	findings := []Finding{{Check: "path", Severity: SeverityWarn, Message: "PATH entry does not exist: /nope"}}
+	if err := WriteJSON(&buf, findings); err != nil {
+		t.Fatalf("WriteJSON() error: %v", err)
+	}
+
+	var decoded struct {
+		SchemaVersion int       `json:"schema_version"`
+		Findings      []Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if decoded.SchemaVersion != JSONSchema || len(decoded.Findings) != 1 || decoded.Findings[0].Severity != SeverityWarn {
+		t.Errorf("unexpected decoded findings: %+v", decoded)
+	}
+	golden(t, "findings.golden", buf.Bytes())
+
+	buf.Reset()
+	if err := WriteJSON(&buf, nil); err != nil {
+		t.Fatalf("WriteJSON(nil) error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"findings": []`) {
+		t.Errorf("expected an empty array for no findings, got %q", buf.String())
+	}
+}
+
+func TestCheckDataFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.json":               `{"theme": "dracula"`,
+		"usage.json":                `{"codex": {"launches": 3}}`,
+		"launches.json.bak":         `[{"tool": "co`,
+		"cache/codex/balance.json":  `{"stored_at": "`,
+		"cache/gemini/balance.json": `{"version": 1, "value": null}`,
+		"logs/amazing.log":          "not JSON",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var warnings []string
+	moved := false
+	for _, f := range checkDataFiles(dir) {
+		switch {
+		case f.Severity == SeverityWarn:
+			warnings = append(warnings, f.Message)
+		case strings.Contains(f.Message, "moved aside"):
+			moved = true
+		}
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "balance.json") || !strings.Contains(warnings[1], "config.json") {
+		t.Errorf("warnings = %q, want the codex balance cache and config.json", warnings)
+	}
+	if !moved {
+		t.Error("the file moved aside to .bak should be reported")
+	}
+
+	if findings := checkDataFiles(filepath.Join(dir, "missing")); len(findings) != 1 || findings[0].Severity != SeverityInfo {
+		t.Errorf("checkDataFiles() of a missing dir = %+v, want one info finding", findings)
+	}
+}
+
+func TestIsUTF8(t *testing.T) {
+	tests := map[string]bool{"en_US.UTF-8": true, "C.utf8": true, "zh_CN.UTF-8": true, "C": false, "en_US.ISO-8859-1": false}
+	for locale, want := range tests {
+		if got := isUTF8(locale); got != want {
+			t.Errorf("isUTF8(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
+
+func TestCheckLaunch(t *testing.T) {
+	if findings := checkLaunch(tool.LaunchOptions{}); len(findings) != 0 {
+		t.Errorf("checkLaunch() without exec_replace = %+v, want nothing", findings)
+	}
+	findings := checkLaunch(tool.LaunchOptions{Exec: true})
+	if runtime.GOOS == "windows" {
+		if len(findings) != 0 {
+			t.Errorf("checkLaunch() on Windows = %+v, want nothing: it never exec-replaces", findings)
+		}
+		return
+	}
+	if len(findings) != 1 || findings[0].Severity != SeverityInfo || !strings.Contains(findings[0].Message, "session length") {
+		t.Errorf("checkLaunch() with exec_replace = %+v, want one info finding about the session length", findings)
+	}
+	if findings := checkLaunch(tool.LaunchOptions{Exec: true, AltScreen: true}); len(findings) != 0 {
+		t.Errorf("checkLaunch() with alt_screen = %+v, want nothing: the launcher waits for the tool", findings)
+	}
+}
+
+// fakeLogin is a provider.CredentialsChecker that fails with err
+type fakeLogin struct{ err error }
+
+func (f fakeLogin) CheckCredentials() (string, error) { return "/home/me/.codex/auth.json", f.err }
+
+func TestCheckCredentials(t *testing.T) {
+	installed := &tool.Tool{Name: "sh", DisplayName: "Shell", Command: "sh", LoginArgs: []string{"login"}}
+	if findings := checkCredentials(installed, fakeLogin{}); len(findings) != 1 || findings[0].Severity != SeverityInfo {
+		t.Errorf("checkCredentials() when logged in = %+v, want one info finding", findings)
+	}
+	findings := checkCredentials(installed, fakeLogin{errors.New("failed to read auth file")})
+	if len(findings) != 1 || findings[0].Severity != SeverityWarn || findings[0].Fix != "run sh login to see its balance" {
+		t.Errorf("checkCredentials() without a login = %+v, want a warning suggesting the login command", findings)
+	}
+	missing := &tool.Tool{Name: "missing", Command: "amazing-cli-test-missing-binary"}
+	if findings := checkCredentials(missing, fakeLogin{errors.New("no login")}); len(findings) != 0 {
+		t.Errorf("checkCredentials() of an uninstalled tool = %+v, want nothing", findings)
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with testdata/name, or rewrites it with -update; the file pins the
+// JSONSchema shape.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test -update if the change is intended):\n%s", path, got)
+	}
+}
@@ -0,0 +1,75 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+// claudeLine is one entry of a Claude Code session log (~/.claude/projects/*/<session>.jsonl).
+type claudeLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	SessionID string    `json:"sessionId"`
+	Cwd       string    `json:"cwd"`
+	Message   *struct {
+		ID    string `json:"id"`
+		Usage *struct {
+			InputTokens         int64 `json:"input_tokens"`
+			OutputTokens        int64 `json:"output_tokens"`
+			CacheCreationTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// claudeHomeDir returns the Claude Code data directory: $CLAUDE_CONFIG_DIR, or ~/.claude by default
+func claudeHomeDir() (string, error) {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".claude"), nil
+}
+
+// Claude imports the Claude Code sessions as launches of "claude".
+func Claude() ([]config.Launch, error) {
+	home, err := claudeHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return importLogs(filepath.Join(home, "projects"), parseClaudeLog)
+}
+
+// parseClaudeLog turns a Claude Code session log into a launch: when and where the session
+// started, and the tokens of all its responses. Malformed lines are skipped.
+func parseClaudeLog(path string, r io.Reader) (config.Launch, bool) {
+	l := config.Launch{Tool: "claude", Session: sessionID(path)}
+	counted := make(map[string]bool) // A response is logged once per content block
+	eachLine(r, func(data []byte) {
+		var line claudeLine
+		if json.Unmarshal(data, &line) != nil || line.Timestamp.IsZero() {
+			return
+		}
+		if l.At.IsZero() || line.Timestamp.Before(l.At) {
+			l.At = line.Timestamp
+		}
+		if l.Dir == "" {
+			l.Dir = line.Cwd
+		}
+		if line.SessionID != "" {
+			l.Session = line.SessionID
+		}
+		if m := line.Message; m != nil && m.Usage != nil && (m.ID == "" || !counted[m.ID]) {
+			counted[m.ID] = true
+			l.Tokens += m.Usage.InputTokens + m.Usage.OutputTokens + m.Usage.CacheCreationTokens + m.Usage.CacheReadTokens
+		}
+	})
+	return l, !l.At.IsZero()
+}
@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+// codexLine is one entry of a Codex session log (~/.codex/sessions/**/rollout-*.jsonl).
+type codexLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	Payload   struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`  // session_meta
+		Cwd  string `json:"cwd"` // session_meta
+		Info *struct {
+			LastTokenUsage struct {
+				TotalTokens int64 `json:"total_tokens"`
+			} `json:"last_token_usage"`
+		} `json:"info"` // token_count events
+	} `json:"payload"`
+}
+
+// codexHomeDir returns the Codex data directory: $CODEX_HOME, or ~/.codex by default
+func codexHomeDir() (string, error) {
+	if dir := os.Getenv("CODEX_HOME"); dir != "" {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".codex"), nil
+}
+
+// Codex imports the Codex sessions as launches of "codex".
+func Codex() ([]config.Launch, error) {
+	home, err := codexHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return importLogs(filepath.Join(home, "sessions"), parseCodexLog)
+}
+
+// parseCodexLog turns a Codex session log into a launch: when and where the session
+// started, and the tokens of all its turns. Malformed lines are skipped.
+func parseCodexLog(path string, r io.Reader) (config.Launch, bool) {
+	l := config.Launch{Tool: "codex", Session: sessionID(path)}
+	eachLine(r, func(data []byte) {
+		var line codexLine
+		if json.Unmarshal(data, &line) != nil || line.Timestamp.IsZero() {
+			return
+		}
+		if l.At.IsZero() || line.Timestamp.Before(l.At) {
+			l.At = line.Timestamp
+		}
+		switch {
+		case line.Type == "session_meta":
+			if line.Payload.ID != "" {
+				l.Session = line.Payload.ID
+			}
+			l.Dir = line.Payload.Cwd
+		case line.Payload.Type == "token_count" && line.Payload.Info != nil:
+			l.Tokens += line.Payload.Info.LastTokenUsage.TotalTokens
+		}
+	})
+	return l, !l.At.IsZero()
+}
@@ -0,0 +1,76 @@
+// Package importer backfills the launch history from the agents' own session logs, so the
+// history and stats cover the time before amazing-cli was adopted.
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+// Source imports the sessions of one agent as launches.
+type Source struct {
+	Tool   string                          // Name of the tool the sessions belong to
+	Import func() ([]config.Launch, error) // Returns the sessions found, oldest first
+}
+
+// Sources are the agents whose session logs can be imported.
+var Sources = []Source{
+	{Tool: "claude", Import: Claude},
+	{Tool: "codex", Import: Codex},
+}
+
+// importLogs parses every .jsonl file under root into a launch, oldest first.
+// Files that hold no session are skipped, and a missing root means no sessions.
+func importLogs(root string, parse func(path string, r io.Reader) (config.Launch, bool)) ([]config.Launch, error) {
+	var launches []config.Launch
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".jsonl") {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer f.Close()
+		if l, ok := parse(path, f); ok {
+			launches = append(launches, l)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	sort.SliceStable(launches, func(i, j int) bool { return launches[i].At.Before(launches[j].At) })
+	return launches, nil
+}
+
+// eachLine calls fn with every line of r until the end or a read error.
+func eachLine(r io.Reader, fn func(line []byte)) {
+	reader := bufio.NewReader(r)
+	for {
+		// Lines can be very long (tool output), so don't use a bufio.Scanner
+		data, err := reader.ReadBytes('\n')
+		if len(data) > 0 {
+			fn(data)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// sessionID returns the log file's name without its extension.
+func sessionID(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
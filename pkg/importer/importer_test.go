@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const claudeFixture = `{"type":"summary","summary":"Fix login"}
+{"type":"user","timestamp":"2026-01-05T09:00:00.000Z","sessionId":"c1","cwd":"/src/api","message":{"role":"user"}}
+{"type":"assistant","timestamp":"2026-01-05T09:00:03.000Z","sessionId":"c1","cwd":"/src/api","message":{"id":"msg_1","usage":{"input_tokens":10,"cache_read_input_tokens":500,"output_tokens":40}}}
+{"type":"assistant","timestamp":"2026-01-05T09:00:04.000Z","sessionId":"c1","cwd":"/src/api","message":{"id":"msg_1","usage":{"input_tokens":10,"cache_read_input_tokens":500,"output_tokens":40}}}
+not json
+{"type":"assistant","timestamp":"2026-01-05T09:05:00.000Z","sessionId":"c1","cwd":"/src/api","message":{"id":"msg_2","usage":{"input_tokens":5,"cache_creation_input_tokens":100,"output_tokens":45}}}`
+
+const codexFixture = `{"timestamp":"2026-01-04T18:30:00.000Z","type":"session_meta","payload":{"id":"x1","cwd":"/src/web"}}
+{"timestamp":"2026-01-04T18:30:01.000Z","type":"turn_context","payload":{"model":"o3"}}
+{"timestamp":"2026-01-04T18:30:05.000Z","type":"event_msg","payload":{"type":"token_count","info":{"last_token_usage":{"total_tokens":100}}}}
+{"timestamp":"2026-01-04T18:31:00.000Z","type":"event_msg","payload":{"type":"token_count","info":null}}
+{"timestamp":"2026-01-04T18:32:00.000Z","type":"event_msg","payload":{"type":"token_count","info":{"last_token_usage":{"total_tokens":250}}}}`
+
+func writeLog(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClaude(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", home)
+
+	// No projects directory yet
+	if got, err := Claude(); err != nil || len(got) != 0 {
+		t.Fatalf("Claude() without sessions = %v, %v; want empty, nil", got, err)
+	}
+
+	writeLog(t, filepath.Join(home, "projects", "-src-api", "c1.jsonl"), claudeFixture)
+	writeLog(t, filepath.Join(home, "projects", "-src-api", "empty.jsonl"), `{"type":"summary"}`)
+	got, err := Claude()
+	if err != nil {
+		t.Fatalf("Claude() error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Claude() = %+v, want the one session with entries", got)
+	}
+	l := got[0]
+	if l.Tool != "claude" || l.Session != "c1" || l.Dir != "/src/api" || !l.At.Equal(time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Claude() = %+v", l)
+	}
+	if l.Tokens != 700 {
+		t.Errorf("Tokens = %d, want 700 with each response counted once", l.Tokens)
+	}
+}
+
+func TestCodex(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CODEX_HOME", home)
+	writeLog(t, filepath.Join(home, "sessions", "2026", "01", "04", "rollout-x1.jsonl"), codexFixture)
+	writeLog(t, filepath.Join(home, "sessions", "2026", "01", "03", "rollout-old.jsonl"),
+		`{"timestamp":"2026-01-03T08:00:00.000Z","type":"turn_context","payload":{"model":"o3"}}`)
+
+	got, err := Codex()
+	if err != nil {
+		t.Fatalf("Codex() error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Codex() = %+v, want 2 sessions", got)
+	}
+	// Oldest first; a log without session_meta falls back to its file name
+	if got[0].Session != "rollout-old" || got[1].Session != "x1" {
+		t.Errorf("sessions = %q, %q", got[0].Session, got[1].Session)
+	}
+	if l := got[1]; l.Tool != "codex" || l.Dir != "/src/web" || l.Tokens != 350 || !l.At.Equal(time.Date(2026, 1, 4, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("Codex() = %+v", l)
+	}
+}
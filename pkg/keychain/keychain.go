@@ -0,0 +1,62 @@
+// Package keychain reads the API keys users save in the system keychain instead of
+// their shell profile: the login keychain on macOS, the Secret Service (GNOME Keyring,
+// KWallet) on Linux.
+package keychain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Service is the service every key is saved under; the account is the tool's name.
+const Service = "amazing-cli"
+
+// ErrNotFound means the keychain holds no key for the account.
+var ErrNotFound = errors.New("not in the keychain")
+
+// Lookup returns the key saved in the keychain for account.
+func Lookup(ctx context.Context, account string) (string, error) {
+	args, err := lookupArgs(runtime.GOOS, account)
+	if err != nil {
+		return "", err
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return "", fmt.Errorf("%s not found, can't read the keychain", args[0])
+	}
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		// Both tools exit non-zero when there is no such item
+		return "", ErrNotFound
+	}
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+// Describe tells where Lookup reads the key for account, for messages.
+func Describe(account string) string {
+	return fmt.Sprintf("keychain (%s/%s)", Service, account)
+}
+
+// lookupArgs returns the command that prints the key for account on goos.
+func lookupArgs(goos, account string) ([]string, error) {
+	switch goos {
+	case "darwin":
+		// Saved with: security add-generic-password -s amazing-cli -a <tool> -w
+		return []string{"security", "find-generic-password", "-s", Service, "-a", account, "-w"}, nil
+	case "linux", "freebsd", "openbsd":
+		// Saved with: secret-tool store --label=<tool> service amazing-cli account <tool>
+		return []string{"secret-tool", "lookup", "service", Service, "account", account}, nil
+	default:
+		return nil, fmt.Errorf("no keychain support on %s", goos)
+	}
+}
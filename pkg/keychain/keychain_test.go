@@ -0,0 +1,53 @@
+package keychain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeKeychain puts a secret-tool on PATH that knows one key, for the deepseek account.
+func fakeKeychain(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the fake keychain is a Linux secret-tool")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1 $2 $3 $4 $5" = "lookup service amazing-cli account deepseek" ] || exit 1
+echo sk-from-keychain
+`
+	if err := os.WriteFile(filepath.Join(dir, "secret-tool"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLookup(t *testing.T) {
+	fakeKeychain(t)
+	tests := []struct {
+		account string
+		want    string
+		wantErr error
+	}{
+		{"deepseek", "sk-from-keychain", nil},
+		{"glm", "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		got, err := Lookup(context.Background(), tt.account)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("Lookup(%q) = %q, %v, want %q, %v", tt.account, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLookupArgs(t *testing.T) {
+	if args, _ := lookupArgs("darwin", "deepseek"); args[0] != "security" || args[len(args)-1] != "-w" {
+		t.Errorf("darwin lookup = %v, want security find-generic-password ... -w", args)
+	}
+	if _, err := lookupArgs("plan9", "deepseek"); err == nil {
+		t.Error("plan9 lookup should fail: it has no keychain")
+	}
+}
@@ -0,0 +1,107 @@
+// Package log writes leveled diagnostics to ~/.amazing-cli/logs/amazing.log, for the
+// errors the launcher recovers from without telling the user (a balance that couldn't be
+// fetched, a cache that couldn't be written). The TUI owns the terminal, so nothing is
+// written to stderr.
+//
+// Warnings and errors are always logged; --verbose or $AMAZING_CLI_DEBUG adds the debug
+// and info messages, e.g. why each Codex usage strategy failed.
+package log
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DebugEnv names the environment variable that turns on debug logging like --verbose
+// (any value but "", "0" or "false").
+const DebugEnv = "AMAZING_CLI_DEBUG"
+
+// maxSize is the size from which the log is moved to amazing.log.1 when it is opened.
+const maxSize = 1 << 20
+
+var (
+	level  = new(slog.LevelVar) // slog.LevelWarn until Setup
+	output = &lazyFile{}
+	logger = slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{Level: level}))
+)
+
+func init() {
+	level.Set(slog.LevelWarn)
+}
+
+// Setup sets what is logged: everything when verbose or $AMAZING_CLI_DEBUG is set,
+// only warnings and errors otherwise.
+func Setup(verbose bool) {
+	env := strings.ToLower(os.Getenv(DebugEnv))
+	if verbose || env != "" && env != "0" && env != "false" {
+		level.Set(slog.LevelDebug)
+	} else {
+		level.Set(slog.LevelWarn)
+	}
+}
+
+// Verbose reports whether debug messages are logged.
+func Verbose() bool {
+	return level.Level() <= slog.LevelDebug
+}
+
+// Path returns the log file.
+func Path() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".amazing-cli.log"
+	}
+	return filepath.Join(homeDir, ".amazing-cli", "logs", "amazing.log")
+}
+
+// Debug logs details for diagnosing a problem; args are key-value pairs, as for slog.
+func Debug(msg string, args ...any) { logger.Debug(msg, args...) }
+
+// Info logs something worth knowing that isn't a problem.
+func Info(msg string, args ...any) { logger.Info(msg, args...) }
+
+// Warn logs an error the launcher recovered from, e.g. by showing a stale balance.
+func Warn(msg string, args ...any) { logger.Warn(msg, args...) }
+
+// Error logs an error that made something fail.
+func Error(msg string, args ...any) { logger.Error(msg, args...) }
+
+// lazyFile opens the log file on the first write, so runs without anything to log
+// don't create it.
+type lazyFile struct {
+	mu   sync.Mutex
+	w    io.Writer
+	path string // Path of the open file
+}
+
+func (f *lazyFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if path := Path(); f.w == nil || f.path != path {
+		if c, ok := f.w.(io.Closer); ok {
+			c.Close() // $HOME changed, e.g. in tests
+		}
+		f.w, f.path = open(path), path
+	}
+	return f.w.Write(p)
+}
+
+// open opens the log file for appending, starting a new one when it has grown past
+// maxSize. Logging is best effort: when the file can't be opened, messages are dropped.
+func open(path string) io.Writer {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return io.Discard
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxSize {
+		_ = os.Rename(path, path+".1")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return io.Discard
+	}
+	return file
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		verbose bool
+		env     string
+		want    bool
+	}{
+		{false, "", false},
+		{true, "", true},
+		{false, "1", true},
+		{false, "0", false},
+		{false, "FALSE", false},
+	}
+	for _, tt := range tests {
+		t.Setenv(DebugEnv, tt.env)
+		Setup(tt.verbose)
+		if got := Verbose(); got != tt.want {
+			t.Errorf("Setup(%v) with $%s=%q: Verbose() = %v, want %v", tt.verbose, DebugEnv, tt.env, got, tt.want)
+		}
+	}
+	Setup(false)
+}
+
+func TestLog(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(DebugEnv, "")
+	Setup(false)
+
+	Debug("not logged by default")
+	if _, err := os.Stat(Path()); !os.IsNotExist(err) {
+		t.Fatalf("the log file should only be created by a message that is logged: %v", err)
+	}
+	Warn("balance fetch failed", "tool", "codex", "err", "timeout")
+	Setup(true)
+	Debug("trying OAuth", "tool", "codex")
+	Setup(false)
+
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		t.Fatalf("reading the log: %v", err)
+	}
+	log := string(data)
+	for _, want := range []string{`level=WARN msg="balance fetch failed" tool=codex err=timeout`, `level=DEBUG msg="trying OAuth"`} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log = %q, want a line with %q", log, want)
+		}
+	}
+	if strings.Contains(log, "not logged by default") {
+		t.Errorf("log = %q, want debug messages left out unless verbose", log)
+	}
+}
@@ -0,0 +1,116 @@
+// Package metrics serves the balances polled by "amazing daemon" in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// contentType is the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler serves /metrics and /healthz from the balance cache, read on every request so
+// the handler always reflects the daemon's last poll. /healthz fails while the cache is stale.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		cache, _ := config.LoadBalanceCache()
+		w.Header().Set("Content-Type", contentType)
+		_ = Write(w, cache)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if cache, _ := config.LoadBalanceCache(); !cache.Fresh(time.Now()) {
+			http.Error(w, "balances are stale", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// metric is one metric family being written.
+type metric struct {
+	name, help string
+	samples    []string
+}
+
+func (m *metric) add(value float64, labels ...string) {
+	var pairs []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", labels[i], labels[i+1]))
+	}
+	sample := m.name
+	if len(pairs) > 0 {
+		sample += "{" + strings.Join(pairs, ",") + "}"
+	}
+	m.samples = append(m.samples, sample+" "+strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// Write writes the balances in cache as Prometheus gauges, ordered by tool name.
+func Write(w io.Writer, cache config.BalanceCache) error {
+	updated := &metric{name: "amazing_balances_updated_timestamp_seconds", help: "When the daemon last finished polling balances."}
+	fetched := &metric{name: "amazing_balance_fetched_timestamp_seconds", help: "When the provider fetched the tool's balance."}
+	percent := &metric{name: "amazing_balance_remaining_percent", help: "Share of the tool's allowance left."}
+	remaining := &metric{name: "amazing_balance_remaining", help: "Amount of an absolute balance left, in its unit."}
+	total := &metric{name: "amazing_balance_total", help: "Allowance per period of an absolute balance, in its unit."}
+	limitPercent := &metric{name: "amazing_limit_remaining_percent", help: "Share of a limit window left."}
+	limitReset := &metric{name: "amazing_limit_resets_timestamp_seconds", help: "When a limit window resets."}
+
+	if !cache.UpdatedAt.IsZero() {
+		updated.add(unixSeconds(cache.UpdatedAt))
+	}
+	names := make([]string, 0, len(cache.Balances))
+	for name := range cache.Balances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b := cache.Balances[name]
+		if b == nil {
+			continue
+		}
+		if !b.FetchedAt.IsZero() {
+			fetched.add(unixSeconds(b.FetchedAt), "tool", name)
+		}
+		if pct, ok := b.RemainingPercent(); ok {
+			percent.add(float64(pct), "tool", name)
+		}
+		if b.Unit != tool.UnitPercent {
+			remaining.add(b.Remaining, "tool", name, "unit", string(b.Unit))
+			if b.Total > 0 {
+				total.add(b.Total, "tool", name, "unit", string(b.Unit))
+			}
+		}
+		for _, l := range b.Limits {
+			if strings.Contains(l.Display, "?") {
+				continue // Unknown usage
+			}
+			limitPercent.add(float64(l.RemainingPercent), "tool", name, "limit", l.Label)
+			if !l.ResetsAt.IsZero() {
+				limitReset.add(unixSeconds(l.ResetsAt), "tool", name, "limit", l.Label)
+			}
+		}
+	}
+
+	for _, m := range []*metric{updated, fetched, percent, remaining, total, limitPercent, limitReset} {
+		if len(m.samples) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s\n", m.name, m.help, m.name, strings.Join(m.samples, "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
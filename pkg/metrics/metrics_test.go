@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestWrite(t *testing.T) {
+	resets := time.Unix(1760000000, 0)
+	cache := config.BalanceCache{
+		UpdatedAt: time.Unix(1750000000, 500_000_000),
+		Interval:  5 * time.Minute,
+		Balances: map[string]*tool.Balance{
+			"codex": {
+				Percentage: 80,
+				Limits: []tool.LimitDetail{
+					{Label: tool.LimitFiveHour, RemainingPercent: 80, Display: "80% left", ResetsAt: resets},
+					{Label: tool.LimitWeekly, RemainingPercent: 0, Display: "?% left"},
+				},
+			},
+			"copilot": {Percentage: 40, Unit: tool.UnitRequests, Remaining: 120, Total: 300},
+		},
+	}
+
+	var b strings.Builder
+	if err := Write(&b, cache); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"# TYPE amazing_balances_updated_timestamp_seconds gauge\namazing_balances_updated_timestamp_seconds 1750000000.5\n",
+		"amazing_balance_remaining_percent{tool=\"codex\"} 80\namazing_balance_remaining_percent{tool=\"copilot\"} 40\n",
+		"amazing_balance_remaining{tool=\"copilot\",unit=\"requests\"} 120\n",
+		"amazing_balance_total{tool=\"copilot\",unit=\"requests\"} 300\n",
+		"amazing_limit_remaining_percent{tool=\"codex\",limit=\"5h\"} 80\n",
+		"amazing_limit_resets_timestamp_seconds{tool=\"codex\",limit=\"5h\"} 1760000000\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Write() output is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `limit="Wk"`) {
+		t.Errorf("Write() reported a limit with unknown usage:\n%s", got)
+	}
+}
+
+func TestHandler_Healthz(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	check := func(want int) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if rec.Code != want {
+			t.Errorf("GET /healthz = %d, want %d", rec.Code, want)
+		}
+	}
+
+	check(http.StatusServiceUnavailable) // No poll yet
+	if err := config.SaveBalanceCache(config.BalanceCache{UpdatedAt: time.Now(), Interval: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	check(http.StatusOK)
+}
@@ -0,0 +1,30 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"linux", "notify-send", []string{"--app-name=amazing-cli", "Limit", `say "hi"`}, false},
+		{"darwin", "osascript", []string{"-e", `display notification "say \"hi\"" with title "Limit"`}, false},
+		{"windows", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args, err := command(tt.goos, "Limit", `say "hi"`)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("command() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("command() = %q %q, want %q %q", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/cache"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/claude"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/codex"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/deepseek"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/gemini"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// 内置的余额查询，新的工具在这里注册
+func init() {
+	Register("codex", func() BalanceFetcher { return codex.NewBalanceFetcher() })
+	Register("gemini", func() BalanceFetcher { return gemini.NewBalanceFetcher() })
+	Register(deepseek.ToolName, func() BalanceFetcher { return deepseek.NewBalanceFetcher("") })
+	RegisterAuth("claude", claude.AuthChecker{})
+}
+
+// SetToolEnv passes the env per tool name of the settings to the built-in providers that
+// read API keys from it.
+func SetToolEnv(env map[string]map[string]string) {
+	deepseek.SetConfigEnv(env[deepseek.ToolName])
+}
+
+// SupportsBalance reports whether FetchBalance can fetch a balance for the tool.
+func SupportsBalance(t *tool.Tool) bool {
+	_, ok := Lookup(t.Name)
+	return ok
+}
+
+// balanceTTL is how long a fetched balance is used before fetching it again.
+const balanceTTL = 5 * time.Minute
+
+// errNoBalance means a fetcher returned no balance or an unknown one.
+var errNoBalance = errors.New("no balance")
+
+// FetchBalance fetches the balance for a tool that supports it, using the cached balance
+// while it is fresh, and the stale one when fetching fails.
+// Returns nil for tools without a registered balance fetcher.
+func FetchBalance(ctx context.Context, t *tool.Tool) *tool.Balance {
+	factory, ok := Lookup(t.Name)
+	if !ok {
+		return nil
+	}
+	return fetchCached(ctx, t.Name, factory)
+}
+
+// CachedBalance returns the balance last fetched for the tool, however old, so it can be
+// shown while a fresh one is fetched. Returns nil if there is none.
+func CachedBalance(t *tool.Tool) *tool.Balance {
+	var balance *tool.Balance
+	cache.New(t.Name, tool.BalanceSchema, balanceTTL).Load("balance", &balance)
+	return balance
+}
+
+// fetchCached fetches a balance through the tool's cache namespace.
+func fetchCached(ctx context.Context, name string, factory Factory) *tool.Balance {
+	var balance *tool.Balance
+	_ = cache.New(name, tool.BalanceSchema, balanceTTL).Fetch("balance", &balance, func() error {
+		balance = factory().GetBalance(ctx)
+		if balance == nil || balance.Unknown() {
+			return errNoBalance
+		}
+		return nil
+	})
+	// Nothing cached and the fetch failed: an unknown balance is still worth showing
+	return balance
+}
+
+// FetchBudget is how long FetchAll waits for all providers together when ctx has no deadline.
+const FetchBudget = 3 * time.Second
+
+// FetchAll fetches the balances of the installed tools (every tool with all) with a balance
+// provider concurrently, keyed by tool name. Each provider runs with its own context, and the
+// ones still running when the budget runs out (ctx's deadline, or FetchBudget without one) are
+// cancelled and left out, so one slow provider can't hold up the others. Tools whose fetch
+// returns nothing are left out too.
+func FetchAll(ctx context.Context, registry *tool.Registry, all bool) map[string]*tool.Balance {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, FetchBudget)
+		defer cancel()
+	}
+
+	type result struct {
+		name    string
+		balance *tool.Balance
+	}
+	results := make(chan result, len(registry.List())) // Buffered, so late providers don't block
+	pending := 0
+	for _, t := range registry.List() {
+		factory, ok := Lookup(t.Name)
+		if !ok || !all && !t.RefreshInstalled() {
+			continue
+		}
+		pending++
+		fetchCtx, cancel := context.WithCancel(ctx)
+		go func(name string, factory Factory) {
+			defer cancel()
+			results <- result{name: name, balance: fetchCached(fetchCtx, name, factory)}
+		}(t.Name, factory)
+	}
+
+	balances := make(map[string]*tool.Balance)
+	for ; pending > 0; pending-- {
+		select {
+		case r := <-results:
+			if r.balance != nil {
+				balances[r.name] = r.balance
+			}
+		case <-ctx.Done():
+			// Out of budget; the providers still running see their context cancelled
+			return balances
+		}
+	}
+	return balances
+}
@@ -0,0 +1,76 @@
+// Package claude tells whether Claude Code is logged in. Claude Code publishes no usage
+// API, so there is no balance fetcher, only an auth checker.
+package claude
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Credentials is the login Claude Code saves in .credentials.json on Linux and Windows;
+// on macOS it is kept in the keychain instead.
+type Credentials struct {
+	OAuth *struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+		ExpiresAt    int64  `json:"expiresAt"` // Unix milliseconds
+	} `json:"claudeAiOauth"`
+}
+
+// AuthChecker implements provider.AuthChecker for Claude Code.
+type AuthChecker struct{}
+
+// AuthStatus reads, in order: an API key in the environment, .credentials.json, and the
+// account recorded in .claude.json, which is all there is on macOS.
+func (AuthChecker) AuthStatus() tool.AuthState {
+	if os.Getenv("ANTHROPIC_API_KEY") != "" || os.Getenv("ANTHROPIC_AUTH_TOKEN") != "" {
+		return tool.AuthLoggedIn
+	}
+	configDir, stateFile, err := claudePaths()
+	if err != nil {
+		return tool.AuthUnknown
+	}
+
+	var creds Credentials
+	if readJSON(filepath.Join(configDir, ".credentials.json"), &creds) && creds.OAuth != nil && creds.OAuth.AccessToken != "" {
+		if creds.OAuth.RefreshToken == "" && creds.OAuth.ExpiresAt > 0 && time.Now().After(time.UnixMilli(creds.OAuth.ExpiresAt)) {
+			return tool.AuthExpired
+		}
+		return tool.AuthLoggedIn
+	}
+
+	var state struct {
+		OAuthAccount  json.RawMessage `json:"oauthAccount"`
+		PrimaryAPIKey string          `json:"primaryApiKey"`
+	}
+	if !readJSON(stateFile, &state) {
+		return tool.AuthLoggedOut
+	}
+	if len(state.OAuthAccount) > 0 && string(state.OAuthAccount) != "null" || state.PrimaryAPIKey != "" {
+		return tool.AuthLoggedIn
+	}
+	return tool.AuthLoggedOut
+}
+
+// claudePaths returns the Claude Code config directory ($CLAUDE_CONFIG_DIR, or ~/.claude)
+// and its state file (.claude.json in $CLAUDE_CONFIG_DIR, or ~/.claude.json).
+func claudePaths() (configDir, stateFile string, err error) {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir, filepath.Join(dir, ".claude.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(home, ".claude"), filepath.Join(home, ".claude.json"), nil
+}
+
+// readJSON decodes the file at path into v, reporting whether it could.
+func readJSON(path string, v any) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && json.Unmarshal(data, v) == nil
+}
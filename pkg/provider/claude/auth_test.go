@@ -0,0 +1,51 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestAuthStatus(t *testing.T) {
+	past := time.Now().Add(-time.Hour).UnixMilli()
+	tests := []struct {
+		name  string
+		creds string // Contents of .credentials.json; empty for none
+		state string // Contents of .claude.json; empty for none
+		env   string // Variable set to "1"
+		want  tool.AuthState
+	}{
+		{name: "nothing", want: tool.AuthLoggedOut},
+		{name: "API key", env: "ANTHROPIC_API_KEY", want: tool.AuthLoggedIn},
+		{name: "refreshable login", creds: fmt.Sprintf(`{"claudeAiOauth": {"accessToken": "a", "refreshToken": "r", "expiresAt": %d}}`, past), want: tool.AuthLoggedIn},
+		{name: "expired login", creds: fmt.Sprintf(`{"claudeAiOauth": {"accessToken": "a", "expiresAt": %d}}`, past), want: tool.AuthExpired},
+		{name: "account in state", state: `{"oauthAccount": {"emailAddress": "me@example.com"}}`, want: tool.AuthLoggedIn},
+		{name: "state without account", state: `{"oauthAccount": null, "numStartups": 3}`, want: tool.AuthLoggedOut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("CLAUDE_CONFIG_DIR", dir)
+			t.Setenv("ANTHROPIC_API_KEY", "")
+			t.Setenv("ANTHROPIC_AUTH_TOKEN", "")
+			if tt.env != "" {
+				t.Setenv(tt.env, "1")
+			}
+			for name, data := range map[string]string{".credentials.json": tt.creds, ".claude.json": tt.state} {
+				if data == "" {
+					continue
+				}
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := (AuthChecker{}).AuthStatus(); got != tt.want {
+				t.Errorf("AuthStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
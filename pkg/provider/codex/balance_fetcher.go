@@ -3,6 +3,7 @@ package codex
 
 import (
 	"context"
+	"time"
 
 	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
@@ -23,19 +24,34 @@ func NewBalanceFetcher() *BalanceFetcher {
 func (b *BalanceFetcher) GetBalance(ctx context.Context) *tool.Balance {
 	usage := b.usageFetcher.GetUsage(ctx)
 
-	return &tool.Balance{
-		Percentage: usage.Percentage,
+	balance := &tool.Balance{
+		Percentage: usage.RemainingPercent,
 		Display:    usage.Display,
-		Color:      usage.Color,
-		FiveHourLimit: tool.LimitDetail{
-			Percentage: usage.FiveHourLimit.Percentage,
-			Display:    usage.FiveHourLimit.Display,
-			ResetTime:  usage.FiveHourLimit.ResetTime,
-		},
-		WeeklyLimit: tool.LimitDetail{
-			Percentage: usage.WeeklyLimit.Percentage,
-			Display:    usage.WeeklyLimit.Display,
-			ResetTime:  usage.WeeklyLimit.ResetTime,
+		FetchedAt:  usage.LastFetched,
+		Limits: []tool.LimitDetail{
+			{
+				Label:            tool.LimitFiveHour,
+				RemainingPercent: usage.FiveHourLimit.RemainingPercent,
+				Display:          usage.FiveHourLimit.Display,
+				ResetTime:        usage.FiveHourLimit.ResetTime,
+				ResetsAt:         usage.FiveHourLimit.ResetsAt,
+			},
+			{
+				Label:            tool.LimitWeekly,
+				RemainingPercent: usage.WeeklyLimit.RemainingPercent,
+				Display:          usage.WeeklyLimit.Display,
+				ResetTime:        usage.WeeklyLimit.ResetTime,
+				ResetsAt:         usage.WeeklyLimit.ResetsAt,
+			},
 		},
+		Credits: usage.Credits.Display(),
+	}
+
+	// Show what ate the 5h window; session logs are optional, so errors are ignored
+	if breakdown, err := FetchUsageBreakdown(time.Now().Add(-FiveHourWindow)); err == nil {
+		for _, m := range breakdown {
+			balance.Breakdown = append(balance.Breakdown, tool.UsageShare{Label: m.Model, Tokens: m.Tokens})
+		}
 	}
+	return balance
 }
@@ -0,0 +1,39 @@
+package codex
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/providertest"
+)
+
+func TestConformance(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home) // For the log of the failing backends
+	t.Setenv("CODEX_HOME", home)
+	t.Setenv("PATH", t.TempDir()) // No codex binary, so only the OAuth API is tried
+	auth := fmt.Sprintf(`{"tokens": {"access_token": %q, "account_id": "acc"}}`, testJWT(time.Now().Add(time.Hour)))
+	if err := os.WriteFile(filepath.Join(home, "auth.json"), []byte(auth), 0600); err != nil {
+		t.Fatal(err)
+	}
+	resetsAt := time.Now().Add(2 * time.Hour).Unix()
+	oldURL := chatGPTUsageURL
+	defer func() { chatGPTUsageURL = oldURL }()
+
+	providertest.Run(t, func(t *testing.T, b providertest.Backend) providertest.Fetcher {
+		server := httptest.NewServer(providertest.Handler(b, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"plan_type": "plus", "rate_limit": {
+				"primary_window": {"used_percent": 30, "reset_at": %d, "limit_window_seconds": 18000},
+				"secondary_window": {"used_percent": 55, "reset_at": %d, "limit_window_seconds": 604800}
+			}}`, resetsAt, resetsAt+86400)
+		})))
+		t.Cleanup(server.Close)
+		chatGPTUsageURL = server.URL
+		return NewBalanceFetcher()
+	})
+}
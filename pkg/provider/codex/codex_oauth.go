@@ -2,21 +2,39 @@
 package codex
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
 
 const (
-	// chatGPTUsageURL is the endpoint for fetching Codex usage via OAuth
-	chatGPTUsageURL = "https://chatgpt.com/backend-api/wham/usage"
+	// oauthClientID is the OAuth client of the Codex CLI, which issued the stored tokens
+	oauthClientID = "app_EMoamEEZ73f0CkXaXp7hrann"
+	// tokenExpiryMargin refreshes access tokens this long before they expire
+	tokenExpiryMargin = time.Minute
 )
 
+// chatGPTUsageURL is the endpoint for fetching Codex usage via OAuth (a variable for tests)
+var chatGPTUsageURL = "https://chatgpt.com/backend-api/wham/usage"
+
+// oauthTokenURL is the endpoint that exchanges a refresh token for new tokens (a variable for tests)
+var oauthTokenURL = "https://auth.openai.com/oauth/token"
+
+// errUnauthorized means the usage API rejected the access token
+var errUnauthorized = errors.New("unauthorized: token may be expired, run 'codex' to re-authenticate")
+
 // OAuthUsageResponse represents the response from the ChatGPT usage API.
 type OAuthUsageResponse struct {
 	PlanType  string           `json:"plan_type,omitempty"`
@@ -57,17 +75,25 @@ type OAuthAuthFile struct {
 	OpenAIAPIKey string `json:"OPENAI_API_KEY,omitempty"`
 }
 
-// loadOAuthCredentials loads OAuth credentials from ~/.codex/auth.json
-func loadOAuthCredentials() (*OAuthAuthFile, error) {
+// codexHomeDir returns the Codex data directory: $CODEX_HOME, or ~/.codex by default
+func codexHomeDir() (string, error) {
+	// Check CODEX_HOME environment variable first
+	if codexHome := os.Getenv("CODEX_HOME"); codexHome != "" {
+		return codexHome, nil
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
+	return filepath.Join(homeDir, ".codex"), nil
+}
 
-	// Check CODEX_HOME environment variable first
-	codexHome := os.Getenv("CODEX_HOME")
-	if codexHome == "" {
-		codexHome = filepath.Join(homeDir, ".codex")
+// loadOAuthCredentials loads OAuth credentials from ~/.codex/auth.json
+func loadOAuthCredentials() (*OAuthAuthFile, error) {
+	codexHome, err := codexHomeDir()
+	if err != nil {
+		return nil, err
 	}
 
 	authFile := filepath.Join(codexHome, "auth.json")
@@ -89,7 +115,36 @@ func loadOAuthCredentials() (*OAuthAuthFile, error) {
 	return &auth, nil
 }
 
+// CheckCredentials implements provider.CredentialsChecker for ~/.codex/auth.json.
+// An expired access token is fine: it is refreshed on the next fetch.
+func (b *BalanceFetcher) CheckCredentials() (string, error) {
+	codexHome, err := codexHomeDir()
+	if err != nil {
+		return "", err
+	}
+	_, err = loadOAuthCredentials()
+	return filepath.Join(codexHome, "auth.json"), err
+}
+
+// AuthStatus implements provider.AuthChecker for ~/.codex/auth.json. An expired access
+// token is refreshed on the next fetch, so only one without a refresh token is expired.
+func (b *BalanceFetcher) AuthStatus() tool.AuthState {
+	creds, err := loadOAuthCredentials()
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return tool.AuthLoggedOut
+	case err != nil:
+		return tool.AuthUnknown
+	case creds.OpenAIAPIKey == "" && creds.Tokens.RefreshToken == "" && tokenExpired(creds.Tokens.AccessToken, time.Now()):
+		return tool.AuthExpired
+	default:
+		return tool.AuthLoggedIn
+	}
+}
+
 // FetchUsageViaOAuth fetches usage information using OAuth API.
+// An expired access token is refreshed first, and the new tokens are saved to auth.json
+// like the codex CLI does, so the next codex run picks them up.
 func FetchUsageViaOAuth(ctx context.Context) (UsageInfo, error) {
 	creds, err := loadOAuthCredentials()
 	if err != nil {
@@ -101,10 +156,35 @@ func FetchUsageViaOAuth(ctx context.Context) (UsageInfo, error) {
 		return UsageInfo{}, fmt.Errorf("API key mode does not support OAuth usage API")
 	}
 
+	refreshed := false
+	if creds.Tokens.RefreshToken != "" && tokenExpired(creds.Tokens.AccessToken, time.Now()) {
+		if err := refreshOAuthToken(ctx, creds); err != nil {
+			return UsageInfo{}, err
+		}
+		refreshed = true
+	}
+
+	usageResp, err := requestOAuthUsage(ctx, creds)
+	if errors.Is(err, errUnauthorized) && !refreshed && creds.Tokens.RefreshToken != "" {
+		// Revoked early or rejected for another reason: one refresh is worth a try
+		if err := refreshOAuthToken(ctx, creds); err != nil {
+			return UsageInfo{}, err
+		}
+		usageResp, err = requestOAuthUsage(ctx, creds)
+	}
+	if err != nil {
+		return UsageInfo{}, err
+	}
+
+	return convertOAuthToUsageInfo(usageResp)
+}
+
+// requestOAuthUsage calls the usage API with the credentials' access token.
+func requestOAuthUsage(ctx context.Context, creds *OAuthAuthFile) (*OAuthUsageResponse, error) {
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "GET", chatGPTUsageURL, nil)
 	if err != nil {
-		return UsageInfo{}, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
@@ -121,14 +201,14 @@ func FetchUsageViaOAuth(ctx context.Context) (UsageInfo, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return UsageInfo{}, fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return UsageInfo{}, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Check status code
@@ -136,99 +216,215 @@ func FetchUsageViaOAuth(ctx context.Context) (UsageInfo, error) {
 	case http.StatusOK:
 		// Success, parse response
 	case http.StatusUnauthorized, http.StatusForbidden:
-		return UsageInfo{}, fmt.Errorf("unauthorized: token may be expired, run 'codex' to re-authenticate")
+		return nil, errUnauthorized
 	default:
-		return UsageInfo{}, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	// Parse response
 	var usageResp OAuthUsageResponse
 	if err := json.Unmarshal(body, &usageResp); err != nil {
-		return UsageInfo{}, fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	return &usageResp, nil
+}
 
-	return convertOAuthToUsageInfo(&usageResp)
+// tokenExpired reports whether the JWT access token expires within tokenExpiryMargin of now.
+// Tokens whose expiry can't be read are assumed valid; the usage API has the final say.
+func tokenExpired(token string, now time.Time) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return false
+	}
+	return now.Add(tokenExpiryMargin).After(time.Unix(claims.Exp, 0))
 }
 
-// convertOAuthToUsageInfo converts OAuth API response to UsageInfo.
-func convertOAuthToUsageInfo(resp *OAuthUsageResponse) (UsageInfo, error) {
-	if resp.RateLimit == nil {
-		return UsageInfo{}, fmt.Errorf("no rate limit data in response")
+// lockAuthFile takes the lock on auth.json.lock that amazing-cli processes refresh the
+// tokens under, waiting until ctx is done, and returns the function that releases it.
+func lockAuthFile(ctx context.Context) (func(), error) {
+	codexHome, err := codexHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(codexHome, "auth.json.lock"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock auth file: %w", err)
 	}
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock auth file: %w", err)
+		}
+		if locked {
+			return func() {
+				unlockFile(f)
+				f.Close()
+			}, nil
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, fmt.Errorf("failed to lock auth file: %w", ctx.Err())
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
 
-	now := time.Now()
+// refreshOAuthToken exchanges the refresh token for new tokens, updates creds and saves
+// them to auth.json. It runs under lockAuthFile, and when auth.json holds another valid
+// access token by then (codex or another process refreshed first) creds takes that one
+// instead: refresh tokens are single use, so refreshing again would fail.
+func refreshOAuthToken(ctx context.Context, creds *OAuthAuthFile) error {
+	unlock, err := lockAuthFile(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if current, err := loadOAuthCredentials(); err == nil && current.Tokens.AccessToken != creds.Tokens.AccessToken && !tokenExpired(current.Tokens.AccessToken, time.Now()) {
+		*creds = *current
+		return nil
+	}
 
-	// Parse primary window (5h limit) - store remaining percentage
-	var fiveHourInfo LimitInfo
-	if resp.RateLimit.PrimaryWindow != nil {
-		used := resp.RateLimit.PrimaryWindow.UsedPercent
-		remaining := 100 - used
-		if remaining < 0 {
-			remaining = 0
-		}
-		fiveHourInfo.Percentage = remaining // Store remaining, not used
+	reqBody, err := json.Marshal(map[string]string{
+		"client_id":     oauthClientID,
+		"grant_type":    "refresh_token",
+		"refresh_token": creds.Tokens.RefreshToken,
+		"scope":         "openid profile email",
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", oauthTokenURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create refresh request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "amazing-cli")
 
-		resetDesc := ""
-		if resp.RateLimit.PrimaryWindow.ResetAt > 0 {
-			resetTime := time.Unix(resp.RateLimit.PrimaryWindow.ResetAt, 0)
-			resetDesc = formatResetTime(resetTime)
-			fiveHourInfo.ResetTime = "resets " + resetDesc
-		}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("token refresh failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read refresh response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token refresh failed (%d), run 'codex login' to re-authenticate", resp.StatusCode)
+	}
 
-		// Display format: "95% left (resets 05:09)"
-		if fiveHourInfo.ResetTime != "" {
-			fiveHourInfo.Display = fmt.Sprintf("%d%% left (%s)", remaining, fiveHourInfo.ResetTime)
-		} else {
-			fiveHourInfo.Display = fmt.Sprintf("%d%% left", remaining)
-		}
+	var tokens struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		IDToken      string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return fmt.Errorf("failed to parse refresh response: %w", err)
+	}
+	if tokens.AccessToken == "" {
+		return fmt.Errorf("token refresh returned no access token")
 	}
 
-	// Parse secondary window (weekly limit) - store remaining percentage
-	var weeklyInfo LimitInfo
-	if resp.RateLimit.SecondaryWindow != nil {
-		used := resp.RateLimit.SecondaryWindow.UsedPercent
-		remaining := 100 - used
-		if remaining < 0 {
-			remaining = 0
-		}
-		weeklyInfo.Percentage = remaining // Store remaining, not used
+	// The server may keep the refresh and ID tokens as they are
+	creds.Tokens.AccessToken = tokens.AccessToken
+	if tokens.RefreshToken != "" {
+		creds.Tokens.RefreshToken = tokens.RefreshToken
+	}
+	if tokens.IDToken != "" {
+		creds.Tokens.IDToken = tokens.IDToken
+	}
+	creds.LastRefresh = time.Now().UTC().Format(time.RFC3339Nano)
+	return saveOAuthCredentials(creds)
+}
 
-		resetDesc := ""
-		if resp.RateLimit.SecondaryWindow.ResetAt > 0 {
-			resetTime := time.Unix(resp.RateLimit.SecondaryWindow.ResetAt, 0)
-			resetDesc = formatResetTimeWithDate(resetTime)
-			weeklyInfo.ResetTime = "resets " + resetDesc
-		}
+// saveOAuthCredentials writes refreshed tokens back to auth.json, keeping any fields
+// this package doesn't know about. The file is read again right before, replaced
+// atomically and stays private.
+func saveOAuthCredentials(creds *OAuthAuthFile) error {
+	codexHome, err := codexHomeDir()
+	if err != nil {
+		return err
+	}
+	authFile := filepath.Join(codexHome, "auth.json")
+	data, err := os.ReadFile(authFile)
+	if err != nil {
+		return fmt.Errorf("failed to read auth file: %w", err)
+	}
 
-		// Display format: "98% left (resets 16:22 on 10 Feb)"
-		if weeklyInfo.ResetTime != "" {
-			weeklyInfo.Display = fmt.Sprintf("%d%% left (%s)", remaining, weeklyInfo.ResetTime)
-		} else {
-			weeklyInfo.Display = fmt.Sprintf("%d%% left", remaining)
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse auth file: %w", err)
+	}
+	tokens := map[string]json.RawMessage{}
+	if raw, ok := doc["tokens"]; ok {
+		if err := json.Unmarshal(raw, &tokens); err != nil {
+			return fmt.Errorf("failed to parse auth file: %w", err)
 		}
 	}
+	set := func(m map[string]json.RawMessage, key, value string) {
+		m[key], _ = json.Marshal(value)
+	}
+	set(tokens, "access_token", creds.Tokens.AccessToken)
+	set(tokens, "refresh_token", creds.Tokens.RefreshToken)
+	set(tokens, "id_token", creds.Tokens.IDToken)
+	if doc["tokens"], err = json.Marshal(tokens); err != nil {
+		return err
+	}
+	set(doc, "last_refresh", creds.LastRefresh)
 
-	// Use 5h limit as primary
-	primaryPercent := fiveHourInfo.Percentage
-	if resp.RateLimit.PrimaryWindow == nil && resp.RateLimit.SecondaryWindow != nil {
-		primaryPercent = weeklyInfo.Percentage
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
 	}
+	tmp, err := os.CreateTemp(codexHome, "auth-*.json.tmp") // Created 0600
+	if err != nil {
+		return fmt.Errorf("failed to save auth file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // Gone after the rename; cleans up after a failure
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save auth file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save auth file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), authFile); err != nil {
+		return fmt.Errorf("failed to save auth file: %w", err)
+	}
+	return nil
+}
 
-	// Determine color based on remaining percentage (high remaining = green)
-	color := "green"
-	if primaryPercent <= 20 {
-		color = "red"
-	} else if primaryPercent <= 40 {
-		color = "yellow"
+// convertOAuthToUsageInfo converts OAuth API response to UsageInfo.
+func convertOAuthToUsageInfo(resp *OAuthUsageResponse) (UsageInfo, error) {
+	if resp.RateLimit == nil {
+		return UsageInfo{}, fmt.Errorf("no rate limit data in response")
 	}
 
-	return UsageInfo{
-		Percentage:    primaryPercent,
-		Display:       fiveHourInfo.Display,
-		Color:         color,
-		Source:        "oauth",
-		LastFetched:   now,
-		FiveHourLimit: fiveHourInfo,
-		WeeklyLimit:   weeklyInfo,
-	}, nil
+	var fiveHour, weekly *LimitInfo
+	if w := resp.RateLimit.PrimaryWindow; w != nil {
+		limit := windowLimitInfo(tool.RemainingFromUsed(float64(w.UsedPercent)), w.ResetAt, formatResetTime)
+		fiveHour = &limit
+	}
+	if w := resp.RateLimit.SecondaryWindow; w != nil {
+		limit := windowLimitInfo(tool.RemainingFromUsed(float64(w.UsedPercent)), w.ResetAt, formatResetTimeWithDate)
+		weekly = &limit
+	}
+	usage := newUsageInfo("oauth", fiveHour, weekly)
+	if c := resp.Credits; c != nil {
+		usage.Credits = CreditsInfo{HasCredits: c.HasCredits, Unlimited: c.Unlimited, Balance: c.Balance.String()}
+	}
+	return usage, nil
 }
@@ -0,0 +1,206 @@
+package codex
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// testJWT returns an unsigned JWT expiring at exp.
+func testJWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "eyJhbGciOiJub25lIn0." + payload + ".sig"
+}
+
+func TestTokenExpired(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"valid", testJWT(now.Add(time.Hour)), false},
+		{"expired", testJWT(now.Add(-time.Hour)), true},
+		{"about to expire", testJWT(now.Add(30 * time.Second)), true},
+		{"opaque token", "not-a-jwt", false},
+		{"bad payload", "a.!!!.c", false},
+	}
+	for _, tt := range tests {
+		if got := tokenExpired(tt.token, now); got != tt.want {
+			t.Errorf("%s: tokenExpired() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRefreshOAuthToken(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CODEX_HOME", home)
+	authFile := filepath.Join(home, "auth.json")
+	initial := `{"OPENAI_API_KEY": null, "tokens": {"access_token": "old", "refresh_token": "r1", "id_token": "id1", "account_id": "acc"}, "last_refresh": "2026-01-01T00:00:00Z"}`
+	if err := os.WriteFile(authFile, []byte(initial), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding refresh request: %v", err)
+		}
+		fmt.Fprint(w, `{"access_token": "new", "refresh_token": "r2"}`)
+	}))
+	defer server.Close()
+	oldURL := oauthTokenURL
+	oauthTokenURL = server.URL
+	defer func() { oauthTokenURL = oldURL }()
+
+	creds, err := loadOAuthCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := refreshOAuthToken(context.Background(), creds); err != nil {
+		t.Fatalf("refreshOAuthToken() error: %v", err)
+	}
+	if got["grant_type"] != "refresh_token" || got["refresh_token"] != "r1" || got["client_id"] != oauthClientID {
+		t.Errorf("refresh request = %v", got)
+	}
+
+	saved, err := loadOAuthCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Tokens.AccessToken != "new" || saved.Tokens.RefreshToken != "r2" || saved.Tokens.IDToken != "id1" || saved.Tokens.AccountID != "acc" {
+		t.Errorf("saved tokens = %+v", saved.Tokens)
+	}
+	if saved.LastRefresh == "2026-01-01T00:00:00Z" {
+		t.Error("last_refresh was not updated")
+	}
+	data, _ := os.ReadFile(authFile)
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc["OPENAI_API_KEY"]; !ok {
+		t.Error("OPENAI_API_KEY was dropped from auth.json")
+	}
+	if info, err := os.Stat(authFile); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("auth.json mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestRefreshOAuthToken_Concurrent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CODEX_HOME", home)
+	expired := testJWT(time.Now().Add(-time.Hour))
+	if err := os.WriteFile(filepath.Join(home, "auth.json"), []byte(`{"tokens": {"access_token": "`+expired+`", "refresh_token": "r1"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fresh := testJWT(time.Now().Add(time.Hour))
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 1 {
+			http.Error(w, `{"error": "refresh_token_reused"}`, http.StatusBadRequest)
+			return
+		}
+		time.Sleep(50 * time.Millisecond) // Long enough for the others to wait on the lock
+		fmt.Fprintf(w, `{"access_token": %q, "refresh_token": "r2"}`, fresh)
+	}))
+	defer server.Close()
+	oldURL := oauthTokenURL
+	oauthTokenURL = server.URL
+	defer func() { oauthTokenURL = oldURL }()
+
+	// Processes that read auth.json before any of them refreshed
+	var wg sync.WaitGroup
+	for range 3 {
+		creds, err := loadOAuthCredentials()
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := refreshOAuthToken(context.Background(), creds); err != nil {
+				t.Errorf("refreshOAuthToken() error: %v", err)
+			}
+			if creds.Tokens.AccessToken != fresh {
+				t.Errorf("access token = %q, want the refreshed one", creds.Tokens.AccessToken)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("refresh endpoint called %d times, want once", n)
+	}
+	entries, _ := os.ReadDir(home)
+	for _, e := range entries {
+		if filepath.Ext(e.Name()) == ".tmp" {
+			t.Errorf("temporary file %s left behind", e.Name())
+		}
+	}
+}
+
+func TestRefreshOAuthToken_Rejected(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CODEX_HOME", home)
+	if err := os.WriteFile(filepath.Join(home, "auth.json"), []byte(`{"tokens": {"access_token": "old", "refresh_token": "r1"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+	oldURL := oauthTokenURL
+	oauthTokenURL = server.URL
+	defer func() { oauthTokenURL = oldURL }()
+
+	creds, err := loadOAuthCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := refreshOAuthToken(context.Background(), creds); err == nil {
+		t.Error("refreshOAuthToken() = nil, want an error for a rejected refresh token")
+	}
+	if saved, _ := loadOAuthCredentials(); saved.Tokens.AccessToken != "old" {
+		t.Errorf("access token = %q after a failed refresh, want it unchanged", saved.Tokens.AccessToken)
+	}
+}
+
+func TestAuthStatus(t *testing.T) {
+	expired := testJWT(time.Now().Add(-time.Hour))
+	tests := []struct {
+		name string
+		auth string // Contents of auth.json; empty for none
+		want tool.AuthState
+	}{
+		{name: "no auth.json", want: tool.AuthLoggedOut},
+		{name: "API key", auth: `{"OPENAI_API_KEY": "sk-test"}`, want: tool.AuthLoggedIn},
+		{name: "refreshable login", auth: fmt.Sprintf(`{"tokens": {"access_token": %q, "refresh_token": "r1"}}`, expired), want: tool.AuthLoggedIn},
+		{name: "expired login", auth: fmt.Sprintf(`{"tokens": {"access_token": %q}}`, expired), want: tool.AuthExpired},
+		{name: "corrupt auth.json", auth: `{`, want: tool.AuthUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("CODEX_HOME", home)
+			if tt.auth != "" {
+				if err := os.WriteFile(filepath.Join(home, "auth.json"), []byte(tt.auth), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := NewBalanceFetcher().AuthStatus(); got != tt.want {
+				t.Errorf("AuthStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
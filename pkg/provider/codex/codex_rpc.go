@@ -11,6 +11,9 @@ import (
 	"os/exec"
 	"sync"
 	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
 
 // RPCRateLimitWindow represents a rate limit window from Codex RPC.
@@ -332,89 +335,39 @@ func convertRPCToUsageInfo(resp *RPCRateLimitsResponse) (UsageInfo, error) {
 		return UsageInfo{}, fmt.Errorf("no rate limit data available")
 	}
 
-	now := time.Now()
-	
-	// Parse primary (5h limit) - store remaining percentage
-	var fiveHourInfo LimitInfo
-	if resp.RateLimits.Primary != nil {
-		used := int(resp.RateLimits.Primary.UsedPercent)
-		remaining := 100 - used
-		if remaining < 0 {
-			remaining = 0
-		}
-		fiveHourInfo.Percentage = remaining // Store remaining, not used
-		
-		resetDesc := ""
-		if resp.RateLimits.Primary.ResetsAt > 0 {
-			resetTime := time.Unix(resp.RateLimits.Primary.ResetsAt, 0)
-			resetDesc = formatResetTime(resetTime)
-			fiveHourInfo.ResetTime = "resets " + resetDesc
-		}
-		
-		// Display format: "95% left (resets 05:09)"
-		if fiveHourInfo.ResetTime != "" {
-			fiveHourInfo.Display = fmt.Sprintf("%d%% left (%s)", remaining, fiveHourInfo.ResetTime)
-		} else {
-			fiveHourInfo.Display = fmt.Sprintf("%d%% left", remaining)
-		}
+	var fiveHour, weekly *LimitInfo
+	if w := resp.RateLimits.Primary; w != nil {
+		limit := windowLimitInfo(tool.RemainingFromUsed(w.UsedPercent), w.ResetsAt, formatResetTime)
+		fiveHour = &limit
 	}
-
-	// Parse secondary (weekly limit) - store remaining percentage
-	var weeklyInfo LimitInfo
-	if resp.RateLimits.Secondary != nil {
-		used := int(resp.RateLimits.Secondary.UsedPercent)
-		remaining := 100 - used
-		if remaining < 0 {
-			remaining = 0
-		}
-		weeklyInfo.Percentage = remaining // Store remaining, not used
-		
-		resetDesc := ""
-		if resp.RateLimits.Secondary.ResetsAt > 0 {
-			resetTime := time.Unix(resp.RateLimits.Secondary.ResetsAt, 0)
-			resetDesc = formatResetTimeWithDate(resetTime)
-			weeklyInfo.ResetTime = "resets " + resetDesc
-		}
-		
-		// Display format: "98% left (resets 16:22 on 10 Feb)"
-		if weeklyInfo.ResetTime != "" {
-			weeklyInfo.Display = fmt.Sprintf("%d%% left (%s)", remaining, weeklyInfo.ResetTime)
-		} else {
-			weeklyInfo.Display = fmt.Sprintf("%d%% left", remaining)
-		}
+	if w := resp.RateLimits.Secondary; w != nil {
+		limit := windowLimitInfo(tool.RemainingFromUsed(w.UsedPercent), w.ResetsAt, formatResetTimeWithDate)
+		weekly = &limit
 	}
-
-	// Use 5h limit as primary
-	primaryPercent := fiveHourInfo.Percentage
-	if resp.RateLimits.Primary == nil && resp.RateLimits.Secondary != nil {
-		primaryPercent = weeklyInfo.Percentage
-	}
-
-	// Determine color based on remaining percentage (high remaining = green)
-	color := "green"
-	if primaryPercent <= 20 {
-		color = "red"
-	} else if primaryPercent <= 40 {
-		color = "yellow"
+	usage := newUsageInfo("rpc", fiveHour, weekly)
+	if c := resp.RateLimits.Credits; c != nil {
+		usage.Credits = CreditsInfo{HasCredits: c.HasCredits, Unlimited: c.Unlimited, Balance: c.Balance}
 	}
-
-	return UsageInfo{
-		Percentage:    primaryPercent,
-		Display:       fiveHourInfo.Display,
-		Color:         color,
-		Source:        "rpc",
-		LastFetched:   now,
-		FiveHourLimit: fiveHourInfo,
-		WeeklyLimit:   weeklyInfo,
-	}, nil
+	return usage, nil
 }
 
 // formatResetTime formats a reset time for 5h limit (time only).
 func formatResetTime(t time.Time) string {
-	return t.Format("15:04")
+	return timefmt.Time(t)
 }
 
 // formatResetTimeWithDate formats a reset time for weekly limit (time + date).
 func formatResetTimeWithDate(t time.Time) string {
-	return t.Format("15:04 2 Jan")
+	return timefmt.DateTime(t)
+}
+
+// windowLimitInfo returns the limit of a rate limit window of the OAuth or RPC API, with
+// remaining percent left and the window's reset time (Unix seconds, 0 if unknown) described
+// by format.
+func windowLimitInfo(remaining int, resetAt int64, format func(time.Time) string) LimitInfo {
+	if resetAt <= 0 {
+		return newLimitInfo(remaining, "", time.Time{})
+	}
+	resetsAt := time.Unix(resetAt, 0)
+	return newLimitInfo(remaining, "resets "+format(resetsAt), resetsAt)
 }
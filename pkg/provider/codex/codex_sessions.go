@@ -0,0 +1,125 @@
+// Package codex provides functionality to fetch Codex token usage information.
+package codex
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FiveHourWindow is the length of Codex's short rate limit window.
+const FiveHourWindow = 5 * time.Hour
+
+// ModelUsage is the number of tokens used with a single model.
+type ModelUsage struct {
+	Model  string
+	Tokens int64
+}
+
+// sessionLine is one entry of a Codex session log (~/.codex/sessions/**/rollout-*.jsonl).
+type sessionLine struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// sessionPayload holds the payload fields used for the breakdown.
+// "turn_context" entries carry the model; "token_count" events carry the tokens used by a turn.
+type sessionPayload struct {
+	Type  string `json:"type"`
+	Model string `json:"model"`
+	Info  *struct {
+		LastTokenUsage struct {
+			TotalTokens int64 `json:"total_tokens"`
+		} `json:"last_token_usage"`
+	} `json:"info"`
+}
+
+// FetchUsageBreakdown parses the Codex session logs and returns the tokens used per model
+// since the given time, largest first.
+func FetchUsageBreakdown(since time.Time) ([]ModelUsage, error) {
+	codexHome, err := codexHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64)
+	root := filepath.Join(codexHome, "sessions")
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".jsonl") {
+			return nil
+		}
+		// Sessions last written before the window can't contain usage inside it
+		if info, err := d.Info(); err != nil || info.ModTime().Before(since) {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer f.Close()
+		return parseSessionLog(f, since, totals)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return sortModelUsage(totals), nil
+}
+
+// parseSessionLog adds the tokens used per model in a session log since the given time to totals.
+// Malformed lines are skipped.
+func parseSessionLog(r io.Reader, since time.Time, totals map[string]int64) error {
+	reader := bufio.NewReader(r)
+	model := "unknown"
+	for {
+		// Lines can be very long (tool output), so don't use a bufio.Scanner
+		data, err := reader.ReadBytes('\n')
+		if len(data) > 0 {
+			var line sessionLine
+			var payload sessionPayload
+			if json.Unmarshal(data, &line) == nil && json.Unmarshal(line.Payload, &payload) == nil {
+				switch {
+				case line.Type == "turn_context" && payload.Model != "":
+					model = payload.Model
+				case payload.Type == "token_count" && payload.Info != nil && !line.Timestamp.Before(since):
+					totals[model] += payload.Info.LastTokenUsage.TotalTokens
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// sortModelUsage converts totals into a list ordered by tokens, largest first.
+func sortModelUsage(totals map[string]int64) []ModelUsage {
+	var usage []ModelUsage
+	for model, tokens := range totals {
+		if tokens > 0 {
+			usage = append(usage, ModelUsage{Model: model, Tokens: tokens})
+		}
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Tokens != usage[j].Tokens {
+			return usage[i].Tokens > usage[j].Tokens
+		}
+		return usage[i].Model < usage[j].Model
+	})
+	return usage
+}
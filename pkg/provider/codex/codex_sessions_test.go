@@ -0,0 +1,63 @@
+package codex
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sessionFixture = `{"timestamp":"2026-03-01T09:00:00.000Z","type":"session_meta","payload":{"id":"abc"}}
+{"timestamp":"2026-03-01T09:00:01.000Z","type":"turn_context","payload":{"model":"o3"}}
+{"timestamp":"2026-03-01T09:00:05.000Z","type":"event_msg","payload":{"type":"token_count","info":{"last_token_usage":{"total_tokens":100}}}}
+{"timestamp":"2026-03-01T11:00:00.000Z","type":"event_msg","payload":{"type":"token_count","info":{"last_token_usage":{"total_tokens":1000}}}}
+not json
+{"timestamp":"2026-03-01T11:00:01.000Z","type":"event_msg","payload":{"type":"token_count","info":null}}
+{"timestamp":"2026-03-01T11:01:00.000Z","type":"turn_context","payload":{"model":"o4-mini"}}
+{"timestamp":"2026-03-01T11:02:00.000Z","type":"event_msg","payload":{"type":"token_count","info":{"last_token_usage":{"total_tokens":250}}}}`
+
+func TestParseSessionLog(t *testing.T) {
+	since := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	totals := make(map[string]int64)
+	if err := parseSessionLog(strings.NewReader(sessionFixture), since, totals); err != nil {
+		t.Fatalf("parseSessionLog() error: %v", err)
+	}
+
+	got := sortModelUsage(totals)
+	want := []ModelUsage{{Model: "o3", Tokens: 1000}, {Model: "o4-mini", Tokens: 250}}
+	if len(got) != len(want) {
+		t.Fatalf("breakdown = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("breakdown[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFetchUsageBreakdown(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CODEX_HOME", home)
+
+	// No sessions directory yet
+	if got, err := FetchUsageBreakdown(time.Time{}); err != nil || len(got) != 0 {
+		t.Fatalf("FetchUsageBreakdown() without sessions = %v, %v; want empty, nil", got, err)
+	}
+
+	dir := filepath.Join(home, "sessions", "2026", "03", "01")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "rollout-abc.jsonl"), []byte(sessionFixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FetchUsageBreakdown(time.Time{})
+	if err != nil {
+		t.Fatalf("FetchUsageBreakdown() error: %v", err)
+	}
+	if len(got) != 2 || got[0].Model != "o3" || got[0].Tokens != 1100 {
+		t.Errorf("FetchUsageBreakdown() = %v, want o3 with 1100 tokens first", got)
+	}
+}
@@ -13,9 +13,16 @@ import (
 	"time"
 
 	"github.com/creack/pty"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
 
 func runCodexStatus(ctx context.Context, codexPath string) (string, error) {
+	// Driving codex through a PTY is unreliable on Termux; the RPC and session sources still work
+	if tool.IsTermux() {
+		return "", fmt.Errorf("codex /status needs a PTY, which isn't used on Termux")
+	}
+
 	// Run codex without restrictions to get full /status output
 	cmd := exec.CommandContext(ctx, codexPath)
 	// Set environment variables to make codex think it's in a real terminal
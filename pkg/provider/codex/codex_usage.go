@@ -4,15 +4,15 @@ package codex
 import (
 	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
 
 const (
@@ -23,25 +23,80 @@ const (
 )
 
 // LimitInfo represents information about a single limit (5h or weekly).
+// The Codex APIs and CLI report how much was used; every strategy converts that with
+// tool.RemainingFromUsed, so LimitInfo and UsageInfo only hold the share left.
 type LimitInfo struct {
-	Percentage int    // 0-100, percentage used
-	Display    string // Human-readable display (e.g., "0% (resets 03:31 5 Feb)")
-	ResetTime  string // When the limit resets
+	RemainingPercent int       // 0-100, share of the window left
+	Display          string    // Human-readable display (e.g., "100% left (resets 03:31 5 Feb)")
+	ResetTime        string    // When the limit resets (e.g., "resets 05:09")
+	ResetsAt         time.Time // When the limit resets (zero if only a description is known)
+}
+
+// newLimitInfo returns a limit with remaining percent left, displayed like
+// "95% left (resets 05:09)".
+func newLimitInfo(remaining int, resetTime string, resetsAt time.Time) LimitInfo {
+	limit := LimitInfo{RemainingPercent: remaining, ResetTime: resetTime, ResetsAt: resetsAt}
+	limit.Display = fmt.Sprintf("%d%% left", remaining)
+	if resetTime != "" {
+		limit.Display += " (" + resetTime + ")"
+	}
+	return limit
 }
 
 // UsageInfo represents Codex token usage information.
 type UsageInfo struct {
-	Percentage   int       // 0-100, percentage used (from primary limit)
-	Display      string    // Human-readable display (e.g., "45%", "2h 30m remaining")
-	Color        string    // Color hint: "green", "yellow", "red"
-	ResetTime    time.Time // When the limit resets
-	LastFetched  time.Time // When this data was fetched
-	Source       string    // Where this data came from: "cli", "oauth", "cache"
-	ErrorMessage string    // Error message if fetch failed
+	RemainingPercent int       // 0-100, share left of the primary limit
+	Display          string    // Human-readable display of the primary limit (e.g., "55% left (resets in 2h 30m)")
+	ResetTime        time.Time // When the limit resets
+	LastFetched      time.Time // When this data was fetched
+	Source           string    // Where this data came from: "oauth", "rpc", "cli", or "default" when all failed
+	ErrorMessage     string    // Error message if fetch failed
 	
 	// Individual limit information
 	FiveHourLimit LimitInfo // 5h limit details
 	WeeklyLimit   LimitInfo // Weekly limit details
+
+	Credits CreditsInfo // Credits bought on top of the plan (zero if the account has none)
+}
+
+// CreditsInfo represents the credits of an account, which are spent once the limits run out.
+type CreditsInfo struct {
+	HasCredits bool
+	Unlimited  bool
+	Balance    string // Credits left as reported (e.g., "1,234.56"); empty if unknown
+}
+
+// Display returns the credits left for rendering, e.g. "1,234.56" or "unlimited", or ""
+// when the account has no credits.
+func (c CreditsInfo) Display() string {
+	switch {
+	case c.Unlimited:
+		return "unlimited"
+	case !c.HasCredits:
+		return ""
+	case c.Balance == "":
+		return "available"
+	}
+	return c.Balance
+}
+
+// newUsageInfo returns the usage of the limits a strategy found (nil if it found none),
+// the 5h limit being the primary one unless only the weekly limit is known.
+func newUsageInfo(source string, fiveHour, weekly *LimitInfo) UsageInfo {
+	usage := UsageInfo{Source: source, LastFetched: time.Now()}
+	if fiveHour != nil {
+		usage.FiveHourLimit = *fiveHour
+	}
+	if weekly != nil {
+		usage.WeeklyLimit = *weekly
+	}
+	primary := usage.FiveHourLimit
+	if fiveHour == nil && weekly != nil {
+		primary = usage.WeeklyLimit
+	}
+	usage.RemainingPercent = primary.RemainingPercent
+	usage.Display = primary.Display
+	return usage
 }
 
 // OAuthCredentials represents the OAuth tokens stored in ~/.codex/auth.json
@@ -56,71 +111,46 @@ type OAuthCredentials struct {
 }
 
 // UsageFetcher provides methods to fetch Codex token usage.
-type UsageFetcher struct {
-	cacheFile string
-	cacheTTL  time.Duration
-}
+// Fetched usage is cached by the provider package, see pkg/cache.
+type UsageFetcher struct{}
 
 // NewUsageFetcher creates a new UsageFetcher.
 func NewUsageFetcher() *UsageFetcher {
-	homeDir, _ := os.UserHomeDir()
-	cacheDir := filepath.Join(homeDir, ".amazing-cli", "cache")
-	os.MkdirAll(cacheDir, 0755)
-
-	return &UsageFetcher{
-		cacheFile: filepath.Join(cacheDir, "codex-usage.json"),
-		cacheTTL:  5 * time.Minute, // Cache for 5 minutes
-	}
+	return &UsageFetcher{}
 }
 
 // GetUsage fetches the current Codex token usage.
 // It tries multiple strategies in order: OAuth API, RPC, CLI PTY.
 // Priority: OAuth API (fastest) > RPC > CLI PTY
 func (f *UsageFetcher) GetUsage(ctx context.Context) UsageInfo {
-	// Try to load from cache first if it's fresh
-	if cached, err := f.loadCache(); err == nil {
-		if time.Since(cached.LastFetched) < f.cacheTTL {
-			cached.Source = "cache"
-			return cached
-		}
-	}
-
 	// Try OAuth API strategy (fastest, most accurate) - Priority 1
-	if usage, err := FetchUsageViaOAuth(ctx); err == nil {
-		f.saveCache(usage)
+	usage, err := FetchUsageViaOAuth(ctx)
+	if err == nil {
 		return usage
 	}
+	log.Debug("codex usage via OAuth failed", "err", err)
 
 	// Try RPC strategy (codex app-server) - Priority 2
-	if usage, err := FetchUsageViaRPC(ctx); err == nil {
-		f.saveCache(usage)
+	if usage, err = FetchUsageViaRPC(ctx); err == nil {
 		return usage
 	}
+	log.Debug("codex usage via app-server failed", "err", err)
 
 	// Try CLI PTY strategy (running codex /status) as fallback - Priority 3
-	if usage, err := f.fetchFromCLI(ctx); err == nil {
-		f.saveCache(usage)
+	if usage, err = f.fetchFromCLI(ctx); err == nil {
 		return usage
 	}
 
 	// If all strategies fail, return a default "unknown" state with dual limits
+	log.Warn("codex usage unavailable", "err", err)
 	return UsageInfo{
-		Percentage:   0, // Show 0% as fallback (unknown)
-		Display:      "?%",
-		Color:        "green",
-		Source:       "default",
-		LastFetched:  time.Now(),
-		ErrorMessage: "unable to fetch usage data",
-		FiveHourLimit: LimitInfo{
-			Percentage: 0,
-			Display:    "?%",
-			ResetTime:  "",
-		},
-		WeeklyLimit: LimitInfo{
-			Percentage: 0,
-			Display:    "?%",
-			ResetTime:  "",
-		},
+		RemainingPercent: 0, // Drawn as "?%", not as an empty allowance
+		Display:          tool.UnknownDisplay,
+		Source:           "default",
+		LastFetched:      time.Now(),
+		ErrorMessage:     "unable to fetch usage data",
+		FiveHourLimit:    LimitInfo{Display: "?%"},
+		WeeklyLimit:      LimitInfo{Display: "?%"},
 	}
 }
 
@@ -138,14 +168,13 @@ func (f *UsageFetcher) fetchFromCLI(ctx context.Context) (UsageInfo, error) {
 
 	output, err := runCodexStatus(ctx, codexPath)
 	if err != nil {
-		f.writeDebugOutput("runCodexStatus error", err.Error())
-		return UsageInfo{}, err
+		return UsageInfo{}, fmt.Errorf("codex /status in a pseudo-terminal: %w", err)
 	}
 
 	// Parse the output
 	usage, parseErr := parseStatusOutput(output)
 	if parseErr != nil {
-		f.writeDebugOutput("parseStatusOutput error", output)
+		log.Debug("unexpected codex /status output", "output", output)
 		return UsageInfo{}, parseErr
 	}
 	return usage, nil
@@ -157,141 +186,91 @@ func (f *UsageFetcher) fetchFromCLI(ctx context.Context) (UsageInfo, error) {
 // New format: "5h limit: [████████████████████] 100% left (resets 03:31 on 5 Feb)"
 // - "Weekly limit: 23% used (resets in 4 days)"
 // - "Credits: 1,234.56"
+// Both formats are read as the share left: "45% used" is 55% left.
 func parseStatusOutput(output string) (UsageInfo, error) {
 	cleanOutput := stripANSICodes(output)
 	scanner := bufio.NewScanner(strings.NewReader(cleanOutput))
 
-	var fiveHourPercent int
-	var fiveHourReset string
-	var weeklyPercent int
-	var weeklyReset string
-	foundFiveHour := false
-	foundWeekly := false
-
-	// Regex patterns
-	// Match patterns like "45% used" or "45.5% used"
-	usedPattern := regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%\s*used`)
-	// Match patterns like "100% left", "50% left", or "90% remaining"
-	leftPattern := regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%\s*(left|remaining)`)
-	// Match patterns like "resets in 2h 30m" or "resets in 4 days"
-	resetInPattern := regexp.MustCompile(`resets in (.+)`)
-	// Match patterns like "resets 03:31 on 5 Feb" or "resets 16:22 on 10 Feb"
-	resetOnPattern := regexp.MustCompile(`resets (\d{2}:\d{2}) on (\d+\s+\w+)`)
-	// Match patterns like "resets 05:09"
-	resetAtPattern := regexp.MustCompile(`resets (\d{2}:\d{2})`)
-
+	var fiveHour, weekly *LimitInfo
+	var credits CreditsInfo
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		// Look for the credits line
+		if matches := creditsPattern.FindStringSubmatch(line); len(matches) > 1 {
+			credits = CreditsInfo{HasCredits: true, Balance: matches[1]}
+			if strings.EqualFold(matches[1], "unlimited") {
+				credits = CreditsInfo{HasCredits: true, Unlimited: true}
+			}
+		}
+
 		// Look for 5h limit line
 		if strings.Contains(line, "5h limit") || strings.Contains(line, "5-hour") {
-			// Try "% used" pattern first
-			if matches := usedPattern.FindStringSubmatch(line); len(matches) > 1 {
-				if percent, err := strconv.ParseFloat(matches[1], 64); err == nil {
-					fiveHourPercent = int(percent)
-					foundFiveHour = true
-				}
-			} else if matches := leftPattern.FindStringSubmatch(line); len(matches) > 1 {
-				// Try "% left" pattern - convert to used
-				if percent, err := strconv.ParseFloat(matches[1], 64); err == nil {
-					fiveHourPercent = 100 - int(percent)
-					foundFiveHour = true
-				}
-			}
-			
-			// Try to extract reset time
-			if matches := resetInPattern.FindStringSubmatch(line); len(matches) > 1 {
-				fiveHourReset = matches[1]
-			} else if matches := resetOnPattern.FindStringSubmatch(line); len(matches) > 2 {
-				fiveHourReset = fmt.Sprintf("%s %s", matches[1], matches[2])
-			} else if matches := resetAtPattern.FindStringSubmatch(line); len(matches) > 1 {
-				fiveHourReset = matches[1]
+			if limit, ok := parseLimitLine(line); ok {
+				fiveHour = &limit
 			}
 		}
 
 		// Look for weekly limit line
 		if strings.Contains(line, "Weekly limit") || strings.Contains(line, "weekly") {
-			// Try "% used" pattern first
-			if matches := usedPattern.FindStringSubmatch(line); len(matches) > 1 {
-				if percent, err := strconv.ParseFloat(matches[1], 64); err == nil {
-					weeklyPercent = int(percent)
-					foundWeekly = true
-				}
-			} else if matches := leftPattern.FindStringSubmatch(line); len(matches) > 1 {
-				// Try "% left" pattern - convert to used
-				if percent, err := strconv.ParseFloat(matches[1], 64); err == nil {
-					weeklyPercent = 100 - int(percent)
-					foundWeekly = true
-				}
-			}
-			
-			// Try to extract reset time
-			if matches := resetInPattern.FindStringSubmatch(line); len(matches) > 1 {
-				weeklyReset = matches[1]
-			} else if matches := resetOnPattern.FindStringSubmatch(line); len(matches) > 2 {
-				weeklyReset = fmt.Sprintf("%s %s", matches[1], matches[2])
-			} else if matches := resetAtPattern.FindStringSubmatch(line); len(matches) > 1 {
-				weeklyReset = matches[1]
+			if limit, ok := parseLimitLine(line); ok {
+				weekly = &limit
 			}
 		}
 	}
 
-	if !foundFiveHour && !foundWeekly {
+	if fiveHour == nil && weekly == nil {
 		return UsageInfo{}, fmt.Errorf("failed to parse usage from codex output")
 	}
+	usage := newUsageInfo("cli", fiveHour, weekly)
+	usage.Credits = credits
+	return usage, nil
+}
 
-	// Use 5h limit as primary, fall back to weekly
-	primaryPercent := fiveHourPercent
-	primaryReset := fiveHourReset
-	if !foundFiveHour && foundWeekly {
-		primaryPercent = weeklyPercent
-		primaryReset = weeklyReset
-	}
-
-	// Determine color based on primary usage
-	color := "green"
-	if primaryPercent >= 80 {
-		color = "red"
-	} else if primaryPercent >= 60 {
-		color = "yellow"
-	}
-
-	// Build display string for primary limit
-	display := fmt.Sprintf("%d%%", primaryPercent)
-	if primaryReset != "" {
-		display = fmt.Sprintf("%d%% (%s)", primaryPercent, primaryReset)
-	}
+var (
+	// Match patterns like "45% used" or "45.5% used"
+	usedPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%\s*used`)
+	// Match patterns like "100% left", "50% left", or "90% remaining"
+	leftPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%\s*(left|remaining)`)
+	// Match patterns like "resets in 2h 30m" or "resets in 4 days"
+	resetInPattern = regexp.MustCompile(`resets in (.+)`)
+	// Match patterns like "resets 03:31 on 5 Feb" or "resets 16:22 on 10 Feb"
+	resetOnPattern = regexp.MustCompile(`resets (\d{2}:\d{2}) on (\d+\s+\w+)`)
+	// Match patterns like "resets 05:09"
+	resetAtPattern = regexp.MustCompile(`resets (\d{2}:\d{2})`)
+	// Match patterns like "Credits: 1,234.56" or "Credits: unlimited"
+	creditsPattern = regexp.MustCompile(`^\s*Credits:\s*(unlimited|\d[\d,]*(?:\.\d+)?)`)
+)
 
-	// Build LimitInfo structs
-	fiveHourInfo := LimitInfo{
-		Percentage: fiveHourPercent,
-		ResetTime:  fiveHourReset,
-	}
-	if fiveHourReset != "" {
-		fiveHourInfo.Display = fmt.Sprintf("%d%% (%s)", fiveHourPercent, fiveHourReset)
+// parseLimitLine parses the share of a limit ("45% used" or "55% left") and its reset time
+// from a line of "codex /status". Returns false when the line has no percentage.
+func parseLimitLine(line string) (LimitInfo, bool) {
+	var remaining int
+	if matches := usedPattern.FindStringSubmatch(line); len(matches) > 1 {
+		used, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return LimitInfo{}, false
+		}
+		remaining = tool.RemainingFromUsed(used)
+	} else if matches := leftPattern.FindStringSubmatch(line); len(matches) > 1 {
+		left, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return LimitInfo{}, false
+		}
+		remaining = max(0, min(100, int(left)))
 	} else {
-		fiveHourInfo.Display = fmt.Sprintf("%d%%", fiveHourPercent)
+		return LimitInfo{}, false
 	}
 
-	weeklyInfo := LimitInfo{
-		Percentage: weeklyPercent,
-		ResetTime:  weeklyReset,
-	}
-	if weeklyReset != "" {
-		weeklyInfo.Display = fmt.Sprintf("%d%% (%s)", weeklyPercent, weeklyReset)
-	} else {
-		weeklyInfo.Display = fmt.Sprintf("%d%%", weeklyPercent)
+	var resetTime string
+	if matches := resetInPattern.FindStringSubmatch(line); len(matches) > 1 {
+		resetTime = "resets in " + strings.TrimSuffix(strings.TrimSpace(matches[1]), ")")
+	} else if matches := resetOnPattern.FindStringSubmatch(line); len(matches) > 2 {
+		resetTime = fmt.Sprintf("resets %s %s", matches[1], matches[2])
+	} else if matches := resetAtPattern.FindStringSubmatch(line); len(matches) > 1 {
+		resetTime = "resets " + matches[1]
 	}
-
-	return UsageInfo{
-		Percentage:    primaryPercent,
-		Display:       display,
-		Color:         color,
-		Source:        "cli",
-		LastFetched:   time.Now(),
-		FiveHourLimit: fiveHourInfo,
-		WeeklyLimit:   weeklyInfo,
-	}, nil
+	return newLimitInfo(remaining, resetTime, time.Time{}), true
 }
 
 func stripANSICodes(s string) string {
@@ -304,35 +283,3 @@ func stripANSICodes(s string) string {
 func ParseStatusOutputForTest(output string) (UsageInfo, error) {
 	return parseStatusOutput(output)
 }
-
-// loadCache loads cached usage info from disk.
-func (f *UsageFetcher) loadCache() (UsageInfo, error) {
-	data, err := os.ReadFile(f.cacheFile)
-	if err != nil {
-		return UsageInfo{}, err
-	}
-
-	var info UsageInfo
-	if err := json.Unmarshal(data, &info); err != nil {
-		return UsageInfo{}, err
-	}
-
-	return info, nil
-}
-
-// saveCache saves usage info to disk cache.
-func (f *UsageFetcher) saveCache(info UsageInfo) error {
-	data, err := json.MarshalIndent(info, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(f.cacheFile, data, 0644)
-}
-
-func (f *UsageFetcher) writeDebugOutput(prefix, content string) {
-	dir := filepath.Dir(f.cacheFile)
-	_ = os.MkdirAll(dir, 0755)
-	path := filepath.Join(dir, "codex-usage-debug.txt")
-	_ = os.WriteFile(path, []byte(prefix+"\n"+content+"\n"), 0644)
-}
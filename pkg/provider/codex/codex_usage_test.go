@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
 
 func TestParseStatusOutput(t *testing.T) {
@@ -24,7 +27,7 @@ Weekly limit: 10% used (resets in 4 days)
 Credits: 1,234.56
 `,
 			expectError:    false,
-			expectPercent:  45,
+			expectPercent:  55, // 45% used = 55% left
 			expectColor:    "green",
 			expectContains: "2h 30m",
 		},
@@ -35,7 +38,7 @@ Credits: 1,234.56
 Weekly limit:         [████████████████████] 100% left (resets 16:22 on 10 Feb)
 `,
 			expectError:   false,
-			expectPercent: 0, // 100% left = 0% used
+			expectPercent: 100,
 			expectColor:   "green",
 		},
 		{
@@ -45,7 +48,7 @@ Weekly limit:         [███████████████████
 Weekly limit:         [████████████████████] 95% left (resets 16:22 on 10 Feb)
 `,
 			expectError:   false,
-			expectPercent: 40, // 60% left = 40% used
+			expectPercent: 60,
 			expectColor:   "green",
 		},
 		{
@@ -55,7 +58,7 @@ Weekly limit:         [███████████████████
 Weekly limit: 20% used
 `,
 			expectError:   false,
-			expectPercent: 85,
+			expectPercent: 15,
 			expectColor:   "red",
 		},
 		{
@@ -64,7 +67,7 @@ Weekly limit: 20% used
 5h limit: 65% used (resets in 3h)
 `,
 			expectError:   false,
-			expectPercent: 65,
+			expectPercent: 35,
 			expectColor:   "yellow",
 		},
 		{
@@ -73,7 +76,7 @@ Weekly limit: 20% used
 Weekly limit: 30% used (resets in 3 days)
 `,
 			expectError:   false,
-			expectPercent: 30,
+			expectPercent: 70,
 			expectColor:   "green",
 		},
 		{
@@ -90,7 +93,7 @@ Type /help for assistance
 5h limit: 42.5% used (resets in 1h 15m)
 `,
 			expectError:   false,
-			expectPercent: 42,
+			expectPercent: 58,
 			expectColor:   "green",
 		},
 	}
@@ -111,12 +114,12 @@ Type /help for assistance
 				return
 			}
 
-			if result.Percentage != tt.expectPercent {
-				t.Errorf("expected percentage %d, got %d", tt.expectPercent, result.Percentage)
+			if result.RemainingPercent != tt.expectPercent {
+				t.Errorf("expected %d%% left, got %d%%", tt.expectPercent, result.RemainingPercent)
 			}
 
-			if result.Color != tt.expectColor {
-				t.Errorf("expected color %s, got %s", tt.expectColor, result.Color)
+			if color := tool.RemainingColor(result.RemainingPercent); color != tt.expectColor {
+				t.Errorf("expected color %s, got %s", tt.expectColor, color)
 			}
 
 			if tt.expectContains != "" && !strings.Contains(result.Display, tt.expectContains) {
@@ -155,8 +158,119 @@ func TestUsageInfoColorMapping(t *testing.T) {
 			continue
 		}
 
-		if result.Color != tt.expectedColor {
-			t.Errorf("for %d%%, expected color %s, got %s", tt.percentage, tt.expectedColor, result.Color)
+		if color := tool.RemainingColor(result.RemainingPercent); color != tt.expectedColor {
+			t.Errorf("for %d%% used, expected color %s, got %s", tt.percentage, tt.expectedColor, color)
+		}
+	}
+}
+
+// TestStrategiesAgree checks that the OAuth API, the app-server RPC and "codex /status",
+// which all report usage, end up with the same share left, and so the same color.
+func TestStrategiesAgree(t *testing.T) {
+	resetAt := time.Date(2026, 2, 10, 16, 22, 0, 0, time.UTC).Unix()
+	tests := []struct {
+		name            string
+		fiveHour        int
+		weekly          int
+		cli             string
+		wantFiveHour    int
+		wantWeekly      int
+		wantColor       string
+		wantDisplayLeft string
+	}{
+		{"plenty left", 30, 55, "5h limit: 30% used (resets in 2h)\nWeekly limit: 45% left", 70, 45, "green", "70% left"},
+		{"5h window almost gone", 85, 20, "5h limit: [███░░░░░░░] 15% left (resets 05:09)\nWeekly limit: 20% used", 15, 80, "red", "15% left"},
+		{"over the limit", 120, 100, "5h limit: 120% used\nWeekly limit: 100% used", 0, 0, "red", "0% left"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oauth, err := convertOAuthToUsageInfo(&OAuthUsageResponse{RateLimit: &RateLimitDetail{
+				PrimaryWindow:   &WindowSnapshot{UsedPercent: tt.fiveHour, ResetAt: resetAt},
+				SecondaryWindow: &WindowSnapshot{UsedPercent: tt.weekly, ResetAt: resetAt},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rpcResp RPCRateLimitsResponse
+			rpcResp.RateLimits.Primary = &RPCRateLimitWindow{UsedPercent: float64(tt.fiveHour), ResetsAt: resetAt}
+			rpcResp.RateLimits.Secondary = &RPCRateLimitWindow{UsedPercent: float64(tt.weekly)}
+			rpc, err := convertRPCToUsageInfo(&rpcResp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cli, err := parseStatusOutput(tt.cli)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, u := range []UsageInfo{oauth, rpc, cli} {
+				if u.RemainingPercent != tt.wantFiveHour || u.FiveHourLimit.RemainingPercent != tt.wantFiveHour || u.WeeklyLimit.RemainingPercent != tt.wantWeekly {
+					t.Errorf("%s: %d%% left (5h %d%%, weekly %d%%), want %d%% (5h %d%%, weekly %d%%)", u.Source,
+						u.RemainingPercent, u.FiveHourLimit.RemainingPercent, u.WeeklyLimit.RemainingPercent, tt.wantFiveHour, tt.wantFiveHour, tt.wantWeekly)
+				}
+				if color := tool.RemainingColor(u.RemainingPercent); color != tt.wantColor {
+					t.Errorf("%s: color = %s, want %s", u.Source, color, tt.wantColor)
+				}
+				if !strings.HasPrefix(u.Display, tt.wantDisplayLeft) {
+					t.Errorf("%s: Display = %q, want it to start with %q", u.Source, u.Display, tt.wantDisplayLeft)
+				}
+			}
+		})
+	}
+}
+
+func TestWeeklyOnlyIsPrimary(t *testing.T) {
+	var resp RPCRateLimitsResponse
+	resp.RateLimits.Secondary = &RPCRateLimitWindow{UsedPercent: 70}
+	u, err := convertRPCToUsageInfo(&resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.RemainingPercent != 30 || u.Display != "30% left" {
+		t.Errorf("usage = %d%% %q, want the weekly limit: 30%% left", u.RemainingPercent, u.Display)
+	}
+}
+
+func TestCredits(t *testing.T) {
+	oauth, err := convertOAuthToUsageInfo(&OAuthUsageResponse{
+		RateLimit: &RateLimitDetail{PrimaryWindow: &WindowSnapshot{UsedPercent: 100}},
+		Credits:   &CreditDetail{HasCredits: true, Balance: "1234.56"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rpcResp RPCRateLimitsResponse
+	rpcResp.RateLimits.Primary = &RPCRateLimitWindow{UsedPercent: 10}
+	rpcResp.RateLimits.Credits = &struct {
+		HasCredits bool   `json:"hasCredits"`
+		Unlimited  bool   `json:"unlimited"`
+		Balance    string `json:"balance,omitempty"`
+	}{HasCredits: true, Unlimited: true}
+	rpc, err := convertRPCToUsageInfo(&rpcResp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := parseStatusOutput("5h limit: 45% used\nCredits: 1,234.56\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	none, err := parseStatusOutput("5h limit: 45% used\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		usage UsageInfo
+		want  string
+	}{
+		{oauth, "1234.56"},
+		{rpc, "unlimited"},
+		{cli, "1,234.56"},
+		{none, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.usage.Credits.Display(); got != tt.want {
+			t.Errorf("%s: Credits.Display() = %q, want %q", tt.usage.Source, got, tt.want)
 		}
 	}
 }
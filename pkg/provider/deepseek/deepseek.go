@@ -0,0 +1,187 @@
+// Package deepseek fetches the prepaid API balance of a DeepSeek account from the
+// official billing endpoint, GET /user/balance, for the tools that run on DeepSeek's API.
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/keychain"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// DefaultBaseURL is where the DeepSeek API is served.
+const DefaultBaseURL = "https://api.deepseek.com"
+
+// KeyEnv names the environment variable holding the API key, as DeepSeek's docs use it.
+const KeyEnv = "DEEPSEEK_API_KEY"
+
+// ToolName is the built-in tool whose config env and keychain item hold the key.
+const ToolName = "deepseek"
+
+// tokenEnv is the variable the tool passes the key to claude in, which is what a key
+// written in the tool's env in the config sets.
+const tokenEnv = "ANTHROPIC_AUTH_TOKEN"
+
+var (
+	configMu  sync.Mutex
+	configEnv map[string]string // The deepseek tool's env in the settings
+)
+
+// SetConfigEnv sets the env of the deepseek tool in the settings, where apiKey looks for
+// a key written there. The launcher sets it whenever it loads the settings.
+func SetConfigEnv(env map[string]string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	configEnv = env
+}
+
+// configToken returns the key written in the deepseek tool's env in the settings.
+func configToken() string {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return os.ExpandEnv(configEnv[tokenEnv])
+}
+
+// BalanceResponse is the response of GET /user/balance.
+type BalanceResponse struct {
+	IsAvailable  bool          `json:"is_available"` // Whether the balance covers API calls
+	BalanceInfos []BalanceInfo `json:"balance_infos"`
+}
+
+// BalanceInfo is the balance in one currency. Amounts are decimal strings.
+type BalanceInfo struct {
+	Currency        string `json:"currency"` // "CNY" or "USD"
+	TotalBalance    string `json:"total_balance"`
+	GrantedBalance  string `json:"granted_balance"`
+	ToppedUpBalance string `json:"topped_up_balance"`
+}
+
+// BalanceFetcher implements the provider.BalanceFetcher interface for DeepSeek.
+type BalanceFetcher struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewBalanceFetcher creates a BalanceFetcher for the API at baseURL (DefaultBaseURL
+// if empty).
+func NewBalanceFetcher(baseURL string) *BalanceFetcher {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	// No client timeout: the context given to GetBalance bounds each fetch
+	return &BalanceFetcher{baseURL: baseURL, client: &http.Client{}}
+}
+
+// GetBalance fetches the account balance and converts it to an absolute tool.Balance in
+// yuan or dollars.
+func (b *BalanceFetcher) GetBalance(ctx context.Context) *tool.Balance {
+	resp, err := b.FetchBalance(ctx)
+	if err == nil {
+		var balance *tool.Balance
+		if balance, err = balanceFromResponse(resp, time.Now()); err == nil {
+			return balance
+		}
+	}
+	log.Warn("deepseek balance unavailable", "err", err)
+	return &tool.Balance{Display: tool.UnknownDisplay, FetchedAt: time.Now()}
+}
+
+// FetchBalance calls GET /user/balance with the key from apiKey.
+func (b *BalanceFetcher) FetchBalance(ctx context.Context) (*BalanceResponse, error) {
+	key, _, err := apiKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.baseURL+"/user/balance", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("Accept", "application/json")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("balance request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("balance request failed: %s", resp.Status)
+	}
+	var balance BalanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&balance); err != nil {
+		return nil, fmt.Errorf("invalid balance response: %w", err)
+	}
+	return &balance, nil
+}
+
+// CheckCredentials implements provider.CredentialsChecker: it returns where the API key
+// was found, or why there is none.
+func (b *BalanceFetcher) CheckCredentials() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, source, err := apiKey(ctx)
+	return source, err
+}
+
+// AuthStatus implements provider.AuthChecker: an API key is all a DeepSeek login is.
+func (b *BalanceFetcher) AuthStatus() tool.AuthState {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := apiKey(ctx); err != nil {
+		return tool.AuthLoggedOut
+	}
+	return tool.AuthLoggedIn
+}
+
+// errNoKey means none of the places apiKey looks in holds a key.
+var errNoKey = errors.New("no API key: set $" + KeyEnv + ", " + tokenEnv + " in the deepseek env of the config, or save it in the keychain")
+
+// apiKey returns the API key and where it was found, trying in order $DEEPSEEK_API_KEY,
+// ANTHROPIC_AUTH_TOKEN in the deepseek tool's env in the config, and the keychain
+// (service amazing-cli, account deepseek).
+func apiKey(ctx context.Context) (key, source string, err error) {
+	if key := os.Getenv(KeyEnv); key != "" {
+		return key, "$" + KeyEnv, nil
+	}
+	if key := configToken(); key != "" {
+		return key, "the deepseek env of the config", nil
+	}
+	key, err = keychain.Lookup(ctx, ToolName)
+	if errors.Is(err, keychain.ErrNotFound) {
+		err = errNoKey
+	}
+	return key, keychain.Describe(ToolName), err
+}
+
+// units are the currencies DeepSeek bills in.
+var units = map[string]tool.BalanceUnit{
+	"CNY": tool.UnitYuan,
+	"USD": tool.UnitDollars,
+}
+
+// balanceFromResponse converts the balance of the first currency the account has into an
+// absolute balance. It is prepaid credit, so there is no Total and no bar.
+func balanceFromResponse(resp *BalanceResponse, now time.Time) (*tool.Balance, error) {
+	for _, info := range resp.BalanceInfos {
+		unit, ok := units[info.Currency]
+		if !ok {
+			continue
+		}
+		remaining, err := strconv.ParseFloat(info.TotalBalance, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s balance %q", info.Currency, info.TotalBalance)
+		}
+		balance := &tool.Balance{Unit: unit, Remaining: max(0, remaining), FetchedAt: now}
+		balance.Display = balance.AmountDisplay()
+		return balance, nil
+	}
+	return nil, errors.New("no balance in CNY or USD")
+}
@@ -0,0 +1,94 @@
+package deepseek
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/providertest"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// fakeAPI serves /user/balance like the real API, for an account with topped up credit.
+func fakeAPI(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/balance" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("Authorization = %q, want the key from $%s", got, KeyEnv)
+		}
+		fmt.Fprint(w, `{"is_available": true, "balance_infos": [
+			{"currency": "CNY", "total_balance": "110.00", "granted_balance": "10.00", "topped_up_balance": "100.00"}
+		]}`)
+	})
+}
+
+func TestConformance(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // For the log of the failing backends
+	t.Setenv(KeyEnv, "sk-test")
+	providertest.Run(t, func(t *testing.T, b providertest.Backend) providertest.Fetcher {
+		server := httptest.NewServer(providertest.Handler(b, fakeAPI(t)))
+		t.Cleanup(server.Close)
+		return NewBalanceFetcher(server.URL)
+	})
+}
+
+func TestBalanceFromResponse(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		infos   []BalanceInfo
+		unit    tool.BalanceUnit
+		display string
+		wantErr bool
+	}{
+		{"yuan", []BalanceInfo{{Currency: "CNY", TotalBalance: "110.00"}}, tool.UnitYuan, "¥110.00", false},
+		{"dollars", []BalanceInfo{{Currency: "USD", TotalBalance: "4.5"}}, tool.UnitDollars, "$4.50", false},
+		{"unknown currency skipped", []BalanceInfo{{Currency: "EUR", TotalBalance: "1"}, {Currency: "CNY", TotalBalance: "0"}}, tool.UnitYuan, "¥0.00", false},
+		{"owing shows nothing left", []BalanceInfo{{Currency: "CNY", TotalBalance: "-2.10"}}, tool.UnitYuan, "¥0.00", false},
+		{"no balance", nil, "", "", true},
+		{"not a number", []BalanceInfo{{Currency: "CNY", TotalBalance: "n/a"}}, "", "", true},
+	}
+	for _, tt := range tests {
+		b, err := balanceFromResponse(&BalanceResponse{BalanceInfos: tt.infos}, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: balanceFromResponse() = %+v, want an error", tt.name, b)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: balanceFromResponse() error: %v", tt.name, err)
+		}
+		if b.Unit != tt.unit || b.Display != tt.display || b.Total != 0 {
+			t.Errorf("%s: balance = %s %q total %v, want %s %q without a total", tt.name, b.Unit, b.Display, b.Total, tt.unit, tt.display)
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", t.TempDir()) // No keychain tool
+	t.Setenv(KeyEnv, "")
+	SetConfigEnv(map[string]string{tokenEnv: "sk-config"})
+	defer SetConfigEnv(nil)
+	if key, _, err := apiKey(t.Context()); key != "sk-config" || err != nil {
+		t.Errorf("apiKey() = %q, %v, want the key from the config", key, err)
+	}
+
+	t.Setenv(KeyEnv, "sk-env")
+	if key, source, _ := apiKey(t.Context()); key != "sk-env" || source != "$"+KeyEnv {
+		t.Errorf("apiKey() = %q from %s, want $%s first", key, source, KeyEnv)
+	}
+
+	t.Setenv(KeyEnv, "")
+	SetConfigEnv(nil)
+	if _, _, err := apiKey(t.Context()); err == nil {
+		t.Error("apiKey() found a key with none set")
+	}
+}
@@ -0,0 +1,138 @@
+// Package example is a reference balance provider for a made-up "example" agent whose
+// usage API is a single JSON endpoint. It isn't registered; copy it as the starting
+// point of a new provider:
+//
+//  1. Fetch the usage with the context GetBalance is given, so the launcher can give
+//     up on a slow backend (see FetchUsage).
+//  2. Convert the response to a tool.Balance (see balanceFromUsage); keep this free of
+//     I/O so it can be tested with fixed input.
+//  3. Return an unknown balance when fetching fails, and log why (see GetBalance).
+//  4. Register the fetcher under the tool's name in pkg/provider/balances.go.
+//  5. Run providertest.Run against a fake backend (see example_test.go).
+//
+// Caching is done for every provider by pkg/provider, so a fetcher may call its API
+// every time GetBalance is called.
+package example
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// DefaultBaseURL is where the example API is served. Tests point the fetcher at a
+// fake backend instead.
+const DefaultBaseURL = "https://api.example.com"
+
+// TokenEnv names the environment variable holding the API token. Real providers usually
+// read the token the agent saved at login instead (e.g., ~/.gemini/oauth_creds.json).
+const TokenEnv = "EXAMPLE_API_KEY"
+
+// Usage is the response of GET /v1/usage.
+type Usage struct {
+	Limits []UsageLimit `json:"limits"`
+}
+
+// UsageLimit is one quota window of the account.
+type UsageLimit struct {
+	Window   string  `json:"window"`    // "5h", "weekly", ...
+	Used     float64 `json:"used"`      // Requests made in the window
+	Limit    float64 `json:"limit"`     // Requests allowed in the window
+	ResetsAt string  `json:"resets_at"` // RFC 3339; empty if the window doesn't reset
+}
+
+// BalanceFetcher implements the provider.BalanceFetcher interface for the example agent.
+type BalanceFetcher struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewBalanceFetcher creates a BalanceFetcher for the API at baseURL (DefaultBaseURL
+// if empty).
+func NewBalanceFetcher(baseURL string) *BalanceFetcher {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	// No client timeout: the context given to GetBalance bounds each fetch
+	return &BalanceFetcher{baseURL: baseURL, client: &http.Client{}}
+}
+
+// GetBalance fetches the usage of each window and converts it to tool.Balance, with one
+// limit per window and the lowest share left as the overall balance.
+func (b *BalanceFetcher) GetBalance(ctx context.Context) *tool.Balance {
+	usage, err := b.FetchUsage(ctx)
+	if err != nil {
+		log.Warn("example usage unavailable", "err", err)
+		// Not nil: an unknown balance tells the TUI the fetch is over, and
+		// pkg/provider keeps showing the last balance it cached
+		return &tool.Balance{Display: tool.UnknownDisplay, FetchedAt: time.Now()}
+	}
+	return balanceFromUsage(usage, time.Now())
+}
+
+// FetchUsage calls GET /v1/usage. Errors describe what failed, for the log; the
+// launcher itself only shows that the balance is unknown.
+func (b *BalanceFetcher) FetchUsage(ctx context.Context) (*Usage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.baseURL+"/v1/usage", nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv(TokenEnv); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("usage request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("usage request failed: %s", resp.Status)
+	}
+	var usage Usage
+	if err := json.NewDecoder(resp.Body).Decode(&usage); err != nil {
+		return nil, fmt.Errorf("invalid usage response: %w", err)
+	}
+	return &usage, nil
+}
+
+// windowLabels shortens the API's window names for the limit bars.
+var windowLabels = map[string]string{
+	"5h":     tool.LimitFiveHour,
+	"weekly": tool.LimitWeekly,
+}
+
+// balanceFromUsage converts the usage into a balance. Every field the TUI draws is set:
+// Percentage is the share left (0-100), Display its text, and each limit gets a Label,
+// RemainingPercent, Display and reset time. The TUI picks the bar's color from Percentage.
+func balanceFromUsage(usage *Usage, now time.Time) *tool.Balance {
+	balance := &tool.Balance{Percentage: 100, FetchedAt: now}
+	for _, u := range usage.Limits {
+		if u.Limit <= 0 {
+			continue // No allowance to measure against
+		}
+		label := u.Window
+		if short, ok := windowLabels[u.Window]; ok {
+			label = short
+		}
+		left := max(0, min(100, int((u.Limit-u.Used)*100/u.Limit)))
+
+		limit := tool.LimitDetail{Label: label, RemainingPercent: left, Display: fmt.Sprintf("%d%% left", left)}
+		if resetsAt, err := time.Parse(time.RFC3339, u.ResetsAt); err == nil {
+			limit.ResetsAt = resetsAt
+			limit.ResetTime = "resets " + timefmt.DateTime(resetsAt)
+			limit.Display += " (" + limit.ResetTime + ")"
+		}
+		balance.Limits = append(balance.Limits, limit)
+		balance.Percentage = min(balance.Percentage, left)
+	}
+
+	balance.Display = fmt.Sprintf("%d%%", balance.Percentage)
+	return balance
+}
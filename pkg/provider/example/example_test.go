@@ -0,0 +1,70 @@
+package example
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/providertest"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// fakeAPI serves /v1/usage like the real API, for an account with some quota left.
+func fakeAPI(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/usage" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want the token from $%s", got, TokenEnv)
+		}
+		fmt.Fprint(w, `{"limits": [
+			{"window": "5h", "used": 30, "limit": 100, "resets_at": "2026-02-10T17:00:00Z"},
+			{"window": "weekly", "used": 700, "limit": 1000}
+		]}`)
+	})
+}
+
+func TestConformance(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // For the log of the failing backends
+	t.Setenv(TokenEnv, "test-token")
+	providertest.Run(t, func(t *testing.T, b providertest.Backend) providertest.Fetcher {
+		// The failure modes are the same for every provider; only the healthy API is ours
+		server := httptest.NewServer(providertest.Handler(b, fakeAPI(t)))
+		t.Cleanup(server.Close)
+		return NewBalanceFetcher(server.URL)
+	})
+}
+
+func TestBalanceFromUsage(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		limits []UsageLimit
+		want   int
+		color  string
+		labels []string
+	}{
+		{"lowest window wins", []UsageLimit{{Window: "5h", Used: 30, Limit: 100}, {Window: "weekly", Used: 700, Limit: 1000}}, 30, "yellow", []string{tool.LimitFiveHour, tool.LimitWeekly}},
+		{"over the limit", []UsageLimit{{Window: "daily", Used: 120, Limit: 100}}, 0, "red", []string{"daily"}},
+		{"no allowance is skipped", []UsageLimit{{Window: "5h", Used: 5, Limit: 0}}, 100, "green", nil},
+		{"no limits", nil, 100, "green", nil},
+	}
+	for _, tt := range tests {
+		b := balanceFromUsage(&Usage{Limits: tt.limits}, now)
+		if b.Percentage != tt.want || b.Color() != tt.color || b.Display != fmt.Sprintf("%d%%", tt.want) {
+			t.Errorf("%s: balance = %d%% %s %q, want %d%% %s", tt.name, b.Percentage, b.Color(), b.Display, tt.want, tt.color)
+		}
+		if len(b.Limits) != len(tt.labels) {
+			t.Fatalf("%s: limits = %+v, want %v", tt.name, b.Limits, tt.labels)
+		}
+		for i, label := range tt.labels {
+			if b.Limits[i].Label != label {
+				t.Errorf("%s: limit %d label = %q, want %q", tt.name, i, b.Limits[i].Label, label)
+			}
+		}
+	}
+}
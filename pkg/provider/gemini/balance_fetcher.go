@@ -0,0 +1,86 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// BalanceFetcher implements the provider.BalanceFetcher interface for the Gemini CLI.
+type BalanceFetcher struct{}
+
+// NewBalanceFetcher creates a new Gemini BalanceFetcher.
+func NewBalanceFetcher() *BalanceFetcher {
+	return &BalanceFetcher{}
+}
+
+// GetBalance fetches the daily quota of each Gemini model and converts it to tool.Balance,
+// with one limit per model and the lowest share left as the overall balance.
+func (b *BalanceFetcher) GetBalance(ctx context.Context) *tool.Balance {
+	buckets, err := FetchQuota(ctx)
+	if err != nil {
+		// Unknown usage, like the codex fallback
+		log.Warn("gemini quota unavailable", "err", err)
+		return &tool.Balance{Display: tool.UnknownDisplay, FetchedAt: time.Now()}
+	}
+	return balanceFromBuckets(buckets, time.Now())
+}
+
+// balanceFromBuckets merges the quota buckets by model (keeping the lowest share left)
+// into one limit per model, in the order the API listed them.
+func balanceFromBuckets(buckets []QuotaBucket, now time.Time) *tool.Balance {
+	balance := &tool.Balance{Percentage: 100, FetchedAt: now}
+	index := map[string]int{}
+	for _, bucket := range buckets {
+		label := modelLabel(bucket.ModelID)
+		remaining := max(0, min(100, int(math.Round(bucket.RemainingFraction*100))))
+
+		limit := tool.LimitDetail{Label: label, RemainingPercent: remaining}
+		if resetsAt, err := time.Parse(time.RFC3339, bucket.ResetTime); err == nil {
+			limit.ResetsAt = resetsAt
+			if resetsAt.Sub(now) < 24*time.Hour {
+				limit.ResetTime = "resets " + timefmt.Time(resetsAt)
+			} else {
+				limit.ResetTime = "resets " + timefmt.DateTime(resetsAt)
+			}
+		}
+		limit.Display = fmt.Sprintf("%d%% left", remaining)
+		if limit.ResetTime != "" {
+			limit.Display += " (" + limit.ResetTime + ")"
+		}
+
+		if i, ok := index[label]; ok {
+			if remaining < balance.Limits[i].RemainingPercent {
+				balance.Limits[i] = limit
+			}
+		} else {
+			index[label] = len(balance.Limits)
+			balance.Limits = append(balance.Limits, limit)
+		}
+	}
+
+	for _, limit := range balance.Limits {
+		balance.Percentage = min(balance.Percentage, limit.RemainingPercent)
+	}
+	balance.Display = fmt.Sprintf("%d%%", balance.Percentage)
+	return balance
+}
+
+// modelLabel shortens a model ID for the limit bars, e.g. "gemini-2.5-flash-lite" to "Flash-lite".
+func modelLabel(modelID string) string {
+	label, _, _ := strings.Cut(modelID, "_") // "gemini-2.5-pro_vertex"
+	label = strings.TrimPrefix(label, "gemini-")
+	if version, rest, ok := strings.Cut(label, "-"); ok && strings.Trim(version, "0123456789.") == "" {
+		label = rest
+	}
+	if label == "" {
+		return modelID
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
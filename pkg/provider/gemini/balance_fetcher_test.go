@@ -0,0 +1,184 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider/providertest"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestModelLabel(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"gemini-2.5-pro", "Pro"},
+		{"gemini-2.5-flash-lite", "Flash-lite"},
+		{"gemini-2.0-flash_vertex", "Flash"},
+		{"gemini-exp", "Exp"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := modelLabel(tt.id); got != tt.want {
+			t.Errorf("modelLabel(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestBalanceFromBuckets(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	buckets := []QuotaBucket{
+		{ModelID: "gemini-2.5-pro", RemainingFraction: 0.35, ResetTime: "2026-02-11T00:00:00Z"},
+		{ModelID: "gemini-2.5-flash", RemainingFraction: 0.9},
+		{ModelID: "gemini-2.5-pro_vertex", RemainingFraction: 0.5},
+	}
+	b := balanceFromBuckets(buckets, now)
+	if b.Percentage != 35 || b.Color() != "yellow" || b.Display != "35%" {
+		t.Errorf("balance = %d%% %s %q, want 35%% yellow", b.Percentage, b.Color(), b.Display)
+	}
+	if len(b.Limits) != 2 {
+		t.Fatalf("got %d limits, want one per model: %+v", len(b.Limits), b.Limits)
+	}
+	pro, flash := b.Limits[0], b.Limits[1]
+	if pro.Label != "Pro" || pro.RemainingPercent != 35 || !pro.ResetsAt.Equal(time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Pro limit = %+v", pro)
+	}
+	if flash.Label != "Flash" || flash.RemainingPercent != 90 || flash.Display != "90% left" {
+		t.Errorf("Flash limit = %+v", flash)
+	}
+}
+
+func TestFetchQuota(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	creds := fmt.Sprintf(`{"access_token": "tok", "expiry_date": %d}`, time.Now().Add(time.Hour).UnixMilli())
+	if err := os.MkdirAll(filepath.Join(home, ".gemini"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".gemini", "oauth_creds.json"), []byte(creds), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1internal:loadCodeAssist":
+			fmt.Fprint(w, `{"cloudaicompanionProject": "proj-1"}`)
+		case "/v1internal:retrieveUserQuota":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["project"] != "proj-1" {
+				t.Errorf("retrieveUserQuota project = %q, want proj-1", body["project"])
+			}
+			fmt.Fprint(w, `{"buckets": [{"modelId": "gemini-2.5-pro", "tokenType": "REQUESTS", "remainingFraction": 0.8}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	oldURL := codeAssistURL
+	codeAssistURL = server.URL + "/v1internal"
+	defer func() { codeAssistURL = oldURL }()
+
+	buckets, err := FetchQuota(context.Background())
+	if err != nil {
+		t.Fatalf("FetchQuota() error: %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].ModelID != "gemini-2.5-pro" || buckets[0].RemainingFraction != 0.8 {
+		t.Errorf("FetchQuota() = %+v", buckets)
+	}
+}
+
+func TestFetchQuota_ExpiredLogin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	creds := fmt.Sprintf(`{"access_token": "tok", "expiry_date": %d}`, time.Now().Add(-time.Hour).UnixMilli())
+	if err := os.MkdirAll(filepath.Join(home, ".gemini"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".gemini", "oauth_creds.json"), []byte(creds), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FetchQuota(context.Background()); err == nil {
+		t.Error("FetchQuota() = nil error for an expired login")
+	}
+}
+
+func TestConformance(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "proj-1")
+	creds := fmt.Sprintf(`{"access_token": "tok", "expiry_date": %d}`, time.Now().Add(time.Hour).UnixMilli())
+	if err := os.MkdirAll(filepath.Join(home, ".gemini"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".gemini", "oauth_creds.json"), []byte(creds), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	oldURL := codeAssistURL
+	defer func() { codeAssistURL = oldURL }()
+
+	providertest.Run(t, func(t *testing.T, b providertest.Backend) providertest.Fetcher {
+		server := httptest.NewServer(providertest.Handler(b, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"buckets": [{"modelId": "gemini-2.5-pro", "remainingFraction": 0.8, "resetTime": "2026-02-11T00:00:00Z"}]}`)
+		})))
+		t.Cleanup(server.Close)
+		codeAssistURL = server.URL + "/v1internal"
+		return NewBalanceFetcher()
+	})
+}
+
+func TestAuthStatus(t *testing.T) {
+	past := time.Now().Add(-time.Hour).UnixMilli()
+	tests := []struct {
+		name  string
+		creds string // Contents of oauth_creds.json; empty for none
+		env   string // Variable set to "1"
+		want  tool.AuthState
+	}{
+		{name: "no login", want: tool.AuthLoggedOut},
+		{name: "API key", env: "GEMINI_API_KEY", want: tool.AuthLoggedIn},
+		{name: "Vertex AI", env: "GOOGLE_GENAI_USE_VERTEXAI", want: tool.AuthUnknown},
+		{name: "refreshable login", creds: fmt.Sprintf(`{"access_token": "tok", "refresh_token": "r1", "expiry_date": %d}`, past), want: tool.AuthLoggedIn},
+		{name: "expired login", creds: fmt.Sprintf(`{"access_token": "tok", "expiry_date": %d}`, past), want: tool.AuthExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+			for _, key := range []string{"GEMINI_API_KEY", "GOOGLE_API_KEY", "GOOGLE_GENAI_USE_VERTEXAI"} {
+				t.Setenv(key, "")
+			}
+			if tt.env != "" {
+				t.Setenv(tt.env, "1")
+			}
+			if tt.creds != "" {
+				if err := os.MkdirAll(filepath.Join(home, ".gemini"), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(home, ".gemini", "oauth_creds.json"), []byte(tt.creds), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := NewBalanceFetcher().AuthStatus(); got != tt.want {
+				t.Errorf("AuthStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
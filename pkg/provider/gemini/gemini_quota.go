@@ -0,0 +1,176 @@
+// Package gemini provides functionality to fetch Gemini CLI quota information.
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// codeAssistURL is the Code Assist API the Gemini CLI talks to when logged in with Google
+// (a variable for tests).
+var codeAssistURL = "https://cloudcode-pa.googleapis.com/v1internal"
+
+// OAuthCreds represents the Google OAuth tokens stored in ~/.gemini/oauth_creds.json
+type OAuthCreds struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiryDate   int64  `json:"expiry_date"` // Unix milliseconds
+}
+
+// QuotaBucket is the remaining quota of one model, as reported by retrieveUserQuota.
+type QuotaBucket struct {
+	ModelID           string  `json:"modelId"`
+	TokenType         string  `json:"tokenType"`
+	RemainingFraction float64 `json:"remainingFraction"`
+	RemainingAmount   string  `json:"remainingAmount,omitempty"`
+	ResetTime         string  `json:"resetTime,omitempty"` // RFC 3339
+}
+
+// geminiHomeDir returns the Gemini CLI data directory, ~/.gemini
+func geminiHomeDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".gemini"), nil
+}
+
+// loadOAuthCreds loads the Google login of the Gemini CLI.
+func loadOAuthCreds() (*OAuthCreds, error) {
+	home, err := geminiHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(home, "oauth_creds.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oauth_creds.json: %w", err)
+	}
+	var creds OAuthCreds
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse oauth_creds.json: %w", err)
+	}
+	if creds.AccessToken == "" {
+		return nil, fmt.Errorf("no access token in oauth_creds.json")
+	}
+	return &creds, nil
+}
+
+// CheckCredentials implements provider.CredentialsChecker for ~/.gemini/oauth_creds.json.
+func (b *BalanceFetcher) CheckCredentials() (string, error) {
+	home, err := geminiHomeDir()
+	if err != nil {
+		return "", err
+	}
+	_, err = loadOAuthCreds()
+	return filepath.Join(home, "oauth_creds.json"), err
+}
+
+// AuthStatus implements provider.AuthChecker for ~/.gemini/oauth_creds.json. The gemini
+// CLI refreshes an expired access token itself, so only one without a refresh token is
+// expired. An API key in the environment counts as logged in, and Vertex AI, which leaves
+// no file, as unknown.
+func (b *BalanceFetcher) AuthStatus() tool.AuthState {
+	creds, err := loadOAuthCreds()
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if os.Getenv("GEMINI_API_KEY") != "" || os.Getenv("GOOGLE_API_KEY") != "" {
+			return tool.AuthLoggedIn
+		}
+		if os.Getenv("GOOGLE_GENAI_USE_VERTEXAI") != "" {
+			return tool.AuthUnknown
+		}
+		return tool.AuthLoggedOut
+	case err != nil:
+		return tool.AuthUnknown
+	case creds.RefreshToken == "" && creds.ExpiryDate > 0 && time.Now().After(time.UnixMilli(creds.ExpiryDate)):
+		return tool.AuthExpired
+	default:
+		return tool.AuthLoggedIn
+	}
+}
+
+// FetchQuota fetches the remaining quota per model with the Gemini CLI's Google login.
+// The access token is refreshed by the gemini CLI itself; an expired one asks for a run of gemini.
+func FetchQuota(ctx context.Context) ([]QuotaBucket, error) {
+	creds, err := loadOAuthCreds()
+	if err != nil {
+		return nil, err
+	}
+	if creds.ExpiryDate > 0 && time.Now().After(time.UnixMilli(creds.ExpiryDate)) {
+		return nil, fmt.Errorf("login expired, run 'gemini' to refresh it")
+	}
+
+	project := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if project == "" {
+		var assist struct {
+			Project string `json:"cloudaicompanionProject"`
+		}
+		body := map[string]any{"metadata": map[string]string{"ideType": "IDE_UNSPECIFIED", "pluginType": "GEMINI"}}
+		if err := callCodeAssist(ctx, creds, "loadCodeAssist", body, &assist); err != nil {
+			return nil, err
+		}
+		project = assist.Project
+	}
+
+	var quota struct {
+		Buckets []QuotaBucket `json:"buckets"`
+	}
+	if err := callCodeAssist(ctx, creds, "retrieveUserQuota", map[string]string{"project": project}, &quota); err != nil {
+		return nil, err
+	}
+	if len(quota.Buckets) == 0 {
+		return nil, fmt.Errorf("no quota data in response")
+	}
+	return quota.Buckets, nil
+}
+
+// callCodeAssist posts body to a Code Assist method and decodes the response into out.
+func callCodeAssist(ctx context.Context, creds *OAuthCreds, method string, body, out any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", codeAssistURL+":"+method, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+creds.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "amazing-cli")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("unauthorized: run 'gemini' to re-authenticate")
+	default:
+		return fmt.Errorf("%s: API error %d: %s", method, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", method, err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package provider
 
 import (
 	"context"
+	"sync"
 
 	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
@@ -12,3 +13,76 @@ type BalanceFetcher interface {
 	// GetBalance fetches the current balance/usage for the tool.
 	GetBalance(ctx context.Context) *tool.Balance
 }
+
+// CredentialsChecker is implemented by balance fetchers that read the login the tool
+// saved, so "amazing doctor" can tell a missing login from a backend that's down.
+type CredentialsChecker interface {
+	// CheckCredentials returns the file the credentials are read from, and why they
+	// can't be used if they can't.
+	CheckCredentials() (path string, err error)
+}
+
+// AuthChecker is implemented by balance fetchers, and registered with RegisterAuth for
+// tools without one, that can tell from the tool's saved login whether it is logged in.
+// AuthStatus makes no requests, so it is cheap enough to run for every tool at startup.
+type AuthChecker interface {
+	AuthStatus() tool.AuthState
+}
+
+// Factory creates a BalanceFetcher for a tool.
+type Factory func() BalanceFetcher
+
+var (
+	registryMu   sync.RWMutex
+	registry     = make(map[string]Factory)
+	authRegistry = make(map[string]AuthChecker)
+)
+
+// Register adds a balance fetcher factory for the tool with the given name.
+// Registering a name again replaces the previous factory, so custom builds
+// can override the built-in fetchers.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		delete(registry, name)
+		return
+	}
+	registry[name] = factory
+}
+
+// Lookup returns the factory registered for the tool name.
+func Lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[name]
+	return factory, ok
+}
+
+// RegisterAuth adds the auth checker of a tool whose balance fetcher, if any, doesn't
+// implement AuthChecker. A nil checker removes it.
+func RegisterAuth(name string, checker AuthChecker) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if checker == nil {
+		delete(authRegistry, name)
+		return
+	}
+	authRegistry[name] = checker
+}
+
+// CheckAuth returns whether the tool is logged in, from its registered auth checker or
+// its balance fetcher. Returns tool.AuthUnknown for tools with neither.
+func CheckAuth(t *tool.Tool) tool.AuthState {
+	registryMu.RLock()
+	checker, ok := authRegistry[t.Name]
+	factory, hasFactory := registry[t.Name]
+	registryMu.RUnlock()
+	if !ok && hasFactory {
+		checker, ok = factory().(AuthChecker)
+	}
+	if !ok {
+		return tool.AuthUnknown
+	}
+	return checker.AuthStatus()
+}
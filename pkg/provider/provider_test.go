@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+type fakeFetcher struct{ balance *tool.Balance }
+
+func (f fakeFetcher) GetBalance(ctx context.Context) *tool.Balance { return f.balance }
+
+func TestRegister(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	want := &tool.Balance{Percentage: 42, Display: "42%"}
+	Register("fake", func() BalanceFetcher { return fakeFetcher{balance: want} })
+	defer Register("fake", nil)
+
+	tests := []struct {
+		name      string
+		tool      string
+		supported bool
+		balance   *tool.Balance
+	}{
+		{name: "built-in codex", tool: "codex", supported: true},
+		{name: "registered fake", tool: "fake", supported: true, balance: want},
+		{name: "unregistered", tool: "nope", supported: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &tool.Tool{Name: tt.tool}
+			if got := SupportsBalance(tl); got != tt.supported {
+				t.Fatalf("SupportsBalance(%q) = %v, want %v", tt.tool, got, tt.supported)
+			}
+			if tt.tool == "codex" {
+				return // 不访问真实的 codex
+			}
+			if got := FetchBalance(context.Background(), tl); got != tt.balance {
+				t.Errorf("FetchBalance(%q) = %v, want %v", tt.tool, got, tt.balance)
+			}
+		})
+	}
+
+	Register("fake", nil)
+	if SupportsBalance(&tool.Tool{Name: "fake"}) {
+		t.Error("Register(nil) should remove the factory")
+	}
+}
+
+// slowFetcher blocks until its context is cancelled
+type slowFetcher struct{ cancelled chan struct{} }
+
+func (f slowFetcher) GetBalance(ctx context.Context) *tool.Balance {
+	<-ctx.Done()
+	close(f.cancelled)
+	return &tool.Balance{Percentage: 1}
+}
+
+func TestFetchAll(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fast := &tool.Balance{Percentage: 42, Display: "42%"}
+	slow := slowFetcher{cancelled: make(chan struct{})}
+	Register("fake-fast", func() BalanceFetcher { return fakeFetcher{balance: fast} })
+	Register("fake-slow", func() BalanceFetcher { return slow })
+	Register("fake-empty", func() BalanceFetcher { return fakeFetcher{} })
+	defer Register("fake-fast", nil)
+	defer Register("fake-slow", nil)
+	defer Register("fake-empty", nil)
+
+	registry := tool.NewRegistry()
+	for _, name := range []string{"fake-fast", "fake-slow", "fake-empty", "no-provider"} {
+		registry.Register(&tool.Tool{Name: name, Command: "amazing-cli-test-missing"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	got := FetchAll(ctx, registry, true)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FetchAll() took %s, want it to stop at the budget", elapsed)
+	}
+	if len(got) != 1 || got["fake-fast"] != fast {
+		t.Errorf("FetchAll() = %v, want only fake-fast", got)
+	}
+	select {
+	case <-slow.cancelled:
+	case <-time.After(time.Second):
+		t.Error("the slow provider's context wasn't cancelled")
+	}
+
+	if got := FetchAll(context.Background(), registry, false); len(got) != 0 {
+		t.Errorf("FetchAll(all=false) = %v, want nothing for tools that aren't installed", got)
+	}
+}
+
+// authFetcher is a fetcher that also knows whether its tool is logged in
+type authFetcher struct {
+	fakeFetcher
+	state tool.AuthState
+}
+
+func (f authFetcher) AuthStatus() tool.AuthState { return f.state }
+
+func TestCheckAuth(t *testing.T) {
+	Register("fake-auth", func() BalanceFetcher { return authFetcher{state: tool.AuthExpired} })
+	defer Register("fake-auth", nil)
+	Register("fake-balance", func() BalanceFetcher { return fakeFetcher{} })
+	defer Register("fake-balance", nil)
+	RegisterAuth("fake-login", authFetcher{state: tool.AuthLoggedOut})
+	defer RegisterAuth("fake-login", nil)
+
+	tests := []struct {
+		tool string
+		want tool.AuthState
+	}{
+		{tool: "fake-auth", want: tool.AuthExpired},
+		{tool: "fake-login", want: tool.AuthLoggedOut},
+		{tool: "fake-balance", want: tool.AuthUnknown},
+		{tool: "nope", want: tool.AuthUnknown},
+	}
+	for _, tt := range tests {
+		if got := CheckAuth(&tool.Tool{Name: tt.tool}); got != tt.want {
+			t.Errorf("CheckAuth(%q) = %q, want %q", tt.tool, got, tt.want)
+		}
+	}
+
+	RegisterAuth("fake-login", nil)
+	if got := CheckAuth(&tool.Tool{Name: "fake-login"}); got != tool.AuthUnknown {
+		t.Errorf("CheckAuth() after RegisterAuth(nil) = %q, want unknown", got)
+	}
+}
@@ -0,0 +1,189 @@
+// Package providertest checks that a balance fetcher behaves the way the launcher
+// expects, so every provider can run the same suite from its tests:
+//
+//	func TestConformance(t *testing.T) {
+//		providertest.Run(t, func(t *testing.T, b providertest.Backend) providertest.Fetcher {
+//			server := httptest.NewServer(providertest.Handler(b, healthyAPI))
+//			t.Cleanup(server.Close)
+//			return yourtool.NewBalanceFetcher(server.URL)
+//		})
+//	}
+//
+// See pkg/provider/example for a complete provider with a fake backend.
+package providertest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Backend is how the fake backend of the provider under test must behave.
+type Backend int
+
+const (
+	Healthy      Backend = iota // Answers with the usage of an account with some quota left
+	Slow                        // Answers like Healthy, after SlowDelay
+	Failing                     // Answers every request with an error (e.g., HTTP 500)
+	Malformed                   // Answers with a body that isn't valid JSON
+	Unauthorized                // Rejects the credentials with HTTP 401
+	Hanging                     // Never answers: read the request body, then wait for r.Context() to be done
+)
+
+// SlowDelay is how long a Slow backend takes to answer: slow, but well within the
+// launcher's budget, so fetchers mustn't give up on it.
+const SlowDelay = 300 * time.Millisecond
+
+// Handler fakes a backend behaving like b for HTTP providers: healthy serves the
+// provider's API for Healthy and Slow, and every other behavior is the same for all
+// providers.
+func Handler(b Backend, healthy http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch b {
+		case Healthy:
+			healthy.ServeHTTP(w, r)
+		case Slow:
+			select {
+			case <-time.After(SlowDelay):
+				healthy.ServeHTTP(w, r)
+			case <-r.Context().Done():
+			}
+		case Failing:
+			http.Error(w, "upstream unavailable", http.StatusInternalServerError)
+		case Malformed:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"limits": [{"wind`)
+		case Unauthorized:
+			http.Error(w, `{"error": "invalid_token"}`, http.StatusUnauthorized)
+		case Hanging:
+			io.Copy(io.Discard, r.Body) // The server only notices the client giving up once the body is read
+			<-r.Context().Done()
+		}
+	})
+}
+
+// Fetcher is provider.BalanceFetcher. It is repeated here so the built-in providers,
+// which pkg/provider imports, can run the suite without an import cycle.
+type Fetcher interface {
+	GetBalance(ctx context.Context) *tool.Balance
+}
+
+// NewFetcher returns a fetcher talking to a fake backend that behaves like b.
+// Clean the backend up with t.Cleanup.
+type NewFetcher func(t *testing.T, b Backend) Fetcher
+
+// Timeout is how long a provider may keep going after its context is done. The
+// launcher gives all providers a few seconds together (provider.FetchBudget).
+const Timeout = 500 * time.Millisecond
+
+// Outcomes a scenario expects from GetBalance
+type outcome int
+
+const (
+	complete outcome = iota // A balance with every field the TUI draws
+	unknown                 // An unknown balance (tool.UnknownDisplay), not nil
+	gaveUp                  // nil or an unknown balance, within Timeout of the deadline
+)
+
+// scenarios is the matrix every provider is run against.
+var scenarios = []struct {
+	name     string
+	backend  Backend
+	deadline time.Duration // 0 means the context is already done
+	want     outcome
+}{
+	{"balance", Healthy, 5 * time.Second, complete},
+	{"slow backend", Slow, 5 * time.Second, complete},
+	{"server error", Failing, 5 * time.Second, unknown},
+	{"malformed JSON", Malformed, 5 * time.Second, unknown},
+	{"unauthorized", Unauthorized, 5 * time.Second, unknown},
+	{"timeout", Hanging, 200 * time.Millisecond, gaveUp},
+	{"slower than the deadline", Slow, SlowDelay / 3, gaveUp},
+	{"cancelled", Hanging, 0, gaveUp},
+}
+
+// Run runs the conformance suite against the fetchers returned by newFetcher:
+//
+//   - A healthy backend, even a slow one, gives a complete balance: Percentage 0-100 left (or an
+//     absolute amount in Unit with Total and Remaining), a Display, FetchedAt and labeled
+//     limits, all holding the share left.
+//   - A backend that fails, answers with malformed JSON or rejects the credentials gives an
+//     unknown balance (tool.UnknownDisplay) rather than nil, so the TUI shows "?%" and keeps
+//     the cached balance, instead of "loading" forever.
+//   - A backend that doesn't answer before the context's deadline, or a cancelled context,
+//     returns within Timeout of the deadline, with nil or an unknown balance.
+func Run(t *testing.T, newFetcher NewFetcher) {
+	t.Helper()
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			b := fetch(t, newFetcher(t, sc.backend), sc.deadline)
+			switch sc.want {
+			case complete:
+				if b == nil || b.Unknown() {
+					t.Fatalf("GetBalance() = %+v, want a balance from a healthy backend", b)
+				}
+				checkBalance(t, b)
+			case unknown:
+				if b == nil || !b.Unknown() {
+					t.Fatalf("GetBalance() = %+v, want Display %q", b, tool.UnknownDisplay)
+				}
+				if b.FetchedAt.IsZero() {
+					t.Error("FetchedAt is zero for an unknown balance")
+				}
+			case gaveUp:
+				if b != nil && !b.Unknown() {
+					t.Errorf("GetBalance() = %+v after the deadline, want nil or an unknown balance", b)
+				}
+			}
+		})
+	}
+}
+
+// fetch calls GetBalance with a context that times out after d (already done when d is 0)
+// and fails the test if it doesn't return within Timeout of that.
+func fetch(t *testing.T, f Fetcher, d time.Duration) *tool.Balance {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	done := make(chan *tool.Balance, 1)
+	go func() { done <- f.GetBalance(ctx) }()
+	select {
+	case b := <-done:
+		return b
+	case <-time.After(d + Timeout):
+		t.Fatalf("GetBalance() still running %v after its context was done", Timeout)
+		return nil
+	}
+}
+
+// checkBalance reports the fields of a fetched balance that the TUI can't draw.
+func checkBalance(t *testing.T, b *tool.Balance) {
+	t.Helper()
+	if b.Display == "" {
+		t.Error("Display is empty")
+	}
+	if b.FetchedAt.IsZero() || time.Since(b.FetchedAt) > time.Minute {
+		t.Errorf("FetchedAt = %v, want the time of the fetch", b.FetchedAt)
+	}
+	if b.Unit == tool.UnitPercent {
+		if b.Percentage < 0 || b.Percentage > 100 {
+			t.Errorf("Percentage = %d, want 0-100", b.Percentage)
+		}
+	} else if b.Remaining < 0 || b.Total < 0 || b.Total > 0 && b.Remaining > b.Total {
+		t.Errorf("Remaining/Total = %v/%v %s, want 0 <= Remaining <= Total", b.Remaining, b.Total, b.Unit)
+	}
+	for _, l := range b.Limits {
+		if l.Label == "" || l.Display == "" {
+			t.Errorf("limit %+v needs a Label and a Display", l)
+		}
+		if l.RemainingPercent < 0 || l.RemainingPercent > 100 {
+			t.Errorf("limit %s RemainingPercent = %d, want 0-100", l.Label, l.RemainingPercent)
+		}
+	}
+}
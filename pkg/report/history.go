@@ -0,0 +1,181 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Export formats of WriteHistory
+const (
+	FormatCSV = "csv" // One row per launch, for spreadsheets
+)
+
+// ParseAge parses a lookback period such as "30d", "2w" or "12h". Days and weeks are
+// added to the units of time.ParseDuration.
+func ParseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid period %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid period %q", s)
+	}
+	return d, nil
+}
+
+// ToolStats sums up the launches of one tool.
+type ToolStats struct {
+	Tool     string    `json:"tool"`
+	Launches int       `json:"launches"`
+	Tokens   int64     `json:"tokens,omitempty"` // Tokens of the sessions imported from the agent's logs
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+}
+
+// Stats sums up the launches per tool, most launched first.
+func Stats(launches []config.Launch) []ToolStats {
+	index := map[string]int{}
+	var stats []ToolStats
+	for _, l := range launches {
+		i, ok := index[l.Tool]
+		if !ok {
+			i = len(stats)
+			index[l.Tool] = i
+			stats = append(stats, ToolStats{Tool: l.Tool, First: l.At, Last: l.At})
+		}
+		s := &stats[i]
+		s.Launches++
+		s.Tokens += l.Tokens
+		if l.At.Before(s.First) {
+			s.First = l.At
+		}
+		if l.At.After(s.Last) {
+			s.Last = l.At
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].Launches > stats[j].Launches })
+	return stats
+}
+
+// ProjectStats sums up the launches in one project, for attributing the time and tokens
+// spent to it.
+type ProjectStats struct {
+	Project  string    `json:"project"` // Root of the git repository, or the directory outside one
+	Launches int       `json:"launches"`
+	Seconds  float64   `json:"seconds"`          // Time spent in the sessions with a known duration
+	Tokens   int64     `json:"tokens,omitempty"` // Tokens of the sessions imported from the agents' logs
+	Tools    []string  `json:"tools"`            // Tools launched in it, first launched first
+	Last     time.Time `json:"last"`
+}
+
+// Duration returns the time spent in the project's sessions with a known duration.
+func (p ProjectStats) Duration() time.Duration {
+	return time.Duration(p.Seconds * float64(time.Second))
+}
+
+// Projects sums up the launches per project, most time spent first. Launches without a
+// directory are left out.
+func Projects(launches []config.Launch) []ProjectStats {
+	index := map[string]int{}
+	var stats []ProjectStats
+	for _, l := range launches {
+		project := l.ProjectPath()
+		if project == "" {
+			continue
+		}
+		i, ok := index[project]
+		if !ok {
+			i = len(stats)
+			index[project] = i
+			stats = append(stats, ProjectStats{Project: project})
+		}
+		p := &stats[i]
+		p.Launches++
+		p.Seconds += l.Seconds
+		p.Tokens += l.Tokens
+		if !slices.Contains(p.Tools, l.Tool) {
+			p.Tools = append(p.Tools, l.Tool)
+		}
+		if l.At.After(p.Last) {
+			p.Last = l.At
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Seconds != stats[j].Seconds {
+			return stats[i].Seconds > stats[j].Seconds
+		}
+		return stats[i].Launches > stats[j].Launches
+	})
+	return stats
+}
+
+// WriteHistory writes the launches as CSV, or as JSON with per-tool and per-project stats.
+func WriteHistory(w io.Writer, format string, launches []config.Launch) error {
+	switch format {
+	case FormatCSV:
+		return writeHistoryCSV(w, launches)
+	case FormatJSON:
+		return writeHistoryJSON(w, launches)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func writeHistoryCSV(w io.Writer, launches []config.Launch) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "tool", "dir", "args", "note", "tokens", "project", "seconds"}); err != nil {
+		return err
+	}
+	for _, l := range launches {
+		tokens, seconds := "", ""
+		if l.Tokens > 0 {
+			tokens = strconv.FormatInt(l.Tokens, 10)
+		}
+		if l.Seconds > 0 {
+			seconds = strconv.Itoa(int(l.Seconds))
+		}
+		record := []string{l.At.Format(time.RFC3339), l.Tool, l.Dir, tool.JoinArgs(l.Args), l.Note, tokens, l.ProjectPath(), seconds}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeHistoryJSON(w io.Writer, launches []config.Launch) error {
+	out := struct {
+		SchemaVersion int            `json:"schema_version"`
+		Launches      []jsonLaunch   `json:"launches"`
+		Tools         []ToolStats    `json:"tools"`
+		Projects      []ProjectStats `json:"projects"`
+	}{SchemaVersion: HistorySchema, Launches: make([]jsonLaunch, 0, len(launches)), Tools: Stats(launches), Projects: Projects(launches)}
+	for _, l := range launches {
+		out.Launches = append(out.Launches, jsonLaunch{
+			Tool: l.Tool, At: l.At, Dir: l.Dir, Args: l.Args, Note: l.Note,
+			Project: l.ProjectPath(), Seconds: l.Seconds, Session: l.Session, Tokens: l.Tokens,
+		})
+	}
+	if out.Tools == nil {
+		out.Tools = []ToolStats{}
+	}
+	if out.Projects == nil {
+		out.Projects = []ProjectStats{}
+	}
+	return writeIndented(w, out)
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"-3d", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, %v, want %v (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func testLaunches() []config.Launch {
+	at := time.Date(2026, 2, 10, 16, 22, 0, 0, time.UTC)
+	return []config.Launch{
+		{Tool: "codex", At: at, Dir: "/src/api/cmd", Project: "/src/api", Seconds: 1500, Note: "fixing auth bug, again"},
+		{Tool: "claude", At: at.Add(time.Hour), Args: []string{"--model", "opus 4"}},
+		{Tool: "codex", At: at.Add(2 * time.Hour), Dir: "/src/web", Session: "x1", Tokens: 350},
+	}
+}
+
+func TestWriteHistory_CSV(t *testing.T) {
+	var b strings.Builder
+	if err := WriteHistory(&b, FormatCSV, testLaunches()); err != nil {
+		t.Fatalf("WriteHistory() error: %v", err)
+	}
+	want := `time,tool,dir,args,note,tokens,project,seconds
+2026-02-10T16:22:00Z,codex,/src/api/cmd,,"fixing auth bug, again",,/src/api,1500
+2026-02-10T17:22:00Z,claude,,--model 'opus 4',,,,
+2026-02-10T18:22:00Z,codex,/src/web,,,350,/src/web,
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteHistory(csv) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteHistory_JSON(t *testing.T) {
+	var b strings.Builder
+	if err := WriteHistory(&b, FormatJSON, testLaunches()); err != nil {
+		t.Fatalf("WriteHistory() error: %v", err)
+	}
+	var got struct {
+		SchemaVersion int            `json:"schema_version"`
+		Launches      []jsonLaunch   `json:"launches"`
+		Tools         []ToolStats    `json:"tools"`
+		Projects      []ProjectStats `json:"projects"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+	}
+	if got.SchemaVersion != HistorySchema {
+		t.Errorf("schema_version = %d, want %d", got.SchemaVersion, HistorySchema)
+	}
+	if len(got.Launches) != 3 || got.Launches[2].Project != "/src/web" {
+		t.Errorf("launches = %+v, want 3 with their projects", got.Launches)
+	}
+	if len(got.Tools) != 2 || got.Tools[0].Tool != "codex" || got.Tools[0].Launches != 2 || got.Tools[0].Tokens != 350 || !got.Tools[0].Last.Equal(got.Launches[2].At) {
+		t.Errorf("tools = %+v, want codex first with 2 launches and 350 tokens", got.Tools)
+	}
+	if len(got.Projects) != 2 || got.Projects[0].Project != "/src/api" || got.Projects[0].Seconds != 1500 || got.Projects[1].Tokens != 350 {
+		t.Errorf("projects = %+v, want /src/api with 25m, then /src/web with 350 tokens", got.Projects)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	at := time.Date(2026, 2, 10, 16, 22, 0, 0, time.UTC)
+	launches := []config.Launch{
+		{Tool: "codex", At: at, Dir: "/src/api", Seconds: 600},
+		{Tool: "claude", At: at.Add(time.Hour), Dir: "/src/web", Seconds: 300},
+		{Tool: "claude", At: at.Add(2 * time.Hour), Dir: "/src/api/cmd", Project: "/src/api", Seconds: 900},
+		{Tool: "codex", At: at.Add(3 * time.Hour)}, // No directory
+	}
+	got := Projects(launches)
+	if len(got) != 2 {
+		t.Fatalf("Projects() = %+v, want 2 projects", got)
+	}
+	api := got[0]
+	if api.Project != "/src/api" || api.Launches != 2 || api.Duration() != 25*time.Minute || !api.Last.Equal(at.Add(2*time.Hour)) {
+		t.Errorf("first project = %+v, want /src/api with 2 launches and 25m", api)
+	}
+	if strings.Join(api.Tools, ",") != "codex,claude" {
+		t.Errorf("/src/api tools = %v, want codex then claude", api.Tools)
+	}
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Reset is an upcoming reset of one of a tool's limit windows.
+type Reset struct {
+	Tool  *tool.Tool
+	Limit tool.LimitDetail
+}
+
+// Resets returns the limit windows of the entries that reset after now, soonest first.
+func Resets(entries []Entry, now time.Time) []Reset {
+	var resets []Reset
+	for _, e := range entries {
+		if e.Balance == nil {
+			continue
+		}
+		for _, l := range e.Balance.Limits {
+			if l.ResetsAt.After(now) {
+				resets = append(resets, Reset{Tool: e.Tool, Limit: l})
+			}
+		}
+	}
+	sort.SliceStable(resets, func(i, j int) bool {
+		return resets[i].Limit.ResetsAt.Before(resets[j].Limit.ResetsAt)
+	})
+	return resets
+}
+
+// WriteResets lists the resets in aligned columns, e.g. "codex  5h  16:22 on 10 Feb  80% left".
+func WriteResets(w io.Writer, resets []Reset) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range resets {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Tool.Name, r.Limit.Label, timefmt.DateTime(r.Limit.ResetsAt), r.Limit.Display)
+	}
+	return tw.Flush()
+}
+
+// icsTime is the UTC date-time format of iCalendar (RFC 5545).
+const icsTime = "20060102T150405Z"
+
+// WriteICS writes the resets as an iCalendar file with one event per reset, stamped now.
+// UIDs only depend on the tool, limit and reset time, so re-importing updates the events.
+func WriteICS(w io.Writer, resets []Reset, now time.Time) error {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(foldICSLine(name + ":" + value))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//amazing-cli//resets//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("X-WR-CALNAME", "Agent quota resets")
+	for _, r := range resets {
+		at := r.Limit.ResetsAt.UTC()
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("%s-%s-%d@amazing-cli", r.Tool.Name, strings.ToLower(r.Limit.Label), at.Unix()))
+		line("DTSTAMP", now.UTC().Format(icsTime))
+		line("DTSTART", at.Format(icsTime))
+		line("DTEND", at.Format(icsTime))
+		line("SUMMARY", escapeICSText(fmt.Sprintf("%s %s limit resets", r.Tool.DisplayName, r.Limit.Label)))
+		if r.Limit.Display != "" {
+			line("DESCRIPTION", escapeICSText(r.Limit.Display+" when exported"))
+		}
+		line("TRANSP", "TRANSPARENT") // Resets don't make anyone busy
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeICSText escapes the characters with a meaning in iCalendar text values.
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// foldICSLine splits content lines longer than 75 octets, continuing them on lines
+// starting with a space, without splitting UTF-8 characters.
+func foldICSLine(s string) string {
+	const limit = 75
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
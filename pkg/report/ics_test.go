@@ -0,0 +1,81 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestResets(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{
+			Tool: &tool.Tool{Name: "codex", DisplayName: "codex"},
+			Balance: &tool.Balance{Limits: []tool.LimitDetail{
+				{Label: tool.LimitFiveHour, Display: "80% left", ResetsAt: now.Add(3 * time.Hour)},
+				{Label: tool.LimitWeekly, Display: "15% left", ResetsAt: now.Add(48 * time.Hour)},
+				{Label: "old", ResetsAt: now.Add(-time.Hour)},
+				{Label: "unknown"},
+			}},
+		},
+		{
+			Tool:    &tool.Tool{Name: "copilot", DisplayName: "copilot"},
+			Balance: &tool.Balance{Limits: []tool.LimitDetail{{Label: "Monthly", ResetsAt: now.Add(time.Hour)}}},
+		},
+		{Tool: &tool.Tool{Name: "claude", DisplayName: "claude"}},
+	}
+
+	var got []string
+	for _, r := range Resets(entries, now) {
+		got = append(got, r.Tool.Name+" "+r.Limit.Label)
+	}
+	if want := []string{"copilot Monthly", "codex 5h", "codex Wk"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Resets() = %v, want %v", got, want)
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	resets := []Reset{{
+		Tool:  &tool.Tool{Name: "codex", DisplayName: "codex, by OpenAI"},
+		Limit: tool.LimitDetail{Label: tool.LimitWeekly, Display: "15% left", ResetsAt: time.Date(2026, 2, 12, 16, 22, 0, 0, time.UTC)},
+	}}
+
+	var b strings.Builder
+	if err := WriteICS(&b, resets, now); err != nil {
+		t.Fatalf("WriteICS() error: %v", err)
+	}
+	want := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//amazing-cli//resets//EN\r\n" +
+		"CALSCALE:GREGORIAN\r\n" +
+		"X-WR-CALNAME:Agent quota resets\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:codex-wk-1770913320@amazing-cli\r\n" +
+		"DTSTAMP:20260210T120000Z\r\n" +
+		"DTSTART:20260212T162200Z\r\n" +
+		"DTEND:20260212T162200Z\r\n" +
+		"SUMMARY:codex\\, by OpenAI Wk limit resets\r\n" +
+		"DESCRIPTION:15% left when exported\r\n" +
+		"TRANSP:TRANSPARENT\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteICS() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("é", 50)
+	folded := foldICSLine(long)
+	for _, line := range strings.Split(folded, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+	}
+	if got := strings.ReplaceAll(folded, "\r\n ", ""); got != long {
+		t.Errorf("unfolded = %q, want %q", got, long)
+	}
+}
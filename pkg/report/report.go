@@ -0,0 +1,214 @@
+// Package report formats tool balances and the launch history for scripts, CI jobs and spreadsheets.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Output formats of Write
+const (
+	FormatText = "text" // Aligned columns for terminals
+	FormatJSON = "json"
+	FormatGHA  = "gha" // Markdown table for a GitHub Actions job summary
+)
+
+// Entry is one tool in a report. Balance is nil when it couldn't be fetched.
+type Entry struct {
+	Tool    *tool.Tool
+	Balance *tool.Balance
+}
+
+// ValidFormat reports whether Write supports format.
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatText, FormatJSON, FormatGHA:
+		return true
+	}
+	return false
+}
+
+// Write writes the entries in the given format.
+func Write(w io.Writer, format string, entries []Entry) error {
+	switch format {
+	case FormatText:
+		return writeText(w, entries)
+	case FormatJSON:
+		return writeJSON(w, entries)
+	case FormatGHA:
+		return writeGHA(w, entries)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func writeText(w io.Writer, entries []Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Tool.Name, balanceText(e.Balance), strings.Join(limitTexts(e.Balance), "  "))
+	}
+	return tw.Flush()
+}
+
+// jsonLimit and jsonBalance are the JSON form of a balance, with the remaining share spelled out.
+type jsonLimit struct {
+	Label            string     `json:"label"`
+	RemainingPercent int        `json:"remaining_percent"`
+	ResetsAt         *time.Time `json:"resets_at,omitempty"`
+}
+
+type jsonBalance struct {
+	RemainingPercent *int        `json:"remaining_percent,omitempty"`
+	Unit             string      `json:"unit,omitempty"`
+	Remaining        *float64    `json:"remaining,omitempty"`
+	Total            *float64    `json:"total,omitempty"`
+	Limits           []jsonLimit `json:"limits,omitempty"`
+	Credits          string      `json:"credits,omitempty"`
+	FetchedAt        *time.Time  `json:"fetched_at,omitempty"`
+}
+
+type jsonEntry struct {
+	Tool      string `json:"tool"`
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	jsonBalance
+}
+
+// newJSONBalance returns the JSON form of b; b may be nil.
+func newJSONBalance(b *tool.Balance) jsonBalance {
+	var jb jsonBalance
+	if b == nil {
+		return jb
+	}
+	if pct, ok := b.RemainingPercent(); ok {
+		jb.RemainingPercent = &pct
+	}
+	if b.Unit != tool.UnitPercent {
+		jb.Unit, jb.Remaining = string(b.Unit), &b.Remaining
+		if b.Total > 0 {
+			jb.Total = &b.Total
+		}
+	}
+	for _, l := range knownLimits(b) {
+		jl := jsonLimit{Label: l.Label, RemainingPercent: l.RemainingPercent}
+		if !l.ResetsAt.IsZero() {
+			jl.ResetsAt = &l.ResetsAt
+		}
+		jb.Limits = append(jb.Limits, jl)
+	}
+	jb.Credits = b.Credits
+	if !b.FetchedAt.IsZero() {
+		jb.FetchedAt = &b.FetchedAt
+	}
+	return jb
+}
+
+func writeJSON(w io.Writer, entries []Entry) error {
+	out := struct {
+		SchemaVersion int         `json:"schema_version"`
+		Tools         []jsonEntry `json:"tools"`
+	}{SchemaVersion: UsageSchema, Tools: make([]jsonEntry, 0, len(entries))}
+	for _, e := range entries {
+		out.Tools = append(out.Tools, jsonEntry{Tool: e.Tool.Name, Name: e.Tool.DisplayName, Available: e.Balance != nil, jsonBalance: newJSONBalance(e.Balance)})
+	}
+	return writeIndented(w, out)
+}
+
+// writeIndented writes v as indented JSON.
+func writeIndented(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func writeGHA(w io.Writer, entries []Entry) error {
+	var b strings.Builder
+	b.WriteString("## Agent quota status\n\n")
+	b.WriteString("| | Tool | Balance | Limits |\n| --- | --- | --- | --- |\n")
+	for _, e := range entries {
+		limits := strings.Join(limitTexts(e.Balance), "<br>")
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", statusIcon(e.Balance), markdownCell(e.Tool.DisplayName), markdownCell(balanceText(e.Balance)), markdownCell(limits))
+	}
+	_, err := io.WriteString(w, b.String()+"\n")
+	return err
+}
+
+// statusIcon shows the lowest share left across the balance and its limits at a glance.
+func statusIcon(b *tool.Balance) string {
+	if b == nil {
+		return "⚪"
+	}
+	lowest, ok := b.RemainingPercent()
+	if !ok {
+		lowest = 100
+	}
+	for _, l := range knownLimits(b) {
+		lowest = min(lowest, l.RemainingPercent)
+	}
+	switch {
+	case lowest <= 20:
+		return "🔴"
+	case lowest <= 40:
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
+
+func balanceText(b *tool.Balance) string {
+	if b == nil {
+		return "unavailable"
+	}
+	if b.Unit == tool.UnitPercent {
+		return fmt.Sprintf("%d%%", b.Percentage)
+	}
+	return b.AmountDisplay()
+}
+
+// limitTexts describes each limit window, e.g. "5h 80% (resets 14:00 on 10 Feb)".
+func limitTexts(b *tool.Balance) []string {
+	if b == nil {
+		return nil
+	}
+	var texts []string
+	for _, l := range knownLimits(b) {
+		text := fmt.Sprintf("%s %d%%", l.Label, l.RemainingPercent)
+		switch {
+		case !l.ResetsAt.IsZero():
+			text += " (resets " + timefmt.DateTime(l.ResetsAt) + ")"
+		case l.ResetTime != "":
+			text += " (" + l.ResetTime + ")"
+		}
+		texts = append(texts, text)
+	}
+	if b.Credits != "" {
+		texts = append(texts, "credits "+b.Credits)
+	}
+	return texts
+}
+
+// knownLimits returns the limits whose usage is known ("?" displays mean it isn't).
+func knownLimits(b *tool.Balance) []tool.LimitDetail {
+	var limits []tool.LimitDetail
+	for _, l := range b.Limits {
+		if !strings.Contains(l.Display, "?") {
+			limits = append(limits, l)
+		}
+	}
+	return limits
+}
+
+// markdownCell escapes the characters that would break a table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
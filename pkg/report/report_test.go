@@ -0,0 +1,83 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func testEntries() []Entry {
+	resets := time.Date(2026, 2, 10, 16, 22, 0, 0, time.Local)
+	return []Entry{
+		{
+			Tool: &tool.Tool{Name: "codex", DisplayName: "codex"},
+			Balance: &tool.Balance{Percentage: 80, Limits: []tool.LimitDetail{
+				{Label: tool.LimitFiveHour, RemainingPercent: 80, Display: "80% left"},
+				{Label: tool.LimitWeekly, RemainingPercent: 15, Display: "15% left", ResetsAt: resets},
+			}},
+		},
+		{
+			Tool:    &tool.Tool{Name: "copilot", DisplayName: "copilot"},
+			Balance: &tool.Balance{Unit: tool.UnitRequests, Remaining: 120, Total: 300},
+		},
+		{Tool: &tool.Tool{Name: "claude", DisplayName: "claude | code"}},
+	}
+}
+
+func TestWrite_GHA(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, FormatGHA, testEntries()); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	want := `## Agent quota status
+
+| | Tool | Balance | Limits |
+| --- | --- | --- | --- |
+| 🔴 | codex | 80% | 5h 80%<br>Wk 15% (resets 16:22 on 10 Feb) |
+| 🟡 | copilot | 120/300 requests |  |
+| ⚪ | claude \| code | unavailable |  |
+
+`
+	if got := b.String(); got != want {
+		t.Errorf("Write(gha) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, FormatJSON, testEntries()); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	var out struct {
+		SchemaVersion int         `json:"schema_version"`
+		Tools         []jsonEntry `json:"tools"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+	}
+	if out.SchemaVersion != UsageSchema {
+		t.Errorf("schema_version = %d, want %d", out.SchemaVersion, UsageSchema)
+	}
+	got := out.Tools
+	if len(got) != 3 {
+		t.Fatalf("got %d entries, want 3", len(got))
+	}
+	if got[0].RemainingPercent == nil || *got[0].RemainingPercent != 80 || len(got[0].Limits) != 2 || got[0].Limits[1].ResetsAt == nil {
+		t.Errorf("codex entry = %+v", got[0])
+	}
+	if got[1].RemainingPercent == nil || *got[1].RemainingPercent != 40 || got[1].Unit != "requests" {
+		t.Errorf("copilot entry = %+v", got[1])
+	}
+	if got[2].Available {
+		t.Errorf("claude entry = %+v, want unavailable", got[2])
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	if err := Write(&strings.Builder{}, "xml", nil); err == nil {
+		t.Error("Write(xml) succeeded, want an error")
+	}
+}
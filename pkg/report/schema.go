@@ -0,0 +1,57 @@
+package report
+
+import (
+	"io"
+	"time"
+)
+
+// Schema versions of the JSON outputs. Each output is an object whose "schema_version"
+// gives the shape of the rest, so scripts can check it before reading on. Within a
+// version fields are only ever added; removing or renaming one, or changing what it means,
+// takes a new version. The shapes are the json* types below and in report.go, and their
+// golden files in testdata.
+const (
+	ListSchema    = 1 // amazing list --json: {"schema_version", "tools": [jsonListedTool]}
+	UsageSchema   = 1 // amazing usage --format json: {"schema_version", "tools": [jsonEntry]}
+	HistorySchema = 1 // amazing history export --format json: {"schema_version", "launches": [jsonLaunch], "tools": [ToolStats], "projects": [ProjectStats]}
+)
+
+// ListedTool is a tool in "amazing list --json".
+type ListedTool struct {
+	Name        string
+	DisplayName string
+	Status      string // installed, not-installed or unsupported
+	Path        string // Binary the tool is launched from ("" when not installed)
+}
+
+type jsonListedTool struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Status      string `json:"status"`
+	Path        string `json:"path,omitempty"`
+}
+
+// WriteList writes the tools as JSON.
+func WriteList(w io.Writer, tools []ListedTool) error {
+	out := struct {
+		SchemaVersion int              `json:"schema_version"`
+		Tools         []jsonListedTool `json:"tools"`
+	}{SchemaVersion: ListSchema, Tools: make([]jsonListedTool, 0, len(tools))}
+	for _, t := range tools {
+		out.Tools = append(out.Tools, jsonListedTool(t))
+	}
+	return writeIndented(w, out)
+}
+
+// jsonLaunch is a launch in "amazing history export --format json".
+type jsonLaunch struct {
+	Tool    string    `json:"tool"`
+	At      time.Time `json:"at"`
+	Dir     string    `json:"dir,omitempty"`
+	Args    []string  `json:"args,omitempty"`
+	Note    string    `json:"note,omitempty"`
+	Project string    `json:"project,omitempty"` // Root of the git repository Dir is in, or Dir outside one
+	Seconds float64   `json:"seconds,omitempty"` // How long the session lasted; left out if unknown
+	Session string    `json:"session,omitempty"` // ID of the agent's own session, for imported launches
+	Tokens  int64     `json:"tokens,omitempty"`  // Tokens used, for imported launches
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with testdata/name, or rewrites it with -update. A change to a
+// golden file is a change to the schema its output is versioned by.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test -update if the change is intended):\n%s", path, got)
+	}
+}
+
+func TestWriteList_Golden(t *testing.T) {
+	var b strings.Builder
+	err := WriteList(&b, []ListedTool{
+		{Name: "codex", DisplayName: "Codex", Status: "installed", Path: "/usr/local/bin/codex"},
+		{Name: "claude", DisplayName: "Claude Code", Status: "not-installed"},
+	})
+	if err != nil {
+		t.Fatalf("WriteList() error: %v", err)
+	}
+	golden(t, "list.golden", []byte(b.String()))
+}
+
+func TestWrite_JSONGolden(t *testing.T) {
+	entries := testEntries()
+	entries[0].Balance.Limits[1].ResetsAt = time.Date(2026, 2, 10, 16, 22, 0, 0, time.UTC) // The same file in every time zone
+	var b strings.Builder
+	if err := Write(&b, FormatJSON, entries); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	golden(t, "usage.golden", []byte(b.String()))
+}
+
+func TestWriteHistory_JSONGolden(t *testing.T) {
+	var b strings.Builder
+	if err := WriteHistory(&b, FormatJSON, testLaunches()); err != nil {
+		t.Fatalf("WriteHistory() error: %v", err)
+	}
+	golden(t, "history.golden", []byte(b.String()))
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Status is one tool of the registry in "amazing status".
+type Status struct {
+	Tool      *tool.Tool
+	Installed bool
+	Path      string        // Binary the tool is launched from ("" when not installed)
+	Version   string        // Version number ("" when unknown)
+	Balance   *tool.Balance // nil for tools without a balance provider, or when it couldn't be fetched
+	LastUsed  time.Time     // When the launcher last started the tool (zero if never)
+	Launches  int
+}
+
+// jsonStatus is the JSON form of a Status, for status bars and dashboards.
+type jsonStatus struct {
+	Tool      string       `json:"tool"`
+	Name      string       `json:"name"`
+	Installed bool         `json:"installed"`
+	Path      string       `json:"path,omitempty"`
+	Version   string       `json:"version,omitempty"`
+	Balance   *jsonBalance `json:"balance,omitempty"`
+	LastUsed  *time.Time   `json:"last_used,omitempty"`
+	Launches  int          `json:"launches"`
+}
+
+// WriteStatus writes the statuses as aligned columns (FormatText) or JSON (FormatJSON).
+func WriteStatus(w io.Writer, format string, statuses []Status) error {
+	switch format {
+	case FormatText:
+		return writeStatusText(w, statuses)
+	case FormatJSON:
+		return writeStatusJSON(w, statuses)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func writeStatusText(w io.Writer, statuses []Status) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, s := range statuses {
+		installed := "not installed"
+		if s.Installed {
+			installed = s.Version
+			if installed == "" {
+				installed = "installed"
+			}
+		}
+		balance := ""
+		if s.Balance != nil {
+			balance = balanceText(s.Balance)
+		}
+		lastUsed := "never used"
+		if !s.LastUsed.IsZero() {
+			lastUsed = "last used " + timefmt.DateTime(s.LastUsed)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Tool.Name, installed, balance, lastUsed)
+	}
+	return tw.Flush()
+}
+
+func writeStatusJSON(w io.Writer, statuses []Status) error {
+	out := make([]jsonStatus, 0, len(statuses))
+	for _, s := range statuses {
+		js := jsonStatus{
+			Tool:      s.Tool.Name,
+			Name:      s.Tool.DisplayName,
+			Installed: s.Installed,
+			Path:      s.Path,
+			Version:   s.Version,
+			Launches:  s.Launches,
+		}
+		if s.Balance != nil {
+			b := newJSONBalance(s.Balance)
+			js.Balance = &b
+		}
+		if !s.LastUsed.IsZero() {
+			js.LastUsed = &s.LastUsed
+		}
+		out = append(out, js)
+	}
+	return writeIndented(w, out)
+}
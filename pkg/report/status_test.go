@@ -0,0 +1,83 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func testStatuses() []Status {
+	lastUsed := time.Date(2026, 2, 10, 9, 30, 0, 0, time.Local)
+	return []Status{
+		{
+			Tool:      &tool.Tool{Name: "codex", DisplayName: "Codex"},
+			Installed: true,
+			Path:      "/usr/local/bin/codex",
+			Version:   "0.46.0",
+			Balance: &tool.Balance{Percentage: 80, Limits: []tool.LimitDetail{
+				{Label: tool.LimitFiveHour, RemainingPercent: 80, Display: "80% left"},
+				{Label: tool.LimitWeekly, Display: "?%"},
+			}, Credits: "1,234.56"},
+			LastUsed: lastUsed,
+			Launches: 12,
+		},
+		{Tool: &tool.Tool{Name: "claude", DisplayName: "Claude Code"}, Installed: true},
+		{Tool: &tool.Tool{Name: "kimi", DisplayName: "Kimi"}},
+	}
+}
+
+func TestWriteStatus_JSON(t *testing.T) {
+	var b strings.Builder
+	if err := WriteStatus(&b, FormatJSON, testStatuses()); err != nil {
+		t.Fatalf("WriteStatus() error: %v", err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d tools, want 3", len(got))
+	}
+
+	codex := got[0]
+	if codex["installed"] != true || codex["version"] != "0.46.0" || codex["launches"] != 12.0 || codex["last_used"] == nil {
+		t.Errorf("codex = %v, want installed 0.46.0, 12 launches and the last use", codex)
+	}
+	balance, _ := codex["balance"].(map[string]any)
+	if balance["remaining_percent"] != 80.0 {
+		t.Errorf("codex balance = %v, want 80%% left", codex["balance"])
+	}
+	if limits, _ := balance["limits"].([]any); len(limits) != 1 {
+		t.Errorf("codex limits = %v, want only the known 5h limit", balance["limits"])
+	}
+	if balance["credits"] != "1,234.56" {
+		t.Errorf("codex credits = %v, want 1,234.56", balance["credits"])
+	}
+
+	kimi := got[2]
+	if kimi["installed"] != false || kimi["launches"] != 0.0 {
+		t.Errorf("kimi = %v, want not installed and never launched", kimi)
+	}
+	for _, key := range []string{"path", "version", "balance", "last_used"} {
+		if _, ok := kimi[key]; ok {
+			t.Errorf("kimi has %q, want it left out", key)
+		}
+	}
+}
+
+func TestWriteStatus_Text(t *testing.T) {
+	var b strings.Builder
+	if err := WriteStatus(&b, FormatText, testStatuses()); err != nil {
+		t.Fatalf("WriteStatus() error: %v", err)
+	}
+	want := `codex   0.46.0         80%  last used 09:30 on 10 Feb
+claude  installed           never used
+kimi    not installed       never used
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteStatus(text) =\n%s\nwant\n%s", got, want)
+	}
+}
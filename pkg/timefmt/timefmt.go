@@ -0,0 +1,87 @@
+// Package timefmt formats times shown to the user (reset times, projections) consistently,
+// following the clock and date order chosen in the config file.
+package timefmt
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock styles
+const (
+	Clock24h = "24h" // 16:22
+	Clock12h = "12h" // 4:22 PM
+)
+
+// Date orders
+const (
+	DayMonth = "day-month" // 10 Feb
+	MonthDay = "month-day" // Feb 10
+)
+
+// Format selects how times and dates are written.
+type Format struct {
+	Clock     string // Clock24h or Clock12h
+	DateOrder string // DayMonth or MonthDay
+}
+
+// DefaultFormat returns the format used unless configured otherwise.
+func DefaultFormat() Format {
+	return Format{Clock: Clock24h, DateOrder: DayMonth}
+}
+
+var (
+	mu      sync.RWMutex
+	current = DefaultFormat()
+)
+
+// Set changes the format used by the package-level helpers. Unknown values keep the default.
+func Set(f Format) {
+	if f.Clock != Clock12h {
+		f.Clock = Clock24h
+	}
+	if f.DateOrder != MonthDay {
+		f.DateOrder = DayMonth
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current = f
+}
+
+// Current returns the format used by the package-level helpers.
+func Current() Format {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Time formats the time of day, e.g. "16:22" or "4:22 PM".
+func Time(t time.Time) string { return Current().Time(t) }
+
+// DateTime formats the time of day and date, e.g. "16:22 on 10 Feb" or "4:22 PM on Feb 10".
+func DateTime(t time.Time) string { return Current().DateTime(t) }
+
+// Weekday formats the weekday and time of day, e.g. "Thu 16:22" or "Thu 4:22 PM".
+func Weekday(t time.Time) string { return Current().Weekday(t) }
+
+// Time formats the time of day.
+func (f Format) Time(t time.Time) string {
+	if f.Clock == Clock12h {
+		return t.Format("3:04 PM")
+	}
+	return t.Format("15:04")
+}
+
+// DateTime formats the time of day and date.
+func (f Format) DateTime(t time.Time) string {
+	date := t.Format("2 Jan")
+	if f.DateOrder == MonthDay {
+		date = t.Format("Jan 2")
+	}
+	return f.Time(t) + " on " + date
+}
+
+// Weekday formats the weekday and time of day.
+func (f Format) Weekday(t time.Time) string {
+	return t.Format("Mon") + " " + f.Time(t)
+}
@@ -0,0 +1,43 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	at := time.Date(2026, 2, 10, 16, 22, 0, 0, time.UTC) // Tuesday
+
+	tests := []struct {
+		name         string
+		format       Format
+		wantTime     string
+		wantDateTime string
+		wantWeekday  string
+	}{
+		{"default", DefaultFormat(), "16:22", "16:22 on 10 Feb", "Tue 16:22"},
+		{"12h month-day", Format{Clock: Clock12h, DateOrder: MonthDay}, "4:22 PM", "4:22 PM on Feb 10", "Tue 4:22 PM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.Time(at); got != tt.wantTime {
+				t.Errorf("Time() = %q, want %q", got, tt.wantTime)
+			}
+			if got := tt.format.DateTime(at); got != tt.wantDateTime {
+				t.Errorf("DateTime() = %q, want %q", got, tt.wantDateTime)
+			}
+			if got := tt.format.Weekday(at); got != tt.wantWeekday {
+				t.Errorf("Weekday() = %q, want %q", got, tt.wantWeekday)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	defer Set(DefaultFormat())
+
+	Set(Format{Clock: "nonsense", DateOrder: MonthDay})
+	if got := Current(); got.Clock != Clock24h || got.DateOrder != MonthDay {
+		t.Errorf("Current() = %+v, want 24h clock with month-day order", got)
+	}
+}
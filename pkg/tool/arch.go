@@ -0,0 +1,74 @@
+package tool
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// rosettaInstallHint is how users enable Rosetta 2 on Apple Silicon.
+const rosettaInstallHint = "softwareupdate --install-rosetta --agree-to-license"
+
+// installCommand returns the shell command that installs the tool on this OS and architecture.
+// rosetta is true when the command installs an Intel build on Apple Silicon. In Termux,
+// the InstallKeyTermux command wins.
+func (t *Tool) installCommand() (cmd string, rosetta bool) {
+	if cmd := t.InstallCmds[InstallKeyTermux]; cmd != "" && IsTermux() {
+		return cmd, false
+	}
+	return selectInstallCommand(t.InstallCmds, runtime.GOOS, runtime.GOARCH)
+}
+
+// selectInstallCommand picks the install command for goos/goarch from cmds.
+// An architecture-specific "GOOS/GOARCH" key (e.g., "linux/arm64") wins over a plain "GOOS"
+// key. Apple Silicon falls back to "darwin/amd64", since Rosetta 2 runs Intel builds.
+func selectInstallCommand(cmds map[string]string, goos, goarch string) (cmd string, rosetta bool) {
+	if cmd := cmds[goos+"/"+goarch]; cmd != "" {
+		return cmd, false
+	}
+	if cmd := cmds[goos]; cmd != "" {
+		return cmd, false
+	}
+	if goos == "darwin" && goarch == "arm64" {
+		if cmd := cmds["darwin/amd64"]; cmd != "" {
+			return cmd, true
+		}
+	}
+	return "", false
+}
+
+// archVariants returns the architectures with their own install command for goos, sorted.
+func archVariants(cmds map[string]string, goos string) []string {
+	var arches []string
+	for key, cmd := range cmds {
+		if arch, ok := strings.CutPrefix(key, goos+"/"); ok && cmd != "" {
+			arches = append(arches, arch)
+		}
+	}
+	sort.Strings(arches)
+	return arches
+}
+
+// archWarning explains an install that doesn't match this machine's architecture:
+// an Intel build on Apple Silicon, or only builds for other architectures.
+// Returns "" when there is nothing to warn about.
+func (t *Tool) archWarning() string {
+	if t.InstallCmds[InstallKeyTermux] != "" && IsTermux() {
+		return ""
+	}
+	return archWarning(t.InstallCmds, runtime.GOOS, runtime.GOARCH)
+}
+
+func archWarning(cmds map[string]string, goos, goarch string) string {
+	cmd, rosetta := selectInstallCommand(cmds, goos, goarch)
+	switch {
+	case rosetta:
+		return "Only an Intel build is available; it runs under Rosetta 2 (enable it with: " + rosettaInstallHint + ")"
+	case cmd == "":
+		if arches := archVariants(cmds, goos); len(arches) > 0 {
+			return fmt.Sprintf("No install command for %s/%s (only for %s)", goos, goarch, strings.Join(arches, ", "))
+		}
+	}
+	return ""
+}
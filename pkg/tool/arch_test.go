@@ -0,0 +1,72 @@
+package tool
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSelectInstallCommand(t *testing.T) {
+	cmds := map[string]string{
+		"darwin/amd64": "curl -fsSL https://example.com/x-darwin-amd64.sh | sh",
+		"linux":        "curl -fsSL https://example.com/x-linux.sh | sh",
+		"linux/arm64":  "curl -fsSL https://example.com/x-linux-arm64.sh | sh",
+	}
+	tests := []struct {
+		goos, goarch string
+		want         string
+		wantRosetta  bool
+	}{
+		{"linux", "arm64", cmds["linux/arm64"], false},
+		{"linux", "amd64", cmds["linux"], false},
+		{"darwin", "amd64", cmds["darwin/amd64"], false},
+		{"darwin", "arm64", cmds["darwin/amd64"], true},
+		{"windows", "amd64", "", false},
+	}
+	for _, tt := range tests {
+		got, rosetta := selectInstallCommand(cmds, tt.goos, tt.goarch)
+		if got != tt.want || rosetta != tt.wantRosetta {
+			t.Errorf("selectInstallCommand(%s/%s) = %q, %v, want %q, %v", tt.goos, tt.goarch, got, rosetta, tt.want, tt.wantRosetta)
+		}
+	}
+}
+
+func TestArchWarning(t *testing.T) {
+	tests := []struct {
+		name         string
+		cmds         map[string]string
+		goos, goarch string
+		want         string
+	}{
+		{name: "matching variant", cmds: map[string]string{"linux/arm64": "x"}, goos: "linux", goarch: "arm64"},
+		{name: "generic command", cmds: map[string]string{"linux": "x", "linux/arm64": "y"}, goos: "linux", goarch: "amd64"},
+		{name: "rosetta", cmds: map[string]string{"darwin/amd64": "x"}, goos: "darwin", goarch: "arm64", want: "Rosetta 2"},
+		{name: "no matching variant", cmds: map[string]string{"linux/amd64": "x", "linux/386": "y"}, goos: "linux", goarch: "arm64", want: "only for 386, amd64"},
+		{name: "nothing for the OS", cmds: map[string]string{"darwin": "x"}, goos: "linux", goarch: "amd64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := archWarning(tt.cmds, tt.goos, tt.goarch)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("archWarning() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTool_Install_OtherArchitecture(t *testing.T) {
+	if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" {
+		t.Skip("Apple Silicon installs amd64 builds with Rosetta 2")
+	}
+	other := "arm64"
+	if runtime.GOARCH == other {
+		other = "amd64"
+	}
+	tl := &Tool{Name: "x", Command: "x", InstallCmds: map[string]string{runtime.GOOS + "/" + other: "true"}}
+	if tl.HasInstallCommand() {
+		t.Error("HasInstallCommand() should be false with only another architecture's command")
+	}
+	if err := tl.Install(); err == nil || !strings.Contains(err.Error(), "only for "+other) {
+		t.Errorf("Install() error = %v, want it to name the available architecture", err)
+	}
+}
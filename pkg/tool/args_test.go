@@ -0,0 +1,48 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", in: "  ", want: nil},
+		{name: "words", in: "--model gpt-5", want: []string{"--model", "gpt-5"}},
+		{name: "double quotes", in: `-p "hello world"`, want: []string{"-p", "hello world"}},
+		{name: "single quotes keep backslash", in: `'a\b' c`, want: []string{`a\b`, "c"}},
+		{name: "escaped space", in: `a\ b`, want: []string{"a b"}},
+		{name: "empty quoted arg", in: `"" x`, want: []string{"", "x"}},
+		{name: "unterminated quote", in: `"oops`, wantErr: true},
+		{name: "trailing backslash", in: `oops\`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitArgs(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitArgs(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinArgs_RoundTrip(t *testing.T) {
+	args := []string{"--model", "gpt-5", "-p", "it's a test", "", `back\slash`}
+	line := JoinArgs(args)
+	got, err := SplitArgs(line)
+	if err != nil {
+		t.Fatalf("SplitArgs(%q) error: %v", line, err)
+	}
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("SplitArgs(JoinArgs(%q)) = %q", args, got)
+	}
+}
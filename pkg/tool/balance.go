@@ -0,0 +1,122 @@
+package tool
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// BalanceSchema is the version of what the fields of Balance mean. Bump it when one changes
+// meaning or name (e.g., LimitDetail.Percentage becoming RemainingPercent), so balances cached
+// by an older binary are fetched again instead of drawn wrong.
+const BalanceSchema = 2
+
+// BalanceUnit is what a balance is measured in.
+type BalanceUnit string
+
+const (
+	UnitPercent  BalanceUnit = ""         // Percentage is the share left (the default)
+	UnitRequests BalanceUnit = "requests" // e.g., Copilot premium requests
+	UnitTokens   BalanceUnit = "tokens"
+	UnitDollars  BalanceUnit = "dollars" // API credit
+	UnitYuan     BalanceUnit = "yuan"    // API credit in CNY (e.g., DeepSeek)
+)
+
+// FormatAmount formats v in the unit, e.g. "120 requests", "1.5M tokens", "$4.50" or "¥110.00".
+func (u BalanceUnit) FormatAmount(v float64) string {
+	switch u {
+	case UnitRequests:
+		return fmt.Sprintf("%d requests", int(math.Round(v)))
+	case UnitTokens:
+		return compactNumber(v) + " tokens"
+	case UnitDollars:
+		return fmt.Sprintf("$%.2f", v)
+	case UnitYuan:
+		return fmt.Sprintf("¥%.2f", v)
+	default:
+		return fmt.Sprintf("%d%%", int(math.Round(v)))
+	}
+}
+
+// RemainingFromUsed converts a share used (0-100), as the Codex APIs and CLI report it, to
+// the share left that balances and limits hold. Providers convert when they parse, so
+// nothing past them deals in usage.
+func RemainingFromUsed(used float64) int {
+	return max(0, min(100, 100-int(used)))
+}
+
+// RemainingColor returns the color hint for a share left: red at 20% or less, yellow at
+// 40% or less, green otherwise. It is the only color policy: the TUI colors a bar from the
+// number it displays, whatever the provider that fetched it.
+func RemainingColor(remaining int) string {
+	switch {
+	case remaining <= 20:
+		return "red"
+	case remaining <= 40:
+		return "yellow"
+	default:
+		return "green"
+	}
+}
+
+// Color returns the color hint of the balance's bar, from the share left it displays.
+// Unknown balances and balances without a bar are green, so a failed fetch doesn't look
+// like an exhausted allowance.
+func (b Balance) Color() string {
+	pct, ok := b.RemainingPercent()
+	if !ok || b.Unknown() {
+		return "green"
+	}
+	return RemainingColor(pct)
+}
+
+// RemainingPercent returns the share of the balance left as 0-100.
+// Returns false for absolute balances without a known total, which can't be drawn as a bar.
+func (b Balance) RemainingPercent() (int, bool) {
+	if b.Unit == UnitPercent {
+		return b.Percentage, true
+	}
+	if b.Total <= 0 {
+		return 0, false
+	}
+	pct := int(math.Round(b.Remaining * 100 / b.Total))
+	return max(0, min(100, pct)), true
+}
+
+// AmountDisplay returns the balance as text in its unit, e.g. "120/300 requests" or "$4.50".
+// Percentage balances use Display.
+func (b Balance) AmountDisplay() string {
+	if b.Unit == UnitPercent {
+		return b.Display
+	}
+	if b.Total > 0 {
+		if b.Unit == UnitDollars || b.Unit == UnitYuan {
+			return fmt.Sprintf("%s/%s", b.Unit.FormatAmount(b.Remaining), b.Unit.FormatAmount(b.Total))
+		}
+		// "120/300 requests": the unit once, after the total
+		return fmt.Sprintf("%s/%s", plainAmount(b.Unit, b.Remaining), b.Unit.FormatAmount(b.Total))
+	}
+	return b.Unit.FormatAmount(b.Remaining)
+}
+
+// plainAmount formats v like FormatAmount but without the unit suffix.
+func plainAmount(u BalanceUnit, v float64) string {
+	if u == UnitTokens {
+		return compactNumber(v)
+	}
+	return strconv.Itoa(int(math.Round(v)))
+}
+
+// compactNumber formats large numbers with k/M/B suffixes, e.g. 1500000 -> "1.5M".
+func compactNumber(v float64) string {
+	switch abs := math.Abs(v); {
+	case abs >= 1e9:
+		return strconv.FormatFloat(v/1e9, 'f', 1, 64) + "B"
+	case abs >= 1e6:
+		return strconv.FormatFloat(v/1e6, 'f', 1, 64) + "M"
+	case abs >= 1e3:
+		return strconv.FormatFloat(v/1e3, 'f', 1, 64) + "k"
+	default:
+		return strconv.Itoa(int(math.Round(v)))
+	}
+}
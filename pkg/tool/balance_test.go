@@ -0,0 +1,120 @@
+package tool
+
+import "testing"
+
+func TestBalance_AmountDisplay(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     Balance
+		wantDisplay string
+		wantPercent int
+		wantBar     bool
+	}{
+		{
+			name:        "percentage",
+			balance:     Balance{Percentage: 80, Display: "80%"},
+			wantDisplay: "80%",
+			wantPercent: 80,
+			wantBar:     true,
+		},
+		{
+			name:        "requests with allowance",
+			balance:     Balance{Unit: UnitRequests, Remaining: 120, Total: 300},
+			wantDisplay: "120/300 requests",
+			wantPercent: 40,
+			wantBar:     true,
+		},
+		{
+			name:        "tokens with allowance",
+			balance:     Balance{Unit: UnitTokens, Remaining: 1_500_000, Total: 2_000_000},
+			wantDisplay: "1.5M/2.0M tokens",
+			wantPercent: 75,
+			wantBar:     true,
+		},
+		{
+			name:        "prepaid dollars",
+			balance:     Balance{Unit: UnitDollars, Remaining: 4.5},
+			wantDisplay: "$4.50",
+			wantBar:     false,
+		},
+		{
+			name:        "overspent is clamped",
+			balance:     Balance{Unit: UnitDollars, Remaining: 12, Total: 10},
+			wantDisplay: "$12.00/$10.00",
+			wantPercent: 100,
+			wantBar:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.balance.AmountDisplay(); got != tt.wantDisplay {
+				t.Errorf("AmountDisplay() = %q, want %q", got, tt.wantDisplay)
+			}
+			pct, bar := tt.balance.RemainingPercent()
+			if bar != tt.wantBar || pct != tt.wantPercent {
+				t.Errorf("RemainingPercent() = %d, %v, want %d, %v", pct, bar, tt.wantPercent, tt.wantBar)
+			}
+		})
+	}
+}
+
+func TestBalanceUnit_FormatAmount(t *testing.T) {
+	tests := []struct {
+		unit BalanceUnit
+		v    float64
+		want string
+	}{
+		{UnitPercent, 42, "42%"},
+		{UnitRequests, 7, "7 requests"},
+		{UnitTokens, 950, "950 tokens"},
+		{UnitTokens, 12_300, "12.3k tokens"},
+		{UnitTokens, 3_200_000_000, "3.2B tokens"},
+		{UnitDollars, 0.5, "$0.50"},
+		{UnitYuan, 110, "¥110.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.unit.FormatAmount(tt.v); got != tt.want {
+			t.Errorf("%q.FormatAmount(%v) = %q, want %q", tt.unit, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestBalance_Limit(t *testing.T) {
+	b := Balance{Limits: []LimitDetail{
+		{Label: LimitFiveHour, RemainingPercent: 90},
+		{Label: LimitWeekly, RemainingPercent: 40},
+		{Label: "Premium", RemainingPercent: 10},
+	}}
+
+	if got, ok := b.Limit(LimitWeekly); !ok || got.RemainingPercent != 40 {
+		t.Errorf("Limit(%q) = %+v, %v, want the weekly limit", LimitWeekly, got, ok)
+	}
+	if got, ok := b.Limit("Premium"); !ok || got.RemainingPercent != 10 {
+		t.Errorf("Limit(Premium) = %+v, %v, want the premium limit", got, ok)
+	}
+	if _, ok := b.Limit("Monthly"); ok {
+		t.Error("Limit(Monthly) should not be found")
+	}
+}
+
+func TestBalance_Color(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance Balance
+		want    string
+	}{
+		{"plenty left", Balance{Percentage: 80, Display: "80%"}, "green"},
+		{"40% left", Balance{Percentage: 40, Display: "40%"}, "yellow"},
+		{"20% left", Balance{Percentage: 20, Display: "20%"}, "red"},
+		{"exhausted", Balance{Percentage: 0, Display: "0%"}, "red"},
+		{"unknown", Balance{Display: UnknownDisplay}, "green"},
+		{"absolute, from the amounts", Balance{Percentage: 90, Unit: UnitRequests, Remaining: 30, Total: 300}, "red"},
+		{"no allowance", Balance{Unit: UnitDollars, Remaining: 4.5}, "green"},
+	}
+	for _, tt := range tests {
+		if got := tt.balance.Color(); got != tt.want {
+			t.Errorf("%s: Color() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
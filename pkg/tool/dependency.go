@@ -0,0 +1,172 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// dependencyProbeTimeout bounds how long a dependency's version command may run.
+const dependencyProbeTimeout = 5 * time.Second
+
+// Dependency describes a runtime a tool needs before it can be installed or run (e.g., node >= 20, git).
+type Dependency struct {
+	Name            string            // Command to look up on PATH (e.g., "node", "python3", "git")
+	MinVersion      string            // Minimum version (e.g., "3.10"); empty means any version
+	VersionArgs     []string          // Arguments that print the version (defaults to "--version")
+	AutoInstallCmds map[string]string // OS-specific commands considered safe to run automatically (key: "darwin", "linux", "windows")
+	InstallURL      string            // Where to get the dependency when it can't be installed automatically
+}
+
+// String returns a short requirement description (e.g., "python3 >= 3.10").
+func (d Dependency) String() string {
+	if d.MinVersion == "" {
+		return d.Name
+	}
+	return fmt.Sprintf("%s >= %s", d.Name, d.MinVersion)
+}
+
+// DependencyStatus is the result of checking a single dependency on this machine.
+type DependencyStatus struct {
+	Dependency Dependency
+	Found      bool   // Whether the command is on PATH
+	Version    string // Version output, if MinVersion required probing it
+	Satisfied  bool   // Found and at least MinVersion
+}
+
+// Check verifies that the dependency is available and new enough.
+func (d Dependency) Check() DependencyStatus {
+	status := DependencyStatus{Dependency: d}
+	path, err := LookPath(d.Name)
+	if err != nil {
+		return status
+	}
+	status.Found = true
+
+	if d.MinVersion == "" {
+		status.Satisfied = true
+		return status
+	}
+
+	args := d.VersionArgs
+	if len(args) == 0 {
+		args = []string{"--version"}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dependencyProbeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if err != nil {
+		return status
+	}
+	status.Version = firstLine(string(out))
+	status.Satisfied = compareVersions(status.Version, d.MinVersion) >= 0
+	return status
+}
+
+// AutoInstallCommand returns the safe install command for the current OS, or "" if there is none.
+func (d Dependency) AutoInstallCommand() string {
+	return d.AutoInstallCmds[runtime.GOOS]
+}
+
+// Message returns an actionable, human-readable description of an unmet dependency.
+// Returns "" when the dependency is satisfied.
+func (s DependencyStatus) Message() string {
+	if s.Satisfied {
+		return ""
+	}
+	d := s.Dependency
+
+	var msg string
+	switch {
+	case !s.Found:
+		msg = fmt.Sprintf("%s is required but was not found", d)
+	case s.Version == "":
+		msg = fmt.Sprintf("%s is required but its version could not be determined", d)
+	default:
+		msg = fmt.Sprintf("%s is required (found %s)", d, s.Version)
+	}
+
+	switch {
+	case d.AutoInstallCommand() != "":
+		msg += fmt.Sprintf("; it will be installed with: %s", d.AutoInstallCommand())
+	case d.InstallURL != "":
+		msg += fmt.Sprintf("; install it from %s", d.InstallURL)
+	}
+	return msg
+}
+
+// CheckDependencies checks every declared dependency of the tool.
+func (t *Tool) CheckDependencies() []DependencyStatus {
+	statuses := make([]DependencyStatus, 0, len(t.Dependencies))
+	for _, dep := range t.Dependencies {
+		statuses = append(statuses, dep.Check())
+	}
+	return statuses
+}
+
+// PreInstallWarnings returns the messages a user should see before confirming an install:
+// unmet dependencies, node version problems and a missing Python package installer.
+// Nix installs bring their own dependencies, so they have no warnings.
+func (t *Tool) PreInstallWarnings() []string {
+	if msg := t.platformWarning(); msg != "" {
+		// Nothing else matters if the tool can't run here
+		return []string{msg}
+	}
+	var warnings []string
+	if msg := t.wslWarning(); msg != "" {
+		warnings = append(warnings, msg)
+	}
+	if t.usesNix() {
+		return warnings
+	}
+	for _, status := range t.CheckDependencies() {
+		if msg := status.Message(); msg != "" {
+			warnings = append(warnings, msg)
+		}
+	}
+	if msg := t.NodeVersionWarning(); msg != "" {
+		warnings = append(warnings, msg)
+	}
+	// Other install methods make up for a missing architecture
+	if msg := t.archWarning(); msg != "" && (t.hasOSInstallCommand() || !t.HasInstallCommand()) {
+		warnings = append(warnings, msg)
+	}
+	if msg := t.PythonPackage.installerWarning(); msg != "" && !t.hasOSInstallCommand() {
+		warnings = append(warnings, msg)
+	}
+	return warnings
+}
+
+// ensureDependencies verifies dependencies before installation, auto-installing the ones
+// that declare a safe command for this OS. Returns an actionable error for the first unmet one.
+func (t *Tool) ensureDependencies(out io.Writer) error {
+	for _, status := range t.CheckDependencies() {
+		if status.Satisfied {
+			continue
+		}
+		dep := status.Dependency
+		if cmd := dep.AutoInstallCommand(); cmd != "" {
+			if err := runInstallCommand(runtime.GOOS, cmd, true, out); err != nil {
+				return fmt.Errorf("failed to install dependency %s: %w", dep.Name, err)
+			}
+			if status = dep.Check(); status.Satisfied {
+				continue
+			}
+			return fmt.Errorf("cannot install %s: %s is still unavailable after running: %s", t.Name, dep, cmd)
+		}
+		return fmt.Errorf("cannot install %s: %s", t.Name, status.Message())
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package tool
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestDependency_Check(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go binary not on PATH")
+	}
+
+	tests := []struct {
+		name          string
+		dep           Dependency
+		wantFound     bool
+		wantSatisfied bool
+	}{
+		{
+			name:          "present without version requirement",
+			dep:           Dependency{Name: "go"},
+			wantFound:     true,
+			wantSatisfied: true,
+		},
+		{
+			name:          "present and new enough",
+			dep:           Dependency{Name: "go", MinVersion: "1.0", VersionArgs: []string{"version"}},
+			wantFound:     true,
+			wantSatisfied: true,
+		},
+		{
+			name:          "present but too old",
+			dep:           Dependency{Name: "go", MinVersion: "999", VersionArgs: []string{"version"}},
+			wantFound:     true,
+			wantSatisfied: false,
+		},
+		{
+			name:          "missing",
+			dep:           Dependency{Name: "nonexistent-runtime-xyz", MinVersion: "1.0"},
+			wantFound:     false,
+			wantSatisfied: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.dep.Check()
+			if status.Found != tt.wantFound {
+				t.Errorf("Found = %v, want %v", status.Found, tt.wantFound)
+			}
+			if status.Satisfied != tt.wantSatisfied {
+				t.Errorf("Satisfied = %v, want %v", status.Satisfied, tt.wantSatisfied)
+			}
+			if tt.wantSatisfied && status.Message() != "" {
+				t.Errorf("satisfied dependency should have no message, got %q", status.Message())
+			}
+		})
+	}
+}
+
+func TestDependencyStatus_Message(t *testing.T) {
+	status := DependencyStatus{
+		Dependency: Dependency{Name: "python3", MinVersion: "3.10", InstallURL: "https://www.python.org/downloads/"},
+		Found:      true,
+		Version:    "Python 3.8.10",
+	}
+
+	msg := status.Message()
+	for _, want := range []string{"python3 >= 3.10", "Python 3.8.10", "https://www.python.org/downloads/"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Message() = %q, want it to contain %q", msg, want)
+		}
+	}
+}
+
+func TestTool_Install_MissingDependency(t *testing.T) {
+	tool := &Tool{
+		Name:    "test-tool",
+		Command: "nonexistent-cli-tool-xyz",
+		InstallCmds: map[string]string{
+			"darwin":     "true",
+			"linux":      "true",
+			"windows_ps": "exit 0",
+		},
+		Dependencies: []Dependency{{Name: "nonexistent-runtime-xyz"}},
+	}
+
+	err := tool.Install()
+	if err == nil || !strings.Contains(err.Error(), "nonexistent-runtime-xyz") {
+		t.Errorf("Install() should fail on the missing dependency, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package tool
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// canExecReplace reports whether execReplace works on this OS.
+const canExecReplace = true
+
+// execReplace replaces the current process with cmd, so the tool inherits its PID,
+// terminal and signals directly. It only returns if the exec fails.
+func execReplace(cmd *exec.Cmd) error {
+	if cmd.Dir != "" {
+		// Unlike exec.Cmd.Run, syscall.Exec ignores Dir: the tool inherits our directory
+		if err := os.Chdir(cmd.Dir); err != nil {
+			return err
+		}
+	}
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	return syscall.Exec(cmd.Path, cmd.Args, env)
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package tool
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// canExecReplace reports whether execReplace works on this OS; Windows has no exec.
+const canExecReplace = false
+
+func execReplace(cmd *exec.Cmd) error {
+	return errors.New("replacing the process is not supported on Windows")
+}
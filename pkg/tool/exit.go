@@ -0,0 +1,120 @@
+package tool
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QuickFailureWindow is how soon after starting a non-zero exit counts as a startup failure
+// (e.g., "not logged in") rather than a normal session ending with an error.
+const QuickFailureWindow = 5 * time.Second
+
+// IsQuickFailure reports whether a tool run that took elapsed and returned err failed at startup.
+func IsQuickFailure(err error, elapsed time.Duration) bool {
+	return err != nil && elapsed < QuickFailureWindow
+}
+
+// ExitCode extracts the process exit code from an error returned by running a tool.
+// Returns 0 for a nil error and -1 when the process didn't exit normally.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// TailBuffer is an io.Writer that keeps only the last few lines written to it.
+// It is safe for concurrent use.
+type TailBuffer struct {
+	mu       sync.Mutex
+	maxLines int
+	lines    []string
+	partial  string
+}
+
+// NewTailBuffer creates a TailBuffer that retains at most maxLines lines.
+func NewTailBuffer(maxLines int) *TailBuffer {
+	if maxLines < 1 {
+		maxLines = 1
+	}
+	return &TailBuffer{maxLines: maxLines}
+}
+
+// Write implements io.Writer.
+func (b *TailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parts := strings.Split(b.partial+string(p), "\n")
+	b.partial = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		b.appendLine(line)
+	}
+	return len(p), nil
+}
+
+func (b *TailBuffer) appendLine(line string) {
+	line = strings.TrimSpace(stripEscapes(lastRedraw(line)))
+	if line == "" {
+		return
+	}
+	b.lines = append(b.lines, line)
+	if len(b.lines) > b.maxLines {
+		b.lines = b.lines[len(b.lines)-b.maxLines:]
+	}
+}
+
+// Lines returns the retained lines, including a trailing line without a newline.
+func (b *TailBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines := append([]string(nil), b.lines...)
+	if last := strings.TrimSpace(stripEscapes(lastRedraw(b.partial))); last != "" {
+		lines = append(lines, last)
+		if len(lines) > b.maxLines {
+			lines = lines[len(lines)-b.maxLines:]
+		}
+	}
+	return lines
+}
+
+// lastRedraw returns what a terminal would show for a line that progress output
+// redraws with carriage returns (e.g. "10%\r20%" shows "20%").
+func lastRedraw(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		return line[i+1:]
+	}
+	return line
+}
+
+// stripEscapes removes ANSI escape sequences and carriage returns from captured output.
+func stripEscapes(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\r':
+			continue
+		case 0x1b:
+			// Skip CSI sequences: ESC [ params final-byte
+			if i+1 < len(s) && s[i+1] == '[' {
+				i += 2
+				for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+					i++
+				}
+				continue
+			}
+			continue
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
+}
@@ -0,0 +1,66 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTailBuffer(t *testing.T) {
+	buf := NewTailBuffer(2)
+	fmt.Fprint(buf, "first\nsecond\n")
+	fmt.Fprint(buf, "\x1b[31mthird\x1b[0m\r\n")
+	fmt.Fprint(buf, "partial")
+
+	want := []string{"third", "partial"}
+	if got := buf.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestTailBuffer_ProgressRedraws(t *testing.T) {
+	buf := NewTailBuffer(5)
+	fmt.Fprint(buf, "fetching 10%\rfetching 55%\rfetching 100%\r\n")
+	fmt.Fprint(buf, "extracting 1/3\rextracting 2/3")
+
+	want := []string{"fetching 100%", "extracting 2/3"}
+	if got := buf.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if got := ExitCode(nil); got != 0 {
+		t.Errorf("ExitCode(nil) = %d, want 0", got)
+	}
+	if got := ExitCode(errors.New("boom")); got != -1 {
+		t.Errorf("ExitCode(non-exit error) = %d, want -1", got)
+	}
+
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	if got := ExitCode(err); got != 3 {
+		t.Errorf("ExitCode(exit 3) = %d, want 3", got)
+	}
+}
+
+func TestIsQuickFailure(t *testing.T) {
+	err := errors.New("exit status 1")
+	tests := []struct {
+		err     error
+		elapsed time.Duration
+		want    bool
+	}{
+		{err, time.Second, true},
+		{err, QuickFailureWindow + time.Second, false},
+		{nil, time.Second, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsQuickFailure(tt.err, tt.elapsed); got != tt.want {
+			t.Errorf("IsQuickFailure(%v, %v) = %v, want %v", tt.err, tt.elapsed, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package tool
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// GitHubRelease installs a single binary from the latest release of a GitHub repository
+// into ~/.local/bin. Asset and checksum names may contain "{version}", replaced by the
+// release tag without its leading "v".
+type GitHubRelease struct {
+	Repo      string            // Repository, e.g. "charmbracelet/crush"
+	Assets    map[string]string // Asset name per "GOOS/GOARCH", e.g. "linux/amd64": "crush_{version}_Linux_x86_64.tar.gz"
+	Binary    string            // Executable inside the archive (defaults to the tool's Command)
+	Checksums string            // Asset listing "sha256  name" lines; empty means "<asset>.sha256" is used
+}
+
+// githubAPI is the GitHub REST API base URL; tests point it at a local server.
+var githubAPI = "https://api.github.com"
+
+// githubClient downloads release metadata and assets.
+var githubClient = &http.Client{Timeout: 5 * time.Minute}
+
+// githubPlatform is the key of the current platform in GitHubRelease.Assets.
+func githubPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// HasAsset reports whether the release provides a binary for the current platform.
+func (g *GitHubRelease) HasAsset() bool {
+	return g != nil && g.Assets[githubPlatform()] != ""
+}
+
+type githubReleaseInfo struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// install downloads the binary for this platform, verifies its SHA-256 checksum
+// and places it in ~/.local/bin as command. Progress is reported to out when it is not nil.
+func (g *GitHubRelease) install(command string, out io.Writer) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	return g.installTo(filepath.Join(home, ".local", "bin"), command, out)
+}
+
+func (g *GitHubRelease) installTo(binDir, command string, out io.Writer) error {
+	if out == nil {
+		out = io.Discard
+	}
+	pattern := g.Assets[githubPlatform()]
+	if pattern == "" {
+		return fmt.Errorf("no release asset for %s", githubPlatform())
+	}
+
+	var release githubReleaseInfo
+	if err := getJSON(githubAPI+"/repos/"+g.Repo+"/releases/latest", &release); err != nil {
+		return fmt.Errorf("failed to look up the latest %s release: %w", g.Repo, err)
+	}
+	version := strings.TrimPrefix(release.TagName, "v")
+	urls := make(map[string]string, len(release.Assets))
+	for _, a := range release.Assets {
+		urls[a.Name] = a.URL
+	}
+
+	assetName := strings.ReplaceAll(pattern, "{version}", version)
+	assetURL, ok := urls[assetName]
+	if !ok {
+		return fmt.Errorf("release %s has no asset %s", release.TagName, assetName)
+	}
+	checksumName := strings.ReplaceAll(g.Checksums, "{version}", version)
+	if checksumName == "" {
+		checksumName = assetName + ".sha256"
+	}
+	checksumURL, ok := urls[checksumName]
+	if !ok {
+		return fmt.Errorf("release %s has no checksum for %s", release.TagName, assetName)
+	}
+
+	fmt.Fprintf(out, "Downloading %s from %s %s\n", assetName, g.Repo, release.TagName)
+	checksums, err := download(checksumURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", checksumName, err)
+	}
+	want, err := findChecksum(checksums, assetName)
+	if err != nil {
+		return err
+	}
+	asset, err := download(assetURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", assetName, err)
+	}
+	sum := sha256.Sum256(asset)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", assetName, got, want)
+	}
+
+	fmt.Fprintf(out, "Verified SHA-256 %s\n", want)
+
+	binary := g.Binary
+	if binary == "" {
+		binary = command
+	}
+	if runtime.GOOS == "windows" && !strings.HasSuffix(binary, ".exe") {
+		binary += ".exe"
+	}
+	data, err := extractBinary(assetName, asset, binary)
+	if err != nil {
+		return err
+	}
+
+	target := command
+	if runtime.GOOS == "windows" && !strings.HasSuffix(target, ".exe") {
+		target += ".exe"
+	}
+	fmt.Fprintf(out, "Installing %s\n", filepath.Join(binDir, target))
+	return writeExecutable(filepath.Join(binDir, target), data)
+}
+
+func getJSON(url string, v any) error {
+	data, err := download(url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// download fetches url, or its rewrite when it matches a mirror rule.
+func download(url string) ([]byte, error) {
+	url = currentMirrors().Rewrite(url)
+	resp, err := githubClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// findChecksum returns the SHA-256 of name from a "sha256sum" style listing.
+// A listing with a single bare hash (a "<asset>.sha256" file) applies to any name.
+func findChecksum(listing []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(listing))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 1:
+			return strings.ToLower(fields[0]), nil
+		case len(fields) >= 2 && strings.TrimPrefix(fields[1], "*") == name:
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// extractBinary returns the executable named binary from a .tar.gz, .tgz or .zip asset,
+// or the asset itself when it is not an archive.
+func extractBinary(assetName string, asset []byte, binary string) ([]byte, error) {
+	switch {
+	case strings.HasSuffix(assetName, ".tar.gz"), strings.HasSuffix(assetName, ".tgz"):
+		gz, err := gzip.NewReader(bytes.NewReader(asset))
+		if err != nil {
+			return nil, err
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == binary {
+				return io.ReadAll(tr)
+			}
+		}
+	case strings.HasSuffix(assetName, ".zip"):
+		zr, err := zip.NewReader(bytes.NewReader(asset), int64(len(asset)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if !f.FileInfo().IsDir() && path.Base(f.Name) == binary {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(rc)
+			}
+		}
+	default:
+		return asset, nil
+	}
+	return nil, fmt.Errorf("%s not found in %s", binary, assetName)
+}
+
+// writeExecutable atomically replaces target with an executable containing data.
+func writeExecutable(target string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
@@ -0,0 +1,132 @@
+package tool
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// releaseServer serves a fake "latest release" with the given assets.
+func releaseServer(t *testing.T, assets map[string][]byte) {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	var release githubReleaseInfo
+	release.TagName = "v1.2.3"
+	for name, data := range assets {
+		mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, r *http.Request) {
+			w.Write(data)
+		})
+		release.Assets = append(release.Assets, struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		}{Name: name, URL: srv.URL + "/download/" + name})
+	}
+	mux.HandleFunc("/repos/acme/agent/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(release)
+	})
+
+	old := githubAPI
+	githubAPI = srv.URL
+	t.Cleanup(func() { githubAPI = old })
+}
+
+func tarGz(t *testing.T, name string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "agent_1.2.3/" + name, Mode: 0755, Size: int64(len(data)), Typeflag: tar.TypeReg})
+	tw.Write(data)
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func sha(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestGitHubRelease_Install(t *testing.T) {
+	binary := "agent"
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	archive := tarGz(t, binary, []byte("#!/bin/sh\necho agent\n"))
+	assetName := "agent_1.2.3_" + runtime.GOOS + "_" + runtime.GOARCH + ".tar.gz"
+	release := &GitHubRelease{
+		Repo:      "acme/agent",
+		Assets:    map[string]string{githubPlatform(): "agent_{version}_" + runtime.GOOS + "_" + runtime.GOARCH + ".tar.gz"},
+		Checksums: "checksums.txt",
+	}
+
+	tests := []struct {
+		name      string
+		checksums string
+		wantErr   string
+	}{
+		{name: "verified", checksums: sha(archive) + "  " + assetName + "\n" + sha(nil) + "  other.zip\n"},
+		{name: "checksum mismatch", checksums: sha(nil) + "  " + assetName + "\n", wantErr: "checksum mismatch"},
+		{name: "not listed", checksums: sha(archive) + "  other.zip\n", wantErr: "no checksum listed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releaseServer(t, map[string][]byte{assetName: archive, "checksums.txt": []byte(tt.checksums)})
+			dir := t.TempDir()
+
+			err := release.installTo(dir, "agent", nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("installTo() error = %v, want %q", err, tt.wantErr)
+				}
+				if _, statErr := os.Stat(filepath.Join(dir, binary)); statErr == nil {
+					t.Error("binary should not be installed when verification fails")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("installTo() error: %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, binary))
+			if err != nil || !strings.Contains(string(data), "echo agent") {
+				t.Errorf("installed binary = %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestGitHubRelease_HasAsset(t *testing.T) {
+	var none *GitHubRelease
+	if none.HasAsset() {
+		t.Error("nil release should have no asset")
+	}
+	other := &GitHubRelease{Assets: map[string]string{"plan9/mips": "agent"}}
+	if other.HasAsset() {
+		t.Error("release without an asset for this platform should have no asset")
+	}
+	ours := &GitHubRelease{Assets: map[string]string{githubPlatform(): "agent"}}
+	if !ours.HasAsset() {
+		t.Error("release with an asset for this platform should have one")
+	}
+}
+
+func TestFindChecksum_SingleHashFile(t *testing.T) {
+	got, err := findChecksum([]byte("ABCDEF\n"), "agent.tar.gz")
+	if err != nil || got != "abcdef" {
+		t.Errorf("findChecksum() = %q, %v, want abcdef", got, err)
+	}
+}
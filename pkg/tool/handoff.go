@@ -0,0 +1,110 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Handoff is what the next tool needs to pick up a task another tool worked on: the
+// changes it made, where its transcript is and the project's instructions.
+type Handoff struct {
+	From         string // DisplayName of the tool the task started in
+	Dir          string // Where it ran
+	DiffStat     string // Output of "git diff --stat HEAD" in Dir; empty outside git or without changes
+	Transcript   string // The tool's transcript of the session; empty if unknown
+	Instructions string // AGENTS.md in Dir; empty if there is none
+}
+
+// handoffDiffFiles is how many changed files the diff summary lists before eliding.
+const handoffDiffFiles = 15
+
+// NewHandoff collects the handoff of t's session that started at start in dir. Parts that
+// can't be found are left empty.
+func NewHandoff(ctx context.Context, t *Tool, dir string, start time.Time) Handoff {
+	h := Handoff{From: t.DisplayName, Dir: dir, Transcript: t.LatestTranscript(start)}
+	if path := filepath.Join(dir, "AGENTS.md"); isFile(path) {
+		h.Instructions = path
+	}
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "diff", "--stat", "HEAD")
+	if out, err := cmd.Output(); err == nil {
+		h.DiffStat = elideDiffStat(strings.TrimRight(string(out), "\n"))
+	}
+	return h
+}
+
+// Empty reports whether there is nothing to hand off.
+func (h Handoff) Empty() bool {
+	return h.DiffStat == "" && h.Transcript == "" && h.Instructions == ""
+}
+
+// Prompt returns the handoff as the starting prompt of the next tool.
+func (h Handoff) Prompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "I'm continuing a task I started with %s in %s.\n", h.From, h.Dir)
+	if h.DiffStat != "" {
+		fmt.Fprintf(&b, "Changes so far (git diff --stat HEAD):\n%s\n", h.DiffStat)
+	}
+	if h.Transcript != "" {
+		fmt.Fprintf(&b, "The transcript of that session is %s.\n", h.Transcript)
+	}
+	if h.Instructions != "" {
+		fmt.Fprintf(&b, "The project's instructions are in %s.\n", h.Instructions)
+	}
+	b.WriteString("Read them and pick up where it left off.")
+	return b.String()
+}
+
+// elideDiffStat keeps the first handoffDiffFiles files of a diff summary and its last
+// line ("3 files changed, ...").
+func elideDiffStat(stat string) string {
+	lines := strings.Split(stat, "\n")
+	if len(lines) <= handoffDiffFiles+1 {
+		return stat
+	}
+	files := len(lines) - 1
+	kept := append(lines[:handoffDiffFiles:handoffDiffFiles], fmt.Sprintf(" ... %d more", files-handoffDiffFiles), lines[files])
+	return strings.Join(kept, "\n")
+}
+
+// LatestTranscript returns the newest file matching SessionsGlob written since since, or
+// empty if there is none.
+func (t *Tool) LatestTranscript(since time.Time) string {
+	if t.SessionsGlob == "" {
+		return ""
+	}
+	matches, _ := filepath.Glob(expandHome(t.SessionsGlob))
+	var latest string
+	var latestMod time.Time
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Before(since) || !info.ModTime().After(latestMod) {
+			continue
+		}
+		latest, latestMod = path, info.ModTime()
+	}
+	return latest
+}
+
+// isFile reports whether path is a regular file.
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// promptPlaceholder stands for the prompt in PromptArgs.
+const promptPlaceholder = "{prompt}"
+
+// PromptLaunchArgs returns PromptArgs with the prompt filled in, or nil if the tool can't
+// be started with a prompt.
+func (t *Tool) PromptLaunchArgs(prompt string) []string {
+	var args []string
+	for _, arg := range t.PromptArgs {
+		args = append(args, strings.ReplaceAll(arg, promptPlaceholder, prompt))
+	}
+	return args
+}
@@ -0,0 +1,96 @@
+package tool
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHandoff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("Run the tests.\n"), 0644)
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+
+	sessions := filepath.Join(home, ".fake", "sessions")
+	os.MkdirAll(sessions, 0755)
+	start := time.Now()
+	old := filepath.Join(sessions, "old.jsonl")
+	os.WriteFile(old, nil, 0644)
+	os.Chtimes(old, start.Add(-time.Hour), start.Add(-time.Hour)) // An earlier session
+	current := filepath.Join(sessions, "current.jsonl")
+	os.WriteFile(current, nil, 0644)
+
+	tl := &Tool{Name: "fake-agent", DisplayName: "Fake Agent", SessionsGlob: "~/.fake/sessions/*.jsonl"}
+	h := NewHandoff(t.Context(), tl, dir, start.Add(-time.Second))
+	if !strings.Contains(h.DiffStat, "main.go") || !strings.Contains(h.DiffStat, "1 file changed") {
+		t.Errorf("DiffStat = %q, want main.go changed", h.DiffStat)
+	}
+	if h.Transcript != current {
+		t.Errorf("Transcript = %q, want the session written since it started (%s)", h.Transcript, current)
+	}
+	if h.Instructions != filepath.Join(dir, "AGENTS.md") {
+		t.Errorf("Instructions = %q, want AGENTS.md", h.Instructions)
+	}
+
+	prompt := h.Prompt()
+	for _, want := range []string{"started with Fake Agent in " + dir, "main.go", current, "AGENTS.md"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Prompt() = %q, want it to mention %q", prompt, want)
+		}
+	}
+
+	if h := NewHandoff(t.Context(), &Tool{Name: "other"}, t.TempDir(), start); !h.Empty() {
+		t.Errorf("NewHandoff() outside git = %+v, want nothing to hand off", h)
+	}
+}
+
+func TestElideDiffStat(t *testing.T) {
+	var lines []string
+	for i := range 20 {
+		lines = append(lines, fmt.Sprintf(" file%d.go | 1 +", i))
+	}
+	lines = append(lines, " 20 files changed, 20 insertions(+)")
+	got := strings.Split(elideDiffStat(strings.Join(lines, "\n")), "\n")
+	if len(got) != handoffDiffFiles+2 || got[handoffDiffFiles] != " ... 5 more" || got[len(got)-1] != lines[20] {
+		t.Errorf("elideDiffStat() = %q, want %d files, the count of the rest and the summary", got, handoffDiffFiles)
+	}
+	if short := " a.go | 1 +\n 1 file changed"; elideDiffStat(short) != short {
+		t.Errorf("elideDiffStat(%q) changed a short summary", short)
+	}
+}
+
+func TestPromptLaunchArgs(t *testing.T) {
+	tests := []struct {
+		promptArgs []string
+		want       []string
+	}{
+		{[]string{"{prompt}"}, []string{"pick up"}},
+		{[]string{"--prompt-interactive", "{prompt}"}, []string{"--prompt-interactive", "pick up"}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got := (&Tool{PromptArgs: tt.promptArgs}).PromptLaunchArgs("pick up")
+		if strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") || len(got) != len(tt.want) {
+			t.Errorf("PromptLaunchArgs() with %q = %q, want %q", tt.promptArgs, got, tt.want)
+		}
+	}
+}
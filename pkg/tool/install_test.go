@@ -0,0 +1,199 @@
+package tool
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// simulateInstalls points installs at the fake package managers in testdata/install/bin,
+// with a temporary HOME so the developer's real rc files are never touched.
+// The fake Homebrew prefix is on PATH, ~/.local/bin is not. Returns the HOME directory.
+func simulateInstalls(t *testing.T, shell string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake package managers are sh scripts")
+	}
+	fakeBin, err := filepath.Abs(filepath.Join("testdata", "install", "bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	home := t.TempDir()
+	brewPrefix := t.TempDir()
+	brewBin := filepath.Join(brewPrefix, "bin")
+
+	t.Setenv("HOME", home)
+	t.Setenv("SHELL", "/bin/"+shell)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("FAKE_BREW_PREFIX", brewPrefix)
+	t.Setenv("PATH", strings.Join([]string{fakeBin, brewBin, "/usr/bin", "/bin"}, string(os.PathListSeparator)))
+	// Keep the host's Nix environment from taking over the install
+	t.Setenv("IN_NIX_SHELL", "")
+	t.Setenv("DEVBOX_SHELL_ENABLED", "")
+	return home
+}
+
+// readFixture returns a file from testdata/install/rc with {{HOME}} replaced.
+func readFixture(t *testing.T, name, home string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "install", "rc", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.ReplaceAll(string(data), "{{HOME}}", home)
+}
+
+func TestInstall_Simulated(t *testing.T) {
+	tests := []struct {
+		name       string
+		shell      string
+		installCmd string
+		deps       []Dependency
+		rcFile     string // Shell config relative to HOME
+		existingRC string // Fixture the rc file starts with, if any
+		wantRC     string // Fixture the rc file should match; "" means it must not exist
+		wantErr    string
+		wantLog    []string
+	}{
+		{
+			name:       "bash gets ~/.local/bin added to .bashrc",
+			shell:      "bash",
+			installCmd: "npm install -g @acme/fake-agent",
+			rcFile:     ".bashrc",
+			wantRC:     "bash.golden",
+			wantLog:    []string{"npm install -g @acme/fake-agent"},
+		},
+		{
+			name:       "zsh appends to an existing .zshrc",
+			shell:      "zsh",
+			installCmd: "npm install -g @acme/fake-agent",
+			rcFile:     ".zshrc",
+			existingRC: "zshrc",
+			wantRC:     "zsh.golden",
+			wantLog:    []string{"npm install -g @acme/fake-agent"},
+		},
+		{
+			name:       "fish creates config.fish",
+			shell:      "fish",
+			installCmd: "npm install -g @acme/fake-agent",
+			rcFile:     filepath.Join(".config", "fish", "config.fish"),
+			wantRC:     "fish.golden",
+			wantLog:    []string{"npm install -g @acme/fake-agent"},
+		},
+		{
+			name:       "rc that already adds the directory is left alone",
+			shell:      "bash",
+			installCmd: "npm install -g @acme/fake-agent",
+			rcFile:     ".bashrc",
+			existingRC: "bash.golden",
+			wantRC:     "bash.golden",
+			wantLog:    []string{"npm install -g @acme/fake-agent"},
+		},
+		{
+			name:       "unsupported shell can't repair PATH",
+			shell:      "tcsh",
+			installCmd: "npm install -g @acme/fake-agent",
+			rcFile:     ".tcshrc",
+			wantErr:    "fake-agent is still not in PATH",
+			wantLog:    []string{"npm install -g @acme/fake-agent"},
+		},
+		{
+			name:       "package manager failure reports its last line",
+			shell:      "bash",
+			installCmd: "npm install -g @acme/does-not-exist",
+			rcFile:     ".bashrc",
+			wantErr:    "install failed: npm ERR! 404 Not Found",
+			wantLog:    []string{"npm install -g @acme/does-not-exist"},
+		},
+		{
+			name:       "install onto PATH needs no repair",
+			shell:      "zsh",
+			installCmd: "brew install fake-agent",
+			rcFile:     ".zshrc",
+			wantLog:    []string{"brew install fake-agent"},
+		},
+		{
+			name:       "missing dependency is installed first",
+			shell:      "bash",
+			installCmd: "npm install -g @acme/fake-agent",
+			deps: []Dependency{{
+				Name:            "fake-runtime",
+				AutoInstallCmds: map[string]string{runtime.GOOS: "brew install fake-runtime"},
+			}},
+			rcFile:  ".bashrc",
+			wantRC:  "bash.golden",
+			wantLog: []string{"brew install fake-runtime", "npm install -g @acme/fake-agent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := simulateInstalls(t, tt.shell)
+			rcPath := filepath.Join(home, tt.rcFile)
+			if tt.existingRC != "" {
+				if err := os.WriteFile(rcPath, []byte(readFixture(t, tt.existingRC, home)), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tl := &Tool{
+				Name:         "fake-agent",
+				Command:      "fake-agent",
+				InstallCmds:  map[string]string{runtime.GOOS: tt.installCmd},
+				Dependencies: tt.deps,
+			}
+			var out strings.Builder
+			err := tl.InstallWithOutput(&out)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Install() error = %v, want %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Install() error: %v\n%s", err, out.String())
+				}
+				if !tl.IsInstalled() {
+					t.Error("tool should be marked installed")
+				}
+				if _, err := exec.LookPath(tl.Command); err != nil {
+					t.Errorf("%s should be on PATH after the install: %v", tl.Command, err)
+				}
+			}
+
+			rc, err := os.ReadFile(rcPath)
+			switch {
+			case tt.wantRC == "" && tt.existingRC == "" && err == nil:
+				t.Errorf("%s should not be written, got:\n%s", tt.rcFile, rc)
+			case tt.wantRC != "" && err != nil:
+				t.Errorf("%s should be written: %v", tt.rcFile, err)
+			case tt.wantRC != "":
+				if want := readFixture(t, tt.wantRC, home); string(rc) != want {
+					t.Errorf("%s =\n%s\nwant\n%s", tt.rcFile, rc, want)
+				}
+			}
+
+			log, _ := os.ReadFile(filepath.Join(home, "package-manager.log"))
+			if got := strings.Split(strings.TrimSpace(string(log)), "\n"); strings.Join(got, "|") != strings.Join(tt.wantLog, "|") {
+				t.Errorf("package manager calls = %q, want %q", got, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestShellConfigLine_XDGConfigHome(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	rcPath, line, err := shellConfigLine("fish", "/home/u", "/home/u/.local/bin")
+	if err != nil {
+		t.Fatalf("shellConfigLine() error: %v", err)
+	}
+	if want := filepath.Join("/xdg", "fish", "config.fish"); rcPath != want {
+		t.Errorf("rcPath = %q, want %q", rcPath, want)
+	}
+	if line != "set -gx PATH \"/home/u/.local/bin\" $PATH\n" {
+		t.Errorf("line = %q", line)
+	}
+}
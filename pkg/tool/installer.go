@@ -0,0 +1,135 @@
+package tool
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Package managers an Installer can use.
+const (
+	ManagerBrew   = "brew"
+	ManagerNpm    = "npm"
+	ManagerPipx   = "pipx"
+	ManagerWinget = "winget"
+	ManagerScoop  = "scoop"
+	ManagerCargo  = "cargo"
+)
+
+// Installer installs a tool's package with a package manager. A tool's Installers are
+// candidates in order of preference: the first one present is used when there is no
+// install command for this OS, and the next ones are offered when an install fails.
+type Installer struct {
+	Manager   string   // One of the Manager constants
+	Package   string   // Package, formula or winget id (e.g., "@openai/codex", "GitHub.Copilot")
+	Platforms []string // GOOS values it is used on (e.g., "darwin"); empty means all
+}
+
+// Command returns the shell command that installs the package.
+func (i Installer) Command() string {
+	switch i.Manager {
+	case ManagerNpm:
+		return "npm install -g " + i.Package
+	case ManagerWinget:
+		return "winget install -e --id " + i.Package
+	default:
+		// brew, pipx, scoop and cargo all take "install <package>"
+		return i.Manager + " install " + i.Package
+	}
+}
+
+// String describes the installer for prompts, e.g. "npm (@openai/codex)".
+func (i Installer) String() string {
+	return fmt.Sprintf("%s (%s)", i.Manager, i.Package)
+}
+
+// appliesHere reports whether the installer is meant for this OS.
+func (i Installer) appliesHere() bool {
+	return len(i.Platforms) == 0 || slices.Contains(i.Platforms, runtime.GOOS)
+}
+
+// Available reports whether the installer is meant for this OS and its package manager is
+// in PATH.
+func (i Installer) Available() bool {
+	if !i.appliesHere() {
+		return false
+	}
+	_, err := LookPath(i.Manager)
+	return err == nil
+}
+
+// hasInstallers reports whether any of the tool's Installers is meant for this OS,
+// whether or not its package manager is present.
+func (t *Tool) hasInstallers() bool {
+	return slices.ContainsFunc(t.Installers, Installer.appliesHere)
+}
+
+// installerCommands returns the commands of the Installers meant for this OS.
+func (t *Tool) installerCommands() []string {
+	var cmds []string
+	for _, i := range t.Installers {
+		if i.appliesHere() {
+			cmds = append(cmds, i.Command())
+		}
+	}
+	return cmds
+}
+
+// InstallAlternatives returns the Installers to offer after InstallWithOutput fails, in
+// order: the ones whose package manager is present, other than the one it ran.
+func (t *Tool) InstallAlternatives() []Installer {
+	var available []Installer
+	for _, i := range t.Installers {
+		if i.Available() {
+			available = append(available, i)
+		}
+	}
+	if t.usesNix() {
+		return available // InstallWithOutput ran nix, not any of them
+	}
+	ran := t.osInstallCmds()
+	if len(ran) == 0 && len(available) > 0 {
+		// The first available installer is what InstallWithOutput runs
+		return available[1:]
+	}
+	return slices.DeleteFunc(available, func(i Installer) bool { return slices.Contains(ran, i.Command()) })
+}
+
+// InstallWith installs the tool with one of its Installers, copying the output of the
+// install command to out as it runs, like InstallWithOutput. out may be nil.
+func (t *Tool) InstallWith(i Installer, out io.Writer) error {
+	if !i.Available() {
+		return fmt.Errorf("%s is not installed", i.Manager)
+	}
+	if err := runInstallCommand(runtime.GOOS, i.Command(), true, out); err != nil {
+		return err
+	}
+	return t.verifyInstalled()
+}
+
+// installWithFirstAvailable installs the tool with the first Installer whose package
+// manager is present.
+func (t *Tool) installWithFirstAvailable(out io.Writer) error {
+	i, err := t.firstAvailableInstaller()
+	if err != nil {
+		return err
+	}
+	return t.InstallWith(i, out)
+}
+
+// firstAvailableInstaller returns the first Installer whose package manager is present, or
+// an error naming the ones to install.
+func (t *Tool) firstAvailableInstaller() (Installer, error) {
+	var managers []string
+	for _, i := range t.Installers {
+		if i.Available() {
+			return i, nil
+		}
+		if i.appliesHere() && !slices.Contains(managers, i.Manager) {
+			managers = append(managers, i.Manager)
+		}
+	}
+	return Installer{}, fmt.Errorf("%s is installed with %s; install one of them first", t.Name, strings.Join(managers, " or "))
+}
@@ -0,0 +1,107 @@
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInstaller_Command(t *testing.T) {
+	tests := []struct {
+		installer Installer
+		want      string
+	}{
+		{Installer{Manager: ManagerNpm, Package: "@openai/codex"}, "npm install -g @openai/codex"},
+		{Installer{Manager: ManagerBrew, Package: "anomalyco/tap/opencode"}, "brew install anomalyco/tap/opencode"},
+		{Installer{Manager: ManagerWinget, Package: "GitHub.Copilot"}, "winget install -e --id GitHub.Copilot"},
+		{Installer{Manager: ManagerPipx, Package: "aider-chat"}, "pipx install aider-chat"},
+		{Installer{Manager: ManagerScoop, Package: "gemini-cli"}, "scoop install gemini-cli"},
+		{Installer{Manager: ManagerCargo, Package: "fake-agent"}, "cargo install fake-agent"},
+	}
+	for _, tt := range tests {
+		if got := tt.installer.Command(); got != tt.want {
+			t.Errorf("%s: Command() = %q, want %q", tt.installer, got, tt.want)
+		}
+	}
+}
+
+func TestInstallAlternatives(t *testing.T) {
+	simulateInstalls(t, "bash") // Fake brew and npm on PATH
+	missing := Installer{Manager: "no-such-manager", Package: "fake-agent"}
+	npm := Installer{Manager: ManagerNpm, Package: "@acme/fake-agent"}
+	brew := Installer{Manager: ManagerBrew, Package: "fake-agent"}
+	tests := []struct {
+		name        string
+		installCmds map[string]string
+		installers  []Installer
+		want        []Installer
+	}{
+		{"first present installer runs, the rest are offered", nil, []Installer{missing, npm, brew}, []Installer{brew}},
+		{"all are offered after the install command", map[string]string{runtime.GOOS: "curl -fsSL https://example.com/install.sh | sh"}, []Installer{npm, brew}, []Installer{npm, brew}},
+		{"the install command isn't offered again", map[string]string{runtime.GOOS: npm.Command()}, []Installer{npm, brew}, []Installer{brew}},
+		{"other platforms are left out", nil, []Installer{npm, {Manager: ManagerBrew, Package: "fake-agent", Platforms: []string{"plan9"}}}, nil},
+	}
+	for _, tt := range tests {
+		tl := &Tool{Name: "fake-agent", Command: "fake-agent", InstallCmds: tt.installCmds, Installers: tt.installers}
+		got := tl.InstallAlternatives()
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: InstallAlternatives() = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i].Command() != tt.want[i].Command() {
+				t.Errorf("%s: InstallAlternatives() = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestInstall_RetryWithNextManager(t *testing.T) {
+	home := simulateInstalls(t, "bash")
+	tl := &Tool{
+		Name:    "fake-agent",
+		Command: "fake-agent",
+		Installers: []Installer{
+			{Manager: "no-such-manager", Package: "fake-agent"}, // Not in PATH, skipped
+			{Manager: ManagerNpm, Package: "@acme/does-not-exist"},
+			{Manager: ManagerBrew, Package: "fake-agent"},
+		},
+	}
+	if !tl.HasInstallCommand() {
+		t.Fatal("Installers should count as an install command")
+	}
+
+	if err := tl.InstallWithOutput(nil); err == nil || !strings.Contains(err.Error(), "404 Not Found") {
+		t.Fatalf("InstallWithOutput() error = %v, want the npm failure", err)
+	}
+	alternatives := tl.InstallAlternatives()
+	if len(alternatives) != 1 || alternatives[0].Manager != ManagerBrew {
+		t.Fatalf("InstallAlternatives() = %v, want brew", alternatives)
+	}
+	if err := tl.InstallWith(alternatives[0], nil); err != nil {
+		t.Fatalf("InstallWith(brew) error: %v", err)
+	}
+	if !tl.IsInstalled() {
+		t.Error("tool should be marked installed")
+	}
+
+	log, _ := os.ReadFile(filepath.Join(home, "package-manager.log"))
+	if got, want := strings.TrimSpace(string(log)), "npm install -g @acme/does-not-exist\nbrew install fake-agent"; got != want {
+		t.Errorf("package manager calls = %q, want %q", got, want)
+	}
+}
+
+func TestInstall_NoManagerPresent(t *testing.T) {
+	simulateInstalls(t, "bash")
+	tl := &Tool{
+		Name:       "fake-agent",
+		Command:    "fake-agent",
+		Installers: []Installer{{Manager: "no-such-manager", Package: "fake-agent"}, {Manager: "other-manager", Package: "fake-agent"}},
+	}
+	err := tl.InstallWithOutput(nil)
+	if err == nil || !strings.Contains(err.Error(), "no-such-manager or other-manager") {
+		t.Errorf("InstallWithOutput() error = %v, want it to name both managers", err)
+	}
+}
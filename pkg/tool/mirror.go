@@ -0,0 +1,84 @@
+package tool
+
+import (
+	"strings"
+	"sync"
+)
+
+// MirrorRule rewrites URLs starting with From to start with To instead
+// (e.g., "https://github.com/" to "https://artifactory.corp/github/").
+type MirrorRule struct {
+	From string
+	To   string
+}
+
+// Mirrors redirects installs to internal artifact mirrors, for networks that
+// block the public download sites and registries.
+type Mirrors struct {
+	Rules       []MirrorRule // URL rewrites applied to install commands and downloads
+	NPMRegistry string       // npm registry used by npm-based installs
+	PyPIIndex   string       // Python package index used by uv and pipx
+}
+
+var (
+	mirrorsMu sync.RWMutex
+	mirrors   Mirrors
+)
+
+// SetMirrors sets the mirrors used by every install from now on.
+func SetMirrors(m Mirrors) {
+	mirrorsMu.Lock()
+	defer mirrorsMu.Unlock()
+	mirrors = m
+}
+
+func currentMirrors() Mirrors {
+	mirrorsMu.RLock()
+	defer mirrorsMu.RUnlock()
+	return mirrors
+}
+
+// Rewrite applies the rules to every URL in s. The first rule matching a URL wins.
+func (m Mirrors) Rewrite(s string) string {
+	if len(m.Rules) == 0 {
+		return s
+	}
+	var out strings.Builder
+	for i := 0; i < len(s); {
+		rule, ok := m.matchAt(s, i)
+		if !ok {
+			out.WriteByte(s[i])
+			i++
+			continue
+		}
+		out.WriteString(rule.To)
+		i += len(rule.From)
+	}
+	return out.String()
+}
+
+// matchAt returns the rule whose From starts at s[i], only at the start of a word
+// so "https://github.com" is not matched inside another URL.
+func (m Mirrors) matchAt(s string, i int) (MirrorRule, bool) {
+	if i > 0 && !strings.ContainsRune(" \t\n'\"(=|;&", rune(s[i-1])) {
+		return MirrorRule{}, false
+	}
+	for _, rule := range m.Rules {
+		if rule.From != "" && strings.HasPrefix(s[i:], rule.From) {
+			return rule, true
+		}
+	}
+	return MirrorRule{}, false
+}
+
+// Env returns the environment variables pointing package managers at the mirrors.
+func (m Mirrors) Env() []string {
+	var env []string
+	if m.NPMRegistry != "" {
+		env = append(env, "npm_config_registry="+m.NPMRegistry)
+	}
+	if m.PyPIIndex != "" {
+		env = append(env, "PIP_INDEX_URL="+m.PyPIIndex, "UV_DEFAULT_INDEX="+m.PyPIIndex)
+	}
+	return env
+}
@@ -0,0 +1,44 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMirrors_Rewrite(t *testing.T) {
+	m := Mirrors{Rules: []MirrorRule{
+		{From: "https://claude.ai/", To: "https://mirror.corp/claude/"},
+		{From: "https://github.com/", To: "https://mirror.corp/github/"},
+	}}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "shell command", in: "curl -fsSL https://claude.ai/install.sh | bash", want: "curl -fsSL https://mirror.corp/claude/install.sh | bash"},
+		{name: "quoted url", in: `irm "https://claude.ai/install.ps1" | iex`, want: `irm "https://mirror.corp/claude/install.ps1" | iex`},
+		{name: "several urls", in: "https://github.com/a (https://github.com/b)", want: "https://mirror.corp/github/a (https://mirror.corp/github/b)"},
+		{name: "not at word start", in: "https://proxy/?u=xhttps://github.com/a", want: "https://proxy/?u=xhttps://github.com/a"},
+		{name: "no match", in: "npm i -g @openai/codex", want: "npm i -g @openai/codex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Rewrite(tt.in); got != tt.want {
+				t.Errorf("Rewrite(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMirrors_Env(t *testing.T) {
+	if env := (Mirrors{}).Env(); env != nil {
+		t.Errorf("Env() without registries = %q, want none", env)
+	}
+	m := Mirrors{NPMRegistry: "https://npm.corp/", PyPIIndex: "https://pypi.corp/simple"}
+	want := []string{"npm_config_registry=https://npm.corp/", "PIP_INDEX_URL=https://pypi.corp/simple", "UV_DEFAULT_INDEX=https://pypi.corp/simple"}
+	if got := m.Env(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Env() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package tool
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Installers for Nix packages, in order of preference
+const (
+	InstallerNix    = "nix"
+	InstallerDevbox = "devbox"
+)
+
+// DetectNixInstaller returns the preferred Nix package installer found in PATH,
+// or "" if neither nix nor devbox is available.
+func DetectNixInstaller() string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	for _, installer := range []string{InstallerNix, InstallerDevbox} {
+		if _, err := LookPath(installer); err == nil {
+			return installer
+		}
+	}
+	return ""
+}
+
+// NixManaged reports whether the system or the current shell is managed by Nix
+// (NixOS, a nix shell or a devbox shell), where curl | bash installers are inappropriate.
+func NixManaged() bool {
+	_, err := os.Stat("/etc/NIXOS")
+	return nixManaged(os.Getenv, err == nil)
+}
+
+func nixManaged(getenv func(string) string, nixOS bool) bool {
+	return nixOS || getenv("IN_NIX_SHELL") != "" || getenv("DEVBOX_SHELL_ENABLED") != ""
+}
+
+// nixInstallArgs returns the command line that installs the nixpkgs attribute pkg with installer.
+func nixInstallArgs(installer, pkg string) []string {
+	switch installer {
+	case InstallerNix:
+		// Flakes may not be enabled in the user's nix.conf
+		return []string{"nix", "--extra-experimental-features", "nix-command flakes", "profile", "install", "nixpkgs#" + pkg}
+	case InstallerDevbox:
+		return []string{"devbox", "global", "add", pkg}
+	default:
+		return nil
+	}
+}
+
+// usesNix reports whether the tool is installed from nixpkgs: always on Nix-managed
+// systems, and elsewhere when there is no install command for this OS.
+func (t *Tool) usesNix() bool {
+	if t.NixPackage == "" || DetectNixInstaller() == "" {
+		return false
+	}
+	return NixManaged() || !t.hasOSInstallCommand()
+}
+
+// installWithNix installs the tool's nixpkgs package into the user's profile.
+func (t *Tool) installWithNix(out io.Writer) error {
+	installer := DetectNixInstaller()
+	args := nixInstallArgs(installer, t.NixPackage)
+	if err := runCommand(exec.Command(args[0], args[1:]...), out); err != nil {
+		return err
+	}
+	err := t.verifyInstalled()
+	if err != nil && installer == InstallerDevbox {
+		return fmt.Errorf(`%w; run eval "$(devbox global shellenv)" to add devbox packages to PATH`, err)
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNixInstallArgs(t *testing.T) {
+	tests := []struct {
+		installer string
+		want      []string
+	}{
+		{installer: InstallerNix, want: []string{"nix", "--extra-experimental-features", "nix-command flakes", "profile", "install", "nixpkgs#claude-code"}},
+		{installer: InstallerDevbox, want: []string{"devbox", "global", "add", "claude-code"}},
+		{installer: "brew", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.installer, func(t *testing.T) {
+			if got := nixInstallArgs(tt.installer, "claude-code"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nixInstallArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNixManaged(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		nixOS bool
+		want  bool
+	}{
+		{name: "plain system", want: false},
+		{name: "NixOS", nixOS: true, want: true},
+		{name: "nix shell", env: map[string]string{"IN_NIX_SHELL": "impure"}, want: true},
+		{name: "devbox shell", env: map[string]string{"DEVBOX_SHELL_ENABLED": "1"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := nixManaged(getenv, tt.nixOS); got != tt.want {
+				t.Errorf("nixManaged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package tool
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// NodeManager identifies a node version manager that fronts node/npm with shims.
+type NodeManager string
+
+const (
+	NodeManagerNone NodeManager = ""
+	NodeManagerAsdf NodeManager = "asdf"
+	NodeManagerMise NodeManager = "mise"
+	NodeManagerNvm  NodeManager = "nvm"
+)
+
+// DetectNodeManager reports which version manager provides the node binary on PATH.
+func DetectNodeManager() NodeManager {
+	path, err := LookPath("node")
+	if err != nil {
+		return NodeManagerNone
+	}
+	return nodeManagerFromPath(path)
+}
+
+// nodeManagerFromPath classifies a resolved node binary path by the version manager that owns it.
+func nodeManagerFromPath(path string) NodeManager {
+	p := filepath.ToSlash(path)
+	switch {
+	case strings.Contains(p, "/.asdf/") || hasPathPrefix(p, os.Getenv("ASDF_DATA_DIR")):
+		return NodeManagerAsdf
+	case strings.Contains(p, "/mise/") || hasPathPrefix(p, os.Getenv("MISE_DATA_DIR")):
+		return NodeManagerMise
+	case strings.Contains(p, "/.nvm/") || hasPathPrefix(p, os.Getenv("NVM_DIR")):
+		return NodeManagerNvm
+	default:
+		return NodeManagerNone
+	}
+}
+
+func hasPathPrefix(path, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	return strings.HasPrefix(path, filepath.ToSlash(dir)+"/")
+}
+
+// NodeVersion returns the version reported by the active node binary (e.g., "v20.11.1"),
+// or "" if node is not available.
+func NodeVersion() string {
+	out, err := exec.Command("node", "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// usesNpm reports whether an install command goes through npm.
+func usesNpm(installCmd string) bool {
+	for _, field := range strings.Fields(installCmd) {
+		if field == "npm" || field == "npx" {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeContextCommand wraps an npm-based install command so it runs against the node version
+// selected by the active version manager, refreshing shims afterwards where the manager needs it.
+// Commands that don't use npm are returned unchanged.
+func nodeContextCommand(installCmd string, manager NodeManager) string {
+	if !usesNpm(installCmd) {
+		return installCmd
+	}
+	switch manager {
+	case NodeManagerAsdf:
+		return fmt.Sprintf("(%s) && { asdf reshim nodejs >/dev/null 2>&1 || true; }", installCmd)
+	case NodeManagerMise:
+		return fmt.Sprintf("(%s) && { mise reshim >/dev/null 2>&1 || true; }", installCmd)
+	case NodeManagerNvm:
+		// nvm is a shell function and isn't available to sh -c, so pin the active version's bin dir instead.
+		if bin := os.Getenv("NVM_BIN"); bin != "" {
+			return fmt.Sprintf("export PATH='%s':\"$PATH\"; %s", strings.ReplaceAll(bin, "'", `'\''`), installCmd)
+		}
+	}
+	return installCmd
+}
+
+// currentInstallCmds returns the install commands that apply to the current OS,
+// including the ones of its Installers.
+func (t *Tool) currentInstallCmds() []string {
+	return append(t.osInstallCmds(), t.installerCommands()...)
+}
+
+// osInstallCmds returns the InstallCmds entries that apply to the current OS, in the order
+// InstallWithOutput tries them.
+func (t *Tool) osInstallCmds() []string {
+	var cmds []string
+	if runtime.GOOS == "windows" {
+		for _, key := range []string{"windows_ps", "windows_cmd"} {
+			if cmd := t.InstallCmds[key]; cmd != "" {
+				cmds = append(cmds, cmd)
+			}
+		}
+	}
+	if cmd, _ := t.installCommand(); cmd != "" {
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+// NodeVersionWarning returns a warning when the tool installs via npm and the active node
+// version is below its MinNodeVersion. Returns "" when there is nothing to warn about, or
+// when node is one of its Dependencies, which are checked on their own.
+func (t *Tool) NodeVersionWarning() string {
+	if t.MinNodeVersion == "" || slices.ContainsFunc(t.Dependencies, func(d Dependency) bool { return d.Name == "node" }) {
+		return ""
+	}
+	npmBased := false
+	for _, cmd := range t.currentInstallCmds() {
+		if usesNpm(cmd) {
+			npmBased = true
+			break
+		}
+	}
+	if !npmBased {
+		return ""
+	}
+
+	current := NodeVersion()
+	if current == "" {
+		if IsTermux() {
+			return fmt.Sprintf("node %s+ is required but node was not found (install it with: pkg install nodejs)", t.MinNodeVersion)
+		}
+		return fmt.Sprintf("node %s+ is required but node was not found", t.MinNodeVersion)
+	}
+	if compareVersions(current, t.MinNodeVersion) >= 0 {
+		return ""
+	}
+
+	msg := fmt.Sprintf("node %s is below the required %s", strings.TrimPrefix(current, "v"), t.MinNodeVersion)
+	if manager := DetectNodeManager(); manager != NodeManagerNone {
+		msg += fmt.Sprintf(" (switch versions with %s)", manager)
+	}
+	return msg
+}
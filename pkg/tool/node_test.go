@@ -0,0 +1,76 @@
+package tool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v20.11.1", "18", 1},
+		{"v16.20.0", "18", -1},
+		{"18.0.0", "18", 0},
+		{"v22.1.0", "v22.1.0", 0},
+		{"git version 2.43.0", "2.40", 1},
+		{"not a version", "1.0.0", -1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNodeManagerFromPath(t *testing.T) {
+	t.Setenv("ASDF_DATA_DIR", "")
+	t.Setenv("MISE_DATA_DIR", "")
+	t.Setenv("NVM_DIR", "")
+
+	tests := []struct {
+		path string
+		want NodeManager
+	}{
+		{"/home/u/.asdf/shims/node", NodeManagerAsdf},
+		{"/home/u/.local/share/mise/shims/node", NodeManagerMise},
+		{"/home/u/.nvm/versions/node/v20.11.1/bin/node", NodeManagerNvm},
+		{"/usr/bin/node", NodeManagerNone},
+	}
+
+	for _, tt := range tests {
+		if got := nodeManagerFromPath(tt.path); got != tt.want {
+			t.Errorf("nodeManagerFromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNodeContextCommand(t *testing.T) {
+	t.Setenv("NVM_BIN", "/home/u/.nvm/versions/node/v20.11.1/bin")
+
+	// Non-npm commands are never wrapped
+	if got := nodeContextCommand("brew install codex", NodeManagerAsdf); got != "brew install codex" {
+		t.Errorf("non-npm command was rewritten: %q", got)
+	}
+
+	got := nodeContextCommand("npm i -g @openai/codex", NodeManagerAsdf)
+	if !strings.Contains(got, "asdf reshim nodejs") {
+		t.Errorf("asdf install should reshim, got %q", got)
+	}
+
+	got = nodeContextCommand("npm i -g @openai/codex", NodeManagerMise)
+	if !strings.Contains(got, "mise reshim") {
+		t.Errorf("mise install should reshim, got %q", got)
+	}
+
+	got = nodeContextCommand("npm i -g @openai/codex", NodeManagerNvm)
+	if !strings.HasPrefix(got, "export PATH='/home/u/.nvm/versions/node/v20.11.1/bin'") {
+		t.Errorf("nvm install should pin NVM_BIN, got %q", got)
+	}
+
+	if got := nodeContextCommand("npm i -g @openai/codex", NodeManagerNone); got != "npm i -g @openai/codex" {
+		t.Errorf("command without a version manager was rewritten: %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+package tool
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// InstallPlan returns the commands InstallWithOutput would run on this system, in order and
+// with the mirrors applied: the installs of missing dependencies, then the tool's own.
+// Steps that aren't shell commands (a GitHub release download) are described in a "# "
+// comment. Returns nil when the tool can't be installed automatically here.
+// Nothing is run, so it can be shown before asking, or printed by a dry run.
+func (t *Tool) InstallPlan() []string {
+	if !t.HasInstallCommand() {
+		return nil
+	}
+	if t.usesNix() {
+		return []string{JoinArgs(nixInstallArgs(DetectNixInstaller(), t.NixPackage))}
+	}
+
+	var plan []string
+	for _, status := range t.CheckDependencies() {
+		if cmd := status.Dependency.AutoInstallCommand(); !status.Satisfied && cmd != "" {
+			plan = append(plan, currentMirrors().Rewrite(cmd))
+		}
+	}
+	return append(plan, t.installStep())
+}
+
+// installStep returns the command InstallWithOutput installs the tool itself with.
+func (t *Tool) installStep() string {
+	rewrite := currentMirrors().Rewrite
+	if runtime.GOOS == "windows" {
+		// The CMD command is only a fallback for a failed PowerShell one
+		for _, key := range []string{"windows_ps", "windows_cmd"} {
+			if cmd := t.InstallCmds[key]; cmd != "" {
+				return rewrite(cmd)
+			}
+		}
+	}
+	if cmd, _ := t.installCommand(); cmd != "" {
+		return rewrite(cmd)
+	}
+	if t.hasInstallers() {
+		i, err := t.firstAvailableInstaller()
+		if err != nil {
+			return "# " + err.Error()
+		}
+		return rewrite(i.Command())
+	}
+	if t.PythonPackage != nil {
+		if installer := DetectPythonInstaller(); installer != "" {
+			return JoinArgs(pythonInstallArgs(installer, *t.PythonPackage))
+		}
+		return "# " + t.PythonPackage.installerWarning()
+	}
+	return fmt.Sprintf("# download %s from the latest github.com/%s release into ~/.local/bin", t.GitHubRelease.Assets[githubPlatform()], t.GitHubRelease.Repo)
+}
@@ -0,0 +1,54 @@
+package tool
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInstallPlan(t *testing.T) {
+	simulateInstalls(t, "bash") // Fake brew and npm on PATH
+	SetMirrors(Mirrors{Rules: []MirrorRule{{From: "https://example.com/", To: "https://mirror.corp/"}}})
+	t.Cleanup(func() { SetMirrors(Mirrors{}) })
+
+	tests := []struct {
+		name string
+		tool *Tool
+		want []string
+	}{
+		{
+			name: "mirrored install command",
+			tool: &Tool{InstallCmds: map[string]string{runtime.GOOS: "curl -fsSL https://example.com/install.sh | bash"}},
+			want: []string{"curl -fsSL https://mirror.corp/install.sh | bash"},
+		},
+		{
+			name: "missing dependency is installed first",
+			tool: &Tool{
+				InstallCmds:  map[string]string{runtime.GOOS: "npm install -g @acme/fake-agent"},
+				Dependencies: []Dependency{{Name: "fake-runtime", AutoInstallCmds: map[string]string{runtime.GOOS: "brew install fake-runtime"}}},
+			},
+			want: []string{"brew install fake-runtime", "npm install -g @acme/fake-agent"},
+		},
+		{
+			name: "first installer present",
+			tool: &Tool{Installers: []Installer{{Manager: "no-such-manager", Package: "fake-agent"}, {Manager: ManagerBrew, Package: "fake-agent"}}},
+			want: []string{"brew install fake-agent"},
+		},
+		{
+			name: "no installer present",
+			tool: &Tool{Name: "fake-agent", Installers: []Installer{{Manager: "no-such-manager", Package: "fake-agent"}}},
+			want: []string{"# fake-agent is installed with no-such-manager; install one of them first"},
+		},
+		{
+			name: "no install here",
+			tool: &Tool{InstallCmds: map[string]string{"plan9": "install fake-agent"}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		got := tt.tool.InstallPlan()
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: InstallPlan() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package tool
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// platformNames are the user-facing names of GOOS values.
+var platformNames = map[string]string{
+	"darwin":  "macOS",
+	"linux":   "Linux",
+	"windows": "Windows",
+}
+
+// SupportsPlatform reports whether the tool runs on this OS. Tools without Platforms run everywhere.
+func (t *Tool) SupportsPlatform() bool {
+	return supportsOS(t.Platforms, runtime.GOOS)
+}
+
+func supportsOS(platforms []string, goos string) bool {
+	if len(platforms) == 0 {
+		return true
+	}
+	for _, p := range platforms {
+		if p == goos {
+			return true
+		}
+	}
+	return false
+}
+
+// platformWarning explains that the tool can't be installed here, or returns "" if it can.
+func (t *Tool) platformWarning() string {
+	if t.SupportsPlatform() {
+		return ""
+	}
+	name := runtime.GOOS
+	if n, ok := platformNames[name]; ok {
+		name = n
+	}
+	return fmt.Sprintf("Not available on %s (%s)", name, t.PlatformNote())
+}
+
+// PlatformNote describes where the tool runs (e.g., "macOS only"), or "" if it runs everywhere.
+func (t *Tool) PlatformNote() string {
+	return platformNote(t.Platforms)
+}
+
+func platformNote(platforms []string) string {
+	if len(platforms) == 0 {
+		return ""
+	}
+	names := make([]string, len(platforms))
+	for i, p := range platforms {
+		if name, ok := platformNames[p]; ok {
+			p = name
+		}
+		names[i] = p
+	}
+	switch len(names) {
+	case 1:
+		return names[0] + " only"
+	default:
+		return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1] + " only"
+	}
+}
@@ -0,0 +1,38 @@
+package tool
+
+import "testing"
+
+func TestSupportsOS(t *testing.T) {
+	tests := []struct {
+		platforms []string
+		goos      string
+		want      bool
+	}{
+		{nil, "linux", true},
+		{[]string{"darwin"}, "darwin", true},
+		{[]string{"darwin"}, "linux", false},
+		{[]string{"windows", "linux"}, "linux", true},
+	}
+	for _, tt := range tests {
+		if got := supportsOS(tt.platforms, tt.goos); got != tt.want {
+			t.Errorf("supportsOS(%v, %q) = %v, want %v", tt.platforms, tt.goos, got, tt.want)
+		}
+	}
+}
+
+func TestPlatformNote(t *testing.T) {
+	tests := []struct {
+		platforms []string
+		want      string
+	}{
+		{nil, ""},
+		{[]string{"darwin"}, "macOS only"},
+		{[]string{"windows", "linux"}, "Windows and Linux only"},
+		{[]string{"darwin", "linux", "freebsd"}, "macOS, Linux and freebsd only"},
+	}
+	for _, tt := range tests {
+		if got := platformNote(tt.platforms); got != tt.want {
+			t.Errorf("platformNote(%v) = %q, want %q", tt.platforms, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package tool
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// curl -# and other download bars end in a percentage: "######## 45.2%"
+	percentBarPattern = regexp.MustCompile(`[#=█━>]\s*(\d{1,3}(?:\.\d+)?)%$`)
+	// curl's progress meter: "% Total" first, then "45 12.3M   45 5.6M    0     0 ..."
+	curlMeterPattern = regexp.MustCompile(`^(\d{1,3})\s+\d+(?:\.\d+)?[kMG]?\s+(\d{1,3})\s+\d`)
+	// npm's gauge: "[#########.........] \ reify:lodash: ..."
+	npmGaugePattern = regexp.MustCompile(`^\[(#*)(\.*)\]`)
+	// npm's summary: "added 12 packages in 3s", "changed 1 package in 900ms"
+	npmDonePattern = regexp.MustCompile(`^(?:added|changed|removed|up to date)\b.* in \d`)
+)
+
+// brewStages are how far a Homebrew install is at each of its "==>" headings. A formula
+// with dependencies goes through them once per dependency, so the furthest one counts.
+var brewStages = []struct {
+	prefix string
+	done   float64
+}{
+	{"==> Fetching", 0.1},
+	{"==> Downloading", 0.2},
+	{"==> Installing", 0.4},
+	{"==> Pouring", 0.7},
+	{"==> Caveats", 0.9},
+	{"==> Summary", 1},
+	{"🍺", 1},
+}
+
+// InstallProgress estimates how far along the install command running is, from its output
+// (the lines of a TailBuffer): the fraction done, from 0 to 1, and false when the output
+// has nothing it knows about. Only the output after the last "$ cmd" line is looked at, so
+// each command of the install plan starts from 0.
+// Download bars (curl, pip), curl's meter, npm's gauge and summary, and the stages of
+// Homebrew are understood; the rest is for the caller to show a spinner for.
+func InstallProgress(lines []string) (float64, bool) {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "$ ") {
+			lines = lines[i+1:]
+			break
+		}
+	}
+
+	// The last bar drawn is the current one
+	for i := len(lines) - 1; i >= 0; i-- {
+		if done, ok := progressLine(lines[i]); ok {
+			return done, true
+		}
+	}
+
+	best, known := 0.0, false
+	for _, line := range lines {
+		for _, stage := range brewStages {
+			if strings.HasPrefix(line, stage.prefix) {
+				known = true
+				best = max(best, stage.done)
+			}
+		}
+	}
+	return best, known
+}
+
+// progressLine reads the fraction done from a line drawn by a progress bar.
+func progressLine(line string) (float64, bool) {
+	if m := percentBarPattern.FindStringSubmatch(line); m != nil {
+		return percent(m[1])
+	}
+	if m := curlMeterPattern.FindStringSubmatch(line); m != nil {
+		return percent(m[2]) // Received, not total
+	}
+	if m := npmGaugePattern.FindStringSubmatch(line); m != nil && len(m[1])+len(m[2]) > 0 {
+		return float64(len(m[1])) / float64(len(m[1])+len(m[2])), true
+	}
+	if npmDonePattern.MatchString(line) {
+		return 1, true
+	}
+	return 0, false
+}
+
+// percent parses a percentage into a fraction, refusing ones over 100%.
+func percent(s string) (float64, bool) {
+	p, err := strconv.ParseFloat(s, 64)
+	if err != nil || p > 100 {
+		return 0, false
+	}
+	return p / 100, true
+}
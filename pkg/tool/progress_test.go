@@ -0,0 +1,34 @@
+package tool
+
+import "testing"
+
+func TestInstallProgress(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		want   float64
+		wantOK bool
+	}{
+		{"curl bar", []string{"$ sh -c curl -# -o agent.tgz", "######################                      45.2%"}, 0.452, true},
+		{"curl meter", []string{"% Total    % Received % Xferd  Average Speed   Time", "100 12.3M   60 7.4M    0     0  5210k      0  0:00:02  0:00:01  0:00:01 5212k"}, 0.6, true},
+		{"npm gauge", []string{"[######..............] / reify:lodash: timing reifyNode"}, 0.3, true},
+		{"npm summary", []string{"npm warn deprecated glob@7.2.3", "added 12 packages in 3s"}, 1, true},
+		{"brew goes by its furthest stage", []string{
+			"==> Fetching dependencies for fake-agent: libfoo",
+			"==> Installing fake-agent dependency: libfoo",
+			"==> Pouring libfoo--1.0.arm64_sonoma.bottle.tar.gz",
+			"==> Installing fake-agent",
+		}, 0.7, true},
+		{"brew done", []string{"==> Pouring fake-agent--1.0.arm64_sonoma.bottle.tar.gz", "🍺  /opt/homebrew/Cellar/fake-agent/1.0: 12 files, 3MB"}, 1, true},
+		{"only the running command counts", []string{"$ sh -c npm install -g node", "added 1 package in 2s", "$ sh -c curl -fsSL https://example.com/install.sh | sh", "Installing to ~/.local/bin"}, 0, false},
+		{"unknown installer", []string{"Downloading fake-agent 1.0", "Done, 100 files"}, 0, false},
+		{"not a bar", []string{"coverage went up 120%"}, 0, false},
+		{"no output yet", nil, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := InstallProgress(tt.lines)
+		if ok != tt.wantOK || got < tt.want-0.001 || got > tt.want+0.001 {
+			t.Errorf("%s: InstallProgress() = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
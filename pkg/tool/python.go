@@ -0,0 +1,103 @@
+package tool
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// Installers for Python packages, in order of preference. Both install each tool
+// into its own virtual environment, keeping the global pip environment clean.
+const (
+	InstallerUV   = "uv"
+	InstallerPipx = "pipx"
+)
+
+// uvInstallURL is where users without an installer are sent.
+const uvInstallURL = "https://docs.astral.sh/uv/getting-started/installation/"
+
+// PythonPackage installs a Python-based tool from PyPI with uv or pipx.
+type PythonPackage struct {
+	Name   string // Package on PyPI (e.g., "aider-chat")
+	Python string // Python version to install with (e.g., "3.12"); empty uses the installer's default
+}
+
+// DetectPythonInstaller returns the preferred Python package installer found in PATH,
+// or "" if neither uv nor pipx is available.
+func DetectPythonInstaller() string {
+	for _, installer := range []string{InstallerUV, InstallerPipx} {
+		if _, err := LookPath(installer); err == nil {
+			return installer
+		}
+	}
+	return ""
+}
+
+// pythonInstallArgs returns the command line that installs pkg with installer.
+func pythonInstallArgs(installer string, pkg PythonPackage) []string {
+	var args []string
+	switch installer {
+	case InstallerUV:
+		args = []string{"uv", "tool", "install"}
+	case InstallerPipx:
+		args = []string{"pipx", "install"}
+	default:
+		return nil
+	}
+	if pkg.Python != "" {
+		args = append(args, "--python", pkg.Python)
+	}
+	return append(args, pkg.Name)
+}
+
+// pythonBinDir returns the directory installer links executables into.
+func pythonBinDir(installer string, getenv func(string) string, home string) string {
+	switch installer {
+	case InstallerUV:
+		if dir := getenv("UV_TOOL_BIN_DIR"); dir != "" {
+			return dir
+		}
+		if dir := getenv("XDG_BIN_HOME"); dir != "" {
+			return dir
+		}
+	case InstallerPipx:
+		if dir := getenv("PIPX_BIN_DIR"); dir != "" {
+			return dir
+		}
+	}
+	return filepath.Join(home, ".local", "bin")
+}
+
+// installerWarning describes the missing installer, or returns "" when one is available.
+func (p *PythonPackage) installerWarning() string {
+	if p == nil || DetectPythonInstaller() != "" {
+		return ""
+	}
+	return fmt.Sprintf("uv or pipx is required to install %s; install uv from %s", p.Name, uvInstallURL)
+}
+
+// install installs the package as command, making sure its bin directory is in PATH.
+// Installer output is copied to out when it is not nil.
+func (p *PythonPackage) install(command string, out io.Writer) error {
+	installer := DetectPythonInstaller()
+	if installer == "" {
+		return fmt.Errorf("cannot install %s: uv or pipx is required; install uv from %s", p.Name, uvInstallURL)
+	}
+
+	args := pythonInstallArgs(installer, *p)
+	if err := runCommand(exec.Command(args[0], args[1:]...), out); err != nil {
+		return err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil // verifyInstalled reports a missing command
+	}
+	if runtime.GOOS != "windows" {
+		_ = ensureDirInPath(pythonBinDir(installer, os.Getenv, home), command)
+	}
+	return nil
+}
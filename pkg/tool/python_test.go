@@ -0,0 +1,55 @@
+package tool
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPythonInstallArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		installer string
+		pkg       PythonPackage
+		want      []string
+	}{
+		{name: "uv", installer: InstallerUV, pkg: PythonPackage{Name: "aider-chat"}, want: []string{"uv", "tool", "install", "aider-chat"}},
+		{name: "uv with python", installer: InstallerUV, pkg: PythonPackage{Name: "aider-chat", Python: "3.12"}, want: []string{"uv", "tool", "install", "--python", "3.12", "aider-chat"}},
+		{name: "pipx with python", installer: InstallerPipx, pkg: PythonPackage{Name: "aider-chat", Python: "3.12"}, want: []string{"pipx", "install", "--python", "3.12", "aider-chat"}},
+		{name: "unknown installer", installer: "pip", pkg: PythonPackage{Name: "aider-chat"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pythonInstallArgs(tt.installer, tt.pkg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pythonInstallArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPythonBinDir(t *testing.T) {
+	home := filepath.Join("home", "me")
+	localBin := filepath.Join(home, ".local", "bin")
+	tests := []struct {
+		name      string
+		installer string
+		env       map[string]string
+		want      string
+	}{
+		{name: "uv default", installer: InstallerUV, want: localBin},
+		{name: "uv tool bin dir", installer: InstallerUV, env: map[string]string{"UV_TOOL_BIN_DIR": "/opt/uv", "XDG_BIN_HOME": "/xdg"}, want: "/opt/uv"},
+		{name: "uv xdg bin home", installer: InstallerUV, env: map[string]string{"XDG_BIN_HOME": "/xdg"}, want: "/xdg"},
+		{name: "pipx default", installer: InstallerPipx, env: map[string]string{"UV_TOOL_BIN_DIR": "/opt/uv"}, want: localBin},
+		{name: "pipx bin dir", installer: InstallerPipx, env: map[string]string{"PIPX_BIN_DIR": "/opt/pipx"}, want: "/opt/pipx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := pythonBinDir(tt.installer, getenv, home); got != tt.want {
+				t.Errorf("pythonBinDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
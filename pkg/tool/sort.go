@@ -0,0 +1,72 @@
+package tool
+
+import (
+	"cmp"
+	"sort"
+)
+
+// CompareRegistration orders tools by when they were registered, then by name, so tools that
+// are otherwise equal always come out in the same order.
+func CompareRegistration(a, b *Tool) int {
+	if c := cmp.Compare(a.seq, b.seq); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Name, b.Name)
+}
+
+// Sort returns a copy of tools in display order: installed tools first, most recently used
+// first (or highest frecency first with byFrecency), then supported uninstalled tools, then
+// the ones that don't run on this OS. Ties keep the registration order, see CompareRegistration.
+func Sort(tools []*Tool, byFrecency bool) []*Tool {
+	sorted := make([]*Tool, len(tools))
+	copy(sorted, tools)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+
+		// 如果安装状态不同，已安装的排在前面
+		if installedA, installedB := a.IsInstalled(), b.IsInstalled(); installedA != installedB {
+			return installedA
+		}
+
+		if a.IsInstalled() {
+			// 都已安装，按使用频率或最后使用时间降序排序（最近使用的在前）
+			if byFrecency && a.Frecency != b.Frecency {
+				return a.Frecency > b.Frecency
+			}
+			if !a.LastUsed.Equal(b.LastUsed) {
+				return a.LastUsed.After(b.LastUsed)
+			}
+		} else if supportedA, supportedB := a.SupportsPlatform(), b.SupportsPlatform(); supportedA != supportedB {
+			// 都未安装，不支持当前系统的排在最后
+			return supportedA
+		}
+		return CompareRegistration(a, b) < 0
+	})
+	return sorted
+}
+
+// Pin returns a copy of tools with the ones named in pinned moved to the front, in the
+// order of pinned; the rest keep their order. Names that aren't in tools are skipped.
+func Pin(tools []*Tool, pinned []string) []*Tool {
+	if len(pinned) == 0 {
+		return tools
+	}
+	rank := make(map[string]int, len(pinned))
+	for i, name := range pinned {
+		if _, dup := rank[name]; !dup {
+			rank[name] = i
+		}
+	}
+	result := make([]*Tool, len(tools))
+	copy(result, tools)
+	sort.SliceStable(result, func(i, j int) bool {
+		ri, pi := rank[result[i].Name]
+		rj, pj := rank[result[j].Name]
+		if pi != pj {
+			return pi
+		}
+		return pi && ri < rj
+	})
+	return result
+}
@@ -0,0 +1,171 @@
+package tool
+
+import (
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSort(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+	otherOS := "plan9"
+	if runtime.GOOS == otherOS {
+		otherOS = "linux"
+	}
+	type spec struct {
+		name      string
+		installed bool
+		lastUsed  time.Time
+		frecency  float64
+		otherOS   bool
+	}
+	tests := []struct {
+		name       string
+		tools      []spec // In registration order
+		byFrecency bool
+		want       string
+	}{
+		{
+			name:  "never used keeps registration order",
+			tools: []spec{{name: "c", installed: true}, {name: "a", installed: true}, {name: "b", installed: true}},
+			want:  "c,a,b",
+		},
+		{
+			name: "installed first, most recent first",
+			tools: []spec{
+				{name: "a"},
+				{name: "b", installed: true, lastUsed: now.Add(-time.Hour)},
+				{name: "c", installed: true, lastUsed: now},
+			},
+			want: "c,b,a",
+		},
+		{
+			name: "equal last use keeps registration order",
+			tools: []spec{
+				{name: "b", installed: true, lastUsed: now},
+				{name: "a", installed: true, lastUsed: now},
+				{name: "c", installed: true, lastUsed: now.Add(time.Minute)},
+			},
+			want: "c,b,a",
+		},
+		{
+			name: "unsupported after supported, ties in registration order",
+			tools: []spec{
+				{name: "x", otherOS: true},
+				{name: "b"},
+				{name: "y", otherOS: true},
+				{name: "a"},
+			},
+			want: "b,a,x,y",
+		},
+		{
+			name: "frecency before last use",
+			tools: []spec{
+				{name: "daily", installed: true, lastUsed: now.Add(-time.Hour), frecency: 5},
+				{name: "once", installed: true, lastUsed: now, frecency: 1},
+			},
+			byFrecency: true,
+			want:       "daily,once",
+		},
+		{
+			name: "recency ignores frecency",
+			tools: []spec{
+				{name: "daily", installed: true, lastUsed: now.Add(-time.Hour), frecency: 5},
+				{name: "once", installed: true, lastUsed: now, frecency: 1},
+			},
+			want: "once,daily",
+		},
+		{
+			name: "equal frecency falls back to last use, then registration",
+			tools: []spec{
+				{name: "a", installed: true, frecency: 2},
+				{name: "b", installed: true, lastUsed: now, frecency: 2},
+				{name: "c", installed: true, frecency: 2},
+			},
+			byFrecency: true,
+			want:       "b,a,c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry()
+			for _, s := range tt.tools {
+				tl := &Tool{Name: s.name, LastUsed: s.lastUsed, Frecency: s.frecency}
+				if s.otherOS {
+					tl.Platforms = []string{otherOS}
+				}
+				tl.SetInstalled(s.installed)
+				registry.Register(tl)
+			}
+
+			// The input order must not matter
+			tools := registry.List()
+			for i := 0; i < 10; i++ {
+				rand.Shuffle(len(tools), func(i, j int) { tools[i], tools[j] = tools[j], tools[i] })
+				var names []string
+				for _, tl := range Sort(tools, tt.byFrecency) {
+					names = append(names, tl.Name)
+				}
+				if got := strings.Join(names, ","); got != tt.want {
+					t.Fatalf("Sort() = %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareRegistration(t *testing.T) {
+	r := NewRegistry()
+	b, a := &Tool{Name: "b"}, &Tool{Name: "a"}
+	r.Register(b)
+	r.Register(a)
+	replaced := &Tool{Name: "b"}
+	r.Put(replaced)
+
+	tests := []struct {
+		name string
+		x, y *Tool
+		want int
+	}{
+		{"registered first", replaced, a, -1},
+		{"registered later", a, replaced, 1},
+		{"unregistered by name", &Tool{Name: "a"}, &Tool{Name: "b"}, -1},
+		{"unregistered before registered", &Tool{Name: "z"}, a, -1},
+		{"same tool", a, a, 0},
+	}
+	for _, tt := range tests {
+		if got := CompareRegistration(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: CompareRegistration() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPin(t *testing.T) {
+	var tools []*Tool
+	for _, name := range []string{"a", "b", "c", "d"} {
+		tools = append(tools, &Tool{Name: name})
+	}
+	tests := []struct {
+		pinned []string
+		want   string
+	}{
+		{nil, "a,b,c,d"},
+		{[]string{"c"}, "c,a,b,d"},
+		{[]string{"d", "b"}, "d,b,a,c"},
+		{[]string{"nope", "c", "c"}, "c,a,b,d"},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, tl := range Pin(tools, tt.pinned) {
+			names = append(names, tl.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("Pin(%q) = %s, want %s", tt.pinned, got, tt.want)
+		}
+	}
+	if tools[0].Name != "a" || tools[2].Name != "c" {
+		t.Error("Pin() reordered its argument")
+	}
+}
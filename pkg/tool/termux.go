@@ -0,0 +1,17 @@
+package tool
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// InstallKeyTermux keys the InstallCmds and UpgradeCmds entries used in Termux,
+// preferred over the "linux" ones there (e.g., "npm install -g @openai/codex").
+const InstallKeyTermux = "termux"
+
+// IsTermux reports whether amazing runs in Termux on Android, detected from its
+// $PREFIX (/data/data/com.termux/files/usr).
+func IsTermux() bool {
+	return runtime.GOOS == "android" || strings.Contains(os.Getenv("PREFIX"), "/com.termux/")
+}
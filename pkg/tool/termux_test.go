@@ -0,0 +1,30 @@
+package tool
+
+import "testing"
+
+func TestTool_InstallCommand_Termux(t *testing.T) {
+	tl := &Tool{InstallCmds: map[string]string{
+		"linux/amd64": "curl -fsSL https://example.com/install.sh | bash",
+		"darwin":      "brew install example",
+		"termux":      "npm install -g example",
+	}}
+
+	t.Setenv("PREFIX", "/data/data/com.termux/files/usr")
+	if !IsTermux() {
+		t.Fatal("IsTermux() = false with the Termux $PREFIX")
+	}
+	if cmd, _ := tl.installCommand(); cmd != "npm install -g example" {
+		t.Errorf("installCommand() = %q, want the termux command", cmd)
+	}
+	if msg := tl.archWarning(); msg != "" {
+		t.Errorf("archWarning() = %q, want none with a termux command", msg)
+	}
+
+	t.Setenv("PREFIX", "/usr/local")
+	if IsTermux() {
+		t.Error("IsTermux() = true outside Termux")
+	}
+	if cmd, _ := tl.installCommand(); cmd == "npm install -g example" {
+		t.Error("installCommand() picked the termux command outside Termux")
+	}
+}
@@ -3,54 +3,225 @@ package tool
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/keychain"
 )
 
 // Tool represents an AI CLI tool that can be launched.
 type Tool struct {
-	Name        string            // Internal identifier (e.g., "aider")
-	DisplayName string            // Human-readable name (e.g., "Aider - AI Pair Programming")
-	Command     string            // Command to execute (e.g., "aider")
-	Description string            // Brief description of the tool
-	Args        []string          // Default arguments to pass
-	InstallCmds map[string]string // OS-specific installation commands (key: "windows", "darwin", "linux")
-	InstallURL  string            // URL to installation documentation
-	LastUsed    time.Time         // 最后使用时间，用于LRU排序
-	Balance     *Balance          // Token balance for this tool (nil means not fetched yet)
-}
-
-// LimitDetail represents details about a specific limit (5h or weekly).
+	Name           string            // Internal identifier (e.g., "aider")
+	DisplayName    string            // Human-readable name (e.g., "Aider - AI Pair Programming")
+	Icon           string            // One-cell glyph drawn before the name in the list (e.g., "♥"); empty for none
+	Command        string            // Command to execute (e.g., "aider")
+	Description    string            // Brief description of the tool
+	Args           []string          // Default arguments to pass
+	Dir            string            // Directory the tool is launched in, with "~/" for the home directory; empty uses the current one
+	Env            map[string]string // Environment variables set when launching (e.g., {"OPENAI_BASE_URL": "https://proxy.local/v1"}); "$VAR" in values is expanded
+	KeychainEnv    map[string]string // Variables Env refers to that are read from the keychain, by account, when the environment doesn't set them
+	LoginArgs      []string          // Arguments that start the tool's login flow (e.g., ["login"]); empty if unknown
+	PromptArgs     []string          // Arguments that start the tool with a first prompt, with "{prompt}" standing for it (e.g., ["-i", "{prompt}"]); empty if it can't
+	SessionsGlob   string            // Where the tool saves session transcripts, as a glob with "~/" for the home directory (e.g., "~/.codex/sessions/*/*/*/*.jsonl"); empty if unknown
+	InstallCmds    map[string]string // OS-specific installation commands (key: "windows", "darwin", "linux", or "GOOS/GOARCH" such as "linux/arm64", or "termux")
+	Installers     []Installer       // Package manager installs in order of preference, used without an install command for this OS and offered when an install fails
+	InstallURL     string            // URL to installation documentation
+	VersionCmd     string            // Command that prints the installed version (defaults to "<Command> --version")
+	UpgradeCmds    map[string]string // OS-specific upgrade commands, keyed like InstallCmds; empty means reinstall
+	UpdateSource   string            // Where releases are published: "npm:<package>", "brew:<formula>", "github:<owner/repo>" or "pypi:<package>"
+	NixPackage     string            // nixpkgs attribute installed with nix or devbox on Nix-managed systems (e.g., "claude-code")
+	PythonPackage  *PythonPackage    // PyPI package installed with uv or pipx when there is no install command for this OS
+	GitHubRelease  *GitHubRelease    // Binary download used when there is no install command or Python package
+	MinNodeVersion string            // Minimum node version for npm-based installs (e.g., "18"); empty means no requirement
+	Dependencies   []Dependency      // Runtimes that must be present before installing (e.g., python >= 3.10, git)
+	LastUsed       time.Time         // 最后使用时间，用于LRU排序
+	Frecency       float64           // Launch frequency weighted by recency, for frecency ranking
+	Balance        *Balance          // Token balance for this tool (nil means not fetched yet)
+	Auth           AuthState         // Whether the tool is logged in (AuthUnknown until checked)
+	Version        string            // Last detected version output ("" if unknown)
+	Latest         string            // Newest published version ("" if unknown or not checked)
+	Tags           []string          // Free-form labels for filtering (e.g., "openai", "local"), without the leading "#"
+	Tips           []string          // Short tips, one a day shown with the launch banner and in the detail pane (e.g., "/compact frees up context")
+	Platforms      []string          // Operating systems the tool runs on, as GOOS values (e.g., "darwin"); empty means all
+	ConfigPath     string            // The tool's own settings file, with "~/" for the home directory (e.g., "~/.codex/config.toml"); empty if unknown
+	SearchDirs     []string          // Directories checked after PATH, with "~/" for the home directory (e.g., "~/.local/bin", where pipx links executables)
+
+	installed  *bool      // Cached result of the last PATH lookup (nil means not checked yet)
+	viaWindows bool       // Launch the Windows-side install through cmd.exe (WSL only, see UseWindows)
+	seq        int        // Registration order, 1-based (0 if never registered); breaks ties when sorting
+	ownLabel   *[2]string // DisplayName and Icon before the first Relabel (nil if never relabelled)
+}
+
+// Labels of well-known limit windows.
+const (
+	LimitFiveHour = "5h"
+	LimitWeekly   = "Wk"
+)
+
+// LimitDetail represents details about a specific limit (e.g., 5h, weekly or monthly quota).
 type LimitDetail struct {
-	Percentage int    // 0-100, percentage used
-	Display    string // Human-readable display
-	ResetTime  string // When the limit resets
+	Label            string    // Short label shown next to the bar (e.g., LimitFiveHour, "Premium")
+	RemainingPercent int       // 0-100, share of the window left
+	Display          string    // Human-readable display (e.g., "95% left (resets 05:09)")
+	ResetTime        string    // When the limit resets
+	ResetsAt         time.Time // When the limit resets (zero if unknown)
 }
 
 // Balance represents a placeholder for token/credit balance information.
 type Balance struct {
-	Percentage int    // 0-100
-	Display    string // Human-readable display (e.g., "100%", "1000 tokens")
-	Color      string // Color hint for display (e.g., "green", "yellow", "red")
-	
-	// Detailed limit information for Codex
-	FiveHourLimit LimitDetail // 5h limit details
-	WeeklyLimit   LimitDetail // Weekly limit details
+	Percentage int       // 0-100, share left (UnitPercent balances; RemainingPercent covers every unit)
+	Display    string    // Human-readable display (e.g., "100%", "1000 tokens")
+	FetchedAt  time.Time // When the provider fetched this data (zero if unknown)
+
+	// Absolute balances (Unit other than UnitPercent)
+	Unit      BalanceUnit // What Remaining and Total are measured in
+	Remaining float64     // Amount left (e.g., 120 requests)
+	Total     float64     // Amount per period (0 if there is no fixed allowance, e.g. prepaid credit)
+
+	// Individual limit windows (e.g., Codex 5h and weekly; Copilot chat, premium and monthly)
+	Limits    []LimitDetail
+	Breakdown []UsageShare // Usage in the 5h window by model, largest first (empty if unknown)
+	Credits   string       // Credits left on top of the limits (e.g., "1,234.56", "unlimited"); empty if none
+}
+
+// AuthState is whether a tool is logged in, as its saved login tells.
+type AuthState string
+
+const (
+	AuthUnknown   AuthState = ""           // Not checked, or the tool's login can't be read
+	AuthLoggedIn  AuthState = "logged-in"  // Usable, if need be after refreshing its token
+	AuthLoggedOut AuthState = "logged-out" // Never logged in, or logged out
+	AuthExpired   AuthState = "expired"    // Logged in, but the login can't be used without logging in again
+)
+
+// UnknownDisplay is the Display of a balance whose provider couldn't fetch it.
+const UnknownDisplay = "?%"
+
+// Unknown reports whether the provider couldn't fetch the balance.
+func (b Balance) Unknown() bool {
+	return b.Display == UnknownDisplay
+}
+
+// Limit returns the limit with the given label.
+func (b Balance) Limit(label string) (LimitDetail, bool) {
+	for _, limit := range b.Limits {
+		if limit.Label == label {
+			return limit, true
+		}
+	}
+	return LimitDetail{}, false
+}
+
+// UsageShare is the usage attributed to one model within a limit window.
+type UsageShare struct {
+	Label  string // Model name (e.g., "o3")
+	Tokens int64
 }
 
 // IsInstalled checks if the tool is available on the system.
+// The first PATH lookup is cached; use RefreshInstalled to check again.
 func (t *Tool) IsInstalled() bool {
-	_, err := exec.LookPath(t.Command)
+	if t.installed == nil {
+		return t.RefreshInstalled()
+	}
+	return *t.installed
+}
+
+// RefreshInstalled re-checks PATH for the tool and updates the cached state.
+func (t *Tool) RefreshInstalled() bool {
+	_, err := t.Path()
+	t.SetInstalled(err == nil)
 	return err == nil
 }
 
+// Path returns the executable the tool is launched from, or an error if it isn't installed.
+func (t *Tool) Path() (string, error) {
+	if t.viaWindows {
+		if path := t.WindowsSidePath(); path != "" {
+			return path, nil
+		}
+		return "", fmt.Errorf("tool not found on the Windows side: %s", t.Command)
+	}
+	path, err := LookPath(t.Command)
+	if err == nil {
+		return path, nil
+	}
+	// Installers like pipx link into directories that aren't always on PATH
+	for _, dir := range t.SearchDirs {
+		if path, dirErr := exec.LookPath(filepath.Join(expandHome(dir), t.Command)); dirErr == nil {
+			return path, nil
+		}
+	}
+	return "", err
+}
+
+// ConfigFile returns ConfigPath with a leading "~/" expanded to the home directory.
+func (t *Tool) ConfigFile() string {
+	return expandHome(t.ConfigPath)
+}
+
+// expandHome expands a leading "~/" in path to the home directory.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, rest)
+	}
+	return path
+}
+
+// SetInstalled records a known installation state (e.g., from a snapshot) without touching PATH.
+func (t *Tool) SetInstalled(installed bool) {
+	t.installed = &installed
+}
+
+// DetectVersion runs VersionCmd, or the tool with --version, and returns the first line
+// of its output. Returns "" if the tool isn't installed or doesn't report a version
+// within the timeout.
+func (t *Tool) DetectVersion(ctx context.Context) string {
+	path, err := t.Path()
+	if err != nil {
+		return ""
+	}
+	args := []string{t.Command, "--version"}
+	if t.VersionCmd != "" {
+		if args, err = SplitArgs(t.VersionCmd); err != nil || len(args) == 0 {
+			return ""
+		}
+		if args[0] != t.Command {
+			if path, err = LookPath(args[0]); err != nil {
+				return ""
+			}
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, dependencyProbeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, args[1:]...).Output()
+	if err != nil {
+		return ""
+	}
+	return firstLine(string(out))
+}
+
 // clearScreen clears the terminal screen in a cross-platform way.
+// It does nothing when stdout is not a terminal, so piped output stays clean.
 func clearScreen() {
+	if !isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+		return
+	}
 	if runtime.GOOS == "windows" {
 		// On Windows, use the cls command
 		cmd := exec.Command("cmd", "/c", "cls")
@@ -67,32 +238,292 @@ func clearScreen() {
 	}
 }
 
+// Terminal control sequences for switching to and from the alternate screen.
+const (
+	EnterAltScreen = "\033[?1049h\033[H\033[2J"
+	LeaveAltScreen = "\033[?1049l"
+)
+
+// LaunchOptions controls how the terminal is prepared before a tool starts.
+type LaunchOptions struct {
+	ClearScreen bool      // Clear the screen before launching
+	Banner      bool      // Print a one-line "Launching <tool> in <dir> …" banner, and the tip of the day, before launching
+	AltScreen   bool      // Run the tool inside the alternate screen so it never touches scrollback
+	Stderr      io.Writer // Optional writer that receives a copy of the tool's stderr (e.g., a TailBuffer)
+	ExtraArgs   []string  // Arguments appended to the tool's configured Args for this launch
+	Exec        bool      // Replace the amazing process with the tool instead of running it as a child (Unix only)
+	Dir         string    // Working directory for this launch, over the tool's Dir; empty uses the tool's
+}
+
+// ReplacesProcess reports whether the launch replaces the amazing process. Exec is ignored
+// on Windows and when AltScreen or Stderr need amazing to stay around after the tool exits.
+func (o LaunchOptions) ReplacesProcess() bool {
+	return o.Exec && canExecReplace && !o.AltScreen && o.Stderr == nil
+}
+
+// DefaultLaunchOptions returns the options used by Execute.
+func DefaultLaunchOptions() LaunchOptions {
+	return LaunchOptions{ClearScreen: true}
+}
+
+// Cmd builds the command that launches the tool with its configured arguments.
+// Standard streams are left unset so callers can attach them as needed.
+func (t *Tool) Cmd() (*exec.Cmd, error) {
+	return t.CmdWithArgs()
+}
+
+// CmdWithArgs builds the launch command like Cmd, appending extra to the configured arguments.
+func (t *Tool) CmdWithArgs(extra ...string) (*exec.Cmd, error) {
+	args := append(append([]string(nil), t.Args...), extra...)
+	cmd, err := t.command(args)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Env = t.Environ()
+	return cmd, nil
+}
+
+// Environ returns the environment the tool is launched with: the launcher's own, with
+// the tool's Env set over it. It returns nil, meaning the launcher's environment, when
+// the tool has no Env.
+func (t *Tool) Environ() []string {
+	if len(t.Env) == 0 {
+		return nil
+	}
+	var env []string
+	for _, kv := range os.Environ() {
+		// Drop overridden variables: syscall.Exec, unlike exec.Cmd, keeps duplicates
+		if key, _, _ := strings.Cut(kv, "="); !t.setsEnv(key) {
+			env = append(env, kv)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(t.Env)) {
+		env = append(env, key+"="+os.Expand(t.Env[key], t.expandEnv))
+	}
+	return env
+}
+
+// expandEnv returns the value of the variable key for expanding Env: the launcher's own,
+// or for the KeychainEnv variables it doesn't set, the key saved in the keychain.
+func (t *Tool) expandEnv(key string) string {
+	account := t.KeychainEnv[key]
+	if value := os.Getenv(key); value != "" || account == "" {
+		return value
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	value, _ := keychain.Lookup(ctx, account) // Not saved there either: the tool says what's missing
+	return value
+}
+
+// setsEnv reports whether the tool's Env sets the variable key, ignoring case on Windows.
+func (t *Tool) setsEnv(key string) bool {
+	if _, ok := t.Env[key]; ok || runtime.GOOS != "windows" {
+		return ok
+	}
+	for k := range t.Env {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEnv sets environment variables for launching the tool, over the ones it already has.
+func (t *Tool) SetEnv(env map[string]string) {
+	if len(env) == 0 {
+		return
+	}
+	merged := make(map[string]string, len(t.Env)+len(env))
+	maps.Copy(merged, t.Env)
+	maps.Copy(merged, env)
+	t.Env = merged
+}
+
+// LaunchCmd builds the launch command like CmdWithArgs with the extra arguments of opts,
+// starting in the launch directory (see LaunchDir).
+func (t *Tool) LaunchCmd(opts LaunchOptions) (*exec.Cmd, error) {
+	dir, err := t.LaunchDir(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := t.CmdWithArgs(opts.ExtraArgs...)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = dir
+	return cmd, nil
+}
+
+// LaunchDir returns the directory the tool starts in: dir, or the tool's Dir without one,
+// made absolute. It returns "" for the current directory and an error if the
+// directory doesn't exist.
+func (t *Tool) LaunchDir(dir string) (string, error) {
+	if dir == "" {
+		dir = t.Dir
+	}
+	if dir == "" {
+		return "", nil
+	}
+	dir, err := filepath.Abs(expandHome(dir))
+	if err != nil {
+		return "", fmt.Errorf("working directory: %w", err)
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("working directory: %w", err)
+	} else if !info.IsDir() {
+		return "", fmt.Errorf("working directory: %s is not a directory", dir)
+	}
+	return dir, nil
+}
+
+// LoginCmd builds the command that runs the tool's login flow.
+func (t *Tool) LoginCmd() (*exec.Cmd, error) {
+	if len(t.LoginArgs) == 0 {
+		return nil, fmt.Errorf("%s has no login command", t.Name)
+	}
+	return t.command(t.LoginArgs)
+}
+
+func (t *Tool) command(args []string) (*exec.Cmd, error) {
+	if t.viaWindows {
+		cmdExe, err := exec.LookPath("cmd.exe")
+		if err != nil {
+			return nil, fmt.Errorf("cmd.exe not found: %w", err)
+		}
+		return exec.Command(cmdExe, append([]string{"/c", t.Command}, args...)...), nil
+	}
+	path, err := t.Path()
+	if err != nil {
+		return nil, fmt.Errorf("tool not found: %s", t.Command)
+	}
+	return exec.Command(path, args...), nil
+}
+
 // Execute launches the tool as a child process with full terminal control.
 // This method is cross-platform compatible (works on Windows, Linux, macOS).
 func (t *Tool) Execute() error {
-	path, err := exec.LookPath(t.Command)
+	return t.ExecuteWithOptions(DefaultLaunchOptions())
+}
+
+// ExecuteWithOptions launches the tool like Execute, preparing the terminal according to opts.
+func (t *Tool) ExecuteWithOptions(opts LaunchOptions) error {
+	cmd, err := t.LaunchCmd(opts)
 	if err != nil {
-		return fmt.Errorf("tool not found: %s", t.Command)
+		return err
 	}
 
-	// Clear the screen before launching the tool
-	clearScreen()
-
-	// Create command with arguments
-	cmd := exec.Command(path, t.Args...)
+	interactive := isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+	if opts.AltScreen && interactive {
+		// A fresh alternate screen needs no clearing, and leaving it restores the previous view
+		fmt.Print(EnterAltScreen)
+		defer fmt.Print(LeaveAltScreen)
+	} else if opts.ClearScreen {
+		clearScreen()
+	}
+	if opts.Banner {
+		printLaunchBanner(t, cmd.Dir)
+	}
+	if opts.ReplacesProcess() {
+		return execReplace(cmd)
+	}
 
 	// Pass through standard streams to allow full terminal interaction
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if opts.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(os.Stderr, opts.Stderr)
+	}
 
 	// Run the command and wait for it to complete
 	return cmd.Run()
 }
 
+// printLaunchBanner prints "Launching <tool> in <dir> …" and the tool's tip of the day to
+// stderr so stdout stays clean for pipes. An empty dir is the current directory.
+func printLaunchBanner(t *Tool, dir string) {
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	if dir != "" {
+		fmt.Fprintf(os.Stderr, "Launching %s in %s …\n", t.Command, ShortenHome(dir))
+	} else {
+		fmt.Fprintf(os.Stderr, "Launching %s …\n", t.Command)
+	}
+	if tip := t.TipOfTheDay(time.Now()); tip != "" {
+		fmt.Fprintf(os.Stderr, "Tip: %s\n", tip)
+	}
+}
+
+// TipOfTheDay returns one of the tool's Tips, the same all day and the next one the day
+// after. Returns "" for tools without tips.
+func (t *Tool) TipOfTheDay(now time.Time) string {
+	if len(t.Tips) == 0 {
+		return ""
+	}
+	y, m, d := now.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60) // Days since 1970 in local time
+	return t.Tips[int(day%int64(len(t.Tips)))]
+}
+
+// ShortenHome replaces the user's home directory prefix with "~".
+func ShortenHome(dir string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return dir
+	}
+	if dir == home {
+		return "~"
+	}
+	if rel, err := filepath.Rel(home, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return dir
+}
+
+// HasTag reports whether the tool has the given tag, ignoring case and a leading "#".
+func (t *Tool) HasTag(tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, own := range t.Tags {
+		if strings.EqualFold(own, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags adds tags the tool doesn't have yet, keeping their order.
+func (t *Tool) AddTags(tags ...string) {
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag != "" && !t.HasTag(tag) {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+}
+
+// Relabel shows the tool as name with icon, e.g. for a display name override. The label
+// it had before is kept for OwnLabel.
+func (t *Tool) Relabel(name, icon string) {
+	if t.ownLabel == nil {
+		t.ownLabel = &[2]string{t.DisplayName, t.Icon}
+	}
+	t.DisplayName, t.Icon = name, icon
+}
+
+// OwnLabel returns the display name and icon the tool came with, before any Relabel.
+func (t *Tool) OwnLabel() (name, icon string) {
+	if t.ownLabel == nil {
+		return t.DisplayName, t.Icon
+	}
+	return t.ownLabel[0], t.ownLabel[1]
+}
+
 // Registry manages a collection of available tools.
 type Registry struct {
 	tools []*Tool
+	next  int // seq of the last registered tool
 }
 
 // NewRegistry creates a new tool registry.
@@ -104,9 +535,28 @@ func NewRegistry() *Registry {
 
 // Register adds a tool to the registry.
 func (r *Registry) Register(tool *Tool) {
+	r.next++
+	tool.seq = r.next
 	r.tools = append(r.tools, tool)
 }
 
+// Put registers a tool, replacing a registered tool with the same name in place.
+func (r *Registry) Put(tool *Tool) {
+	for i, t := range r.tools {
+		if t.Name == tool.Name {
+			tool.seq = t.seq // Takes over the replaced tool's place in the order
+			r.tools[i] = tool
+			return
+		}
+	}
+	r.Register(tool)
+}
+
+// Remove unregisters the tool called name, if there is one.
+func (r *Registry) Remove(name string) {
+	r.tools = slices.DeleteFunc(r.tools, func(t *Tool) bool { return t.Name == name })
+}
+
 // List returns all registered tools sorted by installation status.
 // Installed tools appear first, followed by uninstalled tools.
 func (r *Registry) List() []*Tool {
@@ -143,7 +593,28 @@ func (r *Registry) Get(name string) *Tool {
 // Note: This method should not be called while a TUI is active, as it does not connect stdin
 // to avoid race conditions between the TUI and installation process.
 func (t *Tool) Install() error {
+	return t.InstallWithOutput(nil)
+}
+
+// InstallWithOutput installs the tool like Install, copying the output of the
+// install commands to out as they run. out may be nil.
+func (t *Tool) InstallWithOutput(out io.Writer) error {
 	osType := runtime.GOOS
+	if !t.SupportsPlatform() {
+		return fmt.Errorf("%s can't be installed on %s: %s", t.Name, osType, t.PlatformNote())
+	}
+
+	// Nix provides its own dependencies
+	if t.usesNix() {
+		return t.installWithNix(out)
+	}
+
+	// Verify runtime dependencies before attempting the install itself
+	if t.HasInstallCommand() {
+		if err := t.ensureDependencies(out); err != nil {
+			return err
+		}
+	}
 
 	// Windows can provide separate PowerShell and CMD commands.
 	if osType == "windows" {
@@ -152,10 +623,10 @@ func (t *Tool) Install() error {
 
 		if installCmdPS != "" || installCmdCMD != "" {
 			if installCmdPS != "" {
-				if err := runInstallCommand(osType, installCmdPS, true); err == nil {
+				if err := runInstallCommand(osType, installCmdPS, true, out); err == nil {
 					return t.verifyInstalled()
 				} else if installCmdCMD != "" {
-					if err := runInstallCommand(osType, installCmdCMD, false); err != nil {
+					if err := runInstallCommand(osType, installCmdCMD, false, out); err != nil {
 						return err
 					}
 					return t.verifyInstalled()
@@ -163,41 +634,73 @@ func (t *Tool) Install() error {
 					return err
 				}
 			}
-			if err := runInstallCommand(osType, installCmdCMD, false); err != nil {
+			if err := runInstallCommand(osType, installCmdCMD, false, out); err != nil {
 				return err
 			}
 			return t.verifyInstalled()
 		}
 	}
 
-	// Check if we have installation commands for this OS
-	installCmd, exists := t.InstallCmds[osType]
-	if !exists || installCmd == "" {
+	// Check if we have installation commands for this OS and architecture
+	installCmd, _ := t.installCommand()
+	if installCmd == "" && t.hasInstallers() {
+		return t.installWithFirstAvailable(out)
+	}
+	if installCmd == "" && t.PythonPackage != nil {
+		if err := t.PythonPackage.install(t.Command, out); err != nil {
+			return err
+		}
+		return t.verifyInstalled()
+	}
+	if installCmd == "" && t.GitHubRelease.HasAsset() {
+		if err := t.GitHubRelease.install(t.Command, out); err != nil {
+			return fmt.Errorf("install failed: %w", err)
+		}
+		return t.verifyInstalled()
+	}
+	if installCmd == "" {
+		if arches := archVariants(t.InstallCmds, osType); len(arches) > 0 {
+			return fmt.Errorf("automated installation not available for %s/%s, only for %s", osType, runtime.GOARCH, strings.Join(arches, ", "))
+		}
 		if t.InstallURL != "" {
 			return fmt.Errorf("automated installation not available for %s. Please visit: %s", osType, t.InstallURL)
 		}
 		return fmt.Errorf("automated installation not available for %s", osType)
 	}
 
-	if err := runInstallCommand(osType, installCmd, true); err != nil {
+	if err := runInstallCommand(osType, installCmd, true, out); err != nil {
 		return err
 	}
 	return t.verifyInstalled()
 }
 
 // HasInstallCommand checks if the tool has an installation command for the current OS.
+// Tools that don't support the current OS never do.
 func (t *Tool) HasInstallCommand() bool {
-	osType := runtime.GOOS
-	if osType == "windows" {
+	if !t.SupportsPlatform() {
+		return false
+	}
+	return t.hasOSInstallCommand() || t.PythonPackage != nil || t.GitHubRelease.HasAsset() || t.usesNix()
+}
+
+// hasOSInstallCommand reports whether InstallCmds has a shell command for the current OS
+// and architecture, or Installers has a package manager install for it.
+func (t *Tool) hasOSInstallCommand() bool {
+	if t.hasInstallers() {
+		return true
+	}
+	if runtime.GOOS == "windows" {
 		if t.InstallCmds["windows_ps"] != "" || t.InstallCmds["windows_cmd"] != "" {
 			return true
 		}
 	}
-	cmd, exists := t.InstallCmds[osType]
-	return exists && cmd != ""
+	cmd, _ := t.installCommand()
+	return cmd != ""
 }
 
-func runInstallCommand(osType, installCmd string, preferPowerShell bool) error {
+func runInstallCommand(osType, installCmd string, preferPowerShell bool, out io.Writer) error {
+	installCmd = currentMirrors().Rewrite(installCmd)
+
 	// Execute the installation command
 	// Note: stdin is not connected to avoid race conditions with TUI
 	var cmd *exec.Cmd
@@ -208,12 +711,33 @@ func runInstallCommand(osType, installCmd string, preferPowerShell bool) error {
 			cmd = exec.Command("cmd", "/c", installCmd)
 		}
 	} else {
-		cmd = exec.Command("sh", "-c", installCmd)
+		// Run npm-based installs inside the active node version manager context (asdf/mise/nvm)
+		cmd = exec.Command("sh", "-c", nodeContextCommand(installCmd, DetectNodeManager()))
 	}
 
+	return runCommand(cmd, out)
+}
+
+// runCommand runs an install command with the mirror registries set, reporting the last
+// line of its output on failure. The output is also copied to out when it is not nil.
+func runCommand(cmd *exec.Cmd, out io.Writer) error {
+	var env []string
+	if IsWSL() {
+		// Install into Linux, not through the Windows npm or python on PATH
+		env = append(env, linuxPathEnv())
+	}
+	env = append(env, currentMirrors().Env()...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	var output bytes.Buffer
-	cmd.Stdout = &output
-	cmd.Stderr = &output
+	var w io.Writer = &output
+	if out != nil {
+		w = io.MultiWriter(&output, out)
+		fmt.Fprintf(out, "$ %s\n", strings.Join(cmd.Args, " "))
+	}
+	cmd.Stdout = w
+	cmd.Stderr = w
 	// stdin is intentionally not connected to prevent race conditions with TUI
 
 	if err := cmd.Run(); err != nil {
@@ -238,11 +762,12 @@ func lastNonEmptyLine(s string) string {
 }
 
 func (t *Tool) verifyInstalled() error {
-	if t.IsInstalled() {
+	if t.RefreshInstalled() {
 		return nil
 	}
 	if runtime.GOOS != "windows" {
 		if err := ensureLocalBinInPath(t.Command); err == nil {
+			t.SetInstalled(true)
 			return nil
 		}
 	}
@@ -254,20 +779,24 @@ func ensureLocalBinInPath(command string) error {
 	if err != nil {
 		return err
 	}
-	localBin := filepath.Join(home, ".local", "bin")
-	target := filepath.Join(localBin, command)
+	return ensureDirInPath(filepath.Join(home, ".local", "bin"), command)
+}
+
+// ensureDirInPath adds dir to PATH (and the shell config) when it contains command.
+func ensureDirInPath(dir, command string) error {
+	target := filepath.Join(dir, command)
 	if _, err := os.Stat(target); err != nil {
 		return err
 	}
 
-	if !pathContains(localBin) {
-		if err := appendPathToShellConfig(localBin); err != nil {
+	if !pathContains(dir) {
+		if err := appendPathToShellConfig(dir); err != nil {
 			return err
 		}
-		_ = os.Setenv("PATH", localBin+string(os.PathListSeparator)+os.Getenv("PATH"))
+		_ = os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
 	}
 
-	_, err = exec.LookPath(command)
+	_, err := LookPath(command)
 	return err
 }
 
@@ -281,23 +810,14 @@ func pathContains(dir string) bool {
 }
 
 func appendPathToShellConfig(dir string) error {
-	shell := filepath.Base(os.Getenv("SHELL"))
-	var rc string
-	switch shell {
-	case "zsh":
-		rc = ".zshrc"
-	case "bash":
-		rc = ".bashrc"
-	default:
-		return fmt.Errorf("unsupported shell: %s", shell)
-	}
-
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return err
 	}
-	rcPath := filepath.Join(home, rc)
-	line := fmt.Sprintf("export PATH=\"%s:$PATH\"\n", dir)
+	rcPath, line, err := shellConfigLine(filepath.Base(os.Getenv("SHELL")), home, dir)
+	if err != nil {
+		return err
+	}
 
 	if data, err := os.ReadFile(rcPath); err == nil {
 		if strings.Contains(string(data), dir) {
@@ -307,6 +827,10 @@ func appendPathToShellConfig(dir string) error {
 		return err
 	}
 
+	// fish keeps its config in ~/.config/fish, which may not exist yet
+	if err := os.MkdirAll(filepath.Dir(rcPath), 0o755); err != nil {
+		return err
+	}
 	f, err := os.OpenFile(rcPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
 		return err
@@ -316,3 +840,22 @@ func appendPathToShellConfig(dir string) error {
 	_, err = f.WriteString(line)
 	return err
 }
+
+// shellConfigLine returns the startup file of shell and the line that prepends dir to PATH in it.
+func shellConfigLine(shell, home, dir string) (rcPath, line string, err error) {
+	switch shell {
+	case "zsh":
+		return filepath.Join(home, ".zshrc"), fmt.Sprintf("export PATH=\"%s:$PATH\"\n", dir), nil
+	case "bash":
+		return filepath.Join(home, ".bashrc"), fmt.Sprintf("export PATH=\"%s:$PATH\"\n", dir), nil
+	case "fish":
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			configDir = filepath.Join(home, ".config")
+		}
+		// fish_add_path needs fish 3.2; setting PATH works everywhere
+		return filepath.Join(configDir, "fish", "config.fish"), fmt.Sprintf("set -gx PATH \"%s\" $PATH\n", dir), nil
+	default:
+		return "", "", fmt.Errorf("unsupported shell: %s", shell)
+	}
+}
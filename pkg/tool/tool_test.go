@@ -1,8 +1,13 @@
 package tool
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTool_HasInstallCommand(t *testing.T) {
@@ -44,6 +49,16 @@ func TestTool_HasInstallCommand(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "Tool that doesn't support the current OS",
+			tool: &Tool{
+				Name:        "test-tool",
+				Command:     "test",
+				Platforms:   []string{"plan9"},
+				InstallCmds: map[string]string{runtime.GOOS: "install test"},
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -159,3 +174,239 @@ func TestRegistry_List_SortsByInstallation(t *testing.T) {
 		t.Log("Warning: No tools detected as installed in test environment")
 	}
 }
+
+func TestTool_Tags(t *testing.T) {
+	tl := &Tool{Name: "codex", Tags: []string{"openai"}}
+	tl.AddTags("#work", "OpenAI", " ", "local")
+
+	want := []string{"openai", "work", "local"}
+	if len(tl.Tags) != len(want) {
+		t.Fatalf("Tags = %v, want %v", tl.Tags, want)
+	}
+	for i := range want {
+		if tl.Tags[i] != want[i] {
+			t.Fatalf("Tags = %v, want %v", tl.Tags, want)
+		}
+	}
+
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"openai", true},
+		{"#work", true},
+		{"LOCAL", true},
+		{"#team", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := tl.HasTag(tt.tag); got != tt.want {
+			t.Errorf("HasTag(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestTool_TipOfTheDay(t *testing.T) {
+	tl := &Tool{Tips: []string{"first", "second", "third"}}
+	morning := time.Date(2026, 2, 10, 8, 0, 0, 0, time.Local)
+	tip := tl.TipOfTheDay(morning)
+	if tip == "" {
+		t.Fatal("TipOfTheDay() should pick a tip")
+	}
+	if got := tl.TipOfTheDay(morning.Add(15 * time.Hour)); got != tip {
+		t.Errorf("TipOfTheDay() in the evening = %q, want the morning's %q", got, tip)
+	}
+	seen := map[string]bool{}
+	for day := range 3 {
+		seen[tl.TipOfTheDay(morning.AddDate(0, 0, day))] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("three days showed %v, want every tip once", seen)
+	}
+	if got := (&Tool{}).TipOfTheDay(morning); got != "" {
+		t.Errorf("TipOfTheDay() without tips = %q, want none", got)
+	}
+}
+
+func TestRegistry_Put(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&Tool{Name: "a", Command: "a"})
+	r.Register(&Tool{Name: "b", Command: "b"})
+
+	r.Put(&Tool{Name: "a", Command: "a2"})
+	r.Put(&Tool{Name: "c", Command: "c"})
+
+	var names, commands []string
+	for _, tl := range r.tools {
+		names = append(names, tl.Name)
+		commands = append(commands, tl.Command)
+	}
+	if strings.Join(names, ",") != "a,b,c" || strings.Join(commands, ",") != "a2,b,c" {
+		t.Errorf("registry = %v / %v, want a,b,c with a replaced in place", names, commands)
+	}
+}
+
+func TestRegistry_Remove(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&Tool{Name: "a", Command: "a"})
+	r.Register(&Tool{Name: "b", Command: "b"})
+
+	r.Remove("a")
+	r.Remove("unknown")
+	if r.Get("a") != nil || r.Get("b") == nil || len(r.List()) != 1 {
+		t.Errorf("registry = %v after Remove(a), want only b", r.List())
+	}
+}
+
+func TestLaunchOptions_ReplacesProcess(t *testing.T) {
+	tests := []struct {
+		name string
+		opts LaunchOptions
+		want bool
+	}{
+		{"exec", LaunchOptions{Exec: true}, canExecReplace},
+		{"child process", LaunchOptions{}, false},
+		{"alt screen", LaunchOptions{Exec: true, AltScreen: true}, false},
+		{"captured stderr", LaunchOptions{Exec: true, Stderr: NewTailBuffer(10)}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.ReplacesProcess(); got != tt.want {
+			t.Errorf("%s: ReplacesProcess() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTool_ConfigFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~/.codex/config.toml", filepath.Join(home, ".codex", "config.toml")},
+		{"/etc/tool.json", "/etc/tool.json"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := (&Tool{ConfigPath: tt.path}).ConfigFile(); got != tt.want {
+			t.Errorf("ConfigFile() for %q = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTool_Path_SearchDirs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the tool")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", t.TempDir())
+	bin := filepath.Join(home, ".local", "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeScript(t, filepath.Join(bin, "aider"))
+
+	if _, err := (&Tool{Command: "aider"}).Path(); err == nil {
+		t.Error("Path() found a tool outside PATH without SearchDirs")
+	}
+	aider := &Tool{Command: "aider", SearchDirs: []string{"~/.local/bin"}}
+	if got, err := aider.Path(); err != nil || got != filepath.Join(bin, "aider") {
+		t.Errorf("Path() = %q, %v, want the tool in ~/.local/bin", got, err)
+	}
+	if !aider.RefreshInstalled() {
+		t.Error("RefreshInstalled() = false for a tool in one of its SearchDirs")
+	}
+}
+
+func TestTool_Environ(t *testing.T) {
+	t.Setenv("AMAZING_TEST_PROXY", "http://old")
+	t.Setenv("AMAZING_TEST_HOST", "proxy.local")
+
+	if env := (&Tool{Name: "codex"}).Environ(); env != nil {
+		t.Errorf("Environ() without Env = %d variables, want nil", len(env))
+	}
+
+	tl := &Tool{Name: "codex", Env: map[string]string{"AMAZING_TEST_PROXY": "http://built-in"}}
+	tl.SetEnv(map[string]string{"AMAZING_TEST_PROXY": "http://$AMAZING_TEST_HOST:8080", "AMAZING_TEST_MODE": "on"})
+	got := map[string][]string{}
+	for _, kv := range tl.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		got[key] = append(got[key], value)
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"AMAZING_TEST_PROXY", "http://proxy.local:8080"},
+		{"AMAZING_TEST_MODE", "on"},
+		{"AMAZING_TEST_HOST", "proxy.local"},
+	}
+	for _, tt := range tests {
+		if len(got[tt.key]) != 1 || got[tt.key][0] != tt.want {
+			t.Errorf("Environ() %s = %q, want just %q", tt.key, got[tt.key], tt.want)
+		}
+	}
+}
+
+func TestTool_Environ_Keychain(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the fake keychain is a Linux secret-tool")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\n[ \"$5\" = deepseek ] && echo sk-from-keychain\n"
+	if err := os.WriteFile(filepath.Join(dir, "secret-tool"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	tl := &Tool{
+		Name:        "deepseek",
+		Env:         map[string]string{"ANTHROPIC_AUTH_TOKEN": "$AMAZING_TEST_KEY"},
+		KeychainEnv: map[string]string{"AMAZING_TEST_KEY": "deepseek"},
+	}
+
+	t.Setenv("AMAZING_TEST_KEY", "")
+	if env := tl.Environ(); !slices.Contains(env, "ANTHROPIC_AUTH_TOKEN=sk-from-keychain") {
+		t.Errorf("Environ() = %q, want the token from the keychain", env)
+	}
+	t.Setenv("AMAZING_TEST_KEY", "sk-env")
+	if env := tl.Environ(); !slices.Contains(env, "ANTHROPIC_AUTH_TOKEN=sk-env") {
+		t.Errorf("Environ() = %q, want the token from the environment first", env)
+	}
+}
+
+func TestTool_LaunchDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	work := filepath.Join(home, "src", "work")
+	if err := os.MkdirAll(work, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(home, "notes.txt")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		toolDir string
+		dir     string
+		want    string
+		wantErr bool
+	}{
+		{"current directory", "", "", "", false},
+		{"tool's directory", "~/src/work", "", work, false},
+		{"chosen over the tool's", "~/missing", home, home, false},
+		{"missing", "", filepath.Join(home, "missing"), "", true},
+		{"not a directory", "", file, "", true},
+	}
+	for _, tt := range tests {
+		got, err := (&Tool{Name: "codex", Dir: tt.toolDir}).LaunchDir(tt.dir)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("%s: LaunchDir(%q) = %q, %v, want %q (error: %v)", tt.name, tt.dir, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Registries that publish tool releases, named by the prefix of Tool.UpdateSource.
+const (
+	UpdateSourceNPM    = "npm"    // "npm:@openai/codex"
+	UpdateSourceBrew   = "brew"   // "brew:codex"
+	UpdateSourceGitHub = "github" // "github:charmbracelet/crush"
+	UpdateSourcePyPI   = "pypi"   // "pypi:aider-chat"
+)
+
+// API base URLs of the registries; tests point them at a local server.
+var (
+	npmRegistry = "https://registry.npmjs.org"
+	brewAPI     = "https://formulae.brew.sh/api"
+	pypiAPI     = "https://pypi.org/pypi"
+)
+
+// latestVersionTimeout bounds a single latest version lookup.
+const latestVersionTimeout = 10 * time.Second
+
+// updateSource returns the registry and package name the tool's releases are published under.
+// Tools installed from GitHub releases or PyPI default to those.
+func (t *Tool) updateSource() (registry, name string, ok bool) {
+	if registry, name, ok := strings.Cut(t.UpdateSource, ":"); ok && name != "" {
+		return registry, name, true
+	}
+	if t.GitHubRelease != nil && t.GitHubRelease.Repo != "" {
+		return UpdateSourceGitHub, t.GitHubRelease.Repo, true
+	}
+	if t.PythonPackage != nil && t.PythonPackage.Name != "" {
+		return UpdateSourcePyPI, t.PythonPackage.Name, true
+	}
+	return "", "", false
+}
+
+// ChecksUpdates reports whether the latest version of the tool can be looked up.
+func (t *Tool) ChecksUpdates() bool {
+	_, _, ok := t.updateSource()
+	return ok
+}
+
+// LatestVersion looks up the newest published version of the tool.
+func (t *Tool) LatestVersion(ctx context.Context) (string, error) {
+	registry, name, ok := t.updateSource()
+	if !ok {
+		return "", fmt.Errorf("no update source for %s", t.Name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, latestVersionTimeout)
+	defer cancel()
+
+	switch registry {
+	case UpdateSourceNPM:
+		base := npmRegistry
+		if m := currentMirrors().NPMRegistry; m != "" {
+			base = strings.TrimSuffix(m, "/")
+		}
+		var info struct {
+			Version string `json:"version"`
+		}
+		err := getJSONContext(ctx, base+"/"+name+"/latest", &info)
+		return info.Version, err
+	case UpdateSourceBrew:
+		var info struct {
+			Versions struct {
+				Stable string `json:"stable"`
+			} `json:"versions"`
+		}
+		err := getJSONContext(ctx, brewAPI+"/formula/"+name+".json", &info)
+		return info.Versions.Stable, err
+	case UpdateSourceGitHub:
+		var release githubReleaseInfo
+		err := getJSONContext(ctx, githubAPI+"/repos/"+name+"/releases/latest", &release)
+		return strings.TrimPrefix(release.TagName, "v"), err
+	case UpdateSourcePyPI:
+		var info struct {
+			Info struct {
+				Version string `json:"version"`
+			} `json:"info"`
+		}
+		err := getJSONContext(ctx, pypiAPI+"/"+name+"/json", &info)
+		return info.Info.Version, err
+	default:
+		return "", fmt.Errorf("unknown update source %q", registry)
+	}
+}
+
+// UpdateAvailable reports whether a newer version than the installed one was found.
+func (t *Tool) UpdateAvailable() bool {
+	if t.Latest == "" || t.Version == "" {
+		return false
+	}
+	if _, ok := parseVersion(t.Version); !ok {
+		return false
+	}
+	return compareVersions(t.Version, t.Latest) < 0
+}
+
+// InstalledVersion returns the version number from the detected version output
+// (e.g., "0.46.0" from "codex-cli 0.46.0"), or the output itself if it has none.
+func (t *Tool) InstalledVersion() string {
+	if m := versionPattern.FindString(t.Version); m != "" {
+		return m
+	}
+	return t.Version
+}
+
+// UpgradeWithOutput upgrades the tool in place, copying the output of the upgrade
+// commands to out as they run. out may be nil. Without UpgradeCmds for this OS (or Termux),
+// Homebrew formulae are upgraded with "brew upgrade" and other tools are installed
+// again, which fetches the latest release. It returns the version detected afterwards,
+// leaving t.Version to the caller: upgrades run in the background.
+func (t *Tool) UpgradeWithOutput(out io.Writer) (string, error) {
+	osType := runtime.GOOS
+	var err error
+	switch {
+	case IsTermux() && t.UpgradeCmds[InstallKeyTermux] != "":
+		err = runInstallCommand(osType, t.UpgradeCmds[InstallKeyTermux], true, out)
+	case osType == "windows" && t.UpgradeCmds["windows_ps"] != "":
+		err = runInstallCommand(osType, t.UpgradeCmds["windows_ps"], true, out)
+		if err != nil && t.UpgradeCmds["windows_cmd"] != "" {
+			err = runInstallCommand(osType, t.UpgradeCmds["windows_cmd"], false, out)
+		}
+	case osType == "windows" && t.UpgradeCmds["windows_cmd"] != "":
+		err = runInstallCommand(osType, t.UpgradeCmds["windows_cmd"], false, out)
+	case t.UpgradeCmds[osType] != "":
+		err = runInstallCommand(osType, t.UpgradeCmds[osType], true, out)
+	default:
+		if registry, name, _ := t.updateSource(); registry == UpdateSourceBrew {
+			err = runCommand(exec.Command("brew", "upgrade", name), out)
+		} else {
+			err = t.InstallWithOutput(out)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return t.DetectVersion(context.Background()), nil
+}
+
+func getJSONContext(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, currentMirrors().Rewrite(url), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", req.URL, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,136 @@
+package tool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestTool_LatestVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/npm/@openai/codex/latest":
+			w.Write([]byte(`{"name": "@openai/codex", "version": "0.47.0"}`))
+		case "/brew/formula/codex.json":
+			w.Write([]byte(`{"name": "codex", "versions": {"stable": "0.46.0"}}`))
+		case "/github/repos/charmbracelet/crush/releases/latest":
+			w.Write([]byte(`{"tag_name": "v0.9.1"}`))
+		case "/pypi/aider-chat/json":
+			w.Write([]byte(`{"info": {"version": "0.86.1"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	oldNPM, oldBrew, oldGitHub, oldPyPI := npmRegistry, brewAPI, githubAPI, pypiAPI
+	npmRegistry, brewAPI, githubAPI, pypiAPI = srv.URL+"/npm", srv.URL+"/brew", srv.URL+"/github", srv.URL+"/pypi"
+	defer func() { npmRegistry, brewAPI, githubAPI, pypiAPI = oldNPM, oldBrew, oldGitHub, oldPyPI }()
+
+	tests := []struct {
+		name    string
+		tool    *Tool
+		want    string
+		wantErr bool
+	}{
+		{name: "npm", tool: &Tool{UpdateSource: "npm:@openai/codex"}, want: "0.47.0"},
+		{name: "brew", tool: &Tool{UpdateSource: "brew:codex"}, want: "0.46.0"},
+		{name: "github", tool: &Tool{UpdateSource: "github:charmbracelet/crush"}, want: "0.9.1"},
+		{name: "github release default", tool: &Tool{GitHubRelease: &GitHubRelease{Repo: "charmbracelet/crush"}}, want: "0.9.1"},
+		{name: "python package default", tool: &Tool{PythonPackage: &PythonPackage{Name: "aider-chat"}}, want: "0.86.1"},
+		{name: "unpublished package", tool: &Tool{UpdateSource: "npm:missing"}, wantErr: true},
+		{name: "unknown registry", tool: &Tool{UpdateSource: "cargo:ripgrep"}, wantErr: true},
+		{name: "no source", tool: &Tool{Name: "claude"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tool.LatestVersion(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LatestVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("LatestVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTool_UpdateAvailable(t *testing.T) {
+	tests := []struct {
+		version string
+		latest  string
+		want    bool
+	}{
+		{"codex-cli 0.46.0", "0.47.0", true},
+		{"2.0.1 (Claude Code)", "2.0.1", false},
+		{"0.10.0", "0.9.1", false},
+		{"0.46.0", "", false},
+		{"", "0.47.0", false},
+		{"dev build", "0.47.0", false},
+	}
+	for _, tt := range tests {
+		tl := &Tool{Version: tt.version, Latest: tt.latest}
+		if got := tl.UpdateAvailable(); got != tt.want {
+			t.Errorf("UpdateAvailable() with %q -> %q = %v, want %v", tt.version, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestTool_UpgradeWithOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		tool        *Tool
+		wantLog     string
+		wantVersion string
+	}{
+		{
+			name: "upgrade command",
+			tool: &Tool{
+				UpgradeCmds: map[string]string{runtime.GOOS: "npm install -g @acme/fake-agent@latest"},
+				InstallCmds: map[string]string{runtime.GOOS: "brew install fake-agent"},
+			},
+			wantLog:     "npm install -g @acme/fake-agent@latest",
+			wantVersion: "fake-agent 1.0.0",
+		},
+		{
+			name:        "homebrew formula",
+			tool:        &Tool{UpdateSource: "brew:fake-agent"},
+			wantLog:     "brew upgrade fake-agent",
+			wantVersion: "fake-agent 2.0.0",
+		},
+		{
+			name:        "reinstall",
+			tool:        &Tool{InstallCmds: map[string]string{runtime.GOOS: "brew install fake-agent"}},
+			wantLog:     "brew install fake-agent",
+			wantVersion: "fake-agent 2.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := simulateInstalls(t, "bash")
+			// ~/.local/bin is where the fake npm installs; put it on PATH like a configured shell
+			t.Setenv("PATH", filepath.Join(home, ".local", "bin")+string(os.PathListSeparator)+os.Getenv("PATH"))
+			tt.tool.Name, tt.tool.Command = "fake-agent", "fake-agent"
+			tt.tool.VersionCmd = "fake-agent --version"
+
+			version, err := tt.tool.UpgradeWithOutput(nil)
+			if err != nil {
+				t.Fatalf("UpgradeWithOutput() error: %v", err)
+			}
+			log, _ := os.ReadFile(filepath.Join(home, "package-manager.log"))
+			if got := strings.TrimSpace(string(log)); got != tt.wantLog {
+				t.Errorf("package manager calls = %q, want %q", got, tt.wantLog)
+			}
+			if version != tt.wantVersion || tt.tool.Version != "" {
+				t.Errorf("UpgradeWithOutput() = %q with Version %q, want %q and Version left alone", version, tt.tool.Version, tt.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package tool
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern matches the first dotted version number in a string (e.g., "v20.11.1", "git version 2.43.0").
+var versionPattern = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// parseVersion extracts [major, minor, patch] from a version string.
+// Missing components are treated as 0. Returns false if no version number is found.
+func parseVersion(s string) ([3]int, bool) {
+	var v [3]int
+	matches := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return v, false
+	}
+	for i := 0; i < 3; i++ {
+		if matches[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// compareVersions compares two version strings.
+// Returns -1 if a < b, 0 if a == b, and 1 if a > b. Unparseable versions sort first.
+func compareVersions(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := 0; i < 3; i++ {
+		if va[i] < vb[i] {
+			return -1
+		}
+		if va[i] > vb[i] {
+			return 1
+		}
+	}
+	return 0
+}
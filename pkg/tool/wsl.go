@@ -0,0 +1,128 @@
+package tool
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// wslOSRelease reports the kernel release; WSL kernels mention Microsoft (e.g., "5.15.153.1-microsoft-standard-WSL2").
+var wslOSRelease = "/proc/sys/kernel/osrelease"
+
+// wslMountRoot is where WSL mounts the Windows drives (automount root in /etc/wsl.conf).
+var wslMountRoot = "/mnt/"
+
+// isWSL is replaced in tests.
+var isWSL = sync.OnceValue(detectWSL)
+
+// IsWSL reports whether amazing runs under the Windows Subsystem for Linux.
+func IsWSL() bool {
+	return isWSL()
+}
+
+func detectWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" || os.Getenv("WSL_INTEROP") != "" {
+		return true
+	}
+	data, err := os.ReadFile(wslOSRelease)
+	return err == nil && strings.Contains(strings.ToLower(string(data)), "microsoft")
+}
+
+// isWindowsPath reports whether path is on a Windows drive mounted by WSL (e.g., /mnt/c/Users).
+func isWindowsPath(path string) bool {
+	rest, ok := strings.CutPrefix(path, wslMountRoot)
+	if !ok {
+		return false
+	}
+	drive, _, _ := strings.Cut(rest, "/")
+	return len(drive) == 1 && ('a' <= drive[0] && drive[0] <= 'z' || 'A' <= drive[0] && drive[0] <= 'Z')
+}
+
+// LookPath searches PATH for command like exec.LookPath. Under WSL, the Windows directories
+// PATH interop appends are skipped: Windows tools found there (often npm shims) don't work
+// properly from Linux, so they don't count as installed.
+func LookPath(command string) (string, error) {
+	if !IsWSL() || strings.Contains(command, "/") {
+		return exec.LookPath(command)
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || isWindowsPath(dir) {
+			continue
+		}
+		if path, err := exec.LookPath(filepath.Join(dir, command)); err == nil {
+			return path, nil
+		}
+	}
+	return "", &exec.Error{Name: command, Err: exec.ErrNotFound}
+}
+
+// linuxPathEnv returns the PATH entry for commands run under WSL without the Windows
+// directories, so installers use the Linux npm and python rather than the Windows ones.
+func linuxPathEnv() string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if !isWindowsPath(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return "PATH=" + strings.Join(dirs, string(os.PathListSeparator))
+}
+
+// windowsExts are the extensions of programs cmd.exe runs, tried in this order.
+var windowsExts = []string{".exe", ".cmd", ".bat", ""}
+
+// WindowsSidePath returns where the tool is installed on the Windows side of WSL PATH
+// interop, or "" when not running under WSL or the tool isn't installed there.
+func (t *Tool) WindowsSidePath() string {
+	if !IsWSL() {
+		return ""
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if !isWindowsPath(dir) {
+			continue
+		}
+		for _, ext := range windowsExts {
+			path := filepath.Join(dir, t.Command+ext)
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// CanLaunchOnWindows reports whether the tool's Windows-side install can be launched with
+// cmd.exe /c. cmd.exe can't start in a Linux directory, so the current directory must be
+// on a Windows drive.
+func (t *Tool) CanLaunchOnWindows() bool {
+	if t.WindowsSidePath() == "" {
+		return false
+	}
+	if _, err := exec.LookPath("cmd.exe"); err != nil {
+		return false
+	}
+	wd, err := os.Getwd()
+	return err == nil && isWindowsPath(wd)
+}
+
+// UseWindows launches the tool's Windows-side install through cmd.exe /c from now on.
+func (t *Tool) UseWindows() {
+	t.viaWindows = true
+	t.SetInstalled(true)
+}
+
+// wslWarning explains that the tool is only installed on the Windows side, or returns "".
+func (t *Tool) wslWarning() string {
+	path := t.WindowsSidePath()
+	if path == "" {
+		return ""
+	}
+	return fmt.Sprintf("Only installed on the Windows side (%s); installing adds the Linux version", path)
+}
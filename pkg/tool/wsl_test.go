@@ -0,0 +1,99 @@
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeWSL pretends to run under WSL with the Windows drives mounted in a temporary
+// directory, and returns a Linux and a Windows-side directory on PATH, in that order.
+func fakeWSL(t *testing.T) (linuxDir, windowsDir string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("WSL is a Linux environment")
+	}
+	root := t.TempDir()
+	oldWSL, oldRoot := isWSL, wslMountRoot
+	isWSL, wslMountRoot = func() bool { return true }, root+"/mnt/"
+	t.Cleanup(func() { isWSL, wslMountRoot = oldWSL, oldRoot })
+
+	linuxDir = filepath.Join(root, "usr", "bin")
+	windowsDir = filepath.Join(root, "mnt", "c", "Users", "me", "AppData", "Roaming", "npm")
+	for _, dir := range []string{linuxDir, windowsDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", linuxDir+string(os.PathListSeparator)+windowsDir)
+	return linuxDir, windowsDir
+}
+
+func writeScript(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsWindowsPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/mnt/c/Users/me", true},
+		{"/mnt/D", true},
+		{"/mnt/wsl/docker", false},
+		{"/mnt/c2/x", false},
+		{"/home/me/.local/bin", false},
+	}
+	for _, tt := range tests {
+		if got := isWindowsPath(tt.path); got != tt.want {
+			t.Errorf("isWindowsPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLookPath_WSL(t *testing.T) {
+	linuxDir, windowsDir := fakeWSL(t)
+	writeScript(t, filepath.Join(windowsDir, "claude"))
+	writeScript(t, filepath.Join(windowsDir, "codex"))
+	writeScript(t, filepath.Join(linuxDir, "codex"))
+
+	if path, err := LookPath("claude"); err == nil {
+		t.Errorf("LookPath(claude) = %q, want the Windows-side install skipped", path)
+	}
+	if path, err := LookPath("codex"); err != nil || path != filepath.Join(linuxDir, "codex") {
+		t.Errorf("LookPath(codex) = %q, %v, want the Linux install", path, err)
+	}
+	if got, want := linuxPathEnv(), "PATH="+linuxDir; got != want {
+		t.Errorf("linuxPathEnv() = %q, want %q", got, want)
+	}
+}
+
+func TestTool_WindowsSidePath(t *testing.T) {
+	_, windowsDir := fakeWSL(t)
+	writeScript(t, filepath.Join(windowsDir, "claude"))
+	writeScript(t, filepath.Join(windowsDir, "claude.cmd"))
+
+	claude := &Tool{Name: "claude", Command: "claude"}
+	if got, want := claude.WindowsSidePath(), filepath.Join(windowsDir, "claude.cmd"); got != want {
+		t.Errorf("WindowsSidePath() = %q, want %q", got, want)
+	}
+	if claude.RefreshInstalled() {
+		t.Error("RefreshInstalled() = true for a tool only installed on the Windows side")
+	}
+	if warnings := claude.PreInstallWarnings(); len(warnings) == 0 {
+		t.Error("PreInstallWarnings() is empty, want a Windows-side warning")
+	}
+
+	claude.UseWindows()
+	if !claude.RefreshInstalled() {
+		t.Error("RefreshInstalled() = false after UseWindows")
+	}
+
+	if got := (&Tool{Name: "aider", Command: "aider"}).WindowsSidePath(); got != "" {
+		t.Errorf("WindowsSidePath() = %q for a tool that isn't installed", got)
+	}
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// launchArgs returns the extra arguments for the next launch.
+func (m Model) launchArgs() []string {
+	if m.args == nil {
+		return nil
+	}
+	return *m.args
+}
+
+// launchOptions returns the configured launch options with the extra arguments and directory.
+func (m Model) launchOptions() tool.LaunchOptions {
+	opts := m.settings.LaunchOptions()
+	opts.ExtraArgs = m.launchArgs()
+	opts.Dir = m.launchDir()
+	return opts
+}
+
+// markLaunched moves t up the order for a launch now.
+func markLaunched(t *tool.Tool) {
+	t.LastUsed = time.Now()
+	t.Frecency++ // A launch now counts fully
+}
+
+// launch starts t: right away with return-to-menu, otherwise by quitting with it selected.
+// Launched right after another tool's session, it first gathers what that session left
+// and offers to hand it off; the launch goes on when handoffGatheredMsg arrives.
+func (m Model) launch(t *tool.Tool) (tea.Model, tea.Cmd) {
+	if m.settings.ReturnToMenu {
+		if m.gatheringHandoff {
+			return m, nil // Already launching a tool, once its handoff is gathered
+		}
+		if cmd := m.gatherHandoff(t); cmd != nil {
+			m.gatheringHandoff = true
+			return m, cmd
+		}
+	}
+	return m.launchWith(t, nil)
+}
+
+// launchWith starts t like launch, with extra appended to the launch arguments.
+func (m Model) launchWith(t *tool.Tool, extra []string) (tea.Model, tea.Cmd) {
+	markLaunched(t)
+	m.lastSession = nil // Handed off or not, the next tool starts something new
+	if m.settings.ReturnToMenu {
+		opts := m.launchOptions()
+		opts.ExtraArgs = append(slices.Clip(opts.ExtraArgs), extra...)
+		cmd := m.launchTool(t, opts, m.launchNote())
+		if m.note != nil {
+			*m.note = "" // A note is about one session
+		}
+		if m.dir != nil {
+			*m.dir = "" // So is a chosen directory
+		}
+		return m, cmd
+	}
+	m.selected = t.Name
+	return m, tea.Quit
+}
+
+// updateArgs handles keys while the arguments of the selected tool are being edited.
+func (m Model) updateArgs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.editingArgs = false
+	case tea.KeyEnter:
+		args, err := tool.SplitArgs(m.argsInput)
+		if err != nil {
+			return m, m.showToast("Invalid arguments: " + err.Error())
+		}
+		m.editingArgs = false
+		if m.args == nil {
+			m.args = new([]string)
+		}
+		*m.args = args
+		if tools := m.visibleTools(); m.cursor < len(tools) {
+			return m.launch(tools[m.cursor])
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.argsInput); len(r) > 0 {
+			m.argsInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.argsInput += " "
+	case tea.KeyRunes:
+		m.argsInput += string(msg.Runes)
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderArgs renders the argument editor, or the extra arguments when not editing.
+func (m Model) renderArgs() string {
+	if m.editingArgs {
+		return searchStyle.Render("args › " + m.argsInput + "▏")
+	}
+	return descStyle.Render("args: " + tool.JoinArgs(m.launchArgs()))
+}
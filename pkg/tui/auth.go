@@ -0,0 +1,80 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// authCheckedMsg carries whether each checked tool is logged in, keyed by name
+type authCheckedMsg struct {
+	states map[string]tool.AuthState
+}
+
+// checkAuth reads the saved logins of the tools in the background. Tools are only read
+// here; the states are applied in Update.
+func checkAuth(tools []*tool.Tool) tea.Cmd {
+	return func() tea.Msg {
+		msg := authCheckedMsg{states: make(map[string]tool.AuthState, len(tools))}
+		for _, t := range tools {
+			msg.states[t.Name] = provider.CheckAuth(t)
+		}
+		return msg
+	}
+}
+
+// applyAuth sets the checked auth states on the tools.
+func (m Model) applyAuth(msg authCheckedMsg) {
+	for _, t := range m.tools {
+		if state, ok := msg.states[t.Name]; ok {
+			t.Auth = state
+		}
+	}
+}
+
+// authBadge returns the badge of an installed tool that needs logging in before it can be
+// used; logged in and unknown tools get none, so the badges stand out.
+func authBadge(t *tool.Tool) string {
+	if !t.IsInstalled() {
+		return ""
+	}
+	switch t.Auth {
+	case tool.AuthLoggedOut:
+		return warningStyle.UnsetPaddingLeft().Render("○ logged out")
+	case tool.AuthExpired:
+		return warningStyle.UnsetPaddingLeft().Render("⚠ login expired")
+	default:
+		return ""
+	}
+}
+
+// authText describes the tool's login for the detail pane, with the key that logs in.
+func authText(t *tool.Tool) string {
+	text := map[tool.AuthState]string{
+		tool.AuthLoggedIn:  "logged in",
+		tool.AuthLoggedOut: "logged out",
+		tool.AuthExpired:   "expired",
+	}[t.Auth]
+	if text == "" {
+		return ""
+	}
+	if t.Auth != tool.AuthLoggedIn && len(t.LoginArgs) > 0 {
+		text += " (L: " + t.Command + " " + tool.JoinArgs(t.LoginArgs) + ")"
+	}
+	return text
+}
+
+// login runs the selected tool's login command under the suspended TUI; its login is
+// checked again when it exits.
+func (m Model) login(t *tool.Tool) (tea.Model, tea.Cmd) {
+	if len(t.LoginArgs) == 0 {
+		return m, m.showToast(t.DisplayName + " has no login command; log in from inside it")
+	}
+	cmd, err := t.LoginCmd()
+	if err != nil {
+		m.launchError = err.Error()
+		return m, nil
+	}
+	return m, m.runToolCmd(t.Name, cmd, m.settings.LaunchOptions(), false)
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/analytics"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// balanceMsg carries a freshly fetched balance for one tool, with its weekly
+// projection and burn-rate alert when there is one
+type balanceMsg struct {
+	name       string
+	balance    *tool.Balance // nil if the tool isn't installed or the fetch returned nothing
+	projection *analytics.Projection
+	alert      *analytics.Projection
+}
+
+// fetchBalances starts one concurrent fetch per tool that has a balance provider.
+// Each fetch reports back with its own balanceMsg, so slow providers don't hold up others.
+// While "amazing daemon" keeps the balance cache fresh, its balances are used instead.
+func fetchBalances(tools []*tool.Tool, alerts config.BurnAlertSettings) tea.Cmd {
+	cache, ok := config.LoadBalanceCache()
+	if !ok || !cache.Fresh(time.Now()) {
+		cache.Balances = nil
+	}
+
+	var cmds []tea.Cmd
+	for _, t := range tools {
+		if provider.SupportsBalance(t) {
+			cmds = append(cmds, fetchBalance(t, cache.Balances[t.Name], alerts))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// fetchBalance fetches the balance of a single tool, unless the daemon already polled it.
+// The tool is only read here.
+func fetchBalance(t *tool.Tool, cached *tool.Balance, alerts config.BurnAlertSettings) tea.Cmd {
+	return func() tea.Msg {
+		msg := balanceMsg{name: t.Name, balance: cached}
+
+		if msg.balance == nil {
+			// Only fetch for tools that are installed
+			if _, err := t.Path(); err != nil {
+				return msg
+			}
+			msg.balance = provider.FetchBalance(context.Background(), t)
+		}
+		if msg.balance == nil {
+			return msg
+		}
+
+		samples, ok := config.RecordWeeklySample(t.Name, msg.balance)
+		if !ok {
+			return msg
+		}
+		now := time.Now()
+		weekly, _ := msg.balance.Limit(tool.LimitWeekly)
+		resetsAt := weekly.ResetsAt
+		if projection, ok := analytics.ProjectWeekly(samples, resetsAt, now); ok {
+			msg.projection = &projection
+		}
+		if !alerts.Enabled {
+			return msg
+		}
+		if projection, ok := analytics.BurnAlert(samples, resetsAt, now, alerts.Window(), alerts.Margin()); ok {
+			msg.alert = &projection
+		}
+		return msg
+	}
+}
+
+// balanceLoading reports whether the tool's balance is still being fetched.
+func (m Model) balanceLoading(t *tool.Tool) bool {
+	return m.settings.ShowBalances && provider.SupportsBalance(t) && t.IsInstalled() && !m.balancesDone[t.Name]
+}
+
+// anyBalanceLoading reports whether any listed tool's balance is still being fetched.
+func (m Model) anyBalanceLoading() bool {
+	for _, t := range m.tools {
+		if m.balanceLoading(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyBalance stores a fetched balance and returns the command for its burn-rate alert.
+func (m *Model) applyBalance(msg balanceMsg) tea.Cmd {
+	if m.balancesDone == nil {
+		m.balancesDone = make(map[string]bool)
+	}
+	m.balancesDone[msg.name] = true
+
+	t := m.findTool(msg.name)
+	if t == nil || msg.balance == nil {
+		return nil
+	}
+	t.Balance = msg.balance
+
+	if msg.projection != nil {
+		if m.projections == nil {
+			m.projections = make(map[string]analytics.Projection)
+		}
+		m.projections[msg.name] = *msg.projection
+	}
+	if msg.alert == nil {
+		return nil
+	}
+	return m.burnAlertCmds(map[string]analytics.Projection{msg.name: *msg.alert})
+}
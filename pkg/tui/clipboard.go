@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+	"github.com/muesli/termenv"
+)
+
+// copiedMsg is sent when text was copied to the clipboard; what names it for the toast
+type copiedMsg struct {
+	what string // Empty shows no toast
+}
+
+// copyToClipboard returns a command copying text to the system clipboard with the OS's
+// clipboard program, or, without one or over SSH, with an OSC 52 sequence written to
+// out, which asks the terminal to do it. The program can hang (xclip without a reachable
+// X server), so it runs outside Update.
+func copyToClipboard(out io.Writer, text, what string) tea.Cmd {
+	return func() tea.Msg {
+		if args := clipboardCommand(); args != nil {
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			err := cmd.Run()
+			if err == nil {
+				return copiedMsg{what: what}
+			}
+			log.Debug("clipboard program failed", "cmd", args[0], "err", err)
+		}
+		termenv.NewOutput(out).Copy(text)
+		return copiedMsg{what: what}
+	}
+}
+
+// clipboardCommand returns the clipboard program to pipe text into on this system, or nil.
+// Over SSH the clipboard to fill is the local one, so none is used.
+func clipboardCommand() []string {
+	if os.Getenv("SSH_CONNECTION") != "" {
+		return nil
+	}
+	var candidates [][]string
+	switch {
+	case runtime.GOOS == "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case runtime.GOOS == "windows", tool.IsWSL():
+		candidates = [][]string{{"clip.exe"}}
+	case tool.IsTermux():
+		candidates = [][]string{{"termux-clipboard-set"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		if os.Getenv("DISPLAY") != "" {
+			candidates = append(candidates, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+		}
+	}
+	for _, args := range candidates {
+		if _, err := exec.LookPath(args[0]); err == nil {
+			return args
+		}
+	}
+	return nil
+}
+
+// installClipboardText returns what to copy for installing t by hand: the commands of its
+// install plan, or else its install page. label says which, for the toast and the help.
+func installClipboardText(t *tool.Tool, plan []string) (text, label string) {
+	if len(plan) > 0 {
+		return strings.Join(plan, "\n"), "install command"
+	}
+	if t.InstallURL != "" {
+		return t.InstallURL, "install URL"
+	}
+	return "", ""
+}
+
+// copyInstall copies the install command or URL of t, plan being its InstallPlan, and says
+// so in a toast once it's done.
+func (m *Model) copyInstall(t *tool.Tool, plan []string) tea.Cmd {
+	text, label := installClipboardText(t, plan)
+	if text == "" {
+		return m.showToast(t.DisplayName + " has no install command or URL to copy")
+	}
+	return copyToClipboard(m.output(), text, "the "+label)
+}
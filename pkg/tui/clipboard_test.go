@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// fakeClipboard puts clipboard programs that save what they're given in a file into an
+// otherwise empty PATH, and returns that file.
+func fakeClipboard(t *testing.T, names ...string) string {
+	t.Helper()
+	if runtime.GOOS != "linux" || tool.IsWSL() || tool.IsTermux() {
+		t.Skip("the X11 and Wayland clipboard programs are looked for on Linux")
+	}
+	cat, err := exec.LookPath("cat") // Before PATH is emptied
+	if err != nil {
+		t.Skip(err)
+	}
+	bin, saved := t.TempDir(), filepath.Join(t.TempDir(), "clipboard")
+	for _, name := range names {
+		script := "#!/bin/sh\n" + cat + " > " + saved + "\n"
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	return saved
+}
+
+func TestClipboardCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		programs []string
+		env      map[string]string
+		want     []string
+	}{
+		{"x11", []string{"xclip", "xsel"}, map[string]string{"DISPLAY": ":0"}, []string{"xclip", "-selection", "clipboard"}},
+		{"x11 without xclip", []string{"xsel"}, map[string]string{"DISPLAY": ":0"}, []string{"xsel", "--clipboard", "--input"}},
+		{"wayland first", []string{"wl-copy", "xclip"}, map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, []string{"wl-copy"}},
+		{"no display", []string{"wl-copy", "xclip"}, nil, nil},
+		{"no program", nil, map[string]string{"DISPLAY": ":0"}, nil},
+		{"over ssh", []string{"xclip"}, map[string]string{"DISPLAY": ":0", "SSH_CONNECTION": "10.0.0.1 22 10.0.0.2 22"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClipboard(t, tt.programs...)
+			for _, key := range []string{"DISPLAY", "WAYLAND_DISPLAY", "SSH_CONNECTION"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if got := clipboardCommand(); !slices.Equal(got, tt.want) {
+				t.Errorf("clipboardCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	saved := fakeClipboard(t, "xclip")
+	t.Setenv("DISPLAY", ":0")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("SSH_CONNECTION", "")
+
+	var out bytes.Buffer
+	msg := copyToClipboard(&out, "npm i -g x", "the install command")()
+	if msg != (copiedMsg{what: "the install command"}) {
+		t.Errorf("copyToClipboard() sent %#v, want copiedMsg", msg)
+	}
+	if data, err := os.ReadFile(saved); err != nil || string(data) != "npm i -g x" {
+		t.Errorf("xclip was given %q, %v, want the text", data, err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("copyToClipboard() wrote %q, want nothing with a clipboard program", out.String())
+	}
+
+	// Over SSH the terminal is asked to copy instead
+	t.Setenv("SSH_CONNECTION", "10.0.0.1 22 10.0.0.2 22")
+	copyToClipboard(&out, "npm i -g x", "")()
+	if want := base64.StdEncoding.EncodeToString([]byte("npm i -g x")); !strings.Contains(out.String(), "\x1b]52;c;"+want) {
+		t.Errorf("copyToClipboard() wrote %q, want an OSC 52 sequence", out.String())
+	}
+}
+
+func TestInstallClipboardText(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      *tool.Tool
+		plan      []string
+		wantText  string
+		wantLabel string
+	}{
+		{"plan", &tool.Tool{InstallURL: "https://example.com"}, []string{"brew install node", "npm i -g x"}, "brew install node\nnpm i -g x", "install command"},
+		{"url only", &tool.Tool{InstallURL: "https://example.com"}, nil, "https://example.com", "install URL"},
+		{"nothing", &tool.Tool{}, nil, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, label := installClipboardText(tt.tool, tt.plan)
+			if text != tt.wantText || label != tt.wantLabel {
+				t.Errorf("installClipboardText() = %q, %q, want %q, %q", text, label, tt.wantText, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestCopyInstall_Toast(t *testing.T) {
+	m := testModel(t)
+	a := &tool.Tool{Name: "a", DisplayName: "A"}
+	if cmd := m.copyInstall(a, nil); cmd == nil || !strings.Contains(m.toast, "no install command or URL") {
+		t.Errorf("toast = %q, want it to say there's nothing to copy", m.toast)
+	}
+
+	next, _ := m.Update(copiedMsg{what: "the install URL"})
+	if m = next.(Model); m.toast != "Copied the install URL" {
+		t.Errorf("toast = %q once copied, want it to say what was copied", m.toast)
+	}
+}
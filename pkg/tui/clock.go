@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// clockStyle renders the header line with the time and the next reset
+var clockStyle = lipgloss.NewStyle().
+	Foreground(mutedText).
+	PaddingLeft(2)
+
+// clockTickMsg carries the time at the start of a new minute
+type clockTickMsg time.Time
+
+// clockTick fires on the next minute boundary, so the clock turns over with the wall clock.
+func clockTick() tea.Cmd {
+	return tea.Every(time.Minute, func(t time.Time) tea.Msg {
+		return clockTickMsg(t)
+	})
+}
+
+// nextReset returns the tool and limit window that resets soonest after now.
+func nextReset(tools []*tool.Tool, now time.Time) (*tool.Tool, tool.LimitDetail, bool) {
+	var next *tool.Tool
+	var limit tool.LimitDetail
+	for _, t := range tools {
+		if t.Balance == nil {
+			continue
+		}
+		for _, l := range t.Balance.Limits {
+			if l.ResetsAt.After(now) && (next == nil || l.ResetsAt.Before(limit.ResetsAt)) {
+				next, limit = t, l
+			}
+		}
+	}
+	return next, limit, next != nil
+}
+
+// renderClock renders the header, e.g. "16:22 · next reset: codex 5h at 17:00 (in 38m)".
+func (m Model) renderClock() string {
+	now := m.now
+	line := timefmt.Time(now)
+	if t, limit, ok := nextReset(m.tools, now); ok {
+		at := timefmt.Time(limit.ResetsAt)
+		if limit.ResetsAt.Sub(now) >= 24*time.Hour {
+			at = timefmt.DateTime(limit.ResetsAt)
+		}
+		line += fmt.Sprintf(" · next reset: %s %s at %s (in %s)", t.DisplayName, limit.Label, at, untilText(limit.ResetsAt.Sub(now)))
+	}
+	return clockStyle.Render(line)
+}
+
+// untilText describes a wait in the largest fitting units, e.g. "38m", "2h 5m" or "3d 4h".
+func untilText(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	switch {
+	case minutes < 1:
+		return "<1m"
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes < 24*60:
+		return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+	default:
+		return fmt.Sprintf("%dd %dh", minutes/(24*60), minutes%(24*60)/60)
+	}
+}
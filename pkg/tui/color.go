@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"io"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+// colorProfile returns the colors the TUI drawn on w can use. In auto mode it is detected
+// from w itself rather than stdout, which is redirected when the TUI draws on stderr, and
+// NO_COLOR, CLICOLOR and CLICOLOR_FORCE are honored; a configured mode overrides them.
+func colorProfile(mode string, w io.Writer) termenv.Profile {
+	switch mode {
+	case config.ColorTrueColor:
+		return termenv.TrueColor
+	case config.Color256:
+		return termenv.ANSI256
+	case config.Color16:
+		return termenv.ANSI
+	case config.ColorNone:
+		return termenv.Ascii
+	default:
+		return termenv.NewOutput(w).EnvColorProfile()
+	}
+}
+
+// useColors sets the colors of the TUI drawn on out and applies its theme. The banner
+// was rendered before the profile was known, so it is rendered again: with no colors
+// it is plain text.
+func (m *Model) useColors(out io.Writer) {
+	// Hex colors are converted to the closest ones the terminal can show
+	lipgloss.SetColorProfile(colorProfile(m.settings.Color, out))
+	applyTheme(m.theme)
+	if m.title != "" {
+		m.title = m.renderBanner()
+	}
+}
+
+// renderBanner renders the ASCII art title in the theme's title color, or as a rainbow.
+func (m Model) renderBanner() string {
+	if m.theme.Title != "" {
+		return lipgloss.NewStyle().Foreground(m.theme.Title).Bold(true).Render(titleArt)
+	}
+	return renderBlockColorTitle(titleArt, m.titleHue)
+}
+
+// output returns what the TUI draws on: stdout unless WithOutput says otherwise.
+func (m Model) output() io.Writer {
+	if m.out == nil {
+		return os.Stdout
+	}
+	return m.out
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+func TestUseColors_Banner(t *testing.T) {
+	old := lipgloss.ColorProfile()
+	t.Cleanup(func() { lipgloss.SetColorProfile(old) })
+
+	tests := []struct {
+		name      string
+		color     string
+		theme     Theme
+		wantColor bool
+	}{
+		{"none, rainbow", config.ColorNone, DefaultTheme(), false},
+		{"none, themed", config.ColorNone, Theme{Title: "#ff79c6"}, false},
+		{"truecolor, rainbow", config.ColorTrueColor, DefaultTheme(), true},
+		{"256, themed", config.Color256, Theme{Title: "#ff79c6"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The banner is first rendered by NewModel and WithTheme, before Run knows
+			// the profile; here as if the last TUI had left truecolor on
+			lipgloss.SetColorProfile(termenv.TrueColor)
+			m := testModel(t)
+			m.settings.Color = tt.color
+			WithTheme(tt.theme)(&m)
+
+			m.useColors(io.Discard)
+			if got := strings.Contains(m.title, "\x1b["); got != tt.wantColor {
+				t.Errorf("banner has colors = %v, want %v:\n%s", got, tt.wantColor, m.title)
+			}
+			if !strings.Contains(m.title, "/") {
+				t.Errorf("banner = %q, want the ASCII art", m.title)
+			}
+		})
+	}
+
+	m := testModel(t)
+	WithoutBanner()(&m)
+	m.useColors(io.Discard)
+	if m.title != "" {
+		t.Errorf("banner = %q after WithoutBanner, want none", m.title)
+	}
+}
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+const (
+	detailWidth    = 48  // Width of the detail pane's text
+	detailMinWidth = 110 // Narrower terminals show the pane below the list instead of beside it
+)
+
+// detailStyle renders the pane with the selected tool's details
+var detailStyle = lipgloss.NewStyle().
+	Border(lipgloss.NormalBorder(), false, false, false, true).
+	BorderForeground(gridLine).
+	PaddingLeft(1).
+	MarginLeft(2).
+	Width(detailWidth)
+
+// detailBinary is where the tool shown in the detail pane is installed, looked up in
+// PATH once per selection rather than on every frame.
+type detailBinary struct {
+	tool      *tool.Tool
+	installed bool // Looked up while the tool was installed; an install looks again
+	text      string
+}
+
+// resolveDetail looks up the binary of the selected tool for the detail pane, when the
+// pane is open and the selection or its install state changed.
+func (m *Model) resolveDetail() {
+	if !m.showDetail {
+		return
+	}
+	tools := m.visibleTools()
+	if m.cursor >= len(tools) {
+		return
+	}
+	t := tools[m.cursor]
+	if m.detailBinary.tool != t || m.detailBinary.installed != t.IsInstalled() {
+		m.detailBinary = detailBinary{tool: t, installed: t.IsInstalled(), text: binaryText(t)}
+	}
+}
+
+// binaryText returns where t is installed, or "not installed".
+func binaryText(t *tool.Tool) string {
+	if path, err := t.Path(); err == nil {
+		return path
+	}
+	if path := t.WindowsSidePath(); path != "" {
+		return path + " (Windows side)"
+	}
+	return "not installed"
+}
+
+// detailBelow reports whether the terminal is too narrow for the detail pane beside the
+// list, so it goes below it.
+func (m Model) detailBelow() bool {
+	return m.terminalWidth > 0 && m.terminalWidth < detailMinWidth
+}
+
+// renderDetailPane renders the detail pane of t for beside the rendered list, or for below
+// it on narrow terminals, as reported. The pane narrows to the space left, wrapping long values.
+func (m Model) renderDetailPane(t *tool.Tool, list string) (string, bool) {
+	binary := m.detailBinary.text
+	if m.detailBinary.tool != t {
+		binary = binaryText(t) // Not resolved by Update yet, e.g. in the first frame
+	}
+	style := detailStyle
+	last, _ := m.usage[t.Name].LastSession()
+	frame := style.GetHorizontalFrameSize() - style.GetHorizontalPadding() // Border and margin
+	if m.detailBelow() {
+		style = style.Width(max(20, min(detailWidth, m.terminalWidth-frame-1)))
+		return style.Render(renderDetail(t, binary, last, m.settings.ShowBalances)), true
+	}
+	if m.terminalWidth > 0 {
+		style = style.Width(max(20, min(detailWidth, m.terminalWidth-lipgloss.Width(list)-frame-1)))
+	}
+	return style.Render(renderDetail(t, binary, last, m.settings.ShowBalances)), false
+}
+
+// toolSummary returns the line under the selected row: the tool's description and a
+// health hint, e.g. "Kimi Code by Moonshot • not installed, enter to install".
+// With showBalance, a balance that couldn't be fetched is a health problem too.
+func toolSummary(t *tool.Tool, showBalance bool) string {
+	var parts []string
+	if t.Description != "" && t.Description != t.DisplayName {
+		parts = append(parts, t.Description)
+	}
+	switch {
+	case !t.IsInstalled() && t.HasInstallCommand():
+		parts = append(parts, "not installed, enter to install")
+	case !t.IsInstalled() && t.InstallURL != "":
+		parts = append(parts, "not installed, see "+t.InstallURL+" (y: copy)")
+	case !t.IsInstalled():
+		parts = append(parts, "not installed")
+	case showBalance && t.Balance != nil && t.Balance.Unknown():
+		parts = append(parts, "balance couldn't be fetched; is it logged in?")
+	}
+	return strings.Join(parts, " • ")
+}
+
+// renderDetail describes where the tool is installed (binary), its version, settings, last
+// use and last session (zero if none), and, with showBalance, every part of its balance.
+func renderDetail(t *tool.Tool, binary string, last config.Session, showBalance bool) string {
+	labelStyle := lipgloss.NewStyle().Foreground(activeTheme.Muted)
+	valueStyle := lipgloss.NewStyle().Foreground(activeTheme.Text)
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(activeTheme.Highlight).Render(t.DisplayName))
+	s.WriteString("\n")
+	if t.Description != "" && t.Description != t.DisplayName {
+		s.WriteString(labelStyle.Italic(true).Render(t.Description))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	row := func(label, value string) {
+		s.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render(fmt.Sprintf("%-10s", label)), valueStyle.Render(value)))
+	}
+
+	row("Binary", binary)
+
+	version := "unknown"
+	if v := t.InstalledVersion(); v != "" {
+		version = v
+	}
+	if t.UpdateAvailable() {
+		version += " (" + t.Latest + " available)"
+	}
+	row("Version", version)
+
+	config := "unknown"
+	if path := t.ConfigFile(); path != "" {
+		config = path
+	}
+	row("Config", config)
+	if auth := authText(t); auth != "" {
+		row("Login", auth)
+	}
+	if t.Dir != "" {
+		row("Dir", t.Dir)
+	}
+	if len(t.Env) > 0 {
+		// Names only: values can be tokens
+		row("Env", strings.Join(slices.Sorted(maps.Keys(t.Env)), ", "))
+	}
+
+	lastUsed := "never"
+	if !t.LastUsed.IsZero() {
+		lastUsed = timefmt.DateTime(t.LastUsed)
+	}
+	row("Last used", lastUsed)
+	if !last.Start.IsZero() {
+		row("Last run", sessionText(last))
+	}
+	if tip := t.TipOfTheDay(time.Now()); tip != "" {
+		row("Tip", tip)
+	}
+
+	if !showBalance {
+		return strings.TrimRight(s.String(), "\n")
+	}
+	s.WriteString("\n")
+	b := t.Balance
+	if b == nil {
+		row("Balance", "not fetched yet")
+		return strings.TrimRight(s.String(), "\n")
+	}
+	row("Balance", b.AmountDisplay())
+	for _, l := range b.Limits {
+		line := l.Display
+		switch {
+		case !l.ResetsAt.IsZero():
+			line += " · resets " + timefmt.DateTime(l.ResetsAt)
+		case l.ResetTime != "":
+			line += " · " + l.ResetTime
+		}
+		row("  "+l.Label, line)
+	}
+	if b.Credits != "" {
+		row("  Credits", b.Credits)
+	}
+	if breakdown := renderBreakdown(b.Breakdown); breakdown != "" {
+		s.WriteString(labelStyle.Render(breakdown))
+		s.WriteString("\n")
+	}
+	if !b.FetchedAt.IsZero() {
+		row("Fetched", timefmt.DateTime(b.FetchedAt))
+	}
+	return strings.TrimRight(s.String(), "\n")
+}
+
+// sessionText describes how long a session lasted and how the tool exited, e.g.
+// "42m, exited 0".
+func sessionText(s config.Session) string {
+	if s.Exec {
+		return "session length unavailable with exec_replace"
+	}
+	text := "length unknown"
+	if s.Seconds > 0 {
+		text = untilText(s.Duration())
+	}
+	switch {
+	case s.ExitCode == nil:
+	case *s.ExitCode < 0:
+		text += ", didn't exit normally"
+	default:
+		text += fmt.Sprintf(", exited %d", *s.ExitCode)
+	}
+	return text
+}
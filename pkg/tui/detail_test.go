@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+func TestSessionText(t *testing.T) {
+	zero, failed := 0, -1
+	start := time.Now()
+	tests := []struct {
+		session config.Session
+		want    string
+	}{
+		{config.Session{Start: start, Seconds: 42 * 60, ExitCode: &zero}, "42m, exited 0"},
+		{config.Session{Start: start, ExitCode: &failed}, "length unknown, didn't exit normally"},
+		{config.Session{Start: start}, "length unknown"},
+		{config.Session{Start: start, Exec: true}, "session length unavailable with exec_replace"},
+	}
+	for _, tt := range tests {
+		if got := sessionText(tt.session); got != tt.want {
+			t.Errorf("sessionText(%+v) = %q, want %q", tt.session, got, tt.want)
+		}
+	}
+}
+
+func TestDetail_BinaryResolvedOnSelection(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake tools are shell scripts")
+	}
+	bin := t.TempDir()
+	for _, name := range []string{"a", "b"} {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	m := testModel(t, installedTool("a"), installedTool("b"))
+	m.focusTool("a")
+
+	m = press(m, "tab")
+	if m.detailBinary.tool == nil || m.detailBinary.tool.Name != "a" || m.detailBinary.text != filepath.Join(bin, "a") {
+		t.Fatalf("detailBinary = %+v once the pane opens, want a's path", m.detailBinary)
+	}
+
+	// Drawing the pane uses what was looked up, without looking again
+	if err := os.Remove(filepath.Join(bin, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if view := m.View(); strings.Contains(view, "not installed") {
+		t.Errorf("View() looked up a's binary again, want the resolved one:\n%s", view)
+	}
+
+	m = press(m, "down")
+	if m.detailBinary.tool == nil || m.detailBinary.tool.Name != "b" || m.detailBinary.text != filepath.Join(bin, "b") {
+		t.Errorf("detailBinary = %+v after moving down, want b's path", m.detailBinary)
+	}
+
+	// An uninstall found by revalidation looks again
+	m.detailBinary.tool.SetInstalled(false)
+	if err := os.Remove(filepath.Join(bin, "b")); err != nil {
+		t.Fatal(err)
+	}
+	next, _ := m.Update(clockTickMsg{})
+	if m = next.(Model); m.detailBinary.text != "not installed" {
+		t.Errorf("detailBinary = %+v once b isn't installed, want it looked up again", m.detailBinary)
+	}
+}
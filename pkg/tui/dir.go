@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// maxDirChoices is how many directories the picker lists at once.
+const maxDirChoices = 6
+
+// launchDir returns the working directory the next launch starts in; empty means the
+// tool's configured one, or the current directory.
+func (m Model) launchDir() string {
+	if m.dir == nil {
+		return ""
+	}
+	return *m.dir
+}
+
+// openDirPicker starts choosing the working directory for t, offering its configured
+// directory, the current one and the recently used ones.
+func (m Model) openDirPicker(t *tool.Tool) Model {
+	m.choosingDir = true
+	m.dirInput = ""
+	m.dirCursor = 0
+	m.dirChoices = nil
+
+	candidates := []string{m.launchDir()}
+	if dir, err := t.LaunchDir(""); err == nil {
+		candidates = append(candidates, dir)
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, cwd)
+	}
+	seen := make(map[string]bool)
+	for _, dir := range append(candidates, config.LoadRecentDirs()...) {
+		if dir == "" || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			m.dirChoices = append(m.dirChoices, dir)
+		}
+	}
+	return m
+}
+
+// matchingDirs returns the offered directories containing every typed word, ignoring case.
+func (m Model) matchingDirs() []string {
+	var dirs []string
+	for _, dir := range m.dirChoices {
+		text := strings.ToLower(tool.ShortenHome(dir))
+		match := true
+		for _, word := range strings.Fields(strings.ToLower(m.dirInput)) {
+			match = match && strings.Contains(text, word)
+		}
+		if match {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// updateDir handles keys while the working directory of the next launch is being chosen.
+// Typing filters the offered directories; a typed path nothing matches is used as is.
+func (m Model) updateDir(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tools := m.visibleTools()
+	if m.cursor >= len(tools) {
+		m.choosingDir = false
+		return m, nil
+	}
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.choosingDir = false
+	case tea.KeyUp:
+		m.dirCursor = max(0, m.dirCursor-1)
+	case tea.KeyDown:
+		m.dirCursor = max(0, min(m.dirCursor+1, min(len(m.matchingDirs()), maxDirChoices)-1))
+	case tea.KeyEnter:
+		dir := m.dirInput
+		if matches := m.matchingDirs(); m.dirCursor >= 0 && m.dirCursor < len(matches) {
+			dir = matches[m.dirCursor]
+		}
+		dir, err := tools[m.cursor].LaunchDir(strings.TrimSpace(dir))
+		if err != nil {
+			return m, m.showToast(err.Error())
+		}
+		m.choosingDir = false
+		if m.dir == nil {
+			m.dir = new(string)
+		}
+		*m.dir = dir
+		return m.launch(tools[m.cursor])
+	case tea.KeyBackspace:
+		if r := []rune(m.dirInput); len(r) > 0 {
+			m.dirInput = string(r[:len(r)-1])
+		}
+		m.dirCursor = 0
+	case tea.KeySpace:
+		m.dirInput += " "
+		m.dirCursor = 0
+	case tea.KeyRunes:
+		m.dirInput += string(msg.Runes)
+		m.dirCursor = 0
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderDir renders the directory picker, or the chosen directory when not choosing.
+func (m Model) renderDir() string {
+	if !m.choosingDir {
+		return descStyle.Render("dir: " + tool.ShortenHome(m.launchDir()))
+	}
+	var s strings.Builder
+	s.WriteString(searchStyle.Render("dir › " + m.dirInput + "▏"))
+	for i, dir := range m.matchingDirs() {
+		if i == maxDirChoices {
+			break
+		}
+		if i == m.dirCursor {
+			s.WriteString("\n    " + submenuSelectedStyle.Render("› "+tool.ShortenHome(dir)))
+		} else {
+			s.WriteString("\n    " + submenuStyle.Render("  "+tool.ShortenHome(dir)))
+		}
+	}
+	return s.String()
+}
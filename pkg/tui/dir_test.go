@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpdateDir(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		keys    []string
+		wantDir string
+		toast   string
+	}{
+		{"down with nothing matching, then enter", []string{"zz-nothing", "down", "down", "enter"}, "", "working directory"},
+		{"typed path", []string{dir, "down", "enter"}, dir, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel(t, installedTool("a"))
+			m = m.openDirPicker(m.visibleTools()[0])
+			m.dirChoices = nil // Only what is typed
+			m = press(m, tt.keys...)
+			if m.dirCursor < 0 {
+				t.Errorf("dirCursor = %d, want it clamped at 0", m.dirCursor)
+			}
+			if !strings.Contains(m.toast, tt.toast) {
+				t.Errorf("toast = %q, want it to mention %q", m.toast, tt.toast)
+			}
+			if tt.wantDir != "" && m.launchDir() != tt.wantDir {
+				t.Errorf("launchDir() = %q, want %q", m.launchDir(), tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestLaunch_ClearsDirAndNote(t *testing.T) {
+	a := installedTool("a")
+	a.Command = "true"
+	m := testModel(t, a)
+	m.settings.ReturnToMenu = true
+	dir, note := t.TempDir(), "fixing auth"
+	m.dir, m.note = &dir, &note
+
+	next, cmd := m.launchWith(m.visibleTools()[0], nil)
+	m = next.(Model)
+	if cmd == nil || !m.suspended {
+		t.Fatal("launchWith() with return_to_menu should run the tool under the suspended launcher")
+	}
+	if m.launchDir() != "" || m.launchNote() != "" {
+		t.Errorf("after a launch dir = %q, note = %q, want both cleared for the next one", m.launchDir(), m.launchNote())
+	}
+}
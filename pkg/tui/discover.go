@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/catalog"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+// discoveredMsg carries the known agents found in PATH that aren't in the registry
+type discoveredMsg struct {
+	tools []catalog.Definition
+}
+
+// discoverTools looks for unregistered agents in PATH in the background.
+func discoverTools(m Model) tea.Cmd {
+	registry, hidden := m.registry, m.settings.Hidden
+	return func() tea.Msg {
+		return discoveredMsg{tools: config.DiscoverTools(registry, hidden)}
+	}
+}
+
+// offerDiscovered keeps the agents found in PATH and tells about them in a toast.
+func (m *Model) offerDiscovered(msg discoveredMsg) tea.Cmd {
+	m.discovered = msg.tools
+	if len(msg.tools) == 0 {
+		return nil
+	}
+	return m.showToast(fmt.Sprintf("Found %s in PATH • +: add to the list", discoveredNames(msg.tools)))
+}
+
+// addDiscovered saves the agents found in PATH as custom tools and adds them to the list.
+func (m *Model) addDiscovered() tea.Cmd {
+	if len(m.discovered) == 0 {
+		return nil
+	}
+	if err := config.AddTools(m.discovered); err != nil {
+		return m.showToast(fmt.Sprintf("Adding the tools failed: %v", err))
+	}
+	names := discoveredNames(m.discovered)
+	for _, d := range m.discovered {
+		t := d.Tool()
+		t.SetInstalled(true) // Found in PATH
+		m.registry.Put(t)
+		m.tools = append(m.tools, t)
+	}
+	m.discovered = nil
+	return m.showToast(fmt.Sprintf("Added %s", names))
+}
+
+// discoveredNames lists the names of the agents, e.g. "goose, qodo and amp".
+func discoveredNames(defs []catalog.Definition) string {
+	names := make([]string, len(defs))
+	for i, d := range defs {
+		names[i] = d.Name
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// editableFile is a file the launcher reads that can be opened in the user's editor.
+type editableFile struct {
+	label string
+	path  string
+	theme bool // A theme file rather than the settings
+}
+
+// editableFiles returns the files the edit menu offers: the settings, with the custom
+// tools, and the theme file in use (theme.yaml unless the theme setting is a path).
+func (m Model) editableFiles() []editableFile {
+	themePath := config.ThemeFilePath()
+	if _, builtin := builtinThemes()[m.settings.Theme]; !builtin && m.settings.Theme != "" {
+		themePath = m.settings.Theme
+	}
+	return []editableFile{
+		{label: "Settings and custom tools", path: config.SettingsFilePath()},
+		{label: "Theme", path: themePath, theme: true},
+	}
+}
+
+// editedMsg is sent when the editor opened from the menu exits
+type editedMsg struct {
+	file editableFile
+	err  error
+}
+
+// editFile suspends the TUI and opens file in $VISUAL or $EDITOR.
+func (m Model) editFile(file editableFile) (tea.Model, tea.Cmd) {
+	cmd, err := config.EditorCommand(file.path)
+	if err != nil {
+		return m, m.showToast(err.Error())
+	}
+	m.suspended = true
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editedMsg{file: file, err: err}
+	})
+}
+
+// applyEdit checks the file the editor left and loads it in place of what the launcher
+// was started with; a file that doesn't load is reported and the old one kept.
+func (m Model) applyEdit(msg editedMsg) (Model, tea.Cmd) {
+	m.suspended = false
+	m.lastInput = time.Now()
+	name := filepath.Base(msg.file.path)
+	if msg.err != nil {
+		return m, m.showToast(fmt.Sprintf("The editor failed: %v", msg.err))
+	}
+
+	if msg.file.theme {
+		theme, err := LoadTheme(msg.file.path, msg.file.path)
+		if err != nil {
+			return m, m.showToast(fmt.Sprintf("%v; keeping the current theme", err))
+		}
+		applyTheme(theme)
+		WithTheme(theme)(&m)
+		return m, m.showToast("Reloaded " + name)
+	}
+
+	if err := config.CheckSettingsFile(); err != nil {
+		return m, m.showToast(fmt.Sprintf("%v; keeping the current settings", err))
+	}
+	cmd, err := m.reload(config.LoadSettings())
+	toast := "Reloaded " + name
+	if err != nil {
+		toast = fmt.Sprintf("Reloaded %s, but %v; keeping the current theme", name, err)
+	}
+	return m, tea.Batch(cmd, m.showToast(toast))
+}
+
+// reload switches to settings, rebuilding the tool list from them when the launcher was
+// given a way to (WithReload). Tools keep what was already found out about them. A
+// changed theme or color setting is applied too; the error is about a theme that
+// doesn't load, and the rest of the settings are applied anyway.
+func (m *Model) reload(settings config.Settings) (tea.Cmd, error) {
+	if m.selectOnly {
+		settings.ReturnToMenu = false
+	}
+	prev := m.settings
+	m.settings = settings
+	m.collapsedGroups = collapsedGroups(settings.Groups)
+	timefmt.Set(settings.DateTimeFormat())
+	tool.SetMirrors(settings.Mirrors.Mirrors())
+
+	// Only when changed, so an unchanged setting leaves a --theme given on the command line
+	var themeErr error
+	if settings.Theme != prev.Theme {
+		if theme, err := LoadTheme(settings.Theme, config.ThemeFilePath()); err != nil {
+			themeErr = err
+		} else {
+			WithTheme(theme)(m)
+		}
+	}
+	if settings.Theme != prev.Theme || settings.Color != prev.Color {
+		m.useColors(m.output())
+	}
+	if m.reloadTools == nil {
+		return nil, themeErr
+	}
+
+	var selected string
+	if tools := m.visibleTools(); m.cursor < len(tools) {
+		selected = tools[m.cursor].Name
+	}
+	old := m.tools
+	m.registry = m.reloadTools(settings)
+	m.tools = m.registry.List()
+	if m.filter != nil {
+		m.tools = keepTools(m.tools, m.filter)
+	}
+	var unknown []*tool.Tool // Tools that weren't in the list before
+	for _, t := range m.tools {
+		prev := findIn(old, t.Name)
+		if prev == nil {
+			unknown = append(unknown, t)
+			continue
+		}
+		t.SetInstalled(prev.IsInstalled())
+		t.Version, t.Latest, t.Auth = prev.Version, prev.Latest, prev.Auth
+		if t.Balance == nil {
+			t.Balance = prev.Balance
+		}
+	}
+	seedBalances(m.tools, settings)
+	m.cursor = 0
+	m.focusTool(selected)
+
+	cmds := []tea.Cmd{revalidateTools(unknown), checkAuth(unknown)}
+	if settings.ShowBalances {
+		cmds = append(cmds, fetchBalances(unknown, settings.BurnAlerts))
+	}
+	return tea.Batch(cmds...), themeErr
+}
+
+// findIn returns the tool called name in tools, or nil.
+func findIn(tools []*tool.Tool, name string) *tool.Tool {
+	for _, t := range tools {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// seedBalances sets the last fetched balance on the tools that have none yet, or clears
+// every balance when balances are off.
+func seedBalances(tools []*tool.Tool, settings config.Settings) {
+	for _, t := range tools {
+		if !settings.ShowBalances {
+			t.Balance = nil // Not even the snapshot's: nothing about balances is drawn
+			continue
+		}
+		if t.Balance == nil && provider.SupportsBalance(t) {
+			t.Balance = provider.CachedBalance(t)
+		}
+	}
+}
+
+// updateEditMenu handles keys while the edit menu is open.
+func (m Model) updateEditMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	files := m.editableFiles()
+	switch msg.String() {
+	case "up", "k":
+		if m.editCursor > 0 {
+			m.editCursor--
+		}
+	case "down", "j":
+		if m.editCursor < len(files)-1 {
+			m.editCursor++
+		}
+	case "esc", "q", "e":
+		m.choosingEdit = false
+	case "enter":
+		m.choosingEdit = false
+		return m.editFile(files[m.editCursor])
+	}
+	return m, nil
+}
+
+// renderEditMenu renders the files the edit menu offers, with the given one selected.
+func renderEditMenu(files []editableFile, cursor int) string {
+	var b strings.Builder
+	b.WriteString(submenuSelectedStyle.Render("Edit in $VISUAL or $EDITOR"))
+	b.WriteString("\n\n")
+	for i, f := range files {
+		path := descStyle.UnsetPaddingLeft().Render(f.path)
+		if i == cursor {
+			b.WriteString(fmt.Sprintf("  %s %s  %s\n", submenuSelectedStyle.Render("»"), submenuSelectedStyle.Render(f.label), path))
+		} else {
+			b.WriteString(fmt.Sprintf("    %s  %s\n", submenuStyle.Render(f.label), path))
+		}
+	}
+	return b.String()
+}
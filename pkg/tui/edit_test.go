@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// keepColors restores the color profile and theme the tests change, both package-level.
+func keepColors(t *testing.T) {
+	profile, theme := lipgloss.ColorProfile(), activeTheme
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(profile)
+		applyTheme(theme)
+	})
+}
+
+// writeFile writes data to path, creating its directory.
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyEdit(t *testing.T) {
+	dracula := builtinThemes()[ThemeDracula]
+	settingsFile := editableFile{label: "Settings", path: "config.json"}
+	tests := []struct {
+		name      string
+		settings  string // Left in config.json by the editor
+		themeFile string // Left in the theme file by the editor
+		msg       editedMsg
+		wantToast string
+		check     func(t *testing.T, m Model)
+	}{
+		{
+			name:      "editor failed",
+			settings:  `{"theme": "dracula"}`,
+			msg:       editedMsg{file: settingsFile, err: errors.New("exit status 1")},
+			wantToast: "The editor failed",
+			check: func(t *testing.T, m Model) {
+				if m.settings.Theme != "" {
+					t.Errorf("theme setting = %q, want the file left unread", m.settings.Theme)
+				}
+			},
+		},
+		{
+			name:      "invalid settings",
+			settings:  `{"clear_screen": "maybe"}`,
+			msg:       editedMsg{file: settingsFile},
+			wantToast: "keeping the current settings",
+		},
+		{
+			name:      "theme changed",
+			settings:  `{"theme": "dracula", "density": "compact"}`,
+			msg:       editedMsg{file: settingsFile},
+			wantToast: "Reloaded config.json",
+			check: func(t *testing.T, m Model) {
+				if m.settings.Density != config.DensityCompact {
+					t.Errorf("density = %q, want the edited one", m.settings.Density)
+				}
+				if m.theme.Accent != dracula.Accent || activeTheme.Accent != dracula.Accent {
+					t.Errorf("accent = %q, drawn with %q, want dracula's %q", m.theme.Accent, activeTheme.Accent, dracula.Accent)
+				}
+			},
+		},
+		{
+			name:      "unknown theme",
+			settings:  `{"theme": "nope", "density": "compact"}`,
+			msg:       editedMsg{file: settingsFile},
+			wantToast: `unknown theme "nope"`,
+			check: func(t *testing.T, m Model) {
+				if m.settings.Density != config.DensityCompact || m.theme.Accent != DefaultTheme().Accent {
+					t.Errorf("density %q, accent %q, want the settings applied and the theme kept", m.settings.Density, m.theme.Accent)
+				}
+			},
+		},
+		{
+			name:      "color off",
+			settings:  `{"color": "none"}`,
+			msg:       editedMsg{file: settingsFile},
+			wantToast: "Reloaded",
+			check: func(t *testing.T, m Model) {
+				if lipgloss.ColorProfile() != termenv.Ascii {
+					t.Errorf("color profile = %v, want none", lipgloss.ColorProfile())
+				}
+				if strings.Contains(m.title, "\x1b[") {
+					t.Error("the banner still has colors")
+				}
+			},
+		},
+		{
+			name:      "theme file",
+			themeFile: "base: dracula\naccent: \"#123456\"\n",
+			msg:       editedMsg{file: editableFile{label: "Theme", path: "theme.yaml", theme: true}},
+			wantToast: "Reloaded theme.yaml",
+			check: func(t *testing.T, m Model) {
+				if m.theme.Accent != "#123456" || activeTheme.Accent != "#123456" {
+					t.Errorf("accent = %q, drawn with %q, want the edited #123456", m.theme.Accent, activeTheme.Accent)
+				}
+			},
+		},
+		{
+			name:      "broken theme file",
+			themeFile: "accent: blue\n",
+			msg:       editedMsg{file: editableFile{label: "Theme", path: "theme.yaml", theme: true}},
+			wantToast: "keeping the current theme",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepColors(t)
+			lipgloss.SetColorProfile(termenv.TrueColor)
+			m := testModel(t)
+			m.out = io.Discard
+			if tt.settings != "" {
+				writeFile(t, config.SettingsFilePath(), tt.settings)
+			}
+			if tt.themeFile != "" {
+				writeFile(t, config.ThemeFilePath(), tt.themeFile)
+			}
+			msg := tt.msg
+			if msg.file.theme {
+				msg.file.path = config.ThemeFilePath()
+			} else {
+				msg.file.path = config.SettingsFilePath()
+			}
+
+			m.suspended = true
+			m, _ = m.applyEdit(msg)
+			if m.suspended {
+				t.Error("the launcher is still suspended after the editor exited")
+			}
+			if !strings.Contains(m.toast, tt.wantToast) {
+				t.Errorf("toast = %q, want it to mention %q", m.toast, tt.wantToast)
+			}
+			if tt.check != nil {
+				tt.check(t, m)
+			}
+		})
+	}
+}
+
+func TestReload_Tools(t *testing.T) {
+	keepColors(t)
+	m := testModel(t, installedTool("a"), installedTool("b"))
+	m.tools[1].Version = "1.2.3"
+	m.focusTool("b")
+	WithReload(func(config.Settings) *tool.Registry {
+		registry := tool.NewRegistry()
+		registry.Register(&tool.Tool{Name: "a"})
+		registry.Register(&tool.Tool{Name: "b"})
+		registry.Register(&tool.Tool{Name: "c"}) // Added to the settings by the edit
+		return registry
+	})(&m)
+
+	if _, err := m.reload(config.DefaultSettings()); err != nil {
+		t.Fatalf("reload() error: %v", err)
+	}
+	b := m.findTool("b")
+	if b == nil || !b.IsInstalled() || b.Version != "1.2.3" {
+		t.Fatalf("b = %+v after the reload, want it to keep what was found out about it", b)
+	}
+	if m.findTool("c") == nil {
+		t.Error("the added tool c isn't listed after the reload")
+	}
+	if tools := m.visibleTools(); m.cursor >= len(tools) || tools[m.cursor].Name != "b" {
+		t.Errorf("cursor = %d, want it to stay on b", m.cursor)
+	}
+}
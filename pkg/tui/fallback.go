@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// canStartTUI reports whether the terminal looks capable of running the full-screen TUI.
+func canStartTUI() bool {
+	return os.Getenv("TERM") != "dumb"
+}
+
+// isStartupError reports whether a Bubble Tea error happened while initializing the
+// terminal (as opposed to the program being killed or interrupted once running).
+func isStartupError(err error) bool {
+	return !errors.Is(err, tea.ErrProgramKilled) &&
+		!errors.Is(err, tea.ErrInterrupted) &&
+		!errors.Is(err, tea.ErrProgramPanic)
+}
+
+// RunTextMenu shows a plain numbered menu on in/out and returns the selected tool name.
+// It is used when the full-screen TUI cannot start (no TTY, TERM=dumb), e.g. under docker exec.
+// Uninstalled tools can still be installed from the menu.
+func RunTextMenu(registry *tool.Registry, in io.Reader, out io.Writer, opts ...Option) (string, error) {
+	model := NewModel(registry)
+	for _, opt := range opts {
+		opt(&model)
+	}
+	return runTextMenu(model, in, out)
+}
+
+func runTextMenu(m Model, in io.Reader, out io.Writer) (string, error) {
+	reader := bufio.NewReader(in)
+
+	// No background re-validation here, so don't trust snapshot state
+	for _, t := range m.tools {
+		t.RefreshInstalled()
+	}
+
+	for {
+		// Re-sort every round so freshly installed tools move into the installed group
+		tools := m.getSortedTools()
+		fmt.Fprintln(out, "Amazing CLI - select a tool:")
+		for i, t := range tools {
+			status := "installed"
+			if !t.IsInstalled() {
+				status = "not installed"
+				if !t.SupportsPlatform() {
+					status = "not available (" + t.PlatformNote() + ")"
+				}
+			}
+			fmt.Fprintf(out, "  %d) %-16s %s\n", i+1, t.DisplayName, status)
+		}
+		fmt.Fprintf(out, "Enter 1-%d (q to quit): ", len(tools))
+
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if err != nil && answer == "" {
+			if err == io.EOF {
+				fmt.Fprintln(out)
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to read selection: %w", err)
+		}
+		if answer == "q" || answer == "quit" {
+			return "", nil
+		}
+
+		n, convErr := strconv.Atoi(answer)
+		if convErr != nil || n < 1 || n > len(tools) {
+			fmt.Fprintf(out, "Invalid selection: %q\n\n", answer)
+			continue
+		}
+
+		selected := tools[n-1]
+		if selected.IsInstalled() {
+			selected.LastUsed = time.Now()
+			return selected.Name, nil
+		}
+
+		if selected.CanLaunchOnWindows() {
+			fmt.Fprintf(out, "%s is only installed on the Windows side. Launch it through cmd.exe? [y/N]: ", selected.DisplayName)
+			confirm, _ := reader.ReadString('\n')
+			if c := strings.ToLower(strings.TrimSpace(confirm)); c == "y" || c == "yes" {
+				selected.UseWindows()
+				selected.LastUsed = time.Now()
+				return selected.Name, nil
+			}
+		}
+
+		if !selected.HasInstallCommand() {
+			fmt.Fprintf(out, "%s is not installed and automated installation is not available.\n", selected.DisplayName)
+			if selected.InstallURL != "" {
+				fmt.Fprintf(out, "Please visit: %s\n", selected.InstallURL)
+			}
+			fmt.Fprintln(out)
+			continue
+		}
+
+		for _, warning := range selected.PreInstallWarnings() {
+			fmt.Fprintf(out, "Warning: %s\n", warning)
+		}
+		fmt.Fprintln(out, "This runs:")
+		for _, step := range selected.InstallPlan() {
+			fmt.Fprintf(out, "  %s\n", step)
+		}
+		fmt.Fprintf(out, "%s is not installed. Install it now? [y/N]: ", selected.DisplayName)
+		confirm, _ := reader.ReadString('\n')
+		if c := strings.ToLower(strings.TrimSpace(confirm)); c != "y" && c != "yes" {
+			fmt.Fprintln(out)
+			continue
+		}
+
+		fmt.Fprintln(out, "Installing...")
+		err = selected.InstallWithOutput(out)
+		for _, next := range selected.InstallAlternatives() {
+			if err == nil {
+				break
+			}
+			fmt.Fprintf(out, "✗ Installation failed: %v\n", err)
+			fmt.Fprintf(out, "Retry with %s? [y/N]: ", next)
+			retry, _ := reader.ReadString('\n')
+			if r := strings.ToLower(strings.TrimSpace(retry)); r != "y" && r != "yes" {
+				break
+			}
+			err = selected.InstallWith(next, out)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "✗ Installation failed: %v\n\n", err)
+			continue
+		}
+		fmt.Fprintf(out, "✓ Installed %s\n\n", selected.DisplayName)
+	}
+}
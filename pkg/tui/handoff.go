@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// handoffWindow is how soon after a session ends launching another tool counts as
+// switching tools mid-task.
+const handoffWindow = 15 * time.Minute
+
+// finishedSession is a session of a tool launched from the menu that has ended.
+type finishedSession struct {
+	name       string
+	dir        string
+	start, end time.Time
+}
+
+// handoffOffer asks how to start the next tool with what the last one left.
+type handoffOffer struct {
+	to      *tool.Tool
+	handoff tool.Handoff
+}
+
+// handoffAction is an entry in the handoff dialog.
+type handoffAction struct {
+	label  string
+	prompt bool // Start the tool with the handoff as its first prompt
+	copy   bool // Copy the handoff to the clipboard before starting
+}
+
+// handoffActions returns the ways t can be started with a handoff.
+func handoffActions(t *tool.Tool) []handoffAction {
+	var actions []handoffAction
+	if len(t.PromptArgs) > 0 {
+		actions = append(actions, handoffAction{label: "Start " + t.DisplayName + " with it as the first prompt", prompt: true})
+	}
+	return append(actions,
+		handoffAction{label: "Copy it and start " + t.DisplayName, copy: true},
+		handoffAction{label: "Start " + t.DisplayName + " without it"},
+	)
+}
+
+// handoffGatheredMsg carries what the last session left, for launching to
+type handoffGatheredMsg struct {
+	to      *tool.Tool
+	handoff tool.Handoff
+}
+
+// gatherHandoff returns a command gathering what the last session left to hand off to t
+// (its git diff --stat and transcript) in the background, or nil when t isn't another
+// tool launched right after it.
+func (m Model) gatherHandoff(t *tool.Tool) tea.Cmd {
+	last := m.lastSession
+	if last == nil || last.name == t.Name || time.Since(last.end) > handoffWindow {
+		return nil
+	}
+	from := m.findTool(last.name)
+	if from == nil {
+		return nil
+	}
+	dir, start := last.dir, last.start
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		return handoffGatheredMsg{to: t, handoff: tool.NewHandoff(ctx, from, dir, start)}
+	}
+}
+
+// offerHandoff opens the handoff dialog with what the last session left, or launches
+// the tool right away when it left nothing to hand off.
+func (m Model) offerHandoff(msg handoffGatheredMsg) (tea.Model, tea.Cmd) {
+	m.gatheringHandoff = false
+	if msg.handoff.Empty() {
+		return m.launchWith(msg.to, nil)
+	}
+	m.handoff, m.handoffCursor = &handoffOffer{to: msg.to, handoff: msg.handoff}, 0
+	return m, nil
+}
+
+// updateHandoff handles keys while the handoff dialog is open.
+func (m Model) updateHandoff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	offer := m.handoff
+	actions := handoffActions(offer.to)
+
+	switch msg.String() {
+	case "up", "k":
+		if m.handoffCursor > 0 {
+			m.handoffCursor--
+		}
+	case "down", "j":
+		if m.handoffCursor < len(actions)-1 {
+			m.handoffCursor++
+		}
+	case "esc", "q":
+		m.handoff = nil
+	case "enter":
+		action := actions[m.handoffCursor]
+		m.handoff = nil
+		prompt := offer.handoff.Prompt()
+		var extra []string
+		if action.prompt {
+			extra = offer.to.PromptLaunchArgs(prompt)
+		}
+		next, cmd := m.launchWith(offer.to, extra)
+		if action.copy {
+			// Copied before the tool takes over the terminal
+			cmd = tea.Sequence(copyToClipboard(m.output(), prompt, ""), cmd)
+		}
+		return next, cmd
+	}
+	return m, nil
+}
+
+// renderHandoff renders the handoff dialog with the given action selected, with lines
+// cut at the terminal width (0 if unknown).
+func renderHandoff(offer handoffOffer, cursor, width int) string {
+	var b strings.Builder
+	b.WriteString(submenuSelectedStyle.Render(fmt.Sprintf("Hand off from %s?", offer.handoff.From)))
+	b.WriteString("\n\n")
+	for _, line := range strings.Split(offer.handoff.Prompt(), "\n") {
+		b.WriteString(descStyle.Render(truncateLine(line, width-4)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	for i, action := range handoffActions(offer.to) {
+		if i == cursor {
+			b.WriteString(fmt.Sprintf("  %s %s\n", submenuSelectedStyle.Render("»"), submenuSelectedStyle.Render(action.label)))
+		} else {
+			b.WriteString(fmt.Sprintf("    %s\n", submenuStyle.Render(action.label)))
+		}
+	}
+	return b.String()
+}
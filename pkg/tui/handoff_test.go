@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestLaunch_Handoff(t *testing.T) {
+	tests := []struct {
+		name         string
+		from         string // Tool of the last session
+		ended        time.Duration
+		instructions bool // The session's directory has an AGENTS.md
+		wantGather   bool
+		wantDialog   bool
+	}{
+		{"left instructions", "a", time.Minute, true, true, true},
+		{"left nothing", "a", time.Minute, false, true, false},
+		{"same tool", "b", time.Minute, true, false, false},
+		{"too long ago", "a", time.Hour, true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := installedTool("a"), installedTool("b")
+			a.Command, b.Command = "true", "true"
+			m := testModel(t, a, b)
+			m.settings.ReturnToMenu = true
+			dir := t.TempDir() // Not a git repository: no diff to hand off
+			if tt.instructions {
+				if err := os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("# Agents\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			m.lastSession = &finishedSession{name: tt.from, dir: dir, start: time.Now().Add(-tt.ended - time.Hour), end: time.Now().Add(-tt.ended)}
+			m.focusTool("b")
+
+			next, cmd := m.launch(m.findTool("b"))
+			m = next.(Model)
+			if !tt.wantGather {
+				if !m.suspended || m.handoff != nil {
+					t.Errorf("suspended = %v, handoff = %v, want b launched right away", m.suspended, m.handoff)
+				}
+				return
+			}
+			if cmd == nil || m.suspended || !m.gatheringHandoff {
+				t.Fatalf("suspended = %v, gathering = %v, want the handoff gathered before anything runs", m.suspended, m.gatheringHandoff)
+			}
+			if again, _ := m.launch(m.findTool("b")); again.(Model).suspended {
+				t.Error("a second launch while gathering ran the tool")
+			}
+
+			msg, ok := cmd().(handoffGatheredMsg)
+			if !ok {
+				t.Fatalf("the command sent %T, want handoffGatheredMsg", msg)
+			}
+			next, _ = m.Update(msg)
+			m = next.(Model)
+			if m.gatheringHandoff {
+				t.Error("still gathering after handoffGatheredMsg")
+			}
+			if got := m.handoff != nil; got != tt.wantDialog {
+				t.Fatalf("handoff dialog open = %v, want %v", got, tt.wantDialog)
+			}
+			if tt.wantDialog {
+				if m.suspended || m.handoff.to.Name != "b" || m.handoff.handoff.Instructions == "" {
+					t.Errorf("handoff = %+v, suspended = %v, want b offered the AGENTS.md", m.handoff, m.suspended)
+				}
+			} else if !m.suspended {
+				t.Error("b isn't launched once nothing was found to hand off")
+			}
+		})
+	}
+}
+
+func TestHandoffActions(t *testing.T) {
+	withPrompt := &tool.Tool{DisplayName: "B", PromptArgs: []string{"-p"}}
+	if got := handoffActions(withPrompt); len(got) != 3 || !got[0].prompt {
+		t.Errorf("handoffActions() = %+v, want starting with the prompt first", got)
+	}
+	if got := handoffActions(&tool.Tool{DisplayName: "B"}); len(got) != 2 || got[0].prompt || !got[0].copy {
+		t.Errorf("handoffActions() = %+v without PromptArgs, want copy and start", got)
+	}
+}
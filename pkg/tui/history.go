@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// openHistory shows the launch history, newest first, in a pager searchable with /.
+func (m Model) openHistory() Model {
+	m.showHistory = true
+	m.historyPager = newPager(m.historyPaneLines())
+	m.historyPager.follow = false // The newest launches are at the top
+	m.historyPager.setLines(historyLines(config.LoadLaunches()))
+	return m
+}
+
+// historyLines returns a line per launch, newest first, in the columns of amazing history.
+func historyLines(launches []config.Launch) []string {
+	if len(launches) == 0 {
+		return []string{"No launches yet"}
+	}
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, l := range slices.Backward(launches) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", timefmt.DateTime(l.At), l.Tool, tool.ShortenHome(l.Dir), l.Note)
+	}
+	tw.Flush()
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ") // Launches without a note
+	}
+	return lines
+}
+
+// historyPaneLines returns how many launches the history view shows at once: what the
+// terminal has left besides the compact header, the view's title and status line and
+// the footer.
+func (m Model) historyPaneLines() int {
+	if m.terminalHeight <= 0 {
+		return installPaneHeight
+	}
+	m.showHistory = true // The footer with the history's help
+	footer := lineCount(m.renderFooter(m.visibleTools()))
+	return max(3, m.terminalHeight-lineCount(m.renderHeader(true))-footer-2)
+}
+
+// updateHistory handles keys while the history view is open: the pager scrolls and
+// searches, and H, q or esc go back to the tools.
+func (m Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.historyPager.update(msg.String()) {
+		return m, nil
+	}
+	switch msg.String() {
+	case "H", "q", "esc":
+		m.showHistory = false
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderHistory renders the history view's window of launches.
+func (m Model) renderHistory() string {
+	width := m.terminalWidth - 1
+	if width < 20 {
+		width = 100
+	}
+	title := groupHeaderStyle.Render("── Launch history " + strings.Repeat("─", groupHeaderWidth-18))
+	return title + "\n" + m.historyPager.view(width, normalStyle) + "\n"
+}
@@ -0,0 +1,21 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleExpired reports whether nothing was pressed for the idle timeout by now. The
+// launcher doesn't count as idle while a tool it launched runs or one installs.
+func (m Model) idleExpired(now time.Time) bool {
+	timeout := m.settings.Idle.Timeout()
+	return timeout > 0 && !m.suspended && !m.installing && now.Sub(m.lastInput) >= timeout
+}
+
+// quitIdle quits the launcher after the idle timeout, leaving nothing selected.
+func (m Model) quitIdle() (tea.Model, tea.Cmd) {
+	m.quitting = true
+	m.idleQuit = true
+	return m, tea.Quit
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestIdle_NotWhileSuspended(t *testing.T) {
+	withLogin := installedTool("a")
+	withLogin.Command, withLogin.LoginArgs = "true", []string{"login"}
+	launches := []struct {
+		name string
+		run  func(m Model) Model
+	}{
+		{"launch", func(m Model) Model {
+			m.settings.ReturnToMenu = true
+			return press(m, "enter")
+		}},
+		{"retry after a failure", func(m Model) Model {
+			m.postMortem = &PostMortem{Tool: "a", ExitCode: 1}
+			return press(m, "enter")
+		}},
+		{"login", func(m Model) Model {
+			return press(m, "L")
+		}},
+	}
+	for _, tt := range launches {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel(t, withLogin)
+			m.settings.Idle.TimeoutMinutes = 1
+			m = tt.run(m)
+			if !m.suspended {
+				t.Fatal("the launcher isn't suspended while the tool runs")
+			}
+
+			// A tick queued while the tool ran, long after the last key
+			later := time.Now().Add(time.Hour)
+			next, _ := m.Update(clockTickMsg(later))
+			m = next.(Model)
+			if m.quitting {
+				t.Fatal("the launcher quit as idle while the tool ran")
+			}
+
+			next, _ = m.Update(toolExitedMsg{name: "a", session: true, elapsed: time.Hour})
+			m = next.(Model)
+			if m.suspended {
+				t.Error("the launcher is still suspended after the tool exited")
+			}
+			if m.idleExpired(time.Now()) {
+				t.Error("the idle timeout counts the session, want it to start again when the tool exits")
+			}
+		})
+	}
+}
+
+func TestIdleExpired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		timeout int
+		idle    time.Duration
+		change  func(m *Model)
+		want    bool
+	}{
+		{"timed out", 10, 11 * time.Minute, nil, true},
+		{"recent input", 10, 9 * time.Minute, nil, false},
+		{"no timeout", 0, 24 * time.Hour, nil, false},
+		{"installing", 10, time.Hour, func(m *Model) { m.installing = true }, false},
+		{"suspended", 10, time.Hour, func(m *Model) { m.suspended = true }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel(t, &tool.Tool{Name: "a"})
+			m.settings.Idle.TimeoutMinutes = tt.timeout
+			m.lastInput = now.Add(-tt.idle)
+			if tt.change != nil {
+				tt.change(&m)
+			}
+			if got := m.idleExpired(now); got != tt.want {
+				t.Errorf("idleExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+const (
+	installLogLines    = 500                    // Output lines kept for scrolling back
+	installPaneHeight  = 10                     // Output lines shown at once, fewer on short terminals
+	installLogInterval = 100 * time.Millisecond // How often the pane picks up new output
+)
+
+// installLogStyle renders the install output pane
+var installLogStyle = lipgloss.NewStyle().
+	Border(lipgloss.NormalBorder(), false, false, false, true).
+	BorderForeground(gridLine).
+	Foreground(mutedText).
+	PaddingLeft(1).
+	MarginLeft(2)
+
+// installProgressWidth is the width of the install progress bar
+const installProgressWidth = 30
+
+// renderInstallProgress draws how far the running install command is, as its output tells
+// it, or returns "" when the output doesn't say and the spinner is shown instead.
+func renderInstallProgress(lines []string) string {
+	done, ok := tool.InstallProgress(lines)
+	if !ok {
+		return ""
+	}
+	filled := int(done * installProgressWidth)
+	bar := lipgloss.NewStyle().Foreground(activeTheme.Success).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(activeTheme.Subtle).Render(strings.Repeat("░", installProgressWidth-filled))
+	return fmt.Sprintf("%s %d%%", bar, int(done*100))
+}
+
+// installLogTickMsg asks the model to copy new install output into the pane
+type installLogTickMsg struct{}
+
+func installLogTick() tea.Cmd {
+	return tea.Tick(installLogInterval, func(time.Time) tea.Msg {
+		return installLogTickMsg{}
+	})
+}
+
+// startInstall installs t in the background, streaming its output into the install pane.
+// The package managers to retry with if it fails are kept for the error dialog.
+func (m *Model) startInstall(t *tool.Tool) tea.Cmd {
+	m.installRetries = t.InstallAlternatives()
+	return m.runInstall(t, noVersion(t.InstallWithOutput), false)
+}
+
+// noVersion adapts an install that doesn't detect the version to an installFunc.
+func noVersion(install func(io.Writer) error) installFunc {
+	return func(out io.Writer) (string, error) {
+		return "", install(out)
+	}
+}
+
+// retryInstall installs the tool of the failed install with the next package manager.
+func (m *Model) retryInstall(t *tool.Tool) tea.Cmd {
+	next := m.installRetries[0]
+	m.installRetries = m.installRetries[1:]
+	m.installError = ""
+	return m.runInstall(t, noVersion(func(out io.Writer) error { return t.InstallWith(next, out) }), false)
+}
+
+// canRetryInstall reports whether the failed install can be retried with another package manager.
+func (m Model) canRetryInstall() bool {
+	return m.installError != "" && !m.upgrading && len(m.installRetries) > 0
+}
+
+// runInstall runs install (an install or an upgrade of t) in the background with the install pane.
+func (m *Model) runInstall(t *tool.Tool, install installFunc, upgrading bool) tea.Cmd {
+	m.installing = true
+	m.upgrading = upgrading
+	m.showInstallPrompt = false
+	m.installLog = tool.NewTailBuffer(installLogLines)
+	m.installPager = newPager(m.installPaneLines())
+	return tea.Batch(performInstall(t.Name, install, m.installLog), m.spinner.Tick, installLogTick())
+}
+
+// refreshInstalled brings the list up to date with a tool that was just installed: it is
+// checked for again, which moves it into the installed group with the cursor kept on it,
+// and its version and balance are fetched.
+func (m *Model) refreshInstalled(name string) tea.Cmd {
+	t := m.findTool(name)
+	if t == nil || !t.RefreshInstalled() {
+		return nil
+	}
+	m.focusTool(name)
+	cmds := []tea.Cmd{revalidateTools([]*tool.Tool{t}), checkAuth([]*tool.Tool{t})}
+	if m.settings.ShowBalances && provider.SupportsBalance(t) {
+		delete(m.balancesDone, name) // Shows it loading until the fetch is back
+		cmds = append(cmds, fetchBalance(t, nil, m.settings.BurnAlerts), m.spinner.Tick)
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateInstallLog scrolls or searches the install pane. It reports whether the key was handled.
+func (m *Model) updateInstallLog(key string) bool {
+	if len(m.installPager.lines) == 0 {
+		return false
+	}
+	return m.installPager.update(key)
+}
+
+// renderInstallLog renders the visible window of the install output.
+func (m Model) renderInstallLog() string {
+	if len(m.installPager.lines) == 0 {
+		return ""
+	}
+	width := m.terminalWidth - 8
+	if width < 20 {
+		width = 72
+	}
+	return m.installPager.view(width, installLogStyle)
+}
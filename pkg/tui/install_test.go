@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestUpdate_InstallComplete(t *testing.T) {
+	tests := []struct {
+		name        string
+		msg         installCompleteMsg
+		wantVersion string
+		wantError   bool
+	}{
+		{"upgrade", installCompleteMsg{name: "a", version: "a 2.0.0", success: true}, "a 2.0.0", false},
+		{"install", installCompleteMsg{name: "a", success: true}, "a 1.0.0", false},
+		{"failed upgrade", installCompleteMsg{name: "a", err: errTest}, "a 1.0.0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := installedTool("a")
+			a.Version = "a 1.0.0"
+			m := testModel(t, a)
+			m.installing, m.upgrading = true, true
+			m.installLog = tool.NewTailBuffer(installLogLines)
+
+			next, _ := m.Update(tt.msg)
+			m = next.(Model)
+			if a.Version != tt.wantVersion {
+				t.Errorf("Version = %q, want %q", a.Version, tt.wantVersion)
+			}
+			if m.installing || (m.installError != "") != tt.wantError {
+				t.Errorf("installing = %v, installError = %q, want the install over (error %v)", m.installing, m.installError, tt.wantError)
+			}
+		})
+	}
+}
+
+// installingModel returns a launcher on a 100x30 terminal running an install that has
+// printed lines 1 to n so far.
+func installingModel(t *testing.T, n int) Model {
+	t.Helper()
+	a := &tool.Tool{Name: "a", DisplayName: "a", Command: "a"}
+	m := sized(testModel(t, a), 100, 30)
+	m.runInstall(a, noVersion(func(io.Writer) error { return nil }), false)
+	return logged(m, 1, n)
+}
+
+// logged writes lines from to to of install output and lets the pane pick them up.
+func logged(m Model, from, to int) Model {
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(m.installLog, "line %d\n", i)
+	}
+	next, _ := m.Update(installLogTickMsg{})
+	return next.(Model)
+}
+
+func TestInstallLog_Scroll(t *testing.T) {
+	// The pane shows 10 of the 30 lines on a 30 line terminal
+	tests := []struct {
+		name       string
+		keys       []string
+		more       int // Lines printed after the keys
+		wantTop    int
+		wantFollow bool
+	}{
+		{"follows the output", nil, 5, 25, true},
+		{"scrolled up stays put", []string{"k", "k"}, 5, 18, false},
+		{"scrolling back to the end follows again", []string{"k", "j"}, 5, 25, true},
+		{"start", []string{"g"}, 5, 0, false},
+		{"scroll up clamps at the start", []string{"g", "k", "up"}, 0, 0, false},
+		{"page down", []string{"g", "pgdown", "f"}, 0, 20, true},
+		{"page up", []string{"pgup"}, 5, 10, false},
+		{"end follows again", []string{"g", "G"}, 5, 25, true},
+		{"scroll down clamps at the end", []string{"j", "down"}, 0, 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := logged(press(installingModel(t, 30), tt.keys...), 31, 30+tt.more)
+			p := m.installPager
+			if p.height != 10 {
+				t.Fatalf("pane shows %d lines, want 10", p.height)
+			}
+			if p.top != tt.wantTop || p.follow != tt.wantFollow {
+				t.Errorf("top %d, follow %v, want %d, %v", p.top, p.follow, tt.wantTop, tt.wantFollow)
+			}
+			view := m.renderInstallLog()
+			if first, _, _ := strings.Cut(view, "\n"); !strings.HasSuffix(strings.TrimSpace(first), fmt.Sprintf(" line %d", tt.wantTop+1)) {
+				t.Errorf("pane doesn't start at line %d:\n%s", tt.wantTop+1, view)
+			}
+		})
+	}
+}
+
+func TestInstallLog_ScrollAfterFailure(t *testing.T) {
+	m := installingModel(t, 30)
+	next, _ := m.Update(installCompleteMsg{name: "a", err: errTest})
+	m = press(next.(Model), "g", "j", "/", "1", "5", "enter")
+	if p := m.installPager; p.top != 14 || p.match != 14 {
+		t.Errorf("top %d, match %d, want the failed output scrolled to line 15", p.top, p.match)
+	}
+	if m = press(m, "esc"); m.installError == "" {
+		t.Fatal("esc with a search closed the error, want it to clear the search")
+	}
+	if m = press(m, "esc"); m.installError != "" {
+		t.Error("esc didn't close the error")
+	}
+}
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// toolExitedMsg is sent when a tool launched from the menu exits
+type toolExitedMsg struct {
+	name    string
+	err     error
+	elapsed time.Duration
+	stderr  []string  // Tail of the tool's stderr
+	session bool      // A session of the tool rather than its login
+	dir     string    // Where it ran
+	start   time.Time // When it started
+}
+
+// launchTool suspends the TUI, runs the tool, and resumes the menu when it exits.
+// The launch is recorded in the history with note.
+// With altScreen the tool runs in the alternate screen, so neither program
+// leaves output in the terminal's scrollback history.
+func (m *Model) launchTool(t *tool.Tool, opts tool.LaunchOptions, note string) tea.Cmd {
+	cmd, err := t.LaunchCmd(opts)
+	if err != nil {
+		return func() tea.Msg {
+			return toolExitedMsg{name: t.Name, err: err}
+		}
+	}
+
+	// Non-fatal: a failed write only affects LRU ordering and the history
+	now := time.Now()
+	if err := config.RecordToolLaunch(t.Name, now); err != nil {
+		log.Warn("saving usage data failed", "tool", t.Name, "err", err)
+	}
+	dir := cmd.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	if err := config.RecordRecentDir(dir); err != nil {
+		log.Warn("saving recent directories failed", "err", err)
+	}
+	if err := config.RecordLaunch(config.Launch{Tool: t.Name, At: now, Dir: dir, Args: opts.ExtraArgs, Note: note}); err != nil {
+		log.Warn("saving launch history failed", "tool", t.Name, "err", err)
+	}
+	return m.runToolCmd(t.Name, cmd, opts, true)
+}
+
+// runToolCmd runs an already built tool command under the suspended TUI,
+// capturing the tail of its stderr for the post-mortem dialog. A session is
+// added to the tool's usage stats when it exits. The model is suspended until
+// toolExitedMsg, so ticks queued meanwhile don't count the session as idle time.
+func (m *Model) runToolCmd(name string, cmd *exec.Cmd, opts tool.LaunchOptions, session bool) tea.Cmd {
+	m.suspended = true
+	tail := tool.NewTailBuffer(PostMortemLines)
+	cmd.Stderr = io.MultiWriter(os.Stderr, tail)
+
+	var c tea.ExecCommand = &execCommand{Cmd: cmd}
+	if opts.AltScreen {
+		c = &altScreenCommand{execCommand{Cmd: cmd}}
+	}
+	dir := cmd.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	start := time.Now()
+	return tea.Exec(c, func(err error) tea.Msg {
+		elapsed := time.Since(start)
+		if session {
+			if err := config.RecordSession(name, config.FinishedSession(start, elapsed, err)); err != nil {
+				log.Warn("saving usage data failed", "tool", name, "err", err)
+			}
+		}
+		return toolExitedMsg{name: name, err: err, elapsed: elapsed, stderr: tail.Lines(), session: session, dir: dir, start: start}
+	})
+}
+
+// execCommand adapts an exec.Cmd to tea.ExecCommand, keeping streams that were set explicitly.
+type execCommand struct{ *exec.Cmd }
+
+func (c *execCommand) SetStdin(r io.Reader) {
+	if c.Stdin == nil {
+		c.Stdin = r
+	}
+}
+
+func (c *execCommand) SetStdout(w io.Writer) {
+	if c.Stdout == nil {
+		c.Stdout = w
+	}
+}
+
+func (c *execCommand) SetStderr(w io.Writer) {
+	if c.Stderr == nil {
+		c.Stderr = w
+	}
+}
+
+// altScreenCommand runs the wrapped command inside the terminal's alternate screen.
+type altScreenCommand struct{ execCommand }
+
+func (c *altScreenCommand) Run() error {
+	if c.Stdout != nil {
+		_, _ = io.WriteString(c.Stdout, tool.EnterAltScreen)
+		defer io.WriteString(c.Stdout, tool.LeaveAltScreen)
+	}
+	return c.Cmd.Run()
+}
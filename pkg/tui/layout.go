@@ -0,0 +1,233 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// toolGroup is a run of tools in the list: a group from the settings, or the installed
+// or not installed tools in none.
+type toolGroup struct {
+	label     string
+	start     int // Index of the group's first tool in visibleTools
+	count     int // Number of tools in the group, including collapsed ones
+	collapsed bool
+	expandKey string // Key that expands the group while it is collapsed
+}
+
+// groupHeaderStyle renders the divider above each group
+var groupHeaderStyle = lipgloss.NewStyle().
+	Foreground(mutedText).
+	PaddingLeft(2)
+
+const groupHeaderWidth = 40
+
+// uninstalledGroup is the label of the group of tools that aren't installed.
+const uninstalledGroup = "Not installed"
+
+// pinnedGroup is the label of the group of pinned tools, shown above all others.
+const pinnedGroup = "Pinned"
+
+// section is a group with its tools in display order.
+type section struct {
+	toolGroup
+	tools []*tool.Tool
+}
+
+// sections splits the tools matching the search into the pinned ones, the groups of the
+// settings, then the other installed tools and the not installed ones, leaving out empty
+// groups. Pinned tools are in the order of the settings, the others ranked in each group.
+func (m Model) sections() []section {
+	isPinned := make(map[string]bool, len(m.settings.Pinned))
+	for _, name := range m.settings.Pinned {
+		isPinned[name] = true
+	}
+	named := make([]section, len(m.settings.Groups))
+	groupOf := make(map[string]int)
+	for i, g := range m.settings.Groups {
+		named[i].label = g.Name
+		for _, name := range g.Tools {
+			if _, ok := groupOf[name]; !ok {
+				groupOf[name] = i
+			}
+		}
+	}
+	pinned := section{toolGroup: toolGroup{label: pinnedGroup}}
+	installed := section{toolGroup: toolGroup{label: "Installed"}}
+	uninstalled := section{toolGroup: toolGroup{label: uninstalledGroup, collapsed: m.collapseUninstalled, expandKey: "c"}}
+	for _, t := range tool.Pin(sortTools(m.filteredTools(), m.settings.Ranking.Order), m.settings.Pinned) {
+		i, grouped := groupOf[t.Name]
+		switch {
+		case isPinned[t.Name]:
+			pinned.tools = append(pinned.tools, t)
+		case !t.IsInstalled():
+			uninstalled.tools = append(uninstalled.tools, t)
+		case grouped:
+			named[i].tools = append(named[i].tools, t)
+		default:
+			installed.tools = append(installed.tools, t)
+		}
+	}
+
+	var sections []section
+	start := 0
+	for _, s := range append(append([]section{pinned}, named...), installed, uninstalled) {
+		if len(s.tools) == 0 {
+			continue
+		}
+		if s.label != uninstalledGroup {
+			s.collapsed, s.expandKey = m.collapsedGroups[s.label], "Z"
+		}
+		s.start, s.count = start, len(s.tools)
+		if !s.collapsed {
+			start += s.count
+		}
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// collapsedGroups returns the groups of the settings that start collapsed.
+func collapsedGroups(groups []config.ToolGroup) map[string]bool {
+	collapsed := make(map[string]bool)
+	for _, g := range groups {
+		if g.Collapsed {
+			collapsed[g.Name] = true
+		}
+	}
+	return collapsed
+}
+
+// visibleTools returns the tools shown in the list, in display order.
+// Only tools matching the search are shown, and collapsed groups are left out.
+func (m Model) visibleTools() []*tool.Tool {
+	var tools []*tool.Tool
+	for _, s := range m.sections() {
+		if !s.collapsed {
+			tools = append(tools, s.tools...)
+		}
+	}
+	return tools
+}
+
+// groups returns the groups of the list, in display order.
+func (m Model) groups() []toolGroup {
+	var groups []toolGroup
+	for _, s := range m.sections() {
+		groups = append(groups, s.toolGroup)
+	}
+	return groups
+}
+
+// toggleGroup collapses the group of the selected tool, moving the cursor to the next
+// tool shown, or expands every group but the not installed one with all.
+func (m *Model) toggleGroup(all bool) {
+	if all {
+		var selected string
+		if tools := m.visibleTools(); m.cursor < len(tools) {
+			selected = tools[m.cursor].Name
+		}
+		m.collapsedGroups = nil
+		m.focusTool(selected)
+		return
+	}
+	for _, g := range m.groups() {
+		if g.collapsed || g.label == uninstalledGroup || m.cursor < g.start || m.cursor >= g.start+g.count {
+			continue
+		}
+		if m.collapsedGroups == nil {
+			m.collapsedGroups = make(map[string]bool)
+		}
+		m.collapsedGroups[g.label] = true
+		// The group's tools are gone: its start is the first tool after it
+		m.cursor = max(0, min(g.start, len(m.visibleTools())-1))
+		return
+	}
+}
+
+// rows returns the indices of the group's tools laid out in rows of cols tools.
+func (g toolGroup) rows(cols int) [][]int {
+	if g.collapsed {
+		return nil
+	}
+	var rows [][]int
+	for row := g.start; row < g.start+g.count; row += cols {
+		var indices []int
+		for i := row; i < row+cols && i < g.start+g.count; i++ {
+			indices = append(indices, i)
+		}
+		rows = append(rows, indices)
+	}
+	return rows
+}
+
+// renderHeader renders the group's divider line, e.g. "── Installed (4) ────", shortened
+// to fit terminals narrower than the line (width 0 if unknown).
+func (g toolGroup) renderHeader(width int) string {
+	label := fmt.Sprintf("── %s (%d) ", g.label, g.count)
+	if g.collapsed {
+		label += "· " + g.expandKey + ": expand "
+	}
+	lineWidth := groupHeaderWidth
+	if width > 0 {
+		lineWidth = min(lineWidth, width-4)
+	}
+	if rest := lineWidth - lipgloss.Width(label); rest > 0 {
+		label += strings.Repeat("─", rest)
+	}
+	return groupHeaderStyle.Render(label)
+}
+
+// compact reports whether the list is drawn with config.DensityCompact: no blank lines
+// between sections, nothing under the selected tool but what is being edited, and
+// narrower bars.
+func (m Model) compact() bool {
+	return m.settings.Density == config.DensityCompact
+}
+
+// columns returns how many columns the tool list is laid out in.
+// The detail pane takes the room of the second column.
+func (m Model) columns() int {
+	if m.settings.Layout == config.LayoutSingle || m.terminalWidth < gridMinWidth || m.showDetail {
+		return 1
+	}
+	return gridColumns
+}
+
+// gridRows returns the indices of all visible tools, row by row as they are displayed.
+func (m Model) gridRows() [][]int {
+	var rows [][]int
+	for _, g := range m.groups() {
+		rows = append(rows, g.rows(m.columns())...)
+	}
+	return rows
+}
+
+// moveCursor returns the index of the tool dRows rows and dCols columns away from the
+// cursor, staying put at the edges. Moving onto a shorter row lands on its last tool.
+func (m Model) moveCursor(dRows, dCols int) int {
+	rows := m.gridRows()
+	for r, row := range rows {
+		for c, i := range row {
+			if i != m.cursor {
+				continue
+			}
+			nr, nc := r+dRows, c+dCols
+			if nr < 0 || nr >= len(rows) || nc < 0 {
+				return m.cursor
+			}
+			if dCols != 0 && nc >= len(rows[nr]) {
+				return m.cursor
+			}
+			if nc >= len(rows[nr]) {
+				nc = len(rows[nr]) - 1
+			}
+			return rows[nr][nc]
+		}
+	}
+	return m.cursor
+}
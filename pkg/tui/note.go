@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxNoteLength is how many characters a launch note can have.
+const maxNoteLength = 120
+
+// launchNote returns the note the next launch is recorded with.
+func (m Model) launchNote() string {
+	if m.note == nil {
+		return ""
+	}
+	return *m.note
+}
+
+// updateNote handles keys while the note for the next launch is being written.
+func (m Model) updateNote(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.editingNote = false
+	case tea.KeyEnter:
+		m.editingNote = false
+		if m.note == nil {
+			m.note = new(string)
+		}
+		*m.note = strings.TrimSpace(m.noteInput)
+		if tools := m.visibleTools(); m.cursor < len(tools) {
+			return m.launch(tools[m.cursor])
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.noteInput); len(r) > 0 {
+			m.noteInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.noteInput += " "
+	case tea.KeyRunes:
+		if len([]rune(m.noteInput))+len(msg.Runes) <= maxNoteLength {
+			m.noteInput += string(msg.Runes)
+		}
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderNote renders the note editor, or the note when not editing.
+func (m Model) renderNote() string {
+	if m.editingNote {
+		return searchStyle.Render("note › " + m.noteInput + "▏")
+	}
+	return descStyle.Render("note: " + m.launchNote())
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Option configures the TUI started by Run.
+type Option func(*Model)
+
+// WithoutBanner hides the ASCII art title above the tool list.
+func WithoutBanner() Option {
+	return func(m *Model) {
+		m.title = ""
+	}
+}
+
+// WithTheme renders the TUI with the given colors instead of DefaultTheme.
+func WithTheme(theme Theme) Option {
+	return func(m *Model) {
+		m.theme = theme
+		m.spinner.Style = lipgloss.NewStyle().Foreground(theme.Highlight)
+		if m.title != "" {
+			m.title = m.renderBanner()
+		}
+	}
+}
+
+// WithFilter only lists the tools for which keep returns true.
+func WithFilter(keep func(*tool.Tool) bool) Option {
+	return func(m *Model) {
+		m.filter = keep
+		m.tools = keepTools(m.tools, keep)
+	}
+}
+
+// keepTools returns the tools for which keep returns true.
+func keepTools(tools []*tool.Tool, keep func(*tool.Tool) bool) []*tool.Tool {
+	var kept []*tool.Tool
+	for _, t := range tools {
+		if keep(t) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// WithTour starts the TUI with the guided tour active.
+func WithTour() Option {
+	return func(m *Model) {
+		m.tour = newTour(m.settings.ShowBalances)
+	}
+}
+
+// WithPostMortem opens the TUI on the post-mortem dialog for a tool that failed
+// right after launch, offering to retry or log in.
+func WithPostMortem(pm PostMortem) Option {
+	return func(m *Model) {
+		m.postMortem = &pm
+	}
+}
+
+// WithInput reads keys from r instead of stdin.
+func WithInput(r io.Reader) Option {
+	return func(m *Model) {
+		m.in = r
+	}
+}
+
+// WithOutput renders to w instead of stdout.
+func WithOutput(w io.Writer) Option {
+	return func(m *Model) {
+		m.out = w
+	}
+}
+
+// WithArgs launches the selected tool with *args appended to its configured arguments.
+// Arguments edited in the TUI are stored back in *args, so the caller can use them
+// when it launches the tool itself.
+func WithArgs(args *[]string) Option {
+	return func(m *Model) {
+		m.args = args
+	}
+}
+
+// WithNote records the next launch in the history with *note. A note written in the TUI
+// is stored back in *note, so the caller can record the launch itself.
+func WithNote(note *string) Option {
+	return func(m *Model) {
+		m.note = note
+	}
+}
+
+// WithDir launches the selected tool in *dir, over its configured directory. A directory
+// chosen in the TUI is stored back in *dir, so the caller can launch the tool in it.
+func WithDir(dir *string) Option {
+	return func(m *Model) {
+		m.dir = dir
+	}
+}
+
+// WithSelectOnly returns the chosen tool without launching it, even when
+// return_to_menu is set, for callers that launch it themselves.
+func WithSelectOnly() Option {
+	return func(m *Model) {
+		m.selectOnly = true
+		m.settings.ReturnToMenu = false
+	}
+}
+
+// WithReload rebuilds the tool list with build after the settings are edited from the
+// menu, so custom tools, tags and the rest take effect without a restart. Without it
+// only the settings themselves are reloaded.
+func WithReload(build func(config.Settings) *tool.Registry) Option {
+	return func(m *Model) {
+		m.reloadTools = build
+	}
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+func TestWithSelectOnly(t *testing.T) {
+	m := testModel(t, installedTool("a"))
+	WithSelectOnly()(&m)
+	if m.settings.ReturnToMenu {
+		t.Fatal("WithSelectOnly() kept return_to_menu on")
+	}
+
+	// Turning return_to_menu on in the settings doesn't make it launch tools either
+	settings := config.LoadSettings()
+	settings.ReturnToMenu = true
+	if _, err := m.reload(settings); err != nil {
+		t.Fatal(err)
+	}
+	if m.settings.ReturnToMenu {
+		t.Error("reload() turned return_to_menu back on")
+	}
+
+	next, cmd := m.Update(key("enter"))
+	m = next.(Model)
+	if m.selected != "a" || m.suspended || cmd == nil {
+		t.Fatalf("enter: selected %q, suspended %v, want the launcher to quit with a selected", m.selected, m.suspended)
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("enter didn't quit the launcher")
+	}
+}
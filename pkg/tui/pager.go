@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pager shows text that doesn't fit on screen a window at a time, with line numbers and
+// search, e.g. the install log. Keys: ↑/↓ (k/j) scroll, pgup/pgdown (b/f) page, g/G jump to
+// the start/end, / searches, n/N jump to the next/previous match and esc clears the search.
+type pager struct {
+	lines     []string
+	top       int    // Index of the first line shown
+	height    int    // Lines shown at once
+	follow    bool   // Keep the last lines in view as lines are added
+	searching bool   // The search prompt is open
+	query     string // Search being typed, or the last one; its matches are highlighted
+	match     int    // Index of the line with the current match (-1 if none)
+	notFound  bool   // The last search matched nothing
+}
+
+// matchStyle highlights search matches in the pager
+var matchStyle = lipgloss.NewStyle().
+	Bold(true)
+
+// newPager returns a pager showing height lines at once, following new lines.
+func newPager(height int) pager {
+	return pager{height: max(1, height), follow: true, match: -1}
+}
+
+// setLines replaces the text, e.g. with the install output so far.
+func (p *pager) setLines(lines []string) {
+	p.lines = lines
+	p.clamp()
+}
+
+// setHeight changes how many lines are shown, e.g. after a resize.
+func (p *pager) setHeight(height int) {
+	p.height = max(1, height)
+	p.clamp()
+}
+
+func (p *pager) maxTop() int {
+	return max(0, len(p.lines)-p.height)
+}
+
+// clamp keeps the window inside the text, at its end while following.
+func (p *pager) clamp() {
+	if p.follow {
+		p.top = p.maxTop()
+	}
+	p.top = max(0, min(p.top, p.maxTop()))
+}
+
+// scroll moves the window by n lines; reaching the end follows new lines again.
+func (p *pager) scroll(n int) {
+	p.top = max(0, min(p.top+n, p.maxTop()))
+	p.follow = p.top == p.maxTop()
+}
+
+// update handles a key and reports whether the pager used it.
+func (p *pager) update(key string) bool {
+	if p.searching {
+		switch key {
+		case "enter":
+			p.searching = false
+			p.match = p.top - 1 // Start at the first line shown
+			p.find(1)
+		case "esc":
+			p.searching = false
+			p.query, p.match = "", -1
+		case "backspace":
+			if r := []rune(p.query); len(r) > 0 {
+				p.query = string(r[:len(r)-1])
+			}
+		case "ctrl+c":
+			return false
+		default:
+			if len([]rune(key)) == 1 {
+				p.query += key
+			}
+		}
+		return true
+	}
+
+	switch key {
+	case "up", "k":
+		p.scroll(-1)
+	case "down", "j":
+		p.scroll(1)
+	case "pgup", "b":
+		p.scroll(-p.height)
+	case "pgdown", "f", " ":
+		p.scroll(p.height)
+	case "g", "home":
+		p.top, p.follow = 0, false
+	case "G", "end":
+		p.follow = true
+		p.clamp()
+	case "/":
+		p.searching, p.query, p.match, p.notFound = true, "", -1, false
+	case "n", "N":
+		if p.query == "" {
+			return false
+		}
+		if key == "n" {
+			p.find(1)
+		} else {
+			p.find(-1)
+		}
+	case "esc":
+		if p.query == "" {
+			return false
+		}
+		p.query, p.match, p.notFound = "", -1, false
+	default:
+		return false
+	}
+	return true
+}
+
+// find moves to the next (dir 1) or previous (dir -1) line after the current match
+// containing the query, ignoring case and wrapping around, and scrolls it into view.
+func (p *pager) find(dir int) {
+	p.notFound = false
+	if p.query == "" || len(p.lines) == 0 {
+		return
+	}
+	query := strings.ToLower(p.query)
+	for i := 1; i <= len(p.lines); i++ {
+		line := ((p.match+dir*i)%len(p.lines) + len(p.lines)) % len(p.lines)
+		if strings.Contains(strings.ToLower(p.lines[line]), query) {
+			p.match = line
+			if line < p.top || line >= p.top+p.height {
+				p.top = min(line, p.maxTop())
+			}
+			p.follow = false
+			return
+		}
+	}
+	p.match, p.notFound = -1, true
+}
+
+// matches returns how many lines contain the query and the position of the current
+// match among them, 1-based.
+func (p pager) matches() (count, current int) {
+	query := strings.ToLower(p.query)
+	for i, line := range p.lines {
+		if strings.Contains(strings.ToLower(line), query) {
+			count++
+			if i == p.match {
+				current = count
+			}
+		}
+	}
+	return count, current
+}
+
+// view renders the visible lines, numbered and cut at width, in style, with a status
+// line below while searching or scrolled up.
+func (p pager) view(width int, style lipgloss.Style) string {
+	end := min(p.top+p.height, len(p.lines))
+	digits := len(fmt.Sprint(len(p.lines)))
+	numberStyle := lipgloss.NewStyle().Foreground(activeTheme.Subtle)
+	// The number's color ends with a reset, so the text is colored on its own
+	textStyle := lipgloss.NewStyle().Foreground(style.GetForeground())
+	query := p.query
+	if p.searching || p.notFound {
+		query = ""
+	}
+
+	lines := make([]string, 0, end-p.top)
+	for i := p.top; i < end; i++ {
+		number := numberStyle.Render(fmt.Sprintf("%*d ", digits, i+1))
+		lines = append(lines, number+highlight(truncateLine(p.lines[i], width-digits-1), query, textStyle))
+	}
+	pane := style.Render(strings.Join(lines, "\n"))
+
+	var status string
+	switch below := len(p.lines) - end; {
+	case p.searching:
+		status = searchStyle.Render("/" + p.query + "▏")
+	case p.notFound:
+		status = submenuStyle.Render(fmt.Sprintf("    no match for %q", p.query))
+	case p.query != "":
+		count, current := p.matches()
+		status = submenuStyle.Render(fmt.Sprintf("    %q: match %d of %d (n/N: next/previous, esc: clear)", p.query, current, count))
+	case below > 0:
+		unit := "lines"
+		if below == 1 {
+			unit = "line"
+		}
+		status = submenuStyle.Render(fmt.Sprintf("    ↓ %d more %s (G: end)", below, unit))
+	}
+	if status != "" {
+		pane += "\n" + status
+	}
+	return pane
+}
+
+// highlight renders line in style with every occurrence of query, ignoring case, in
+// matchStyle.
+func highlight(line, query string, style lipgloss.Style) string {
+	q := []rune(query)
+	if len(q) == 0 {
+		return style.Render(line)
+	}
+	r := []rune(line)
+	var s strings.Builder
+	start := 0 // Start of the text not rendered yet
+	for i := 0; i+len(q) <= len(r); {
+		if !strings.EqualFold(string(r[i:i+len(q)]), query) {
+			i++
+			continue
+		}
+		if start < i {
+			s.WriteString(style.Render(string(r[start:i])))
+		}
+		s.WriteString(matchStyle.Render(string(r[i : i+len(q)])))
+		i += len(q)
+		start = i
+	}
+	if start < len(r) {
+		s.WriteString(style.Render(string(r[start:])))
+	}
+	return s.String()
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+)
+
+// sized sends m a terminal size.
+func sized(m Model, width, height int) Model {
+	next, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return next.(Model)
+}
+
+// historyModel returns a launcher with 40 launches in its history, every fifth one noted
+// "fixing auth", and the history view open on a 100x30 terminal.
+func historyModel(t *testing.T) Model {
+	t.Helper()
+	m := testModel(t)
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	for i := range 40 {
+		l := config.Launch{Tool: fmt.Sprintf("tool%02d", i), At: start.Add(time.Duration(i) * time.Hour), Dir: "/src/api"}
+		if i%5 == 0 {
+			l.Note = "fixing auth"
+		}
+		if err := config.RecordLaunch(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return press(sized(m, 100, 30), "H")
+}
+
+func TestHistory_Pager(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []string
+		wantTop   int // -1 for the last window
+		wantMatch int // Line of the current match, -1 for none
+		wantQuery string
+		notFound  bool
+	}{
+		{"opens at the newest", nil, 0, -1, "", false},
+		{"scroll down", []string{"j", "j", "down"}, 3, -1, "", false},
+		{"scroll up clamps at the start", []string{"j", "k", "k", "up"}, 0, -1, "", false},
+		{"end", []string{"G"}, -1, -1, "", false},
+		{"scroll down clamps at the end", []string{"G", "j", "down"}, -1, -1, "", false},
+		{"page up from the end", []string{"G", "pgup", "g"}, 0, -1, "", false},
+		// Newest first: tool35 is on line 4, tool30 on line 9, tool00 at the end
+		{"find", []string{"/", "a", "u", "t", "h", "enter"}, 0, 4, "auth", false},
+		{"find is case-insensitive", []string{"/", "A", "U", "T", "H", "enter"}, 0, 4, "AUTH", false},
+		{"next match", []string{"/", "a", "u", "t", "h", "enter", "n"}, 0, 9, "auth", false},
+		{"previous match wraps to the end", []string{"/", "a", "u", "t", "h", "enter", "N"}, -1, 39, "auth", false},
+		{"next wraps to the start", []string{"/", "t", "o", "o", "l", "0", "0", "enter", "n"}, -1, 39, "tool00", false},
+		{"backspace", []string{"/", "a", "u", "x", "backspace", "t", "h", "enter"}, 0, 4, "auth", false},
+		{"no match", []string{"/", "z", "z", "z", "enter"}, 0, -1, "zzz", true},
+		{"esc clears the search", []string{"/", "a", "u", "t", "h", "enter", "esc"}, 0, -1, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := press(historyModel(t), tt.keys...)
+			p := m.historyPager
+			if !m.showHistory {
+				t.Fatal("the history view closed")
+			}
+			wantTop := tt.wantTop
+			if wantTop < 0 {
+				wantTop = p.maxTop()
+			}
+			if p.top != wantTop || p.match != tt.wantMatch || p.query != tt.wantQuery || p.notFound != tt.notFound {
+				t.Errorf("top %d, match %d, query %q, not found %v; want %d, %d, %q, %v",
+					p.top, p.match, p.query, p.notFound, wantTop, tt.wantMatch, tt.wantQuery, tt.notFound)
+			}
+			if p.match >= 0 && (p.match < p.top || p.match >= p.top+p.height) {
+				t.Errorf("match on line %d is out of the window %d-%d", p.match, p.top, p.top+p.height)
+			}
+		})
+	}
+}
+
+func TestHistory_View(t *testing.T) {
+	m := historyModel(t)
+	if lines := len(m.historyPager.lines); lines != 40 {
+		t.Fatalf("history has %d lines, want one per launch", lines)
+	}
+	if first := m.historyPager.lines[0]; !strings.Contains(first, "tool39") {
+		t.Errorf("first line = %q, want the newest launch", first)
+	}
+	if view := m.View(); lineCount(view) > 30 {
+		t.Errorf("View() has %d lines, want it to fit the 30 line terminal:\n%s", lineCount(view), view)
+	}
+
+	m = press(m, "/", "a", "u", "t", "h", "enter")
+	if view := m.View(); !strings.Contains(view, `"auth": match 1 of 8`) {
+		t.Errorf("View() doesn't count the matches:\n%s", view)
+	}
+
+	// esc clears the search first, then closes the view
+	if m = press(m, "esc"); !m.showHistory {
+		t.Fatal("esc with a search closed the history, want it to clear the search")
+	}
+	if m = press(m, "esc"); m.showHistory {
+		t.Error("esc didn't close the history")
+	}
+	if m = press(m, "H", "q"); m.showHistory || m.quitting {
+		t.Errorf("q: history open %v, quitting %v, want back to the tools", m.showHistory, m.quitting)
+	}
+}
+
+func TestHistory_Empty(t *testing.T) {
+	m := press(sized(testModel(t), 100, 30), "H", "j", "G", "/", "x", "enter")
+	if p := m.historyPager; len(p.lines) != 1 || p.top != 0 || !p.notFound {
+		t.Errorf("pager = %d lines, top %d, not found %v, want the one line saying there are no launches", len(p.lines), p.top, p.notFound)
+	}
+}
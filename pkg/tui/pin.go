@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// togglePin pins t above every group, or unpins it, and saves the pinned tools.
+func (m *Model) togglePin(t *tool.Tool) tea.Cmd {
+	previous := m.settings.Pinned
+	pinned := slices.DeleteFunc(slices.Clone(previous), func(name string) bool { return name == t.Name })
+	label := "Unpinned " + t.DisplayName
+	if len(pinned) == len(previous) {
+		pinned, label = append(pinned, t.Name), "Pinned "+t.DisplayName
+	}
+	if err := m.setPinned(t, pinned); err != nil {
+		return m.showToast(fmt.Sprintf("Failed to save the pinned tools: %v", err))
+	}
+	return m.pushUndo(undoAction{
+		label: label,
+		undo: func(m *Model) error {
+			return m.setPinned(t, previous)
+		},
+	})
+}
+
+// setPinned saves pinned as the pinned tools and keeps the cursor on t as it moves.
+func (m *Model) setPinned(t *tool.Tool, pinned []string) error {
+	if err := config.SavePinned(pinned); err != nil {
+		return err
+	}
+	m.settings.Pinned = pinned
+	m.followTool(t)
+	return nil
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// PostMortemLines is how many trailing stderr lines are kept for the post-mortem dialog.
+const PostMortemLines = 5
+
+// PostMortem describes a launched tool that exited with an error right after starting.
+type PostMortem struct {
+	Tool       string        // Name of the tool that failed
+	ExitCode   int           // Process exit code (-1 if it didn't exit normally)
+	Elapsed    time.Duration // How long the tool ran
+	StderrTail []string      // Last lines the tool wrote to stderr
+}
+
+// Summary returns a one-line description such as "codex exited 1: not logged in".
+func (p PostMortem) Summary() string {
+	summary := fmt.Sprintf("%s exited %d", p.Tool, p.ExitCode)
+	if len(p.StderrTail) > 0 {
+		summary += ": " + p.StderrTail[len(p.StderrTail)-1]
+	}
+	return summary
+}
+
+// postMortemAction is an entry in the post-mortem dialog.
+type postMortemAction struct {
+	label string
+	login bool // Run the tool's login command instead of relaunching it
+	close bool // Dismiss the dialog
+}
+
+// postMortemActions returns the actions available for the failed tool.
+func postMortemActions(t *tool.Tool) []postMortemAction {
+	actions := []postMortemAction{{label: "Retry"}}
+	if t != nil && len(t.LoginArgs) > 0 {
+		actions = append(actions, postMortemAction{
+			label: fmt.Sprintf("Log in (%s %s)", t.Command, strings.Join(t.LoginArgs, " ")),
+			login: true,
+		})
+	}
+	return append(actions, postMortemAction{label: "Close", close: true})
+}
+
+// renderPostMortem renders the post-mortem dialog with the given action selected,
+// with output lines cut at the terminal width (0 if unknown).
+func renderPostMortem(p PostMortem, actions []postMortemAction, cursor, width int) string {
+	var b strings.Builder
+	summaryStyle := errorMsgStyle
+	if width > 0 {
+		summaryStyle = summaryStyle.Width(width - 1)
+	}
+	b.WriteString(summaryStyle.Render("✗ " + p.Summary()))
+	b.WriteString("\n")
+	b.WriteString(descStyle.Render(fmt.Sprintf("after %.1fs", p.Elapsed.Seconds())))
+	b.WriteString("\n")
+
+	// The last line is already in the summary; show the context before it
+	if len(p.StderrTail) > 1 {
+		for _, line := range p.StderrTail[:len(p.StderrTail)-1] {
+			b.WriteString(descStyle.Render(truncateLine(line, width-4)))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+
+	for i, action := range actions {
+		if i == cursor {
+			b.WriteString(fmt.Sprintf("  %s %s\n", submenuSelectedStyle.Render("»"), submenuSelectedStyle.Render(action.label)))
+		} else {
+			b.WriteString(fmt.Sprintf("    %s\n", submenuStyle.Render(action.label)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// maxLabelLength is how many characters a display name override can have.
+const maxLabelLength = 40
+
+// renameFields are the fields of the rename editor, in tab order.
+var renameFields = [...]string{"name", "icon"}
+
+// openRename opens the rename editor for t, filled with its current label.
+func (m *Model) openRename(t *tool.Tool) {
+	m.renaming = true
+	m.renameField = 0
+	m.renameInputs = [len(renameFields)]string{t.DisplayName, t.Icon}
+}
+
+// updateRename handles keys while the display name and icon of the selected tool are
+// being edited.
+func (m Model) updateRename(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	input := &m.renameInputs[m.renameField]
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.renaming = false
+	case tea.KeyTab:
+		m.renameField = (m.renameField + 1) % len(renameFields)
+	case tea.KeyShiftTab:
+		m.renameField = (m.renameField + len(renameFields) - 1) % len(renameFields)
+	case tea.KeyEnter:
+		if tools := m.visibleTools(); m.cursor < len(tools) {
+			return m, m.saveRename(tools[m.cursor])
+		}
+		m.renaming = false
+	case tea.KeyBackspace:
+		if r := []rune(*input); len(r) > 0 {
+			*input = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		*input += " "
+	case tea.KeyRunes:
+		if len([]rune(*input))+len(msg.Runes) <= maxLabelLength {
+			*input += string(msg.Runes)
+		}
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// saveRename saves what the rename editor holds as t's override. Fields left empty or
+// set back to the tool's own label drop out of the override.
+func (m *Model) saveRename(t *tool.Tool) tea.Cmd {
+	name, icon := strings.TrimSpace(m.renameInputs[0]), strings.TrimSpace(m.renameInputs[1])
+	if lipgloss.Width(icon) > 1 {
+		return m.showToast("The icon must be one character wide, like ♥")
+	}
+	m.renaming = false
+
+	ownName, ownIcon := t.OwnLabel()
+	var override config.ToolOverride
+	if name != ownName {
+		override.DisplayName = name
+	}
+	if icon != ownIcon {
+		override.Icon = icon
+	}
+	previous, oldName := m.settings.Overrides[t.Name], t.DisplayName
+	if override == previous {
+		return nil
+	}
+	if err := m.setOverride(t, override); err != nil {
+		return m.showToast(fmt.Sprintf("Failed to save the override: %v", err))
+	}
+	label := "Renamed " + oldName + " to " + t.DisplayName
+	if override == (config.ToolOverride{}) {
+		label = "Reset the name of " + t.DisplayName
+	} else if t.DisplayName == oldName {
+		label = "Changed the icon of " + t.DisplayName
+	}
+	return m.pushUndo(undoAction{
+		label: label,
+		undo: func(m *Model) error {
+			return m.setOverride(t, previous)
+		},
+	})
+}
+
+// setOverride saves o as t's override and relabels t with it.
+func (m *Model) setOverride(t *tool.Tool, o config.ToolOverride) error {
+	if err := config.SaveOverride(t.Name, o); err != nil {
+		return err
+	}
+	overrides := make(map[string]config.ToolOverride, len(m.settings.Overrides)+1)
+	for name, v := range m.settings.Overrides {
+		overrides[name] = v
+	}
+	if o == (config.ToolOverride{}) {
+		delete(overrides, t.Name)
+	} else {
+		overrides[t.Name] = o
+	}
+	m.settings.Overrides = overrides
+
+	ownName, ownIcon := t.OwnLabel()
+	t.Relabel(cmp.Or(o.DisplayName, ownName), cmp.Or(o.Icon, ownIcon))
+	m.followTool(t)
+	return nil
+}
+
+// renderRename renders the rename editor, with the field being typed in highlighted.
+func (m Model) renderRename() string {
+	var fields []string
+	for i, label := range renameFields {
+		if i == m.renameField {
+			fields = append(fields, searchStyle.Render(label+" › "+m.renameInputs[i]+"▏"))
+		} else {
+			fields = append(fields, descStyle.Render(label+": "+m.renameInputs[i]))
+		}
+	}
+	return strings.Join(fields, "  ")
+}
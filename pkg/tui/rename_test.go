@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestRename_Fields(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want int
+	}{
+		{nil, 0},
+		{[]string{"tab"}, 1},
+		{[]string{"tab", "tab"}, 0},
+		{[]string{"shift+tab"}, 1},
+		{[]string{"tab", "shift+tab"}, 0},
+		{[]string{"shift+tab", "shift+tab"}, 0},
+	}
+	for _, tt := range tests {
+		m := press(testModel(t, installedTool("a")), append([]string{"r"}, tt.keys...)...)
+		if !m.renaming || m.renameField != tt.want {
+			t.Errorf("%q: renaming %v, field %d, want field %d", tt.keys, m.renaming, m.renameField, tt.want)
+		}
+	}
+}
+
+func TestRename_OwnLabel(t *testing.T) {
+	// A catalog tool with its own label, renamed when the registry was built
+	goose := &tool.Tool{Name: "goose", DisplayName: "Goose", Icon: "g", Command: "goose"}
+	goose.SetInstalled(true)
+	goose.Relabel("goose – work", "g")
+	clear := slices.Repeat([]string{"backspace"}, len([]rune("goose – work")))
+	tests := []struct {
+		name     string
+		keys     []string
+		want     config.ToolOverride
+		wantName string
+	}{
+		{"typed back", slices.Concat(clear, []string{"G", "o", "o", "s", "e"}), config.ToolOverride{}, "Goose"},
+		{"emptied", clear, config.ToolOverride{}, "Goose"},
+		{"icon changed", []string{"tab", "backspace", "★"}, config.ToolOverride{DisplayName: "goose – work", Icon: "★"}, "goose – work"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := *goose
+			m := testModel(t, &g)
+			if err := config.SaveOverride("goose", config.ToolOverride{DisplayName: "goose – work"}); err != nil {
+				t.Fatal(err)
+			}
+			m.settings = config.LoadSettings()
+
+			m = press(m, append(append([]string{"r"}, tt.keys...), "enter")...)
+			if got := m.settings.Overrides["goose"]; got != tt.want {
+				t.Errorf("override = %+v, want %+v", got, tt.want)
+			}
+			if got := config.LoadSettings().Overrides["goose"]; got != tt.want {
+				t.Errorf("saved override = %+v, want %+v", got, tt.want)
+			}
+			if g.DisplayName != tt.wantName {
+				t.Errorf("DisplayName = %q, want %q", g.DisplayName, tt.wantName)
+			}
+		})
+	}
+}
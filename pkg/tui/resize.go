@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Every frame is laid out from terminalWidth and terminalHeight, so a resize only has to
+// store them and keep the state that depends on them in range.
+
+// resize applies a new terminal size.
+func (m *Model) resize(width, height int) {
+	m.terminalWidth, m.terminalHeight = width, height
+	// The install pane and the history may show fewer lines now
+	m.installPager.setHeight(m.installPaneLines())
+	m.historyPager.setHeight(m.historyPaneLines())
+}
+
+// fit returns style wrapping its text at the terminal width, borders and margins included.
+// The width is left alone until the terminal size is known.
+func (m Model) fit(style lipgloss.Style) lipgloss.Style {
+	if m.terminalWidth <= 0 {
+		return style
+	}
+	// Width counts the padding but not the border and margins; keep the last column free
+	width := m.terminalWidth - style.GetHorizontalBorderSize() - style.GetHorizontalMargins() - 1
+	return style.Width(max(width, 10))
+}
+
+// installPaneLines returns how many output lines the install pane shows, fewer on short
+// terminals so the dialog and help around it stay on screen.
+func (m Model) installPaneLines() int {
+	if m.terminalHeight <= 0 {
+		return installPaneHeight
+	}
+	return max(3, min(installPaneHeight, m.terminalHeight/3))
+}
+
+// renderTitle renders the banner, or a one-line title when the banner doesn't fit.
+func (m Model) renderTitle() string {
+	if m.title == "" || m.terminalWidth <= 0 || lipgloss.Width(m.title) <= m.terminalWidth {
+		return m.title
+	}
+	return compactTitle()
+}
+
+// compactTitle renders the one-line title used instead of the banner.
+func compactTitle() string {
+	return lipgloss.NewStyle().Bold(true).Foreground(activeTheme.Highlight).PaddingLeft(2).Render("amazing-cli")
+}
+
+// truncateLine shortens line to width columns with an ellipsis; width 0 or less keeps it whole.
+func truncateLine(line string, width int) string {
+	if r := []rune(line); width > 0 && len(r) > width {
+		return string(r[:width-1]) + "…"
+	}
+	return line
+}
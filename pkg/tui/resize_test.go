@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestResize_Layout(t *testing.T) {
+	bannerWidth := lipgloss.Width(renderBlockColorTitle(titleArt, 0))
+	tests := []struct {
+		name        string
+		width       int
+		height      int
+		wantColumns int
+		wantBelow   bool // Detail pane below the list
+		wantPane    int  // Install pane lines
+		wantBanner  bool
+	}{
+		{"wide", 200, 60, gridColumns, false, installPaneHeight, true},
+		{"grid from its width", gridMinWidth, 60, gridColumns, false, installPaneHeight, true},
+		{"narrower than the grid", gridMinWidth - 1, 60, 1, false, installPaneHeight, true},
+		{"detail pane beside from its width", detailMinWidth, 24, 1, false, 8, true},
+		{"detail pane below when narrower", detailMinWidth - 1, 24, 1, true, 8, true},
+		{"narrower than the banner", bannerWidth - 1, 24, 1, true, 8, false},
+		{"short", 80, 6, 1, true, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Start from another size so each resize has something to change
+			m := sized(sized(testModel(t, manyTools(6)...), 300, 100), tt.width, tt.height)
+			if got := m.columns(); got != tt.wantColumns {
+				t.Errorf("columns() = %d, want %d", got, tt.wantColumns)
+			}
+			if got := m.detailBelow(); got != tt.wantBelow {
+				t.Errorf("detailBelow() = %v, want %v", got, tt.wantBelow)
+			}
+			if got := m.installPaneLines(); got != tt.wantPane {
+				t.Errorf("installPaneLines() = %d, want %d", got, tt.wantPane)
+			}
+			if banner := m.renderTitle() == m.title; banner != tt.wantBanner {
+				t.Errorf("banner shown = %v, want %v", banner, tt.wantBanner)
+			}
+		})
+	}
+}
+
+func TestResize_ViewFits(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		keys   []string
+	}{
+		{"grid", 200, 40, nil},
+		{"list", 120, 30, nil},
+		{"narrow", 70, 24, nil},
+		{"detail pane beside", 120, 30, []string{"tab"}},
+		{"detail pane below", 90, 40, []string{"tab"}},
+		{"help", 90, 40, []string{"?"}},
+		{"history", 90, 20, []string{"H"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := press(sized(testModel(t, manyTools(30)...), 300, 100), tt.keys...)
+			m = sized(m, tt.width, tt.height)
+			view := m.View()
+			for i, line := range strings.Split(view, "\n") {
+				if w := lipgloss.Width(line); w > tt.width {
+					t.Errorf("line %d is %d columns wide, want at most %d:\n%s", i+1, w, tt.width, view)
+					break
+				}
+			}
+			if lines := lineCount(view); lines > tt.height {
+				t.Errorf("View() has %d lines, want at most %d:\n%s", lines, tt.height, view)
+			}
+		})
+	}
+}
+
+func TestResize_Pagers(t *testing.T) {
+	tests := []struct {
+		name       string
+		height     int
+		wantPane   int
+		wantTop    int
+		wantFollow bool
+	}{
+		{"shorter", 15, 5, 25, true},
+		{"shortest", 6, 3, 27, true},
+		{"taller again", 60, 10, 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The install pane follows the output at the end of any height
+			m := sized(installingModel(t, 30), 100, tt.height)
+			if p := m.installPager; p.height != tt.wantPane || p.top != tt.wantTop || p.follow != tt.wantFollow {
+				t.Errorf("install pane: height %d, top %d, follow %v, want %d, %d, %v",
+					p.height, p.top, p.follow, tt.wantPane, tt.wantTop, tt.wantFollow)
+			}
+
+			// The history stays where it was scrolled to, inside the text
+			m = press(historyModel(t), "G")
+			m = sized(m, 100, tt.height)
+			p := m.historyPager
+			if p.height != m.historyPaneLines() || p.top != p.maxTop() {
+				t.Errorf("history: height %d, top %d, want %d, %d", p.height, p.top, m.historyPaneLines(), p.maxTop())
+			}
+		})
+	}
+}
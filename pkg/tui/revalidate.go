@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// revalidatedMsg carries the fresh install state and versions of the tools, keyed by name
+type revalidatedMsg struct {
+	installed map[string]bool
+	versions  map[string]string
+}
+
+// revalidateTools re-checks PATH and versions in the background, so the list can be
+// rendered from the warm-start snapshot immediately. Tools are only read, never modified,
+// here; the results are applied in Update.
+func revalidateTools(tools []*tool.Tool) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		msg := revalidatedMsg{
+			installed: make(map[string]bool, len(tools)),
+			versions:  make(map[string]string, len(tools)),
+		}
+		for _, t := range tools {
+			_, err := t.Path()
+			msg.installed[t.Name] = err == nil
+			if err == nil {
+				msg.versions[t.Name] = t.DetectVersion(ctx)
+			}
+		}
+		return msg
+	}
+}
+
+// refreshTools checks every tool again (R), e.g. after installing or logging in to one
+// outside the launcher: install state, versions, logins and, when shown, balances,
+// fetched anew instead of from the daemon's cache.
+func (m *Model) refreshTools() tea.Cmd {
+	cmds := []tea.Cmd{revalidateTools(m.tools), checkAuth(m.tools)}
+	if m.settings.ShowBalances {
+		for _, t := range m.tools {
+			if provider.SupportsBalance(t) {
+				delete(m.balancesDone, t.Name) // Shows it loading until the fetch is back
+				cmds = append(cmds, fetchBalance(t, nil, m.settings.BurnAlerts))
+			}
+		}
+		cmds = append(cmds, m.spinner.Tick)
+	}
+	return tea.Batch(cmds...)
+}
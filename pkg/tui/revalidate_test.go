@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestUpdate_RevalidatedKeepsCursor(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed map[string]bool
+		collapsed bool
+		want      string // Tool under the cursor afterwards
+	}{
+		{"another tool installed above", map[string]bool{"a": true, "b": true, "c": false}, false, "c"},
+		{"a tool above uninstalled", map[string]bool{"a": false, "b": false, "c": false}, false, "c"},
+		{"the selected tool installed", map[string]bool{"a": true, "b": false, "c": true}, false, "c"},
+		{"the selected tool uninstalled into a collapsed group", map[string]bool{"a": false, "b": true, "c": false}, true, "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := installedTool("a")
+			b := &tool.Tool{Name: "b", DisplayName: "b", Command: "b"}
+			c := &tool.Tool{Name: "c", DisplayName: "c", Command: "c", InstallCmds: map[string]string{"linux": "true", "darwin": "true"}}
+			if tt.collapsed {
+				c.SetInstalled(true)
+			}
+			m := testModel(t, a, b, c)
+			m.collapseUninstalled = tt.collapsed
+			m.focusTool("c")
+			if !tt.collapsed {
+				m = press(m, "enter") // The install prompt of c
+			}
+
+			next, _ := m.Update(revalidatedMsg{installed: tt.installed})
+			m = next.(Model)
+			tools := m.visibleTools()
+			if m.cursor >= len(tools) || tools[m.cursor].Name != tt.want {
+				t.Fatalf("cursor %d of %d tools, want it on %s", m.cursor, len(tools), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// listLayout is the rendered tool list with where each tool ended up.
+type listLayout struct {
+	text                   string
+	lines                  int   // Lines of text
+	starts                 []int // First line of each visible tool's row
+	cursorStart, cursorEnd int   // Lines of the selected tool's row, end exclusive
+}
+
+// frame is one screen split around the tool list, which scrolls when it doesn't fit.
+type frame struct {
+	header, footer string
+	detail         string // Detail pane, beside the list or below it with detailBelow
+	detailBelow    bool
+	list           listLayout
+	listHeight     int // Lines the list may use, scroll indicators included; 0 if it all fits
+}
+
+// layoutFrame renders the parts of the screen and works out the room left for the list.
+func (m Model) layoutFrame() frame {
+	sortedTools := m.visibleTools()
+	f := frame{
+		header: m.renderHeader(false),
+		footer: m.renderFooter(sortedTools),
+		list:   m.renderList(sortedTools),
+	}
+	if m.showDetail && m.cursor < len(sortedTools) {
+		f.detail, f.detailBelow = m.renderDetailPane(sortedTools[m.cursor], f.list.text)
+	}
+
+	reserved := lineCount(f.footer)
+	if f.detailBelow {
+		reserved += lineCount(f.detail)
+	}
+	var compact bool
+	if f.listHeight, compact = m.fitList(f.list.lines, reserved); compact {
+		f.header = m.renderHeader(true)
+	}
+	return f
+}
+
+// fitList returns the lines the list of listLines lines may use with reserved lines
+// below it, 0 if it all fits, and whether the header has to shrink for it to.
+func (m Model) fitList(listLines, reserved int) (listHeight int, compactHeader bool) {
+	if m.terminalHeight <= 0 || lineCount(m.renderHeader(false))+listLines+reserved <= m.terminalHeight {
+		return 0, false
+	}
+	if room := m.terminalHeight - lineCount(m.renderHeader(true)) - reserved; room < listLines {
+		return max(3, room), true // At least the selected tool between the indicators
+	}
+	return 0, true
+}
+
+// followCursor scrolls the list just enough to keep the selected tool in view. It runs
+// on every key press, so the list is measured with listLines rather than rendered.
+func (m *Model) followCursor() {
+	sortedTools := m.visibleTools()
+	list := m.listLines(sortedTools)
+	reserved := lineCount(m.renderFooter(sortedTools))
+	if m.showDetail && m.detailBelow() && m.cursor < len(sortedTools) {
+		detail, _ := m.renderDetailPane(sortedTools[m.cursor], "")
+		reserved += lineCount(detail)
+	}
+	height, _ := m.fitList(list.lines, reserved)
+	if height == 0 {
+		m.listTop = 0
+		return
+	}
+	m.listTop = list.scrollTop(m.listTop, height-2)
+}
+
+// listLines works out the lines of the list renderList would render, without its text.
+// All but the selected tool and the one with the tour hint take one line, so only those
+// two are rendered to count theirs.
+func (m Model) listLines(sortedTools []*tool.Tool) listLayout {
+	if len(sortedTools) == 0 && m.search != "" {
+		return listLayout{lines: 1}
+	}
+
+	gap, colWidth := tokenGap, 0 // The gap doesn't change the lines outside the grid
+	cols := m.columns()
+	if cols > 1 {
+		gap, colWidth = gridTokenGap, m.terminalWidth/cols
+	}
+	tourRow := m.tour.hintRow(sortedTools, m.cursor)
+	maxNameWidth := -1 // Measured when first needed
+	itemLines := func(i int) int {
+		if i != m.cursor && i != tourRow {
+			return 1
+		}
+		if maxNameWidth < 0 {
+			maxNameWidth = m.nameWidth(sortedTools)
+		}
+		item := m.renderToolItem(i, sortedTools[i], maxNameWidth, gap, tourRow)
+		if colWidth > 0 {
+			item = lipgloss.NewStyle().Width(colWidth).Render(item)
+		}
+		return lineCount(item)
+	}
+
+	layout := listLayout{starts: make([]int, len(sortedTools))}
+	line := 0
+	for gi, group := range m.groups() {
+		if gi > 0 && !m.compact() {
+			line++
+		}
+		headerLine := line
+		line++ // The group's header
+		for ri, row := range group.rows(cols) {
+			height := 1
+			for _, i := range row {
+				layout.starts[i] = line
+				height = max(height, itemLines(i))
+			}
+			if slices.Contains(row, m.cursor) {
+				layout.cursorStart, layout.cursorEnd = line, line+height
+				if ri == 0 {
+					layout.cursorStart = headerLine
+				}
+			}
+			line += height
+		}
+	}
+	layout.lines = line
+	return layout
+}
+
+// scrollTop returns the first line to show in a window of height lines, moved as little
+// as possible from top so the selected tool's row is in view.
+func (l listLayout) scrollTop(top, height int) int {
+	total := l.lines
+	switch {
+	case l.cursorEnd-l.cursorStart >= height || l.cursorStart < top:
+		top = l.cursorStart
+	case l.cursorEnd > top+height:
+		top = l.cursorEnd - height
+	}
+	return max(0, min(top, total-height))
+}
+
+// window returns the part of the list shown in height lines, with a line above and below
+// saying how many tools are scrolled out of view.
+func (l listLayout) window(top, height int) string {
+	lines := strings.Split(strings.TrimSuffix(l.text, "\n"), "\n")
+	height -= 2 // The indicator lines
+	top = l.scrollTop(top, height)
+	end := min(top+height, len(lines))
+
+	above, below := 0, 0
+	for _, start := range l.starts {
+		if start < top {
+			above++
+		} else if start >= end {
+			below++
+		}
+	}
+
+	var s strings.Builder
+	s.WriteString(scrollIndicator("↑", above))
+	s.WriteString("\n")
+	for _, line := range lines[top:end] {
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+	s.WriteString(scrollIndicator("↓", below))
+	s.WriteString("\n")
+	return s.String()
+}
+
+// scrollIndicator says how many tools are out of view in a direction, or nothing if none are.
+func scrollIndicator(arrow string, count int) string {
+	if count == 0 {
+		return ""
+	}
+	unit := "tools"
+	if count == 1 {
+		unit = "tool"
+	}
+	return submenuStyle.Render(fmt.Sprintf("  %s %d more %s", arrow, count, unit))
+}
+
+// lineCount returns how many lines s takes up, not counting a final newline.
+func lineCount(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(s, "\n"), "\n") + 1
+}
+
+// scrolls reports whether msg can move the cursor or change the room for the list.
+func scrolls(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.WindowSizeMsg:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// manyTools returns n tools, the ones at even indices installed.
+func manyTools(n int) []*tool.Tool {
+	tools := make([]*tool.Tool, n)
+	for i := range tools {
+		name := fmt.Sprintf("tool%02d", i)
+		tools[i] = &tool.Tool{Name: name, DisplayName: name, Command: name, Description: "Tool " + name, InstallURL: "https://example.com"}
+		tools[i].SetInstalled(i%2 == 0)
+	}
+	return tools
+}
+
+func TestListLines_MatchesRenderList(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		change func(m *Model)
+	}{
+		{"single column", 100, nil},
+		{"not installed selected", 100, func(m *Model) { m.cursor = len(m.visibleTools()) - 1 }},
+		{"compact", 100, func(m *Model) { m.settings.Density = config.DensityCompact }},
+		{"grid", 200, func(m *Model) { m.cursor = 3 }},
+		{"grid, last row", 200, func(m *Model) { m.cursor = len(m.visibleTools()) - 1 }},
+		{"detail pane", 200, func(m *Model) { m.showDetail = true }},
+		{"arguments", 100, func(m *Model) { m.args = &[]string{"--model", "x"} }},
+		{"install prompt", 100, func(m *Model) {
+			m.cursor = len(m.visibleTools()) - 1
+			m.showInstallPrompt = true
+		}},
+		{"collapsed", 100, func(m *Model) { m.collapseUninstalled = true }},
+		{"tour on the uninstalled", 100, func(m *Model) {
+			m.tour = newTour(true)
+			m.tour.step = 3 // Install
+		}},
+		{"no match", 100, func(m *Model) { m.search = "zzz" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel(t, manyTools(12)...)
+			m.resize(tt.width, 20)
+			if tt.change != nil {
+				tt.change(&m)
+			}
+			sorted := m.visibleTools()
+			want, got := m.renderList(sorted), m.listLines(sorted)
+			if got.lines != want.lines || got.cursorStart != want.cursorStart || got.cursorEnd != want.cursorEnd || !slices.Equal(got.starts, want.starts) {
+				t.Errorf("listLines() = lines %d, cursor %d-%d, starts %v\nrenderList() = lines %d, cursor %d-%d, starts %v:\n%s",
+					got.lines, got.cursorStart, got.cursorEnd, got.starts,
+					want.lines, want.cursorStart, want.cursorEnd, want.starts, want.text)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// searchStyle renders the search prompt above the list
+var searchStyle = lipgloss.NewStyle().
+	Foreground(neonCyan).
+	PaddingLeft(2)
+
+// matchesQuery reports whether the tool matches every space-separated term of query.
+// Terms starting with "#" match the start of a tag, so "#wo" already finds "#work";
+// other terms match anywhere in the name or display name.
+func matchesQuery(t *tool.Tool, query string) bool {
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if tag, ok := strings.CutPrefix(term, "#"); ok {
+			if !hasTagPrefix(t, tag) {
+				return false
+			}
+			continue
+		}
+		if !strings.Contains(strings.ToLower(t.Name), term) &&
+			!strings.Contains(strings.ToLower(t.DisplayName), term) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTagPrefix(t *tool.Tool, prefix string) bool {
+	for _, tag := range t.Tags {
+		if strings.HasPrefix(strings.ToLower(tag), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// filteredTools returns the tools matching the search query, in registry order.
+// Tools that can't be installed on this OS are left out when the settings hide them.
+func (m Model) filteredTools() []*tool.Tool {
+	if strings.TrimSpace(m.search) == "" && !m.settings.HideUnsupported {
+		return m.tools
+	}
+	var tools []*tool.Tool
+	for _, t := range m.tools {
+		if m.settings.HideUnsupported && !t.IsInstalled() && !t.SupportsPlatform() {
+			continue
+		}
+		if matchesQuery(t, m.search) {
+			tools = append(tools, t)
+		}
+	}
+	return tools
+}
+
+// updateSearch handles keys while the search prompt is open.
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		// Close the prompt and drop the filter
+		m.searching = false
+		m.search = ""
+	case tea.KeyEnter:
+		// Keep the filter and return to the list
+		m.searching = false
+		return m, nil
+	case tea.KeyBackspace:
+		if r := []rune(m.search); len(r) > 0 {
+			m.search = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.search += " "
+	case tea.KeyRunes:
+		m.search += string(msg.Runes)
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	default:
+		return m, nil
+	}
+	m.cursor = 0
+	return m, nil
+}
+
+// renderSearch renders the search prompt or the active filter.
+func (m Model) renderSearch() string {
+	if m.searching {
+		return searchStyle.Render("/ " + m.search + "▏")
+	}
+	return searchStyle.Render("/ "+m.search) + submenuStyle.Render("  (esc: clear)")
+}
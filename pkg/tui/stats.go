@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/report"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// sparkStyle renders the sparkline of the latest sessions in the stats view
+var sparkStyle = lipgloss.NewStyle().Foreground(neonCyan)
+
+// sparkLevels are the bars of a sparkline, shortest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// openStats shows the usage stats of every launched tool, read from usage.json.
+func (m Model) openStats() Model {
+	m.showStats = true
+	m.usage = config.LoadUsageStats()
+	m.projects = report.Projects(config.LoadLaunches())
+	return m
+}
+
+// statsProjects is how many projects the stats view lists, most time spent first.
+const statsProjects = 8
+
+// updateStats handles keys while the stats view is open.
+func (m Model) updateStats(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "s", "q", "esc", "enter":
+		m.showStats = false
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// sparkline draws one bar per session, scaled to the longest; sessions whose duration
+// isn't known (the launcher exec'd the tool) are drawn as "·".
+func sparkline(sessions []config.Session) string {
+	longest := 0.0
+	for _, s := range sessions {
+		longest = max(longest, s.Seconds)
+	}
+	var b strings.Builder
+	for _, s := range sessions {
+		if s.Seconds <= 0 {
+			b.WriteRune('·')
+			continue
+		}
+		level := int(s.Seconds / longest * float64(len(sparkLevels)-1))
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// averageSession returns the average duration of the sessions whose duration is known.
+func averageSession(sessions []config.Session) (time.Duration, bool) {
+	var total time.Duration
+	known := 0
+	for _, s := range sessions {
+		if s.Seconds > 0 {
+			total += s.Duration()
+			known++
+		}
+	}
+	if known == 0 {
+		return 0, false
+	}
+	return total / time.Duration(known), true
+}
+
+// renderStats renders one line per launched tool, most launched first, e.g.
+// "Codex   12 launches   3h 20m   avg 25m   last 16:22   ▂▅·█▃".
+func (m Model) renderStats() string {
+	names := make([]string, 0, len(m.usage))
+	for name, u := range m.usage {
+		if u.Launches > 0 || len(u.Sessions) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return descStyle.Render("No launches recorded yet") + "\n"
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(m.usage[b].Launches, m.usage[a].Launches); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	labels := make(map[string]string, len(names))
+	nameWidth := 0
+	for _, name := range names {
+		labels[name] = name
+		if t := m.findTool(name); t != nil {
+			labels[name] = t.DisplayName
+		}
+		nameWidth = max(nameWidth, lipgloss.Width(labels[name]))
+	}
+
+	var s strings.Builder
+	s.WriteString(groupHeaderStyle.Render("── Usage stats "+strings.Repeat("─", groupHeaderWidth-15)) + "\n")
+	for _, name := range names {
+		u := m.usage[name]
+		total, avg, last := "–", "–", "–"
+		if u.TotalSeconds > 0 {
+			total = untilText(u.Total())
+		}
+		if d, ok := averageSession(u.Sessions); ok {
+			avg = untilText(d)
+		}
+		if !u.LastUsed.IsZero() {
+			last = timefmt.DateTime(u.LastUsed)
+		}
+		launches := "launches"
+		if u.Launches == 1 {
+			launches = "launch"
+		}
+		line := fmt.Sprintf("%-*s  %4d %-8s  %8s  avg %-7s  last %-16s  ",
+			nameWidth, labels[name], u.Launches, launches, total, avg, last)
+		s.WriteString(m.fit(normalStyle).Render(line+sparkStyle.Render(sparkline(u.Sessions))) + "\n")
+	}
+	s.WriteString(m.renderProjects())
+	s.WriteString("\n" + m.fit(descStyle).Render("Session times are only known when the launcher waits for the tool (·: unknown)") + "\n")
+	return s.String()
+}
+
+// renderProjects renders one line per project the tools were launched in, most time spent
+// first, e.g. "~/src/api   8 launches   2h 5m   1.2M tokens   codex, claude".
+func (m Model) renderProjects() string {
+	projects := m.projects[:min(len(m.projects), statsProjects)]
+	if len(projects) == 0 {
+		return ""
+	}
+	pathWidth := 0
+	for _, p := range projects {
+		pathWidth = max(pathWidth, lipgloss.Width(tool.ShortenHome(p.Project)))
+	}
+
+	var s strings.Builder
+	s.WriteString("\n" + groupHeaderStyle.Render("── By project "+strings.Repeat("─", groupHeaderWidth-14)) + "\n")
+	for _, p := range projects {
+		total, tokens := "–", ""
+		if p.Seconds > 0 {
+			total = untilText(p.Duration())
+		}
+		if p.Tokens > 0 {
+			tokens = tool.UnitTokens.FormatAmount(float64(p.Tokens))
+		}
+		launches := "launches"
+		if p.Launches == 1 {
+			launches = "launch"
+		}
+		line := fmt.Sprintf("%-*s  %4d %-8s  %8s  %-12s  %s",
+			pathWidth, tool.ShortenHome(p.Project), p.Launches, launches, total, tokens, strings.Join(p.Tools, ", "))
+		s.WriteString(m.fit(normalStyle).Render(line) + "\n")
+	}
+	if more := len(m.projects) - len(projects); more > 0 {
+		s.WriteString(m.fit(descStyle).Render(fmt.Sprintf("%d more in amazing history export --format json", more)) + "\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,285 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// Theme is the set of colors the TUI is rendered with.
+type Theme struct {
+	Accent       lipgloss.Color // Selection, cursor, balances and dialog borders
+	Highlight    lipgloss.Color // Guided tour hints
+	Success      lipgloss.Color // Installed marker and success messages
+	Warning      lipgloss.Color // Warnings
+	Error        lipgloss.Color // Not-installed marker and errors
+	Text         lipgloss.Color // Tool names
+	Muted        lipgloss.Color // Descriptions, help and secondary text
+	SelectedText lipgloss.Color // Text of the selected tool, drawn on Accent
+	Surface      lipgloss.Color // Dialog background
+	Subtle       lipgloss.Color // Empty parts of balance bars and pane borders
+	Title        lipgloss.Color // Banner color; empty keeps the rainbow gradient
+
+	limitPalettes []limitBarConfig // Colors of successive limit bars; derived from the theme when empty
+}
+
+// Built-in theme names
+const (
+	ThemeCyberpunk  = "cyberpunk"
+	ThemeDracula    = "dracula"
+	ThemeLight      = "light"
+	ThemeMonochrome = "monochrome"
+	ThemeOLED       = "oled"
+)
+
+// DefaultTheme returns the cyberpunk theme the TUI uses unless told otherwise.
+func DefaultTheme() Theme {
+	return Theme{
+		Accent:        neonCyan,
+		Highlight:     neonPink,
+		Success:       neonGreen,
+		Warning:       neonYellow,
+		Error:         neonRed,
+		Text:          glowWhite,
+		Muted:         mutedText,
+		SelectedText:  lipgloss.Color("#000000"),
+		Surface:       gridDark,
+		Subtle:        gridLine,
+		limitPalettes: limitBarPalettes,
+	}
+}
+
+// builtinThemes returns the themes that can be selected by name.
+func builtinThemes() map[string]Theme {
+	return map[string]Theme{
+		ThemeCyberpunk: DefaultTheme(),
+		ThemeDracula: {
+			Accent:       "#BD93F9",
+			Highlight:    "#FF79C6",
+			Success:      "#50FA7B",
+			Warning:      "#F1FA8C",
+			Error:        "#FF5555",
+			Text:         "#F8F8F2",
+			Muted:        "#6272A4",
+			SelectedText: "#282A36",
+			Surface:      "#282A36",
+			Subtle:       "#44475A",
+			Title:        "#FF79C6",
+		},
+		ThemeLight: {
+			Accent:       "#0969DA",
+			Highlight:    "#8250DF",
+			Success:      "#1A7F37",
+			Warning:      "#9A6700",
+			Error:        "#CF222E",
+			Text:         "#1F2328",
+			Muted:        "#656D76",
+			SelectedText: "#FFFFFF",
+			Surface:      "#F6F8FA",
+			Subtle:       "#D0D7DE",
+			Title:        "#0969DA",
+		},
+		// Pure black background and dimmer text, for phone OLED screens (the Termux default)
+		ThemeOLED: {
+			Accent:       "#00C8D4",
+			Highlight:    "#D400D4",
+			Success:      "#2ECC40",
+			Warning:      "#E6C300",
+			Error:        "#E0003A",
+			Text:         "#BDBDBD",
+			Muted:        "#5A5A5A",
+			SelectedText: "#000000",
+			Surface:      "#000000",
+			Subtle:       "#1A1A1A",
+			Title:        "#00C8D4",
+		},
+		// ANSI colors only, so the terminal's own palette decides
+		ThemeMonochrome: {
+			Accent:       "15",
+			Highlight:    "15",
+			Success:      "7",
+			Warning:      "15",
+			Error:        "7",
+			Text:         "7",
+			Muted:        "8",
+			SelectedText: "0",
+			Subtle:       "8",
+			Title:        "15",
+		},
+	}
+}
+
+// ThemeNames returns the names of the built-in themes, sorted.
+func ThemeNames() []string {
+	var names []string
+	for name := range builtinThemes() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTheme resolves the theme to render with. name is a built-in theme or the path of a
+// theme file; when it is empty, the theme file at defaultPath is used if it exists and
+// the cyberpunk theme (oled in Termux) otherwise.
+func LoadTheme(name, defaultPath string) (Theme, error) {
+	if theme, ok := builtinThemes()[name]; ok {
+		return theme, nil
+	}
+	path := name
+	if path == "" {
+		if _, err := os.Stat(defaultPath); err != nil {
+			if tool.IsTermux() {
+				return builtinThemes()[ThemeOLED], nil
+			}
+			return DefaultTheme(), nil
+		}
+		path = defaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !strings.ContainsAny(name, `/\.`) {
+			return DefaultTheme(), fmt.Errorf("unknown theme %q (built-in themes: %s)", name, strings.Join(ThemeNames(), ", "))
+		}
+		return DefaultTheme(), err
+	}
+	theme, err := ParseTheme(data)
+	if err != nil {
+		return DefaultTheme(), fmt.Errorf("%s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// hexColor matches "#RGB" and "#RRGGBB" colors; ANSI colors are numbers from 0 to 255
+var hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+var ansiColor = regexp.MustCompile(`^(?:[0-9]|[1-9][0-9]|1[0-9][0-9]|2[0-4][0-9]|25[0-5])$`)
+
+// ParseTheme parses a theme file. It is a flat YAML mapping of color names to "#RRGGBB"
+// or ANSI colors, on top of the built-in theme named by "base" (cyberpunk by default):
+//
+//	base: dracula
+//	accent: "#8BE9FD"
+//	muted: 244
+func ParseTheme(data []byte) (Theme, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return Theme{}, fmt.Errorf("line %d: expected \"key: value\"", n)
+		}
+		values[strings.TrimSpace(key)] = yamlScalar(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return Theme{}, err
+	}
+
+	theme := DefaultTheme()
+	if base, ok := values["base"]; ok {
+		if theme, ok = builtinThemes()[base]; !ok {
+			return Theme{}, fmt.Errorf("unknown base theme %q (built-in themes: %s)", base, strings.Join(ThemeNames(), ", "))
+		}
+		delete(values, "base")
+	}
+
+	fields := map[string]*lipgloss.Color{
+		"accent":        &theme.Accent,
+		"highlight":     &theme.Highlight,
+		"success":       &theme.Success,
+		"warning":       &theme.Warning,
+		"error":         &theme.Error,
+		"text":          &theme.Text,
+		"muted":         &theme.Muted,
+		"selected_text": &theme.SelectedText,
+		"surface":       &theme.Surface,
+		"subtle":        &theme.Subtle,
+		"title":         &theme.Title,
+	}
+	for key, value := range values {
+		field, ok := fields[key]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown color %q", key)
+		}
+		if value != "" && !hexColor.MatchString(value) && !ansiColor.MatchString(value) {
+			return Theme{}, fmt.Errorf("%s: %q is not a \"#RRGGBB\" or ANSI (0-255) color", key, value)
+		}
+		*field = lipgloss.Color(value)
+	}
+	// Customized colors replace the cyberpunk limit bar palettes
+	if len(values) > 0 {
+		theme.limitPalettes = nil
+	}
+	return theme, nil
+}
+
+// yamlScalar returns the value of a plain or quoted YAML scalar, without a trailing comment.
+// Unlike YAML, an unquoted value may start with "#", so `accent: #FF00FF` works too.
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 0 && (s[0] == '"' || s[0] == '\'') {
+		if end := strings.IndexByte(s[1:], s[0]); end >= 0 {
+			return s[1 : end+1]
+		}
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// activeTheme is the theme applied by the last applyTheme, for colors computed while rendering.
+var activeTheme = DefaultTheme()
+
+// applyTheme recolors the package styles. Only one TUI runs at a time, so the styles
+// stay package-level and are updated whenever a TUI starts.
+func applyTheme(t Theme) {
+	activeTheme = t
+	cursorStyle = cursorStyle.Foreground(t.Accent)
+	selectedStyle = selectedStyle.Foreground(t.SelectedText).Background(t.Accent)
+	normalStyle = normalStyle.Foreground(t.Text)
+	unsupportedStyle = unsupportedStyle.Foreground(t.Muted)
+	submenuStyle = submenuStyle.Foreground(t.Muted)
+	submenuSelectedStyle = submenuSelectedStyle.Foreground(t.Accent)
+	installedStyle = installedStyle.Foreground(t.Success)
+	notInstalledStyle = notInstalledStyle.Foreground(t.Error)
+	balanceStyle = balanceStyle.Foreground(t.Accent)
+	descStyle = descStyle.Foreground(t.Muted)
+	groupHeaderStyle = groupHeaderStyle.Foreground(t.Muted)
+	searchStyle = searchStyle.Foreground(t.Accent)
+	helpStyle = helpStyle.Foreground(t.Muted)
+	dialogStyle = dialogStyle.BorderForeground(t.Accent).Background(t.Surface)
+	successMsgStyle = successMsgStyle.Foreground(t.Success)
+	errorMsgStyle = errorMsgStyle.Foreground(t.Error)
+	warningStyle = warningStyle.Foreground(t.Warning)
+	updateStyle = updateStyle.Foreground(t.Warning)
+	toastStyle = toastStyle.Foreground(t.Warning).BorderForeground(t.Warning)
+	tourStyle = tourStyle.BorderForeground(t.Highlight).Foreground(t.Text)
+	tourHeaderStyle = tourHeaderStyle.Foreground(t.Highlight)
+	installLogStyle = installLogStyle.Foreground(t.Muted).BorderForeground(t.Subtle)
+	matchStyle = matchStyle.Foreground(t.SelectedText).Background(t.Highlight)
+	detailStyle = detailStyle.BorderForeground(t.Subtle)
+	clockStyle = clockStyle.Foreground(t.Muted)
+	sparkStyle = sparkStyle.Foreground(t.Accent)
+}
+
+// limitBarColors returns the palettes of successive limit bars for the active theme.
+func limitBarColors() []limitBarConfig {
+	if len(activeTheme.limitPalettes) > 0 {
+		return activeTheme.limitPalettes
+	}
+	t := activeTheme
+	return []limitBarConfig{{labelColor: t.Accent, colors: []lipgloss.Color{t.Error, t.Warning, t.Accent, t.Success}}}
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/analytics"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/notify"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+)
+
+// toastDuration is how long a toast stays on screen.
+const toastDuration = 8 * time.Second
+
+// toastStyle renders short-lived notices above the help text
+var toastStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(neonYellow).
+	Foreground(neonYellow).
+	Padding(0, 1).
+	MarginLeft(2)
+
+// toastExpiredMsg hides the toast it was scheduled for
+type toastExpiredMsg struct {
+	id int
+}
+
+// showToast displays text as a toast and schedules hiding it.
+// A newer toast replaces the current one and its timer.
+func (m *Model) showToast(text string) tea.Cmd {
+	m.toastID++
+	m.toast = text
+	id := m.toastID
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// burnAlertCmds shows a toast for tools burning through their weekly limit and,
+// when enabled, sends desktop notifications for them.
+func (m *Model) burnAlertCmds(alerts map[string]analytics.Projection) tea.Cmd {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(alerts))
+	for name := range alerts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	var cmds []tea.Cmd
+	for _, name := range names {
+		line := fmt.Sprintf("%s: at the pace of the last %d min, the weekly limit runs out %s, before it resets",
+			name, m.settings.BurnAlerts.WindowMinutes, timefmt.Weekday(alerts[name].ExhaustsAt))
+		lines = append(lines, line)
+		if m.settings.BurnAlerts.Desktop {
+			cmds = append(cmds, sendNotification("amazing-cli: "+name+" usage", line))
+		}
+	}
+	cmds = append(cmds, m.showToast("⚠ "+strings.Join(lines, "\n⚠ ")))
+	return tea.Batch(cmds...)
+}
+
+// sendNotification sends a desktop notification in the background; failures are ignored.
+func sendNotification(title, body string) tea.Cmd {
+	return func() tea.Msg {
+		_ = notify.Send(title, body)
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// tourTarget identifies the UI element a tour hint is attached to.
+type tourTarget int
+
+const (
+	tourTargetCursor      tourTarget = iota // The currently selected row
+	tourTargetUninstalled                   // The first tool that is not installed
+	tourTargetFooter                        // Below the list, next to the help text
+)
+
+// tourStep is a single hint in the guided tour.
+type tourStep struct {
+	title       string
+	hint        string
+	target      tourTarget
+	advanceOn   []string // Keys that complete the step (in addition to tab)
+	allowLaunch bool     // Whether enter may launch an installed tool during this step
+	balances    bool     // Whether the step is about balances, left out when they are hidden
+}
+
+// tourSteps is the fixed script of the guided tour.
+var tourSteps = []tourStep{
+	{
+		title:     "Navigation",
+		hint:      "Use ↑/↓ (or j/k) to move between tools.",
+		target:    tourTargetCursor,
+		advanceOn: []string{"up", "down", "j", "k"},
+	},
+	{
+		title:  "Status",
+		hint:   "◉ means installed, ○ means not installed.\nInstalled tools come first, most recently used on top.",
+		target: tourTargetCursor,
+	},
+	{
+		title:    "Balances",
+		hint:     "The bars on the right show how much quota is left\n(for Codex: the 5h and weekly windows).",
+		target:   tourTargetCursor,
+		balances: true,
+	},
+	{
+		title:  "Install",
+		hint:   "Select a ○ tool and press enter to install it\nwithout leaving the launcher.",
+		target: tourTargetUninstalled,
+	},
+	{
+		title:     "Refresh",
+		hint:      "Installed or logged in to a tool elsewhere? Press R to check\nevery tool again and fetch fresh balances.",
+		target:    tourTargetCursor,
+		advanceOn: []string{"R"},
+	},
+	{
+		title:  "Profiles",
+		hint:   "Each tool keeps its own setup: a sets its arguments, d its\ndirectory, r its name and icon; e opens the whole config.",
+		target: tourTargetCursor,
+	},
+	{
+		title:       "Launch",
+		hint:        "Press enter on an installed tool to launch it.\nThat's it — press tab to finish the tour.",
+		target:      tourTargetFooter,
+		allowLaunch: true,
+	},
+}
+
+// tourState is the state machine driving the guided tour.
+// It is either inactive or positioned on one step; steps advance on tab or on the
+// step's own keys, and esc ends the tour early.
+type tourState struct {
+	active bool
+	step   int
+	steps  []tourStep
+}
+
+// newTour returns a tour positioned on its first step, without the balances step when
+// balances are hidden.
+func newTour(showBalances bool) tourState {
+	steps := tourSteps
+	if !showBalances {
+		steps = slices.DeleteFunc(slices.Clone(tourSteps), func(s tourStep) bool { return s.balances })
+	}
+	return tourState{active: true, steps: steps}
+}
+
+// current returns the active step, or nil when the tour is not running.
+func (t tourState) current() *tourStep {
+	if !t.active || t.step >= len(t.steps) {
+		return nil
+	}
+	return &t.steps[t.step]
+}
+
+// next advances to the following step, ending the tour after the last one.
+func (t tourState) next() tourState {
+	t.step++
+	if t.step >= len(t.steps) {
+		t.active = false
+	}
+	return t
+}
+
+// handleKey updates the tour for a key press. It returns the new state and whether the
+// key was consumed by the tour (and must not reach the normal key handling).
+func (t tourState) handleKey(key string) (tourState, bool) {
+	step := t.current()
+	if step == nil {
+		return t, false
+	}
+
+	switch key {
+	case "tab":
+		return t.next(), true
+	case "esc":
+		t.active = false
+		return t, true
+	}
+
+	for _, k := range step.advanceOn {
+		if k == key {
+			return t.next(), false
+		}
+	}
+	return t, false
+}
+
+// hintRow returns the index of the row the active hint is attached to,
+// or -1 when it should be rendered below the list.
+func (t tourState) hintRow(tools []*tool.Tool, cursor int) int {
+	step := t.current()
+	if step == nil {
+		return -1
+	}
+	switch step.target {
+	case tourTargetCursor:
+		return cursor
+	case tourTargetUninstalled:
+		for i, tl := range tools {
+			if !tl.IsInstalled() {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var (
+	tourStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(neonPink).
+			Foreground(glowWhite).
+			Padding(0, 1).
+			MarginLeft(4)
+
+	tourHeaderStyle = lipgloss.NewStyle().
+			Foreground(neonPink).
+			Bold(true)
+)
+
+// renderHint renders the hint box for the active step.
+func (t tourState) renderHint() string {
+	step := t.current()
+	if step == nil {
+		return ""
+	}
+
+	header := tourHeaderStyle.Render(fmt.Sprintf("Tour %d/%d · %s", t.step+1, len(t.steps), step.title))
+	footer := submenuStyle.Render("tab: next • esc: skip tour")
+	return tourStyle.Render(header + "\n" + step.hint + "\n" + footer)
+}
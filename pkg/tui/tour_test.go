@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestNewTour_Steps(t *testing.T) {
+	tests := []struct {
+		balances bool
+		want     []string
+	}{
+		{true, []string{"Navigation", "Status", "Balances", "Install", "Refresh", "Profiles", "Launch"}},
+		{false, []string{"Navigation", "Status", "Install", "Refresh", "Profiles", "Launch"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, s := range newTour(tt.balances).steps {
+			got = append(got, s.title)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("newTour(%v) steps = %q, want %q", tt.balances, got, tt.want)
+		}
+	}
+}
+
+func TestTour_Refresh(t *testing.T) {
+	// b was installed since the launcher started
+	a, b := installedTool("a"), &tool.Tool{Name: "b", DisplayName: "b", Command: "true"}
+	m := sized(testModel(t, a, b), 100, 30)
+	m.tour = newTour(false)
+	m = press(m, "j", "tab", "tab") // Past navigation, status and install
+	if step := m.tour.current(); step == nil || step.title != "Refresh" {
+		t.Fatalf("tour step = %+v, want Refresh", step)
+	}
+
+	next, cmd := m.Update(key("R"))
+	m = next.(Model)
+	if step := m.tour.current(); step == nil || step.title != "Profiles" {
+		t.Errorf("after R the tour step = %+v, want Profiles", step)
+	}
+	if cmd == nil {
+		t.Fatal("R during the tour didn't refresh the tools")
+	}
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(revalidatedMsg); ok {
+			next, _ = m.Update(msg)
+			m = next.(Model)
+		}
+	}
+	if !b.IsInstalled() {
+		t.Error("R didn't find the newly installed tool")
+	}
+}
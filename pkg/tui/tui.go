@@ -3,30 +3,45 @@ package tui
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
-	"sort"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/analytics"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/catalog"
 	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/report"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
 	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
 )
 
 // installCompleteMsg is sent when installation completes
 type installCompleteMsg struct {
+	name    string // Tool installed
+	version string // Version detected after an upgrade; "" after an install
 	success bool
 	err     error
 }
 
-// performInstall runs the installation in a goroutine
-func performInstall(t *tool.Tool) tea.Cmd {
+// installFunc installs or upgrades a tool, writing its output to the writer, and returns
+// the version it detected afterwards, if it looked.
+type installFunc func(io.Writer) (string, error)
+
+// performInstall runs the installation in a goroutine, writing its output to log
+func performInstall(name string, install installFunc, log *tool.TailBuffer) tea.Cmd {
 	return func() tea.Msg {
-		err := t.Install()
+		version, err := install(log)
 		return installCompleteMsg{
+			name:    name,
+			version: version,
 			success: err == nil,
 			err:     err,
 		}
@@ -54,6 +69,11 @@ var (
 			MarginTop(1).
 			MarginBottom(2)
 
+	// Cursor indicator
+	cursorStyle = lipgloss.NewStyle().
+			Foreground(neonCyan).
+			Bold(true)
+
 	// Selected Item - 赛博朋克霓虹效果
 	selectedStyle = lipgloss.NewStyle().
 			Bold(true).
@@ -68,6 +88,11 @@ var (
 			PaddingLeft(2).
 			PaddingRight(2)
 
+	// Tools that can't be installed on this OS
+	unsupportedStyle = normalStyle.
+				Foreground(mutedText).
+				Faint(true)
+
 	// Submenu Items - 无背景色，仅用前景色区分，无padding
 	submenuStyle = lipgloss.NewStyle().
 			Foreground(mutedText)
@@ -127,70 +152,315 @@ var (
 			PaddingLeft(2)
 )
 
+const (
+	tokenGap     = 20  // Space between tool names and balance bars
+	gridTokenGap = 4   // Tighter spacing used in the multi-column layout
+	gridMinWidth = 160 // Terminal width from which tools are laid out in a grid
+	gridColumns  = 2
+)
+
 // Model represents the TUI state.
 type Model struct {
-	tools             []*tool.Tool
-	cursor            int
-	promptCursor      int
-	spinner           spinner.Model
-	selected          string
-	title             string
-	quitting          bool
-	err               error
-	showInstallPrompt bool
-	installing        bool
-	installError      string
-	installSuccess    bool
-	terminalHeight    int // 终端高度，用于固定底部帮助文本
+	registry            *tool.Registry
+	tools               []*tool.Tool
+	cursor              int
+	promptCursor        int
+	spinner             spinner.Model
+	selected            string
+	title               string
+	titleHue            float64 // Where the rainbow of the banner starts, so it renders the same again
+	quitting            bool
+	err                 error
+	showInstallPrompt   bool
+	installing          bool
+	upgrading           bool // The running or finished install is an upgrade
+	installError        string
+	installLog          *tool.TailBuffer // Output of the running install
+	installPager        pager            // Install output shown in the pane
+	installRetries      []tool.Installer // Package managers left to retry the failed install with
+	installSuccess      bool
+	installWarnings     []string  // Pre-install warnings for the prompted tool (missing dependencies, old node)
+	installPlan         []string  // Commands the prompted install will run, shown before confirming
+	promptWindows       bool      // The prompt also offers launching the tool's Windows-side install (WSL)
+	tour                tourState // Guided tour overlay (inactive unless started)
+	settings            config.Settings
+	launchError         string      // Error from the last tool launched with return-to-menu
+	postMortem          *PostMortem // Tool that failed right after launch, shown as a dialog
+	postMortemCursor    int
+	lastSession         *finishedSession // The last session of a tool launched from the menu, for handing off
+	handoff             *handoffOffer    // Offer to start the tool being launched with lastSession's context
+	handoffCursor       int
+	gatheringHandoff    bool // What lastSession left is being gathered for a launch
+	theme               Theme
+	in                  io.Reader // Input for the TUI; nil means stdin
+	out                 io.Writer // Output for the TUI; nil means stdout
+	terminalHeight      int       // 终端高度，用于固定底部帮助文本
+	terminalWidth       int
+	collapseUninstalled bool                            // Hide the not installed group behind its header
+	collapsedGroups     map[string]bool                 // Other groups hidden behind their headers, by label
+	searching           bool                            // Search prompt is open and receiving keys
+	search              string                          // Search query filtering the list (e.g., "cod #work")
+	projections         map[string]analytics.Projection // Weekly limit projections by tool name
+	balancesDone        map[string]bool                 // Tools whose balance fetch has finished
+	toast               string                          // Short-lived notice, e.g. a burn-rate alert
+	toastID             int                             // Identifies the current toast's expiry timer
+	undo                []undoAction                    // Reversible actions, most recent last
+	args                *[]string                       // Extra arguments for the launched tool, shared with the caller
+	editingArgs         bool                            // Argument editor is open for the selected tool
+	argsInput           string
+	note                *string // Note the next launch is recorded with, shared with the caller
+	editingNote         bool    // Note editor is open for the selected tool
+	noteInput           string
+	renaming            bool                      // Rename editor is open for the selected tool
+	renameField         int                       // Index in renameFields being typed in
+	renameInputs        [len(renameFields)]string // Display name and icon being edited
+	dir                 *string                   // Working directory of the next launch, shared with the caller
+	choosingDir         bool                      // Directory picker is open for the selected tool
+	dirInput            string                    // Typed filter, or a path to use as is
+	dirChoices          []string                  // Directories offered by the picker
+	dirCursor           int
+	showDetail          bool                        // Detail pane for the selected tool is open (tab)
+	detailBinary        detailBinary                // Binary of the selected tool, for the detail pane
+	showStats           bool                        // Usage stats view is open (s)
+	showHistory         bool                        // Launch history view is open (H)
+	historyPager        pager                       // Launches shown in the history view
+	usage               map[string]config.ToolUsage // Usage stats, for the stats view and the detail pane
+	projects            []report.ProjectStats       // Time and tokens per project, for the stats view
+	now                 time.Time                   // Time shown in the header, updated every minute
+	listTop             int                         // First line of the tool list shown when it doesn't fit the terminal
+	discovered          []catalog.Definition        // Agents found in PATH that can be added as custom tools (+)
+	lastInput           time.Time                   // Last key press, for the idle timeout
+	suspended           bool                        // A tool launched with return-to-menu is running
+	idleQuit            bool                        // Quit by the idle timeout
+	choosingEdit        bool                        // Edit menu is open (e)
+	editCursor          int
+	reloadTools         func(config.Settings) *tool.Registry // Rebuilds the tools after the settings are edited; nil keeps them
+	filter              func(*tool.Tool) bool                // Tools listed, from WithFilter; nil lists all
+	selectOnly          bool                                 // From WithSelectOnly, kept when the settings are reloaded
 }
 
+// titleArt is the ASCII art banner above the tool list
+const titleArt = `    ___                          _                     ___ 
+   /   |  ____ ___  ____ _____  (_)___  ____ _   _____/ (_)
+  / /| | / __ ` + "`" + `__ \/ __ ` + "`" + `/_  / / / __ \/ __ ` + "`" + `/  / ___/ / / 
+ / ___ |/ / / / / / /_/ / / /_/ / / / / /_/ /  / /__/ / /  
+/_/  |_/_/ /_/ /_/\__,_/ /___/_/_/ /_/\__, /   \___/_/_/   
+                                     /____/               `
+
 // NewModel creates a new TUI model with the given tool registry.
 func NewModel(registry *tool.Registry) Model {
 	spin := spinner.New()
 	spin.Spinner = spinner.Line
 	spin.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
 	rand.Seed(time.Now().UnixNano())
-	title := `    ___                          _                     ___ 
-   /   |  ____ ___  ____ _____  (_)___  ____ _   _____/ (_)
-  / /| | / __ ` + "`" + `__ \/ __ ` + "`" + `/_  / / / __ \/ __ ` + "`" + `/  / ___/ / / 
- / ___ |/ / / / / / /_/ / / /_/ / / / / /_/ /  / /__/ / /  
-/_/  |_/_/ /_/ /_/\__,_/ /___/_/_/ /_/\__, /   \___/_/_/   
-                                     /____/               `
+	settings := config.LoadSettings()
+	tools := registry.List()
+	// Render the last fetched balances right away; they are revalidated in Init
+	seedBalances(tools, settings)
+	hue := rand.Float64() * 360.0
 	return Model{
-		tools:        registry.List(),
-		cursor:       0,
-		promptCursor: 0,
-		spinner:      spin,
-		title:        renderBlockColorTitle(title, rand.Float64()*360.0),
+		registry:            registry,
+		tools:               tools,
+		cursor:              0,
+		promptCursor:        0,
+		spinner:             spin,
+		title:               renderBlockColorTitle(titleArt, hue),
+		titleHue:            hue,
+		settings:            settings,
+		theme:               DefaultTheme(),
+		collapseUninstalled: settings.CollapseUninstalled,
+		collapsedGroups:     collapsedGroups(settings.Groups),
+		usage:               config.LoadUsageStats(),
+		now:                 time.Now(),
+		lastInput:           time.Now(),
 	}
 }
 
 // Init initializes the model (required by Bubble Tea).
+// The list renders from the last known state while tools and balances are re-validated.
 func (m Model) Init() tea.Cmd {
-	return nil
+	cmds := []tea.Cmd{revalidateTools(m.tools), checkAuth(m.tools), discoverTools(m), m.spinner.Tick, clockTick()}
+	if m.settings.ShowBalances {
+		cmds = append(cmds, fetchBalances(m.tools, m.settings.BurnAlerts))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles messages and updates the model (required by Bubble Tea).
+// The tool list is scrolled after keys and resizes to keep the cursor in view.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	updated, ok := next.(Model)
+	if !ok {
+		return next, cmd
+	}
+	updated.resolveDetail()
+	if scrolls(msg) {
+		updated.followCursor()
+	}
+	return updated, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		// 记录终端高度，用于固定底部帮助文本
-		m.terminalHeight = msg.Height
+		// 记录终端尺寸，每一帧都按新的尺寸重新布局
+		m.resize(msg.Width, msg.Height)
 		return m, nil
 
+	case clockTickMsg:
+		m.now = time.Time(msg)
+		if m.idleExpired(m.now) {
+			return m.quitIdle()
+		}
+		return m, clockTick()
+
+	case installLogTickMsg:
+		if !m.installing {
+			return m, nil
+		}
+		m.installPager.setLines(m.installLog.Lines())
+		return m, installLogTick()
+
 	case installCompleteMsg:
 		m.installing = false
+		m.installPager.setLines(m.installLog.Lines())
 		if msg.success {
 			m.installSuccess = true
 			m.installError = ""
-			// Refresh the tool's installation status by checking again
-			// This updates the checkmark in the UI
+			if t := m.findTool(msg.name); t != nil && msg.version != "" {
+				t.Version = msg.version
+			}
+			return m, m.refreshInstalled(msg.name)
 		} else {
 			m.installError = fmt.Sprintf("%v", msg.err)
 		}
 		return m, nil
 
+	case editedMsg:
+		return m.applyEdit(msg)
+
+	case authCheckedMsg:
+		m.applyAuth(msg)
+		return m, nil
+
+	case revalidatedMsg:
+		// Tools found or gone move between the groups; the cursor stays on its tool
+		var selected string
+		if tools := m.visibleTools(); m.cursor < len(tools) {
+			selected = tools[m.cursor].Name
+		}
+		for _, t := range m.tools {
+			if installed, ok := msg.installed[t.Name]; ok {
+				t.SetInstalled(installed)
+				t.Version = msg.versions[t.Name]
+			}
+		}
+		m.focusTool(selected)
+		if visible := len(m.visibleTools()); m.cursor >= visible && visible > 0 {
+			m.cursor = visible - 1 // In a collapsed group now
+		}
+		// Versions are known now, so updates can be checked
+		if m.settings.CheckUpdates {
+			return m, checkUpdates(m.tools)
+		}
+		return m, nil
+
+	case updatesMsg:
+		for _, t := range m.tools {
+			if latest, ok := msg.latest[t.Name]; ok {
+				t.Latest = latest
+			}
+		}
+		return m, nil
+
+	case balanceMsg:
+		return m, m.applyBalance(msg)
+
+	case discoveredMsg:
+		return m, m.offerDiscovered(msg)
+
+	case handoffGatheredMsg:
+		return m.offerHandoff(msg)
+
+	case copiedMsg:
+		if msg.what == "" {
+			return m, nil
+		}
+		return m, m.showToast("Copied " + msg.what)
+
+	case toastExpiredMsg:
+		if msg.id == m.toastID {
+			m.toast = ""
+		}
+		return m, nil
+
+	case toolExitedMsg:
+		// Back from a tool launched with return-to-menu: keep the cursor on it
+		m.suspended = false
+		m.lastInput = time.Now()          // The idle timeout starts over from the tool's exit
+		m.usage = config.LoadUsageStats() // With the session that just ended
+		if msg.session && !tool.IsQuickFailure(msg.err, msg.elapsed) {
+			m.lastSession = &finishedSession{name: msg.name, dir: msg.dir, start: msg.start, end: msg.start.Add(msg.elapsed)}
+		}
+		switch {
+		case tool.IsQuickFailure(msg.err, msg.elapsed):
+			m.postMortem = &PostMortem{
+				Tool:       msg.name,
+				ExitCode:   tool.ExitCode(msg.err),
+				Elapsed:    msg.elapsed,
+				StderrTail: msg.stderr,
+			}
+			m.postMortemCursor = 0
+		case msg.err != nil:
+			m.launchError = fmt.Sprintf("%s exited: %v", msg.name, msg.err)
+		}
+		m.focusTool(msg.name)
+		if t := m.findTool(msg.name); t != nil {
+			return m, checkAuth([]*tool.Tool{t}) // It may have logged in or out
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		m.lastInput = time.Now()
+
+		// The guided tour sees list keys first; it consumes its own navigation keys
+		if m.tour.active && !m.searching && !m.editingArgs && !m.editingNote && !m.renaming && !m.choosingDir && !m.showStats && !m.showHistory && !m.choosingEdit && !m.showInstallPrompt && !m.installing && !m.installSuccess && m.installError == "" {
+			var consumed bool
+			m.tour, consumed = m.tour.handleKey(msg.String())
+			if consumed {
+				return m, nil
+			}
+		}
+
+		// Search prompt receives all keys while it is open
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+
+		// So do the argument and note editors
+		if m.editingArgs {
+			return m.updateArgs(msg)
+		}
+		if m.editingNote {
+			return m.updateNote(msg)
+		}
+		if m.renaming {
+			return m.updateRename(msg)
+		}
+		if m.choosingDir {
+			return m.updateDir(msg)
+		}
+		if m.showStats {
+			return m.updateStats(msg)
+		}
+		if m.showHistory {
+			return m.updateHistory(msg)
+		}
+		if m.choosingEdit {
+			return m.updateEditMenu(msg)
+		}
+
 		// If showing install prompt
 		if m.showInstallPrompt {
 			switch msg.String() {
@@ -200,12 +470,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "down", "j":
-				if m.promptCursor < 1 {
+				if m.promptCursor < 1 || m.promptWindows && m.promptCursor < 2 {
 					m.promptCursor++
 				}
 				return m, nil
 			case "enter", "y":
-				selectedTool := m.tools[m.cursor]
+				selectedTool := m.visibleTools()[m.cursor]
 				if m.promptCursor == 0 {
 					// Cancel - close prompt
 					m.showInstallPrompt = false
@@ -213,13 +483,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.installSuccess = false
 					return m, nil
 				}
+				if m.promptCursor == 2 {
+					// Launch the Windows-side install through cmd.exe
+					m.showInstallPrompt = false
+					selectedTool.UseWindows()
+					return m.launch(selectedTool)
+				}
 				// Install (promptCursor == 1)
 				if selectedTool.HasInstallCommand() {
-					m.installing = true
-					m.showInstallPrompt = false
-					return m, tea.Batch(performInstall(selectedTool), m.spinner.Tick)
+					return m, m.startInstall(selectedTool)
 				}
-				if selectedTool.InstallURL != "" {
+				m.installPager = newPager(m.installPaneLines())
+				if !selectedTool.SupportsPlatform() {
+					m.installError = fmt.Sprintf("%s can't be installed here: %s", selectedTool.DisplayName, selectedTool.PlatformNote())
+				} else if selectedTool.InstallURL != "" {
 					m.installError = fmt.Sprintf("automated installation not available. Please visit: %s", selectedTool.InstallURL)
 				} else {
 					m.installError = "automated installation not available"
@@ -227,6 +504,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.showInstallPrompt = false
 				return m, nil
 
+			case "c":
+				// Copy what the install will run, to run it by hand or look it over
+				return m, m.copyInstall(m.visibleTools()[m.cursor], m.installPlan)
+
 			case "n", "q", "esc":
 				// Cancel installation
 				m.showInstallPrompt = false
@@ -237,6 +518,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// While installing, keys only scroll the output pane
+		if m.installing {
+			if msg.String() == "ctrl+c" {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			m.updateInstallLog(msg.String())
+			return m, nil
+		}
+
 		// If installation completed successfully, allow closing dialog
 		if m.installSuccess {
 			switch msg.String() {
@@ -247,12 +538,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// If there's an install error, allow closing dialog
+		// Post-mortem dialog for a tool that failed right after launch
+		if m.postMortem != nil {
+			return m.updatePostMortem(msg)
+		}
+		if m.handoff != nil {
+			return m.updateHandoff(msg)
+		}
+
+		// If the last launched tool failed, allow closing dialog
+		if m.launchError != "" {
+			switch msg.String() {
+			case "enter", "q", "esc":
+				m.launchError = ""
+				return m, nil
+			}
+			return m, nil
+		}
+
+		// If there's an install error, allow closing dialog, scrolling its output and retrying
 		if m.installError != "" {
+			if msg.String() == "r" && m.canRetryInstall() {
+				return m, m.retryInstall(m.visibleTools()[m.cursor])
+			}
+			if m.updateInstallLog(msg.String()) {
+				return m, nil
+			}
 			switch msg.String() {
 			case "enter", "q", "esc":
 				m.installError = ""
 				return m, nil
+			case "c":
+				// Copy the install command or URL to install it by hand
+				return m, m.copyInstall(m.visibleTools()[m.cursor], m.installPlan)
 			}
 			return m, nil
 		}
@@ -264,18 +582,110 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 
 		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
+			m.cursor = m.moveCursor(-1, 0)
 
 		case "down", "j":
-			if m.cursor < len(m.tools)-1 {
-				m.cursor++
+			m.cursor = m.moveCursor(1, 0)
+
+		case "left", "h":
+			m.cursor = m.moveCursor(0, -1)
+
+		case "right", "l":
+			m.cursor = m.moveCursor(0, 1)
+
+		case "/":
+			m.searching = true
+			return m, nil
+
+		case "esc":
+			// Clear the search filter
+			m.search = ""
+			m.cursor = 0
+
+		case "u":
+			return m, m.undoLast()
+
+		case "U":
+			// Upgrade the selected tool in place; u is undo
+			if tools := m.visibleTools(); m.cursor < len(tools) && tools[m.cursor].IsInstalled() {
+				return m, m.startUpgrade(tools[m.cursor])
+			}
+
+		case "p":
+			// Pin or unpin the selected tool
+			if tools := m.visibleTools(); m.cursor < len(tools) {
+				return m, m.togglePin(tools[m.cursor])
+			}
+
+		case "r":
+			// Rename the selected tool or change its icon, kept as an override in the config
+			if tools := m.visibleTools(); m.cursor < len(tools) {
+				m.openRename(tools[m.cursor])
 			}
 
+		case "x":
+			// Clear the selected tool's recent use
+			if tools := m.visibleTools(); m.cursor < len(tools) {
+				return m, m.clearRecentUse(tools[m.cursor])
+			}
+
+		case "L":
+			// Log the selected tool in
+			if tools := m.visibleTools(); m.cursor < len(tools) && tools[m.cursor].IsInstalled() {
+				return m.login(tools[m.cursor])
+			}
+
+		case "+":
+			// Add the agents found in PATH as custom tools
+			return m, m.addDiscovered()
+
+		case "y":
+			// Copy the selected tool's install command or URL
+			if tools := m.visibleTools(); m.cursor < len(tools) {
+				return m, m.copyInstall(tools[m.cursor], tools[m.cursor].InstallPlan())
+			}
+
+		case "tab":
+			// Show or hide the selected tool's details
+			m.showDetail = !m.showDetail
+
+		case "s":
+			// Show how much each tool has been used
+			return m.openStats(), nil
+
+		case "H":
+			// Browse the launch history; h moves left
+			return m.openHistory(), nil
+
+		case "R":
+			// Check installs, logins and balances again
+			return m, m.refreshTools()
+
+		case "e":
+			// Open the settings or the theme in the user's editor
+			m.choosingEdit, m.editCursor = true, 0
+
+		case "c":
+			// Collapse or expand the not installed group
+			m.collapseUninstalled = !m.collapseUninstalled
+			if visible := len(m.visibleTools()); m.cursor >= visible && visible > 0 {
+				m.cursor = visible - 1
+			}
+
+		case "z":
+			// Collapse the selected tool's group
+			m.toggleGroup(false)
+
+		case "Z":
+			// Expand every group
+			m.toggleGroup(true)
+
 		case "enter":
 			// User selected a tool - 需要先排序获取正确的工具
-			sortedTools := m.getSortedTools()
+			sortedTools := m.visibleTools()
+			if m.cursor >= len(sortedTools) {
+				return m, nil
+			}
 			selectedTool := sortedTools[m.cursor]
 
 			// Check if tool is installed
@@ -283,17 +693,64 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Show install prompt
 				m.showInstallPrompt = true
 				m.promptCursor = 0
+				m.installWarnings = selectedTool.PreInstallWarnings()
+				m.installPlan = selectedTool.InstallPlan()
+				m.promptWindows = selectedTool.CanLaunchOnWindows()
+				if step := m.tour.current(); step != nil && step.target == tourTargetUninstalled {
+					m.tour = m.tour.next()
+				}
+				return m, nil
+			}
+
+			// Don't launch tools in the middle of the tour
+			if step := m.tour.current(); step != nil && !step.allowLaunch {
 				return m, nil
 			}
 
 			// Tool is installed, update last used time and proceed to launch
-			selectedTool.LastUsed = time.Now()
-			m.selected = selectedTool.Name
-			return m, tea.Quit
+			return m.launch(selectedTool)
+
+		case "a":
+			// Edit the extra arguments before launching the selected tool
+			tools := m.visibleTools()
+			if m.cursor >= len(tools) || !tools[m.cursor].IsInstalled() {
+				return m, nil
+			}
+			if step := m.tour.current(); step != nil && !step.allowLaunch {
+				return m, nil
+			}
+			m.editingArgs = true
+			m.argsInput = tool.JoinArgs(m.launchArgs())
+			return m, nil
+
+		case "n":
+			// Write a note about what the selected tool is launched for, kept in the history
+			tools := m.visibleTools()
+			if m.cursor >= len(tools) || !tools[m.cursor].IsInstalled() {
+				return m, nil
+			}
+			if step := m.tour.current(); step != nil && !step.allowLaunch {
+				return m, nil
+			}
+			m.editingNote = true
+			m.noteInput = m.launchNote()
+			return m, nil
+
+		case "d":
+			// Choose the directory the selected tool starts in
+			tools := m.visibleTools()
+			if m.cursor >= len(tools) || !tools[m.cursor].IsInstalled() {
+				return m, nil
+			}
+			if step := m.tour.current(); step != nil && !step.allowLaunch {
+				return m, nil
+			}
+			return m.openDirPicker(tools[m.cursor]), nil
 		}
 	}
 
-	if m.installing {
+	// The spinner keeps ticking while installing or while balances are loading
+	if m.installing || m.anyBalanceLoading() {
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
@@ -303,163 +760,534 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 // View renders the TUI (required by Bubble Tea).
+// When everything doesn't fit the terminal, the banner shrinks and the tool list scrolls.
 func (m Model) View() string {
 	if m.quitting {
 		return ""
 	}
 
+	f := m.layoutFrame()
+	list := f.list.text
+	if f.listHeight > 0 {
+		list = f.list.window(m.listTop, f.listHeight)
+	}
+
+	if m.showHistory {
+		f.header = m.renderHeader(true) // The room historyPaneLines counts on
+	}
+
 	var s strings.Builder
+	s.WriteString(f.header)
+	switch {
+	case m.showStats:
+		s.WriteString(m.renderStats())
+	case m.showHistory:
+		s.WriteString(m.renderHistory())
+	case f.detail == "":
+		s.WriteString(list)
+	case f.detailBelow:
+		s.WriteString(list + f.detail + "\n")
+	default:
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, strings.TrimSuffix(list, "\n"), f.detail) + "\n")
+	}
+	s.WriteString(f.footer)
+	return s.String()
+}
+
+// renderHeader renders the banner, clock and search above the list; compact replaces
+// the banner with a one-line title on short terminals (always with the compact density).
+func (m Model) renderHeader(compact bool) string {
+	var s strings.Builder
+	sep := "\n\n"
+	if m.compact() {
+		compact, sep = true, "\n"
+	}
 
 	// Title
-	s.WriteString(m.title)
-	s.WriteString("\n\n")
+	title := m.renderTitle()
+	if compact && title != "" {
+		title = compactTitle()
+	}
+	if title != "" {
+		s.WriteString(title)
+		s.WriteString(sep)
+	}
+
+	s.WriteString(m.renderClock())
+	s.WriteString(sep)
+
+	if m.searching || m.search != "" {
+		s.WriteString(m.renderSearch())
+		s.WriteString(sep)
+	}
+	return s.String()
+}
+
+// renderList renders the tool list - 按安装状态分组，已安装的按LRU排序
+func (m Model) renderList(sortedTools []*tool.Tool) listLayout {
+	if len(sortedTools) == 0 && m.search != "" {
+		return listLayout{text: descStyle.Render("No tools match") + "\n", lines: 1}
+	}
 
-	// Tool list - 按安装状态分组，已安装的按LRU排序
-	sortedTools := m.getSortedTools()
+	maxNameWidth := m.nameWidth(sortedTools)
+
+	// Wide terminals lay each group out row by row in a grid
+	gap, colWidth := tokenGap, 0
+	if cols := m.columns(); cols > 1 {
+		gap, colWidth = gridTokenGap, m.terminalWidth/cols
+	} else if !m.settings.ShowBalances {
+		gap = 2 // No bars to line up: the name takes the row
+	} else if m.terminalWidth > 0 {
+		// Narrow the gap on narrow terminals so the balance bars stay on the tool's line
+		barWidth := 0
+		for _, t := range sortedTools {
+			barWidth = max(barWidth, lipgloss.Width(renderInlineBalanceBar(getToolBalance(t), m.compact())))
+		}
+		gap = max(2, min(tokenGap, m.terminalWidth-4-maxNameWidth-barWidth-1))
+	}
+	tourRow := m.tour.hintRow(sortedTools, m.cursor)
+	var list strings.Builder
+	layout := listLayout{starts: make([]int, len(sortedTools))}
+	for gi, group := range m.groups() {
+		if gi > 0 && !m.compact() {
+			list.WriteString("\n")
+		}
+		headerLine := strings.Count(list.String(), "\n")
+		list.WriteString(group.renderHeader(m.terminalWidth))
+		list.WriteString("\n")
+		for ri, row := range group.rows(m.columns()) {
+			line := strings.Count(list.String(), "\n")
+			var cells []string
+			for _, i := range row {
+				layout.starts[i] = line
+				item := m.renderToolItem(i, sortedTools[i], maxNameWidth, gap, tourRow)
+				if colWidth > 0 {
+					item = lipgloss.NewStyle().Width(colWidth).Render(item)
+				}
+				cells = append(cells, item)
+			}
+			list.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+			list.WriteString("\n")
+			if slices.Contains(row, m.cursor) {
+				layout.cursorStart, layout.cursorEnd = line, strings.Count(list.String(), "\n")
+				if ri == 0 {
+					layout.cursorStart = headerLine // Scroll the group's header into view with its first row
+				}
+			}
+		}
+	}
+	layout.text = list.String()
+	layout.lines = lineCount(layout.text)
+	return layout
+}
 
+// nameWidth returns the width of the widest tool name in the list, styles included.
+func (m Model) nameWidth(sortedTools []*tool.Tool) int {
 	maxNameWidth := 0
 	for _, t := range sortedTools {
 		// Calculate width with styles applied to account for padding
-		w := lipgloss.Width(normalStyle.Render(t.DisplayName))
-		if sw := lipgloss.Width(selectedStyle.Render(t.DisplayName)); sw > w {
+		w := lipgloss.Width(normalStyle.Render(m.listName(t)))
+		if sw := lipgloss.Width(selectedStyle.Render(m.listName(t))); sw > w {
 			w = sw
 		}
 		if w > maxNameWidth {
 			maxNameWidth = w
 		}
 	}
-	const tokenGap = 20
-	for i, t := range sortedTools {
-		isSelected := m.cursor == i
-		style := normalStyle
-
-		// Cursor indicator
-		var cursor string
-		if isSelected {
-			style = selectedStyle
-			cursor = lipgloss.NewStyle().
-				Foreground(neonCyan).
-				Bold(true).
-				Render("▶ ")
-		} else {
-			cursor = lipgloss.NewStyle().
-				Foreground(gridLine).
-				Render("  ")
-		}
-
-		// Check if tool is installed
-		var statusIcon string
-		if t.IsInstalled() {
-			statusIcon = installedStyle.Render("◉")
-		} else {
-			statusIcon = notInstalledStyle.Render("○")
-		}
-
-		// Render tool item with inline token balance
-		toolName := style.Render(t.DisplayName)
-		toolNameWidth := lipgloss.Width(toolName)
-		
-		// Get balance for this tool
-		balance := getToolBalance(t)
-		balanceBar := renderInlineBalanceBar(balance)
-		
-		// Calculate padding to align all token bars: (maxNameWidth - currentNameWidth) + fixedGap
-		padding := maxNameWidth - toolNameWidth + tokenGap
-		s.WriteString(fmt.Sprintf("%s%s %s%s%s\n", cursor, statusIcon, toolName, strings.Repeat(" ", padding), balanceBar))
-
-		// Inline install options when tool is not installed and selected - 两行箭头显示
-		if m.showInstallPrompt && m.cursor == i && !t.IsInstalled() {
-			cancelLabel := "Cancel"
-			installLabel := "Install"
-			if !t.HasInstallCommand() {
-				installLabel = "Install (N/A)"
-			}
-
-			// Cancel 行 - 选中时显示»，未选中时显示空格
-			if m.promptCursor == 0 {
-				s.WriteString(fmt.Sprintf("      %s %s\n", submenuSelectedStyle.Render("»"), submenuSelectedStyle.Render(cancelLabel)))
-			} else {
-				s.WriteString(fmt.Sprintf("       %s\n", submenuStyle.Render(cancelLabel)))
-			}
+	return maxNameWidth
+}
 
-			// Install 行 - 选中时显示»，未选中时显示空格
-			if m.promptCursor == 1 {
-				s.WriteString(fmt.Sprintf("      %s %s\n", submenuSelectedStyle.Render("»"), submenuSelectedStyle.Render(installLabel)))
-			} else {
-				s.WriteString(fmt.Sprintf("       %s\n", submenuStyle.Render(installLabel)))
-			}
-		}
+// renderFooter renders everything below the list: the tour hint, dialogs, toasts and help.
+func (m Model) renderFooter(sortedTools []*tool.Tool) string {
+	var s strings.Builder
+	tourRow := m.tour.hintRow(sortedTools, m.cursor)
+	if m.tour.active && tourRow < 0 {
+		s.WriteString("\n")
+		s.WriteString(m.tour.renderHint())
+		s.WriteString("\n")
 	}
 
 	// Show installation in progress
 	if m.installing {
 		s.WriteString("\n")
 		var dialogContent strings.Builder
-		dialogContent.WriteString(fmt.Sprintf("%s Installing...\n", m.spinner.View()))
+		verb := "Installing"
+		if m.upgrading {
+			verb = "Upgrading"
+		}
+		if bar := renderInstallProgress(m.installPager.lines); bar != "" {
+			dialogContent.WriteString(fmt.Sprintf("%s... %s\n", verb, bar))
+		} else {
+			dialogContent.WriteString(fmt.Sprintf("%s %s...\n", m.spinner.View(), verb))
+		}
 		s.WriteString(dialogStyle.Render(dialogContent.String()))
+		if pane := m.renderInstallLog(); pane != "" {
+			s.WriteString("\n")
+			s.WriteString(pane)
+			s.WriteString("\n")
+			s.WriteString(m.fit(helpStyle).Render("↑/↓: scroll output • G: follow • /: search • n/N: next/previous match"))
+		}
 		return s.String()
 	}
 
 	// Show installation success message
 	if m.installSuccess {
 		s.WriteString("\n")
-		s.WriteString(successMsgStyle.Render("✓ Installed"))
+		if m.upgrading {
+			s.WriteString(successMsgStyle.Render("✓ Upgraded"))
+		} else {
+			s.WriteString(successMsgStyle.Render("✓ Installed"))
+		}
 		s.WriteString("\n")
-		s.WriteString(helpStyle.Render("Press any key to continue"))
+		s.WriteString(m.fit(helpStyle).Render("Press any key to continue"))
+		return s.String()
+	}
+
+	// Show the post-mortem of a tool that failed right after launch
+	if m.postMortem != nil {
+		s.WriteString("\n")
+		s.WriteString(renderPostMortem(*m.postMortem, postMortemActions(m.findTool(m.postMortem.Tool)), m.postMortemCursor, m.terminalWidth))
+		s.WriteString(m.fit(helpStyle).Render("↑/↓: select • enter: confirm • esc: close"))
+		return s.String()
+	}
+
+	// Choose the file to open in the editor
+	if m.choosingEdit {
+		s.WriteString("\n")
+		s.WriteString(renderEditMenu(m.editableFiles(), m.editCursor))
+		s.WriteString(m.fit(helpStyle).Render("↑/↓: select • enter: edit • esc: cancel"))
+		return s.String()
+	}
+
+	// Ask how to hand the last session off to the tool being launched
+	if m.handoff != nil {
+		s.WriteString("\n")
+		s.WriteString(renderHandoff(*m.handoff, m.handoffCursor, m.terminalWidth))
+		s.WriteString(m.fit(helpStyle).Render("↑/↓: select • enter: confirm • esc: cancel"))
+		return s.String()
+	}
+
+	// Show the error of a tool that exited while returning to the menu
+	if m.launchError != "" {
+		s.WriteString("\n")
+		s.WriteString(m.fit(errorMsgStyle).Render("✗ " + m.launchError))
+		s.WriteString("\n")
+		s.WriteString(m.fit(helpStyle).Render("Press any key to continue"))
 		return s.String()
 	}
 
 	// Show installation error message
 	if m.installError != "" {
 		s.WriteString("\n")
-		s.WriteString(errorMsgStyle.Render("✗ Installation failed"))
+		if m.upgrading {
+			s.WriteString(m.fit(errorMsgStyle).Render("✗ Upgrade failed"))
+		} else {
+			s.WriteString(m.fit(errorMsgStyle).Render("✗ Installation failed"))
+		}
 		s.WriteString("\n")
-		s.WriteString(descStyle.Render(m.installError))
+		s.WriteString(m.fit(descStyle).Render(m.installError))
 		s.WriteString("\n")
-		s.WriteString(helpStyle.Render("Press any key to continue"))
+		retry := ""
+		if m.canRetryInstall() {
+			retry = "r: retry with " + m.installRetries[0].String() + " • "
+		}
+		if pane := m.renderInstallLog(); pane != "" {
+			s.WriteString("\n")
+			s.WriteString(pane)
+			s.WriteString("\n")
+			s.WriteString(m.fit(helpStyle).Render(retry + "↑/↓: scroll output • /: search • n/N: next/previous match • enter: continue"))
+			return s.String()
+		}
+		if text, label := installClipboardText(m.visibleTools()[m.cursor], m.installPlan); text != "" {
+			retry += "c: copy " + label + " • "
+		}
+		if retry != "" {
+			s.WriteString(m.fit(helpStyle).Render(retry + "enter: continue"))
+			return s.String()
+		}
+		s.WriteString(m.fit(helpStyle).Render("Press any key to continue"))
 		return s.String()
 	}
 
+	if m.toast != "" {
+		s.WriteString("\n")
+		s.WriteString(m.fit(toastStyle).Render(m.toast))
+		s.WriteString("\n")
+	}
+
 	// Help text
 	s.WriteString("\n")
 	if m.showInstallPrompt {
-		s.WriteString(helpStyle.Render("↑/↓: select • enter: confirm • esc: cancel"))
+		copyHelp := ""
+		if _, label := installClipboardText(m.visibleTools()[m.cursor], m.installPlan); label != "" {
+			copyHelp = " • c: copy " + label
+		}
+		s.WriteString(m.fit(helpStyle).Render("↑/↓: select • enter: confirm" + copyHelp + " • esc: cancel"))
+	} else if m.searching {
+		s.WriteString(m.fit(helpStyle).Render("type to filter, #tag for tags • enter: apply • esc: clear"))
+	} else if m.editingArgs {
+		s.WriteString(m.fit(helpStyle).Render("arguments appended to the tool's own • enter: launch • esc: cancel"))
+	} else if m.editingNote {
+		s.WriteString(m.fit(helpStyle).Render("what this session is for, kept in the history • enter: launch • esc: cancel"))
+	} else if m.renaming {
+		s.WriteString(m.fit(helpStyle).Render("tab: name/icon • empty or unchanged keeps the tool's own • enter: save • esc: cancel"))
+	} else if m.choosingDir {
+		s.WriteString(m.fit(helpStyle).Render("type to filter or enter a path • ↑/↓: select • enter: launch • esc: cancel"))
+	} else if m.showStats {
+		s.WriteString(m.fit(helpStyle).Render("s/esc: back to the tools"))
+	} else if m.showHistory && m.historyPager.searching {
+		s.WriteString(m.fit(helpStyle).Render("type to search • enter: find • esc: cancel"))
+	} else if m.showHistory {
+		s.WriteString(m.fit(helpStyle).Render("↑/↓: scroll • /: search • n/N: next/previous match • H/esc: back to the tools"))
+	} else if m.columns() > 1 {
+		s.WriteString(m.fit(helpStyle).Render("↑/↓/←/→: navigate • enter: launch • a: args • n: note • d: dir • /: search • tab: details • s: stats • H: history • R: refresh • e: edit config • p: pin • r: rename • x: clear recent • u: undo • c: collapse • z: fold group • q: quit"))
 	} else {
-		s.WriteString(helpStyle.Render("↑/↓: navigate • enter: launch • q: quit"))
+		s.WriteString(m.fit(helpStyle).Render("↑/↓: navigate • enter: launch • a: args • n: note • d: dir • /: search • tab: details • s: stats • H: history • R: refresh • e: edit config • p: pin • r: rename • x: clear recent • u: undo • c: collapse • z: fold group • q: quit"))
 	}
 
 	return s.String()
 }
 
-// GetSelected returns the name of the selected tool, if any.
-func (m Model) GetSelected() string {
-	return m.selected
+// listName is the name of the tool in the list, after its icon. When some tools have
+// icons, the names of the others are indented as if they had one, to keep them aligned.
+func (m Model) listName(t *tool.Tool) string {
+	switch {
+	case t.Icon != "":
+		return t.Icon + " " + t.DisplayName
+	case slices.ContainsFunc(m.tools, func(t *tool.Tool) bool { return t.Icon != "" }):
+		return "  " + t.DisplayName
+	}
+	return t.DisplayName
 }
 
-// getSortedTools returns tools sorted by installation status and LRU (最近使用的在前)
-func (m Model) getSortedTools() []*tool.Tool {
-	sorted := make([]*tool.Tool, len(m.tools))
-	copy(sorted, m.tools)
+// renderToolItem renders the list entry for the tool at index i, including its inline
+// install prompt and tour hint. gap is the space between the name and the balance bar.
+func (m Model) renderToolItem(i int, t *tool.Tool, maxNameWidth, gap, tourRow int) string {
+	isSelected := m.cursor == i
+	style := normalStyle
+
+	// Cursor indicator
+	var cursor string
+	if isSelected {
+		style = selectedStyle
+		cursor = cursorStyle.Render("▶ ")
+	} else {
+		cursor = lipgloss.NewStyle().
+			Foreground(activeTheme.Subtle).
+			Render("  ")
+	}
+
+	// Check if tool is installed
+	var statusIcon string
+	if t.IsInstalled() {
+		statusIcon = installedStyle.Render("◉")
+	} else {
+		statusIcon = notInstalledStyle.Render("○")
+	}
+
+	// Tools that can't run on this OS are grayed out, with where they do run instead of a balance
+	unsupported := !t.IsInstalled() && !t.SupportsPlatform()
+	if unsupported && !isSelected {
+		style = unsupportedStyle
+	}
+
+	// Render tool item with inline token balance
+	toolName := style.Render(m.listName(t))
+	toolNameWidth := lipgloss.Width(toolName)
+
+	// Get balance for this tool
+	balance := getToolBalance(t)
+	balanceBar := renderInlineBalanceBar(balance, m.compact())
+	if !m.settings.ShowBalances {
+		balanceBar = ""
+	}
+	if unsupported {
+		balanceBar = descStyle.Render(t.PlatformNote())
+	} else if m.balanceLoading(t) {
+		if t.Balance == nil {
+			// Nothing to show until the first fetch comes back
+			balanceBar = descStyle.Render("loading…")
+		}
+		balanceBar = m.spinner.View() + " " + balanceBar
+	}
+
+	// Calculate padding to align all token bars: (maxNameWidth - currentNameWidth) + fixedGap
+	padding := maxNameWidth - toolNameWidth + gap
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("%s%s %s%s%s", cursor, statusIcon, toolName, strings.Repeat(" ", padding), balanceBar))
+	if badge := authBadge(t); badge != "" {
+		s.WriteString("  " + badge)
+	}
+	if t.IsInstalled() && t.UpdateAvailable() {
+		s.WriteString("  " + updateStyle.Render("↑ update available"))
+	}
+
+	// Extra arguments the selected tool will be launched with
+	if isSelected && t.IsInstalled() && (m.editingArgs || len(m.launchArgs()) > 0) {
+		s.WriteString("\n  " + m.renderArgs())
+	}
+
+	// Note the selected tool will be launched with
+	if isSelected && t.IsInstalled() && (m.editingNote || m.launchNote() != "") {
+		s.WriteString("\n  " + m.renderNote())
+	}
+
+	// Display name and icon being edited
+	if isSelected && m.renaming {
+		s.WriteString("\n  " + m.renderRename())
+	}
+
+	// Directory the selected tool will be launched in
+	if isSelected && t.IsInstalled() && (m.choosingDir || m.launchDir() != "") {
+		s.WriteString("\n  " + m.renderDir())
+	}
+
+	// Details of the selected tool, left out with the compact density
+	details := isSelected && !m.showInstallPrompt && !m.compact()
+
+	// What the selected tool is, and what's wrong with it, for tools one hasn't used before
+	if line := toolSummary(t, m.settings.ShowBalances); details && line != "" && !unsupported {
+		s.WriteString(fmt.Sprintf("\n    %s", descStyle.Render(line)))
+	}
+
+	// Version change an upgrade of the selected tool brings
+	if details && t.IsInstalled() && t.UpdateAvailable() {
+		s.WriteString(fmt.Sprintf("\n    %s", descStyle.Render(fmt.Sprintf("%s → %s • U: upgrade", t.InstalledVersion(), t.Latest))))
+	}
+
+	// Tags of the selected tool
+	if details && len(t.Tags) > 0 {
+		s.WriteString(fmt.Sprintf("\n    %s", descStyle.Render("#"+strings.Join(t.Tags, " #"))))
+	}
+
+	// What the selected tool's short window was spent on
+	if details && t.Balance != nil && len(t.Balance.Breakdown) > 0 {
+		s.WriteString(fmt.Sprintf("\n    %s", descStyle.Render(renderBreakdown(t.Balance.Breakdown))))
+	}
+
+	// Warn when the weekly limit will run out before it resets
+	if p, ok := m.projections[t.Name]; ok && details && p.BeforeReset {
+		s.WriteString(fmt.Sprintf("\n  %s", warningStyle.Render("Weekly: on pace to run out "+timefmt.Weekday(p.ExhaustsAt))))
+	}
 
-	sort.SliceStable(sorted, func(i, j int) bool {
-		installedI := sorted[i].IsInstalled()
-		installedJ := sorted[j].IsInstalled()
+	// Inline install options when tool is not installed and selected - 两行箭头显示
+	if m.showInstallPrompt && m.cursor == i && !t.IsInstalled() {
+		cancelLabel := "Cancel"
+		installLabel := "Install"
+		if !t.HasInstallCommand() {
+			installLabel = "Install (N/A)"
+		}
+
+		// Cancel 行 - 选中时显示»，未选中时显示空格
+		if m.promptCursor == 0 {
+			s.WriteString(fmt.Sprintf("\n      %s %s", submenuSelectedStyle.Render("»"), submenuSelectedStyle.Render(cancelLabel)))
+		} else {
+			s.WriteString(fmt.Sprintf("\n       %s", submenuStyle.Render(cancelLabel)))
+		}
+
+		// Install 行 - 选中时显示»，未选中时显示空格
+		if m.promptCursor == 1 {
+			s.WriteString(fmt.Sprintf("\n      %s %s", submenuSelectedStyle.Render("»"), submenuSelectedStyle.Render(installLabel)))
+		} else {
+			s.WriteString(fmt.Sprintf("\n       %s", submenuStyle.Render(installLabel)))
+		}
 
-		// 如果安装状态不同，已安装的排在前面
-		if installedI != installedJ {
-			return installedI && !installedJ
+		if m.promptWindows {
+			windowsLabel := "Launch Windows version (cmd.exe)"
+			if m.promptCursor == 2 {
+				s.WriteString(fmt.Sprintf("\n      %s %s", submenuSelectedStyle.Render("»"), submenuSelectedStyle.Render(windowsLabel)))
+			} else {
+				s.WriteString(fmt.Sprintf("\n       %s", submenuStyle.Render(windowsLabel)))
+			}
 		}
 
-		// 如果都已安装，按最后使用时间降序排序（最近使用的在前）
-		if installedI && installedJ {
-			return sorted[i].LastUsed.After(sorted[j].LastUsed)
+		for _, warning := range m.installWarnings {
+			s.WriteString(fmt.Sprintf("\n    %s", warningStyle.Render("⚠ "+warning)))
 		}
 
-		// 都未安装，保持原有顺序
-		return false
-	})
+		// What Install runs, since it may well pipe curl to bash
+		for _, step := range m.installPlan {
+			if !strings.HasPrefix(step, "# ") {
+				step = "$ " + step
+			}
+			s.WriteString(fmt.Sprintf("\n    %s", descStyle.Render(step)))
+		}
+	}
+
+	if tourRow == i {
+		s.WriteString("\n")
+		s.WriteString(m.tour.renderHint())
+	}
+
+	return s.String()
+}
+
+// updatePostMortem handles keys while the post-mortem dialog is open.
+func (m Model) updatePostMortem(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t := m.findTool(m.postMortem.Tool)
+	actions := postMortemActions(t)
+
+	switch msg.String() {
+	case "up", "k":
+		if m.postMortemCursor > 0 {
+			m.postMortemCursor--
+		}
+	case "down", "j":
+		if m.postMortemCursor < len(actions)-1 {
+			m.postMortemCursor++
+		}
+	case "esc", "q":
+		m.postMortem = nil
+	case "enter":
+		action := actions[m.postMortemCursor]
+		m.postMortem = nil
+		if action.close || t == nil {
+			return m, nil
+		}
+		if !action.login {
+			markLaunched(t)
+			cmd := m.launchTool(t, m.launchOptions(), m.launchNote())
+			return m, cmd
+		}
+		return m.login(t)
+	}
+	return m, nil
+}
+
+// findTool returns the tool with the given name, or nil if it isn't in the list.
+func (m Model) findTool(name string) *tool.Tool {
+	for _, t := range m.tools {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// focusTool moves the cursor onto the named tool, wherever the list's order put it.
+func (m *Model) focusTool(name string) {
+	for i, t := range m.visibleTools() {
+		if t.Name == name {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// GetSelected returns the name of the selected tool, if any.
+func (m Model) GetSelected() string {
+	return m.selected
+}
+
+// getSortedTools returns tools sorted by installation status and LRU (最近使用的在前),
+// pinned tools first
+func (m Model) getSortedTools() []*tool.Tool {
+	return tool.Pin(sortTools(m.tools, m.settings.Ranking.Order), m.settings.Pinned)
+}
 
-	return sorted
+// sortTools returns a copy of tools in display order, by frecency with config.RankFrecency
+func sortTools(tools []*tool.Tool, order string) []*tool.Tool {
+	return tool.Sort(tools, order == config.RankFrecency)
 }
 
 // getToolBalance returns the balance for a given tool.
@@ -473,18 +1301,21 @@ func getToolBalance(t *tool.Tool) tool.Balance {
 }
 
 // renderInlineBalanceBar creates a compact visual representation of the token balance.
-// For Codex, it shows both 5h and weekly limits with sophisticated styling.
-func renderInlineBalanceBar(balance tool.Balance) string {
-	// Check if this is Codex with dual limits
-	hasBothLimits := balance.FiveHourLimit.Display != "" || balance.WeeklyLimit.Display != ""
-	
-	if hasBothLimits {
-		return renderDualLimitBar(balance)
-	}
-	
+// Tools with limit windows (e.g., Codex 5h and weekly) get one bar per window. narrow
+// draws shorter bars, for the compact density.
+func renderInlineBalanceBar(balance tool.Balance, narrow bool) string {
+	for _, limit := range balance.Limits {
+		if limit.Display != "" {
+			return renderLimitBars(balance.Limits, narrow) + renderCredits(balance.Credits)
+		}
+	}
+
 	// Original single limit display
 	width := 15
-	percentage := balance.Percentage
+	if narrow {
+		width = 8
+	}
+	percentage, hasBar := balance.RemainingPercent()
 	if percentage < 0 {
 		percentage = 0
 	}
@@ -492,32 +1323,41 @@ func renderInlineBalanceBar(balance tool.Balance) string {
 		percentage = 100
 	}
 
+	labelStyle := lipgloss.NewStyle().
+		Foreground(activeTheme.Accent).
+		Bold(true)
+
+	// Percentages read "Token: 80%"; absolute balances show their amount ("120/300 requests", "$4.50")
+	label := labelStyle.Render(fmt.Sprintf("Token: %s", balance.Display))
+	if balance.Unit != tool.UnitPercent {
+		label = labelStyle.Render(balance.AmountDisplay())
+	}
+	if !hasBar {
+		// No allowance to compare against, e.g. prepaid credit: the number is all there is
+		return label
+	}
+
 	filled := (width * percentage) / 100
 	empty := width - filled
 
 	filledBar := strings.Repeat("█", filled)
 	emptyBar := strings.Repeat("░", empty)
 
+	// The color follows the number drawn, not the provider
 	var barColor lipgloss.Color
-	switch balance.Color {
+	switch balance.Color() {
 	case "green":
-		barColor = neonGreen
+		barColor = activeTheme.Success
 	case "yellow":
-		barColor = neonYellow
+		barColor = activeTheme.Warning
 	case "red":
-		barColor = neonRed
+		barColor = activeTheme.Error
 	default:
-		barColor = neonGreen
+		barColor = activeTheme.Success
 	}
 
 	barStyle := lipgloss.NewStyle().Foreground(barColor)
-	emptyStyle := lipgloss.NewStyle().Foreground(gridLine)
-
-	labelStyle := lipgloss.NewStyle().
-		Foreground(neonCyan).
-		Bold(true)
-
-	label := labelStyle.Render(fmt.Sprintf("Token: %s", balance.Display))
+	emptyStyle := lipgloss.NewStyle().Foreground(activeTheme.Subtle)
 	barStr := barStyle.Render(filledBar) + emptyStyle.Render(emptyBar)
 
 	return fmt.Sprintf("%s %s", label, barStr)
@@ -536,7 +1376,7 @@ func renderLimitBar(limit tool.LimitDetail, barWidth int, cfg limitBarConfig) st
 		return ""
 	}
 
-	percentage := limit.Percentage
+	percentage := limit.RemainingPercent
 	if percentage < 0 {
 		percentage = 0
 	} else if percentage > 100 {
@@ -558,7 +1398,7 @@ func renderLimitBar(limit tool.LimitDetail, barWidth int, cfg limitBarConfig) st
 
 	filled := (barWidth * percentage) / 100
 	filledBar := lipgloss.NewStyle().Foreground(barColor).Bold(true).Render(strings.Repeat("█", filled))
-	emptyBar := lipgloss.NewStyle().Foreground(lipgloss.Color("#2A2A3E")).Render(strings.Repeat("░", barWidth-filled))
+	emptyBar := lipgloss.NewStyle().Foreground(activeTheme.Subtle).Render(strings.Repeat("░", barWidth-filled))
 	label := lipgloss.NewStyle().Foreground(cfg.labelColor).Bold(true).Render(cfg.label)
 
 	// Build percentage string
@@ -574,32 +1414,63 @@ func renderLimitBar(limit tool.LimitDetail, barWidth int, cfg limitBarConfig) st
 	return fmt.Sprintf("%s:%s%s %s", label, filledBar, emptyBar, lipgloss.NewStyle().Foreground(barColor).Render(percentStr))
 }
 
-// renderDualLimitBar creates a sophisticated dual-limit display for Codex.
-func renderDualLimitBar(balance tool.Balance) string {
+// limitBarPalettes are the colors of successive limit bars; further limits reuse them in turn.
+var limitBarPalettes = []limitBarConfig{
+	{labelColor: "#8BE9FD", colors: []lipgloss.Color{"#FF0040", "#FFB000", "#00D9FF", "#00FF88"}},
+	{labelColor: "#BD93F9", colors: []lipgloss.Color{"#FF1493", "#FF69B4", "#9D00FF", "#00FFD4"}},
+	{labelColor: "#F1FA8C", colors: []lipgloss.Color{"#FF5555", "#FFB86C", "#F1FA8C", "#50FA7B"}},
+}
+
+// renderLimitBars lays out one compact bar per limit window, narrower when there are many
+// or with narrow.
+func renderLimitBars(limits []tool.LimitDetail, narrow bool) string {
 	barWidth := 10
+	if len(limits) > 2 {
+		barWidth = 6
+	}
+	if narrow {
+		barWidth /= 2
+	}
 
-	fiveHourBar := renderLimitBar(balance.FiveHourLimit, barWidth, limitBarConfig{
-		label:      "5h",
-		labelColor: lipgloss.Color("#8BE9FD"),
-		colors:     []lipgloss.Color{"#FF0040", "#FFB000", "#00D9FF", "#00FF88"},
-	})
+	palettes := limitBarColors()
+	var bars []string
+	for i, limit := range limits {
+		cfg := palettes[i%len(palettes)]
+		cfg.label = limit.Label
+		if bar := renderLimitBar(limit, barWidth, cfg); bar != "" {
+			bars = append(bars, bar)
+		}
+	}
+	return strings.Join(bars, "  ")
+}
 
-	weeklyBar := renderLimitBar(balance.WeeklyLimit, barWidth, limitBarConfig{
-		label:      "Wk",
-		labelColor: lipgloss.Color("#BD93F9"),
-		colors:     []lipgloss.Color{"#FF1493", "#FF69B4", "#9D00FF", "#00FFD4"},
-	})
+// renderCredits shows the credits left after the limit bars, e.g. "  Cr:1,234.56", or
+// nothing without credits.
+func renderCredits(credits string) string {
+	if credits == "" {
+		return ""
+	}
+	// Colored like a third limit bar
+	palettes := limitBarColors()
+	label := lipgloss.NewStyle().Foreground(palettes[2%len(palettes)].labelColor).Bold(true).Render("Cr")
+	return fmt.Sprintf("  %s:%s", label, lipgloss.NewStyle().Foreground(activeTheme.Text).Render(credits))
+}
 
-	switch {
-	case fiveHourBar != "" && weeklyBar != "":
-		return fiveHourBar + "  " + weeklyBar
-	case fiveHourBar != "":
-		return fiveHourBar
-	case weeklyBar != "":
-		return weeklyBar
-	default:
-		return renderInlineBalanceBar(balance)
+// renderBreakdown describes the split of usage by model, e.g. "5h by model: o3 72% · o4-mini 28%".
+func renderBreakdown(shares []tool.UsageShare) string {
+	var total int64
+	for _, share := range shares {
+		total += share.Tokens
 	}
+	if total == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(shares))
+	for _, share := range shares {
+		parts = append(parts, fmt.Sprintf("%s %d%%", share.Label, int(math.Round(float64(share.Tokens)*100/float64(total)))))
+	}
+	return "5h by model: " + strings.Join(parts, " · ")
 }
 
 func renderBlockColorTitle(text string, hueOffset float64) string {
@@ -730,12 +1601,38 @@ func hslToRGB(h, s, l float64) (uint8, uint8, uint8) {
 }
 
 // Run starts the TUI and returns the selected tool name.
-func Run(registry *tool.Registry) (string, error) {
+func Run(registry *tool.Registry, opts ...Option) (string, error) {
 	model := NewModel(registry)
-	p := tea.NewProgram(model)
+	for _, opt := range opts {
+		opt(&model)
+	}
+	return run(model)
+}
+
+func run(model Model) (string, error) {
+	in, out := model.in, model.output()
+	if in == nil {
+		in = os.Stdin
+	}
+
+	if !canStartTUI() {
+		return runTextMenu(model, in, out)
+	}
+
+	model.useColors(out)
+	opts := []tea.ProgramOption{tea.WithInput(in), tea.WithOutput(out)}
+	if model.settings.AltScreen {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(model, opts...)
 
 	finalModel, err := p.Run()
 	if err != nil {
+		// Fall back to a plain numbered menu when the terminal can't host the TUI
+		if isStartupError(err) {
+			fmt.Fprintf(os.Stderr, "Warning: TUI unavailable (%v), falling back to text menu\n", err)
+			return runTextMenu(model, in, out)
+		}
 		return "", fmt.Errorf("error running TUI: %w", err)
 	}
 
@@ -743,5 +1640,14 @@ func Run(registry *tool.Registry) (string, error) {
 	if !ok {
 		return "", fmt.Errorf("unexpected model type returned from TUI")
 	}
+
+	if m.idleQuit {
+		fmt.Fprintf(out, "Quit after %s without input (idle.timeout_minutes)\n", untilText(m.settings.Idle.Timeout()))
+	}
+
+	// Persist what we learned so the next start can render instantly (non-fatal)
+	if err := config.SaveSnapshot(config.TakeSnapshot(m.tools)); err != nil {
+		log.Warn("saving the registry snapshot failed", "err", err)
+	}
 	return m.GetSelected(), nil
 }
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// testModel returns a launcher over tools, with $HOME in a temporary directory so that
+// nothing the keys save reaches the real settings.
+func testModel(t *testing.T, tools ...*tool.Tool) Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	registry := tool.NewRegistry()
+	for _, tl := range tools {
+		registry.Register(tl)
+	}
+	return NewModel(registry)
+}
+
+// installedTool returns an installed tool called name.
+func installedTool(name string) *tool.Tool {
+	t := &tool.Tool{Name: name, DisplayName: name, Command: name}
+	t.SetInstalled(true)
+	return t
+}
+
+// keyMsgs are the special keys press understands; anything else is typed as runes.
+var keyMsgs = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"backspace": tea.KeyBackspace,
+	"space":     tea.KeySpace,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+}
+
+// key returns the message of pressing k.
+func key(k string) tea.KeyMsg {
+	if typ, ok := keyMsgs[k]; ok {
+		return tea.KeyMsg{Type: typ}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// press sends the keys to m one after another, dropping the commands they return.
+func press(m Model, keys ...string) Model {
+	for _, k := range keys {
+		next, _ := m.Update(key(k))
+		m = next.(Model)
+	}
+	return m
+}
+
+// errTest is an error for the tests to fail things with.
+var errTest = errors.New("test error")
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// maxUndo is how many reversible actions are remembered.
+const maxUndo = 20
+
+// undoAction reverts one state change made from the menu.
+type undoAction struct {
+	label string               // What was done, e.g. "Cleared recent use of Codex"
+	undo  func(m *Model) error // Restores the previous state on the current model
+}
+
+// pushUndo remembers a reversible action and confirms it with a toast.
+func (m *Model) pushUndo(action undoAction) tea.Cmd {
+	m.undo = append(m.undo, action)
+	if len(m.undo) > maxUndo {
+		m.undo = m.undo[len(m.undo)-maxUndo:]
+	}
+	return m.showToast(action.label + " • u: undo")
+}
+
+// undoLast reverts the most recent action, if any.
+func (m *Model) undoLast() tea.Cmd {
+	if len(m.undo) == 0 {
+		return m.showToast("Nothing to undo")
+	}
+	action := m.undo[len(m.undo)-1]
+	m.undo = m.undo[:len(m.undo)-1]
+	if err := action.undo(m); err != nil {
+		return m.showToast(fmt.Sprintf("Undo failed: %v", err))
+	}
+	return m.showToast("Undone: " + action.label)
+}
+
+// clearRecentUse forgets when t was last used, moving it out of the recent order.
+func (m *Model) clearRecentUse(t *tool.Tool) tea.Cmd {
+	if t.LastUsed.IsZero() {
+		return m.showToast(t.DisplayName + " has no recent use to clear")
+	}
+	if err := config.ClearToolUsage(t.Name); err != nil {
+		return m.showToast(fmt.Sprintf("Failed to clear recent use: %v", err))
+	}
+
+	lastUsed, frecency := t.LastUsed, t.Frecency
+	t.LastUsed, t.Frecency = time.Time{}, 0
+	m.followTool(t)
+	return m.pushUndo(undoAction{
+		label: "Cleared recent use of " + t.DisplayName,
+		undo: func(m *Model) error {
+			if err := config.RecordToolUsage(t.Name, lastUsed); err != nil {
+				return err
+			}
+			t.LastUsed, t.Frecency = lastUsed, frecency
+			m.followTool(t)
+			return nil
+		},
+	})
+}
+
+// followTool keeps the cursor on t after the list order changed.
+func (m *Model) followTool(t *tool.Tool) {
+	for i, v := range m.visibleTools() {
+		if v == t {
+			m.cursor = i
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/log"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// updateStyle marks tools with a newer version available
+var updateStyle = lipgloss.NewStyle().Foreground(neonYellow)
+
+// updatesMsg carries the latest published version of the tools, keyed by name
+type updatesMsg struct {
+	latest map[string]string
+}
+
+// checkUpdates looks up the latest version of the installed tools in the background.
+// Versions checked within the last day are taken from the cache instead of the registries.
+// Tools are only read here; the results are applied in Update.
+func checkUpdates(tools []*tool.Tool) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		now := time.Now()
+		checks := config.LoadUpdateChecks()
+		msg := updatesMsg{latest: make(map[string]string, len(tools))}
+		changed := false
+		for _, t := range tools {
+			if !t.IsInstalled() || !t.ChecksUpdates() {
+				continue
+			}
+			if check, ok := checks[t.Name]; ok && check.Fresh(now) {
+				msg.latest[t.Name] = check.Latest
+				continue
+			}
+			latest, err := t.LatestVersion(ctx)
+			if err != nil {
+				// Offline or unpublished: try again next time
+				log.Debug("update check failed", "tool", t.Name, "err", err)
+				continue
+			}
+			checks[t.Name] = config.UpdateCheck{Latest: latest, CheckedAt: now}
+			msg.latest[t.Name] = latest
+			changed = true
+		}
+		if changed {
+			if err := config.SaveUpdateChecks(checks); err != nil {
+				log.Warn("saving update checks failed", "err", err) // Non-fatal
+			}
+		}
+		return msg
+	}
+}
+
+// startUpgrade upgrades t in the background, streaming its output into the install pane.
+func (m *Model) startUpgrade(t *tool.Tool) tea.Cmd {
+	m.installPlan = nil // Not the plan of an install prompted before
+	return m.runInstall(t, t.UpgradeWithOutput, true)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/report"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+)
+
+// runResets lists the upcoming limit resets of the tools with a balance provider, or
+// exports them as an iCalendar file with --ics, and returns the process exit code.
+func runResets(args []string) int {
+	fs := flag.NewFlagSet("resets", flag.ContinueOnError)
+	ics := fs.Bool("ics", false, "write the resets as an iCalendar file for calendar apps")
+	output := fs.String("output", "", "file to write instead of stdout")
+	all := fs.Bool("all", false, "also include tools whose CLI isn't installed, from their credentials alone")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	settings := config.LoadSettings()
+	timefmt.Set(settings.DateTimeFormat())
+	registry := loadRegistry(settings)
+	now := time.Now()
+	resets := report.Resets(usageEntries(context.Background(), registry, *all), now)
+
+	var buf bytes.Buffer
+	var err error
+	if *ics {
+		err = report.WriteICS(&buf, resets, now)
+	} else {
+		err = report.WriteResets(&buf, resets)
+	}
+	if err == nil {
+		if *output != "" && *output != "-" {
+			err = os.WriteFile(*output, buf.Bytes(), 0644)
+		} else {
+			_, err = os.Stdout.Write(buf.Bytes())
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
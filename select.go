@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// What happens after a tool is selected (--on-select)
+const (
+	onSelectExec  = "exec"  // Launch the tool (default)
+	onSelectPrint = "print" // Print the tool name and exit
+	onSelectJSON  = "json"  // Print a JSON description of the launch and exit
+)
+
+// selection describes the launch a wrapper should perform for --on-select json.
+type selection struct {
+	Name    string   `json:"name"`
+	Command string   `json:"command"` // Absolute path when found in PATH
+	Args    []string `json:"args"`    // Configured arguments followed by the extra ones
+	Cwd     string   `json:"cwd"`
+}
+
+// validOnSelect reports whether mode is a known --on-select value.
+func validOnSelect(mode string) bool {
+	switch mode {
+	case onSelectExec, onSelectPrint, onSelectJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeSelection prints the selected tool for wrappers, in the form given by mode, with dir
+// as the directory to start it in.
+func writeSelection(w io.Writer, mode string, t *tool.Tool, extraArgs []string, dir string) error {
+	if mode == onSelectPrint {
+		_, err := fmt.Fprintln(w, t.Name)
+		return err
+	}
+
+	sel := selection{
+		Name:    t.Name,
+		Command: t.Command,
+		Args:    append(append([]string{}, t.Args...), extraArgs...),
+		Cwd:     dir,
+	}
+	if path, err := t.Path(); err == nil {
+		sel.Command = path
+	}
+	enc := json.NewEncoder(w)
+	return enc.Encode(sel)
+}
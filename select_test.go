@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+func TestValidOnSelect(t *testing.T) {
+	tests := []struct {
+		mode string
+		want bool
+	}{
+		{onSelectExec, true},
+		{onSelectPrint, true},
+		{onSelectJSON, true},
+		{"", false},
+		{"JSON", false},
+		{"launch", false},
+	}
+	for _, tt := range tests {
+		if got := validOnSelect(tt.mode); got != tt.want {
+			t.Errorf("validOnSelect(%q) = %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestWriteSelection(t *testing.T) {
+	testHome(t)
+	codex, err := exec.LookPath("codex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		mode  string
+		tool  *tool.Tool
+		extra []string
+		want  string
+	}{
+		{
+			"print", onSelectPrint,
+			&tool.Tool{Name: "codex", Command: "codex", Args: []string{"--full-auto"}}, []string{"--model", "gpt-5"},
+			"codex\n",
+		},
+		{
+			"json with the path", onSelectJSON,
+			&tool.Tool{Name: "codex", Command: "codex", Args: []string{"--full-auto"}}, []string{"--model", "gpt-5"},
+			`{"name":"codex","command":"` + codex + `","args":["--full-auto","--model","gpt-5"],"cwd":"/src/api"}` + "\n",
+		},
+		{
+			"json not in PATH", onSelectJSON,
+			&tool.Tool{Name: "claude", Command: "claude"}, nil,
+			`{"name":"claude","command":"claude","args":[],"cwd":"/src/api"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configured := append([]string{}, tt.tool.Args...)
+			var out bytes.Buffer
+			if err := writeSelection(&out, tt.mode, tt.tool, tt.extra, "/src/api"); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("writeSelection() wrote %q, want %q", out.String(), tt.want)
+			}
+			if !slices.Equal(tt.tool.Args, configured) {
+				t.Errorf("Args = %q after writeSelection(), want the configured %q kept", tt.tool.Args, configured)
+			}
+			if tt.mode == onSelectJSON {
+				var sel selection
+				if err := json.Unmarshal(out.Bytes(), &sel); err != nil {
+					t.Errorf("output isn't JSON: %v", err)
+				}
+			}
+		})
+	}
+}
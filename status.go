@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/report"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// runStatus prints every tool of the registry with whether it is installed, its version,
+// balance and last use, and returns the process exit code.
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "print the status as JSON, for status bars and dashboards")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	settings := config.LoadSettings()
+	timefmt.Set(settings.DateTimeFormat())
+	registry := loadRegistry(settings)
+	statuses := toolStatuses(context.Background(), registry)
+
+	format := report.FormatText
+	if *jsonOutput {
+		format = report.FormatJSON
+	}
+	if err := report.WriteStatus(os.Stdout, format, statuses); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// toolStatuses returns the status of every tool in the registry. Versions are detected
+// while the balances are fetched, since both run the tools or their providers.
+func toolStatuses(ctx context.Context, registry *tool.Registry) []report.Status {
+	tools := registry.List()
+	statuses := make([]report.Status, len(tools))
+	var wg sync.WaitGroup
+	for i, t := range tools {
+		statuses[i].Tool = t
+		path, err := t.Path()
+		if err != nil {
+			continue
+		}
+		statuses[i].Installed, statuses[i].Path = true, path
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if t.Version = t.DetectVersion(ctx); t.Version != "" {
+				statuses[i].Version = t.InstalledVersion()
+			}
+		}()
+	}
+	balances := currentBalances(ctx, registry, false)
+	wg.Wait()
+
+	usage := config.LoadUsageStats()
+	for i := range statuses {
+		name := statuses[i].Tool.Name
+		statuses[i].Balance = balances[name]
+		statuses[i].LastUsed, statuses[i].Launches = usage[name].LastUsed, usage[name].Launches
+	}
+	return statuses
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/config"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/provider"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/report"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/timefmt"
+	"github.com/huajianxiaowanzi/amazing-cli/pkg/tool"
+)
+
+// runUsage prints the balances of the tools with a balance provider and returns the
+// process exit code. With --format gha the Markdown table is also appended to the
+// GitHub Actions job summary when $GITHUB_STEP_SUMMARY is set.
+func runUsage(args []string) int {
+	fs := flag.NewFlagSet("usage", flag.ContinueOnError)
+	format := fs.String("format", report.FormatText, "output format: text, json, or gha (GitHub Actions job summary)")
+	all := fs.Bool("all", false, "also report tools whose CLI isn't installed, from their credentials alone")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !report.ValidFormat(*format) {
+		fmt.Fprintf(os.Stderr, "Error: --format must be text, json or gha, not %q\n", *format)
+		return 2
+	}
+
+	settings := config.LoadSettings()
+	timefmt.Set(settings.DateTimeFormat())
+	registry := loadRegistry(settings)
+	entries := usageEntries(context.Background(), registry, *all)
+
+	var w io.Writer = os.Stdout
+	if path := os.Getenv("GITHUB_STEP_SUMMARY"); *format == report.FormatGHA && path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = io.MultiWriter(os.Stdout, f) // Keep the table in the job log too
+	}
+	if err := report.Write(w, *format, entries); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// usageEntries returns the balances of the installed tools (every tool with all) with a
+// balance provider, from the daemon's cache while it is fresh and fetched otherwise.
+func usageEntries(ctx context.Context, registry *tool.Registry, all bool) []report.Entry {
+	balances := currentBalances(ctx, registry, all)
+	var entries []report.Entry
+	for _, t := range registry.List() {
+		if !provider.SupportsBalance(t) {
+			continue
+		}
+		balance := balances[t.Name]
+		if balance == nil && !all && !t.RefreshInstalled() {
+			continue
+		}
+		entries = append(entries, report.Entry{Tool: t, Balance: balance})
+	}
+	return entries
+}
+
+// currentBalances returns the daemon's cached balances while they are fresh, and fetches
+// those of the installed tools (every tool with all) otherwise.
+func currentBalances(ctx context.Context, registry *tool.Registry, all bool) map[string]*tool.Balance {
+	if cache, _ := config.LoadBalanceCache(); cache.Fresh(time.Now()) {
+		return cache.Balances
+	}
+	return fetchBalances(ctx, registry, all)
+}